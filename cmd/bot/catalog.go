@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+)
+
+const catalogUsage = `Использование:
+  fitlife import -kind nutrition|trainings [-format csv|json] [-dry-run] ФАЙЛ
+  fitlife export -kind nutrition|trainings [-format csv|json] [-o ФАЙЛ]
+
+Экспорт пишет в файл, а не в stdout: SQL-лог GORM выводится в stdout.
+`
+
+// runCatalogCommand выполняет подкоманду импорта или экспорта каталога
+// и возвращает код выхода процесса
+func runCatalogCommand(catalogService *service.CatalogService, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, catalogUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "import":
+		return runImport(catalogService, args[1:])
+	case "export":
+		return runExport(catalogService, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Неизвестная команда %q\n%s", args[0], catalogUsage)
+		return 2
+	}
+}
+
+func runImport(catalogService *service.CatalogService, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	kind := flags.String("kind", service.CatalogNutrition, "раздел каталога: nutrition или trainings")
+	format := flags.String("format", "", "формат файла: csv или json (по умолчанию по расширению)")
+	dryRun := flags.Bool("dry-run", false, "только проверить файл, ничего не записывая")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprint(os.Stderr, catalogUsage)
+		return 2
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		detected, err := service.FormatFromFilename(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		*format = detected
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer file.Close()
+
+	report, err := catalogService.Import(*kind, *format, file, *dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка импорта:", err)
+		return 1
+	}
+	fmt.Print(report.Summary(i18n.Default(), len(report.Rows)))
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func runExport(catalogService *service.CatalogService, args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	kind := flags.String("kind", service.CatalogNutrition, "раздел каталога: nutrition или trainings")
+	format := flags.String("format", service.FormatCSV, "формат файла: csv или json")
+	output := flags.String("o", "", "файл для записи (по умолчанию <kind>.<format>)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *output == "" {
+		*output = *kind + "." + *format
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer file.Close()
+
+	if err := catalogService.Export(*kind, *format, file); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка экспорта:", err)
+		return 1
+	}
+	if err := file.Sync(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println("Сохранено в", *output)
+	return 0
+}
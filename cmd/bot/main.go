@@ -1,13 +1,27 @@
 package main
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Часовые пояса пользователей не зависят от tzdata в контейнере
 
+	"github.com/alenapavlenkko/telegramfitnes/internal/admin"
+	"github.com/alenapavlenkko/telegramfitnes/internal/api"
 	"github.com/alenapavlenkko/telegramfitnes/internal/bot"
+	"github.com/alenapavlenkko/telegramfitnes/internal/callback"
 	"github.com/alenapavlenkko/telegramfitnes/internal/database"
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
 	"github.com/alenapavlenkko/telegramfitnes/internal/models"
 	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+	"github.com/alenapavlenkko/telegramfitnes/internal/scheduler"
+	"github.com/alenapavlenkko/telegramfitnes/internal/server"
 	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	"github.com/alenapavlenkko/telegramfitnes/internal/web"
 	"github.com/alenapavlenkko/telegramfitnes/pkg/utils"
 	"github.com/joho/godotenv"
 )
@@ -42,10 +56,32 @@ func main() {
 		&models.WeeklyMenu{},
 		&models.MenuDay{},
 		&models.DayMeal{},
+		&models.FSMSession{},
+		&models.WorkoutLog{},
+		&models.FoodDiaryEntry{},
+		&models.BodyMeasurement{},
+		&models.ReminderSettings{},
+		&models.SentReminder{},
+		&models.Ingredient{},
+		&models.RecipeItem{},
+		&models.ShoppingList{},
+		&models.ShoppingItem{},
+		&models.MenuAssignment{},
+		&models.Exercise{},
+		&models.ProgramDay{},
+		&models.ProgramExercise{},
 	); err != nil {
 		utils.Log.Error("Failed to migrate database: " + err.Error())
 		os.Exit(1)
 	}
+	if err := database.EnableSearch(db); err != nil {
+		utils.Log.Error(err.Error())
+		os.Exit(1)
+	}
+	if err := database.RenameStoreSections(db, service.StoreSectionKeys(i18n.Default())); err != nil {
+		utils.Log.Error(err.Error())
+		os.Exit(1)
+	}
 
 	// REPOSITORIES
 	trainingRepo := repository.NewTrainingRepo(db)
@@ -53,12 +89,57 @@ func main() {
 	nutritionRepo := repository.NewNutritionRepo(db)
 	weeklyMenuRepo := repository.NewWeeklyMenuRepo(db)
 	userRepo := repository.NewUserRepo(db)
+	workoutLogRepo := repository.NewWorkoutLogRepo(db)
+	foodDiaryRepo := repository.NewFoodDiaryRepo(db)
+	measurementRepo := repository.NewMeasurementRepo(db)
+	reminderRepo := repository.NewReminderRepo(db)
+	fsmSessionRepo := repository.NewFSMSessionRepo(db)
+	ingredientRepo := repository.NewIngredientRepo(db)
+	recipeRepo := repository.NewRecipeRepo(db)
+	shoppingListRepo := repository.NewShoppingListRepo(db)
+	menuAssignmentRepo := repository.NewMenuAssignmentRepo(db)
+	exerciseRepo := repository.NewExerciseRepo(db)
+	programRepo := repository.NewProgramRepo(db)
+	searchRepo := repository.NewSearchRepo(db)
 
 	// SERVICES
 	trainingService := service.NewTrainingService(trainingRepo)
 	categoryService := service.NewCategoryService(categoryRepo)
-	nutritionService := service.NewNutritionService(nutritionRepo, weeklyMenuRepo)
+	nutritionService := service.NewNutritionService(nutritionRepo, weeklyMenuRepo, recipeRepo)
 	userService := service.NewUserService(userRepo)
+	workoutService := service.NewWorkoutService(workoutLogRepo, trainingRepo)
+	foodDiaryService := service.NewFoodDiaryService(foodDiaryRepo, nutritionRepo)
+	measurementService := service.NewMeasurementService(measurementRepo)
+	recipeService := service.NewRecipeService(ingredientRepo, recipeRepo, nutritionRepo, nutritionService)
+	menuService := service.NewMenuAssignmentService(menuAssignmentRepo, nutritionService)
+	generatorService := service.NewMenuGeneratorService(nutritionService)
+	shoppingService := service.NewShoppingService(shoppingListRepo, recipeRepo, nutritionService)
+	programService := service.NewProgramService(programRepo, exerciseRepo, trainingRepo)
+	searchService := service.NewSearchService(searchRepo, trainingRepo, nutritionRepo)
+	catalogService := service.NewCatalogService(trainingRepo, nutritionRepo, categoryRepo, nutritionService)
+	reminderService := service.NewReminderService(reminderRepo, userRepo, nutritionService, menuService,
+		getEnv("REMINDER_TIMEZONE", "Europe/Moscow"))
+
+	// CLI: импорт и экспорт каталога без запуска бота
+	if len(os.Args) > 1 {
+		os.Exit(runCatalogCommand(catalogService, os.Args[1:]))
+	}
+
+	// Останавливаемся по SIGINT/SIGTERM, дав воркерам доработать
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// СОСТОЯНИЯ ДИАЛОГОВ (админ-мастера и пользовательские диалоги)
+	stateStoreKind := os.Getenv("ADMIN_STATE_STORE")
+	stateTTL := getEnvDuration("ADMIN_STATE_TTL", fsm.DefaultTTL)
+
+	adminFSM := admin.NewAdminFSM(newStateStore(stateStoreKind, fsmSessionRepo, "admin"), stateTTL)
+	userFSM := fsm.NewMachine(newStateStore(stateStoreKind, fsmSessionRepo, "user"), stateTTL)
+	// Истекшие состояния в БД общие для всех scope, поэтому хватает одного уборщика
+	go adminFSM.RunJanitor(ctx, 10*time.Minute)
+	if stateStoreKind == "memory" {
+		go userFSM.RunJanitor(ctx, 10*time.Minute)
+	}
 
 	// BOT
 	token := os.Getenv("TELEGRAM_TOKEN")
@@ -70,12 +151,32 @@ func main() {
 	adminIDs := bot.ParseAdminIDs(os.Getenv("ADMIN_IDS"))
 	utils.Log.Info("Loaded admin IDs")
 
+	// Данные inline-кнопок подписываются, если задан CALLBACK_SECRET
+	callbackCodec := callback.NewCodec(os.Getenv("CALLBACK_SECRET"))
+	if !callbackCodec.Signed() {
+		utils.Log.Info("CALLBACK_SECRET not set, callback data is not signed")
+	}
+
 	botApp, err := bot.NewBotApp(
 		token,
 		trainingService,
 		nutritionService,
 		categoryService,
 		userService,
+		workoutService,
+		foodDiaryService,
+		measurementService,
+		reminderService,
+		catalogService,
+		recipeService,
+		shoppingService,
+		menuService,
+		generatorService,
+		programService,
+		searchService,
+		adminFSM,
+		userFSM,
+		callback.NewRouter(callbackCodec),
 		adminIDs,
 	)
 	if err != nil {
@@ -83,6 +184,123 @@ func main() {
 		os.Exit(1)
 	}
 
+	// HTTP: вебхук и REST API админки используют один сервер на SERVER_PORT
+	engine := server.NewEngine(os.Getenv("ENVIRONMENT"))
+	serveHTTP := false
+
+	// РЕЖИМ ПОЛУЧЕНИЯ АПДЕЙТОВ
+	mode := os.Getenv("BOT_MODE")
+	if mode == "" {
+		mode = bot.ModePolling
+	}
+	switch mode {
+	case bot.ModePolling:
+		utils.Log.Info("Using long polling")
+	case bot.ModeWebhook:
+		if err := botApp.EnableWebhook(engine, bot.WebhookConfig{
+			URL:    os.Getenv("WEBHOOK_URL"),
+			Path:   os.Getenv("WEBHOOK_PATH"),
+			Secret: os.Getenv("WEBHOOK_SECRET"),
+		}); err != nil {
+			utils.Log.Error("Failed to enable webhook: " + err.Error())
+			os.Exit(1)
+		}
+		serveHTTP = true
+	default:
+		utils.Log.Error("Unknown BOT_MODE: " + mode)
+		os.Exit(1)
+	}
+
+	// REST API И ВЕБ-АДМИНКА - включаются, если заданы логин и пароль
+	apiUser, apiPassword := os.Getenv("ADMIN_USERNAME"), os.Getenv("ADMIN_PASSWORD")
+	if apiUser != "" || apiPassword != "" {
+		apiHandler := api.NewHandler(trainingService, nutritionService, categoryService, recipeService,
+			userService, menuService, generatorService, programService)
+		if err := apiHandler.Register(engine, apiUser, apiPassword); err != nil {
+			utils.Log.Error("Failed to enable admin API: " + err.Error())
+			os.Exit(1)
+		}
+		utils.Log.Info("Admin REST API enabled at /api/admin")
+
+		dashboard, err := web.NewDashboard(trainingService, nutritionService, categoryService, recipeService)
+		if err == nil {
+			err = dashboard.Register(engine, apiUser, apiPassword)
+		}
+		if err != nil {
+			utils.Log.Error("Failed to enable web dashboard: " + err.Error())
+			os.Exit(1)
+		}
+		utils.Log.Info("Web dashboard enabled at /admin")
+		serveHTTP = true
+	}
+
+	if serveHTTP {
+		addr := ":" + getEnv("SERVER_PORT", "8080")
+		go func() {
+			if err := server.Run(ctx, addr, engine); err != nil {
+				utils.Log.Error("HTTP server error: " + err.Error())
+				stop()
+			}
+		}()
+	}
+
+	// НАПОМИНАНИЯ
+	if os.Getenv("REMINDERS_DISABLED") != "true" {
+		reminderScheduler := scheduler.NewScheduler(reminderService, botApp.SendReminder,
+			scheduler.SystemClock{}, getEnvDuration("REMINDER_INTERVAL", time.Minute))
+		go reminderScheduler.Run(ctx)
+	}
+
 	utils.Log.Info("Telegram bot starting...")
-	botApp.Run()
+	botApp.Run(ctx, bot.DispatchConfig{
+		Workers:         getEnvInt("BOT_WORKERS", 8),
+		QueueSize:       getEnvInt("BOT_QUEUE_SIZE", 100),
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+	})
+}
+
+// newStateStore создает хранилище состояний диалогов: "memory" или БД (по умолчанию)
+func newStateStore(kind string, repo repository.FSMSessionRepository, scope string) fsm.Store {
+	if kind == "memory" {
+		utils.Log.Info("FSM '" + scope + "' uses in-memory store")
+		return fsm.NewMemoryStore()
+	}
+	utils.Log.Info("FSM '" + scope + "' uses database store")
+	return fsm.NewDBStore(repo, scope)
+}
+
+// getEnv читает строку из окружения, при отсутствии возвращает def
+func getEnv(name, def string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return def
+}
+
+// getEnvInt читает целое число из окружения, при отсутствии возвращает def
+func getEnvInt(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		utils.Log.Error("Invalid " + name + ": " + err.Error())
+		os.Exit(1)
+	}
+	return parsed
+}
+
+// getEnvDuration читает длительность (например, "30s", "24h") из окружения
+func getEnvDuration(name string, def time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		utils.Log.Error("Invalid " + name + ": " + err.Error())
+		os.Exit(1)
+	}
+	return parsed
 }
@@ -0,0 +1,166 @@
+package admin
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/callback"
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// FileTransfer - отправка и скачивание файлов через Telegram
+type FileTransfer interface {
+	SendDocument(chatID int64, name string, data []byte, caption string) error
+	DownloadFile(fileID string) ([]byte, error)
+}
+
+// maxImportFileSize - предел размера загружаемого каталога
+const maxImportFileSize = 5 << 20
+
+// Сколько ошибок строк показывать в отчете импорта
+const importReportErrors = 15
+
+// Разделы каталога; подписи - admin.catalog.kind.<раздел>
+var catalogKinds = map[string]bool{
+	service.CatalogNutrition: true,
+	service.CatalogTrainings: true,
+}
+
+// ShowCatalogAdmin - меню импорта и экспорта каталога
+func (ah *AdminHandler) ShowCatalogAdmin(chatID int64) {
+	tr := ah.tr(chatID)
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.catalog.import_dishes"), "import", service.CatalogNutrition),
+			ah.button(tr.T("admin.catalog.import_trainings"), "import", service.CatalogTrainings),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.catalog.dishes_csv"), "export", service.CatalogNutrition, service.FormatCSV),
+			ah.button(tr.T("admin.catalog.dishes_json"), "export", service.CatalogNutrition, service.FormatJSON),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.catalog.trainings_csv"), "export", service.CatalogTrainings, service.FormatCSV),
+			ah.button(tr.T("admin.catalog.trainings_json"), "export", service.CatalogTrainings, service.FormatJSON),
+		),
+		tgbotapi.NewInlineKeyboardRow(ah.button(tr.T("admin.back_short"), "panel")),
+	}
+	ah.sendTextWithKeyboard(chatID, tr.T("admin.catalog.title"), rows)
+}
+
+// StartImportFlow ждет от админа файл каталога
+func (ah *AdminHandler) StartImportFlow(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	kind := c.String(0)
+	if !catalogKinds[kind] {
+		return
+	}
+	state := &AdminState{
+		Action:   "import",
+		Step:     1,
+		TempData: make(fsm.TempData),
+	}
+	state.TempData.Set("kind", kind)
+	ah.Fsm.SetState(c.From.ID, state)
+
+	columns := "title, description, calories, protein, carbs, fats, category, diets, allergens"
+	if kind == service.CatalogTrainings {
+		columns = "title, description, difficulty, duration, category, youtube_link"
+	}
+	ah.sendTextFunc(c.ChatID, tr.T("admin.import.prompt",
+		tr.T("admin.catalog.kind."+kind), columns))
+}
+
+// HandleAdminDocument принимает файл каталога в мастере импорта
+func (ah *AdminHandler) HandleAdminDocument(chatID, userID int64, state *AdminState, doc *tgbotapi.Document) {
+	tr := ah.tr(chatID)
+	if state.Action != "import" || state.Step != 1 {
+		ah.sendTextFunc(chatID, tr.T("admin.import.unexpected"))
+		return
+	}
+	format, err := service.FormatFromFilename(doc.FileName)
+	if err != nil {
+		ah.sendTextFunc(chatID, "❌ "+tr.Error(err))
+		return
+	}
+	if doc.FileSize > maxImportFileSize {
+		ah.sendTextFunc(chatID, tr.T("admin.import.too_large", maxImportFileSize>>20))
+		return
+	}
+
+	report, err := ah.importFile(state.TempData.String("kind"), format, doc.FileID, true)
+	if err != nil {
+		ah.sendTextFunc(chatID, "❌ "+tr.Error(err))
+		return
+	}
+
+	state.Step = 2
+	state.TempData.Set("file_id", doc.FileID)
+	state.TempData.Set("format", format)
+	ah.Fsm.SetState(userID, state)
+
+	text := "🔎 " + report.Summary(tr, importReportErrors)
+	if report.Created+report.Updated == 0 {
+		ah.Fsm.DeleteState(userID)
+		ah.sendTextFunc(chatID, text+tr.T("admin.import.nothing"))
+		return
+	}
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.import.apply"), "import_apply"),
+			ah.button(tr.T("admin.cancel"), "cancel"),
+		),
+	}
+	if report.Failed > 0 {
+		text += tr.T("admin.import.skip_failed")
+	}
+	ah.sendTextWithKeyboard(chatID, text, rows)
+}
+
+// applyImport записывает проверенный файл
+func (ah *AdminHandler) applyImport(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	state, ok := ah.Fsm.GetState(c.From.ID)
+	if !ok || state.Action != "import" || state.Step != 2 {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.import.expired"))
+		return
+	}
+	ah.Fsm.DeleteState(c.From.ID)
+
+	report, err := ah.importFile(state.TempData.String("kind"), state.TempData.String("format"),
+		state.TempData.String("file_id"), false)
+	if err != nil {
+		ah.sendTextFunc(c.ChatID, "❌ "+tr.Error(err))
+		return
+	}
+	ah.sendTextFunc(c.ChatID, tr.T("admin.import.done")+report.Summary(tr, importReportErrors))
+}
+
+func (ah *AdminHandler) importFile(kind, format, fileID string, dryRun bool) (*service.ImportReport, error) {
+	data, err := ah.files.DownloadFile(fileID)
+	if err != nil {
+		return nil, err
+	}
+	return ah.catalogService.Import(kind, format, bytes.NewReader(data), dryRun)
+}
+
+// exportCatalog отправляет раздел каталога файлом
+func (ah *AdminHandler) exportCatalog(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	kind, format := c.String(0), c.String(1)
+	if !catalogKinds[kind] {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := ah.catalogService.Export(kind, format, &buf); err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.export.error", err))
+		return
+	}
+	name := fmt.Sprintf("%s-%s.%s", kind, time.Now().Format("2006-01-02"), format)
+	if err := ah.files.SendDocument(c.ChatID, name, buf.Bytes(), tr.T("admin.catalog.exported", tr.T("admin.catalog.kind."+kind))); err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.export.send_error", err))
+	}
+}
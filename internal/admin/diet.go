@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+)
+
+// tagListPrompt - нумерованный список тегов для мастеров; номера начинаются с first
+func tagListPrompt(tr *i18n.Localizer, tags []service.DietTag, first int, prefix string) string {
+	msg := ""
+	for i, tag := range tags {
+		msg += fmt.Sprintf("%d. %s%s\n", first+i, prefix, strings.ToLower(tr.T("tag."+tag.Key)))
+	}
+	return msg
+}
+
+// parseTagNumbers собирает маску из номеров через запятую или пробел.
+// Номер first соответствует первому тегу, «-» - ни одного
+func parseTagNumbers(tr *i18n.Localizer, text string, tags []service.DietTag, first int) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "-" {
+		return 0, nil
+	}
+	mask := 0
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' }) {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < first || n >= first+len(tags) {
+			return 0, errors.New(tr.T("admin.tags.invalid", first, first+len(tags)-1))
+		}
+		mask |= tags[n-first].Bit
+	}
+	return mask, nil
+}
+
+// parseRestrictions - ограничения мастера генерации: номера диет, затем номера аллергенов
+func parseRestrictions(tr *i18n.Localizer, text string) (models.DishFilter, error) {
+	text = strings.TrimSpace(text)
+	if text == "-" {
+		return models.DishFilter{}, nil
+	}
+	last := len(service.DietTags) + len(service.AllergenTags)
+	var filter models.DishFilter
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' }) {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > last {
+			return models.DishFilter{}, errors.New(tr.T("admin.tags.invalid", 1, last))
+		}
+		if n <= len(service.DietTags) {
+			filter.Diets |= service.DietTags[n-1].Bit
+		} else {
+			filter.Allergens |= service.AllergenTags[n-1-len(service.DietTags)].Bit
+		}
+	}
+	return filter, nil
+}
+
+// formatTags - теги маски через запятую или «нет»
+func formatTags(tr *i18n.Localizer, tags []service.DietTag, mask int) string {
+	labels := render.TagLabels(tr, tags, mask)
+	if len(labels) == 0 {
+		return tr.T("common.none")
+	}
+	return strings.ToLower(strings.Join(labels, ", "))
+}
@@ -0,0 +1,190 @@
+package admin
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+)
+
+// mealSlotPresets - готовые наборы приемов пищи для мастера генерации, ID из service.MealSlots
+var mealSlotPresets = [][]string{
+	{"breakfast", "lunch", "dinner"},
+	{"breakfast", "lunch", "snack", "dinner"},
+	{"breakfast", "second_breakfast", "lunch", "afternoon_snack", "dinner"},
+}
+
+// Если дни меню в среднем отходят от нормы больше чем на эту долю,
+// админу подсказывается, что каталогу не хватает блюд
+const generatorMissWarning = 0.1
+
+// StartGenerateMenuFlow - мастер генерации недельного меню из каталога блюд
+func (ah *AdminHandler) StartGenerateMenuFlow(chatID int64, userID int64) {
+	tr := ah.tr(chatID)
+	ah.Fsm.SetState(userID, &AdminState{
+		Action:   "generate_menu",
+		Step:     1,
+		TempData: make(fsm.TempData),
+	})
+	ah.sendTextFunc(chatID, tr.T("admin.generate.start"))
+}
+
+func (ah *AdminHandler) handleGenerateMenu(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
+	text = strings.TrimSpace(text)
+	switch state.Step {
+	case 1:
+		calories, err := strconv.Atoi(text)
+		if err != nil || calories <= 0 {
+			ah.sendTextFunc(chatID, tr.T("admin.generate.calories_invalid"))
+			return
+		}
+		state.TempData.Set("calories", calories)
+		state.Step = 2
+		ah.sendTextFunc(chatID, tr.T("admin.generate.split_prompt",
+			service.DefaultMacroSplit[0], service.DefaultMacroSplit[1], service.DefaultMacroSplit[2]))
+
+	case 2:
+		if text != "-" {
+			parts := strings.Split(text, "/")
+			if len(parts) != 3 {
+				ah.sendTextFunc(chatID, tr.T("admin.generate.split_invalid"))
+				return
+			}
+			var split [3]int
+			for i, part := range parts {
+				value, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(part, "%")))
+				if err != nil {
+					ah.sendTextFunc(chatID, tr.T("admin.generate.split_invalid"))
+					return
+				}
+				split[i] = value
+			}
+			if split[0] < 0 || split[1] < 0 || split[2] < 0 || split[0]+split[1]+split[2] != 100 {
+				ah.sendTextFunc(chatID, tr.T("admin.generate.split_sum"))
+				return
+			}
+			state.TempData.Set("protein_pct", split[0])
+			state.TempData.Set("fats_pct", split[1])
+			state.TempData.Set("carbs_pct", split[2])
+		}
+		state.Step = 3
+		msg := tr.T("admin.generate.meals_prompt")
+		for i, preset := range mealSlotPresets {
+			names := make([]string, len(preset))
+			for j, mealType := range preset {
+				names[j] = service.MealName(tr, mealType)
+			}
+			msg += fmt.Sprintf("%d. %s\n", i+1, strings.Join(names, ", "))
+		}
+		ah.sendTextFunc(chatID, msg+tr.T("admin.generate.meals_custom"))
+
+	case 3:
+		var mealTypes []string
+		if n, err := strconv.Atoi(text); err == nil {
+			if n < 1 || n > len(mealSlotPresets) {
+				ah.sendTextFunc(chatID, tr.T("admin.generate.meals_invalid", len(mealSlotPresets)))
+				return
+			}
+			mealTypes = mealSlotPresets[n-1]
+		} else {
+			mealTypes = splitList(text)
+		}
+		if len(mealTypes) == 0 {
+			ah.sendTextFunc(chatID, tr.T("admin.generate.meals_empty"))
+			return
+		}
+		state.TempData.Set("meal_types", strings.Join(mealTypes, ","))
+		state.Step = 4
+		ah.sendTextFunc(chatID, tr.T("admin.generate.exclude_prompt"))
+
+	case 4:
+		if text != "-" {
+			state.TempData.Set("exclude", text)
+		}
+		state.Step = 5
+		ah.sendTextFunc(chatID, tr.T("admin.generate.restrictions_prompt")+tagListPrompt(tr, service.DietTags, 1, "")+
+			tagListPrompt(tr, service.AllergenTags, len(service.DietTags)+1, tr.T("admin.generate.without")))
+
+	case 5:
+		filter, err := parseRestrictions(tr, text)
+		if err != nil {
+			ah.sendTextFunc(chatID, "❌ "+tr.Error(err))
+			return
+		}
+		state.TempData.Set("diets", filter.Diets)
+		state.TempData.Set("allergens", filter.Allergens)
+		state.Step = 6
+		ah.sendTextFunc(chatID, tr.T("admin.generate.seed_prompt"))
+
+	case 6:
+		var seed int64
+		if text != "-" {
+			value, err := strconv.ParseInt(text, 10, 64)
+			if err != nil || value == 0 {
+				ah.sendTextFunc(chatID, tr.T("admin.generate.seed_invalid"))
+				return
+			}
+			seed = value
+		}
+
+		ah.sendTextFunc(chatID, tr.T("admin.generate.running"))
+		result, err := ah.generatorService.Generate(service.GenerateMenuDTO{
+			Calories:     state.TempData.Int("calories"),
+			ProteinPct:   state.TempData.Int("protein_pct"),
+			FatsPct:      state.TempData.Int("fats_pct"),
+			CarbsPct:     state.TempData.Int("carbs_pct"),
+			MealTypes:    splitList(state.TempData.String("meal_types")),
+			ExcludeWords: splitList(state.TempData.String("exclude")),
+			Restrictions: models.DishFilter{
+				Diets:     state.TempData.Int("diets"),
+				Allergens: state.TempData.Int("allergens"),
+			},
+			Seed:      seed,
+			Localizer: tr,
+		})
+		ah.Fsm.DeleteState(userID)
+		if err != nil {
+			ah.sendTextFunc(chatID, tr.T("admin.generate.error", err))
+			ah.ShowWeeklyMenusAdmin(chatID)
+			return
+		}
+		ah.sendTextFunc(chatID, formatGeneratedMenu(tr, result))
+		ah.ShowWeeklyMenuDetails(chatID, result.Menu.ID)
+	}
+}
+
+// formatGeneratedMenu - насколько дни сгенерированного меню попали в норму
+func formatGeneratedMenu(tr *i18n.Localizer, result *service.GeneratedMenu) string {
+	target := result.Target
+	msg := tr.T("admin.generate.done", result.Menu.Name, result.Seed)
+	msg += tr.T("admin.generate.target", target.Calories, target.Protein, target.Fats, target.Carbs)
+
+	miss := 0.0
+	for i, day := range result.Days {
+		diff := day.Calories - target.Calories
+		msg += tr.T("admin.generate.day",
+			tr.T("weekday."+strconv.Itoa(i+1)), day.Calories, diff, day.Protein, day.Fats, day.Carbs)
+		miss += math.Abs(float64(diff))
+	}
+	if len(result.Days) > 0 && miss/float64(len(result.Days)) > generatorMissWarning*float64(target.Calories) {
+		msg += tr.T("admin.generate.miss")
+	}
+	return msg
+}
+
+// splitList - непустые элементы списка через запятую
+func splitList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
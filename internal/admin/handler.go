@@ -3,10 +3,15 @@ package admin
 import (
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/alenapavlenkko/telegramfitnes/internal/callback"
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
 	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
 	"github.com/alenapavlenkko/telegramfitnes/internal/service"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -17,103 +22,176 @@ type AdminHandler struct {
 	nutritionService     *service.NutritionService
 	categoryService      *service.CategoryService
 	userService          *service.UserService
+	catalogService       *service.CatalogService
+	menuService          *service.MenuAssignmentService
+	generatorService     *service.MenuGeneratorService
+	programService       *service.ProgramService
 	Fsm                  *AdminFSM
 	sendTextFunc         func(chatID int64, text string)
 	sendTextWithKeyboard func(chatID int64, text string, rows [][]tgbotapi.InlineKeyboardButton)
+	// Сообщение по шаблону render на языке чата, в HTML; rows может быть пустым
+	sendView func(chatID int64, name string, data any, rows [][]tgbotapi.InlineKeyboardButton)
 
-	// Callbacks
-	adminCallbacks map[string]func(*tgbotapi.CallbackQuery)
+	// Переводчик для чата: язык администратора определяет бот
+	tr func(chatID int64) *i18n.Localizer
+
+	// Роутер inline-кнопок, общий с пользовательской частью бота
+	callbacks *callback.Router
+
+	// Файлы каталога для импорта и экспорта
+	files FileTransfer
 }
 
+// RegisterAdminCallbacks регистрирует обработчики админских inline-кнопок
 func (ah *AdminHandler) RegisterAdminCallbacks() {
-	ah.adminCallbacks = make(map[string]func(*tgbotapi.CallbackQuery))
+	r := ah.callbacks
+	ns := callback.Admin
 
-	ah.adminCallbacks["admin_panel"] = func(c *tgbotapi.CallbackQuery) {
-		ah.ShowAdminPanel(c.Message.Chat.ID)
-	}
+	r.Handle(ns, "noop", func(c *callback.Context) {})
 
-	ah.adminCallbacks["admin_add_training"] = func(c *tgbotapi.CallbackQuery) {
-		ah.StartAddTrainingFlow(c.Message.Chat.ID, c.From.ID)
-	}
+	r.Handle(ns, "cancel", func(c *callback.Context) {
+		ah.Fsm.DeleteState(c.From.ID)
+		ah.sendTextFunc(c.ChatID, ah.tr(c.ChatID).T("admin.cancelled"))
+		ah.ShowAdminPanel(c.ChatID)
+	})
 
-	ah.adminCallbacks["admin_trainings"] = func(c *tgbotapi.CallbackQuery) {
-		ah.ShowTrainingsAdmin(c.Message.Chat.ID)
-	}
+	r.Handle(ns, "panel", func(c *callback.Context) {
+		ah.ShowAdminPanel(c.ChatID)
+	})
 
-	ah.adminCallbacks["noop"] = func(c *tgbotapi.CallbackQuery) {
-	}
+	r.Handle(ns, "trainings", func(c *callback.Context) {
+		ah.ShowTrainingsAdmin(c.ChatID)
+	})
 
-	ah.adminCallbacks["admin_nutrition"] = func(c *tgbotapi.CallbackQuery) {
-		ah.ShowNutritionAdmin(c.Message.Chat.ID)
-	}
+	r.Handle(ns, "nutrition", func(c *callback.Context) {
+		ah.ShowNutritionAdmin(c.ChatID)
+	})
 
-	ah.adminCallbacks["admin_categories"] = func(c *tgbotapi.CallbackQuery) {
-		ah.ShowCategoriesAdmin(c.Message.Chat.ID)
-	}
+	r.Handle(ns, "categories", func(c *callback.Context) {
+		ah.ShowCategoriesAdmin(c.ChatID)
+	})
 
-	ah.adminCallbacks["admin_weekly_menus"] = func(c *tgbotapi.CallbackQuery) {
-		ah.ShowWeeklyMenusAdmin(c.Message.Chat.ID)
-	}
+	r.Handle(ns, "weekly_menus", func(c *callback.Context) {
+		ah.ShowWeeklyMenusAdmin(c.ChatID)
+	})
 
-	ah.adminCallbacks["admin_add_nutrition"] = func(c *tgbotapi.CallbackQuery) {
-		chatID := c.Message.Chat.ID
-		userID := c.From.ID
-		ah.StartAddNutritionFlow(chatID, userID)
-	}
+	r.Handle(ns, "add_training", func(c *callback.Context) {
+		ah.StartAddTrainingFlow(c.ChatID, c.From.ID)
+	})
 
-	ah.adminCallbacks["admin_add_category"] = func(c *tgbotapi.CallbackQuery) {
-		chatID := c.Message.Chat.ID
-		userID := c.From.ID
-		ah.StartAddCategoryFlow(chatID, userID)
-	}
+	r.Handle(ns, "add_nutrition", func(c *callback.Context) {
+		ah.StartAddNutritionFlow(c.ChatID, c.From.ID)
+	})
 
-	ah.adminCallbacks["admin_add_weekly_menu"] = func(c *tgbotapi.CallbackQuery) {
-		chatID := c.Message.Chat.ID
-		userID := c.From.ID
-		ah.StartAddWeeklyMenuFlow(chatID, userID)
-	}
+	r.Handle(ns, "add_category", func(c *callback.Context) {
+		ah.StartAddCategoryFlow(c.ChatID, c.From.ID)
+	})
+
+	r.Handle(ns, "add_weekly_menu", func(c *callback.Context) {
+		ah.StartAddWeeklyMenuFlow(c.ChatID, c.From.ID)
+	})
+
+	r.Handle(ns, "generate_menu", func(c *callback.Context) {
+		ah.StartGenerateMenuFlow(c.ChatID, c.From.ID)
+	})
+
+	// Недельные меню
+	r.Handle(ns, "view_menu", func(c *callback.Context) {
+		ah.ShowWeeklyMenuDetails(c.ChatID, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "add_day", func(c *callback.Context) {
+		ah.StartAddDayToMenuFlow(c.ChatID, c.From.ID, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "activate_menu", ah.activateWeeklyMenu, callback.Uint)
+	r.Handle(ns, "publish_menu", ah.publishWeeklyMenu, callback.Uint, callback.Uint)
+	r.Handle(ns, "assign_menu", func(c *callback.Context) {
+		ah.StartAssignMenuFlow(c.ChatID, c.From.ID, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "del_menu", ah.confirmDeleteWeeklyMenu, callback.Uint)
+	r.Handle(ns, "del_menu_ok", ah.deleteWeeklyMenu, callback.Uint)
+
+	// Тренировки
+	r.Handle(ns, "view_training", ah.viewTraining, callback.Uint)
+	r.Handle(ns, "edit_training", func(c *callback.Context) {
+		ah.StartEditTrainingFlow(c.ChatID, c.From.ID, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "del_training", ah.confirmDeleteTraining, callback.Uint)
+	r.Handle(ns, "del_training_ok", ah.deleteTraining, callback.Uint)
+
+	// Питание
+	r.Handle(ns, "view_nutrition", ah.viewNutrition, callback.Uint)
+	r.Handle(ns, "edit_nutrition", ah.editNutrition, callback.Uint)
+	r.Handle(ns, "del_nutrition", ah.confirmDeleteNutrition, callback.Uint)
+	r.Handle(ns, "del_nutrition_ok", ah.deleteNutrition, callback.Uint)
+
+	// Категории
+	r.Handle(ns, "view_category", ah.viewCategory, callback.Uint)
+	r.Handle(ns, "edit_category", ah.editCategory, callback.Uint)
+	r.Handle(ns, "del_category", ah.confirmDeleteCategory, callback.Uint)
+	r.Handle(ns, "del_category_ok", ah.deleteCategory, callback.Uint)
+
+	// Импорт и экспорт каталога
+	r.Handle(ns, "catalog", func(c *callback.Context) {
+		ah.ShowCatalogAdmin(c.ChatID)
+	})
+	r.Handle(ns, "import", ah.StartImportFlow, callback.String)
+	r.Handle(ns, "import_apply", ah.applyImport)
+	r.Handle(ns, "export", ah.exportCatalog, callback.String, callback.String)
+
+	ah.registerProgramCallbacks()
+}
+
+// button - кнопка из пространства админских обработчиков
+func (ah *AdminHandler) button(text, action string, params ...any) tgbotapi.InlineKeyboardButton {
+	return ah.callbacks.Button(text, callback.Admin, action, params...)
 }
 
 func (ah *AdminHandler) ShowAdminPanel(chatID int64) {
+	tr := ah.tr(chatID)
 	log.Printf("[ADMIN DEBUG] ShowAdminPanel called for chat %d", chatID)
 	log.Printf("[ADMIN DEBUG] sendTextFunc is nil? %v", ah.sendTextFunc == nil)
 	log.Printf("[ADMIN DEBUG] sendTextWithKeyboard is nil? %v", ah.sendTextWithKeyboard == nil)
 	rows := [][]tgbotapi.InlineKeyboardButton{
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🏋️ Тренировки", "admin_trainings"),
-			tgbotapi.NewInlineKeyboardButtonData("🍎 Питание", "admin_nutrition"),
+			ah.button(tr.T("admin.trainings"), "trainings"),
+			ah.button(tr.T("admin.nutrition"), "nutrition"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.categories"), "categories"),
+			ah.button(tr.T("admin.menus"), "weekly_menus"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📂 Категории", "admin_categories"),
-			tgbotapi.NewInlineKeyboardButtonData("📅 Недельные меню", "admin_weekly_menus"),
+			ah.button(tr.T("admin.exercises"), "exercises"),
+			ah.button(tr.T("admin.catalog"), "catalog"),
 		),
 	}
 	log.Printf("[ADMIN DEBUG] Sending panel with %d rows", len(rows))
 
 	if ah.sendTextWithKeyboard != nil {
-		ah.sendTextWithKeyboard(chatID, "⚙️ Панель администратора", rows)
+		ah.sendTextWithKeyboard(chatID, tr.T("admin.panel"), rows)
 		log.Printf("[ADMIN DEBUG] Panel sent successfully")
 	} else {
 		log.Printf("[ADMIN DEBUG] ERROR: sendTextWithKeyboard is nil!")
 	}
 }
 func (ah *AdminHandler) ShowTrainingsAdmin(chatID int64) {
+	tr := ah.tr(chatID)
 	trainings, err := ah.trainingService.ListTrainings()
 	if err != nil {
-		ah.sendTextFunc(chatID, "❌ Ошибка при получении тренировок: "+err.Error())
+		ah.sendTextFunc(chatID, tr.T("admin.trainings.error", err))
 		return
 	}
 
 	if len(trainings) == 0 {
 		rows := [][]tgbotapi.InlineKeyboardButton{
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("➕ Добавить тренировку", "admin_add_training"),
+				ah.button(tr.T("admin.trainings.add"), "add_training"),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад в админ-панель", "admin_panel"),
+				ah.button(tr.T("admin.back"), "panel"),
 			),
 		}
-		ah.sendTextWithKeyboard(chatID, "📭 Тренировок пока нет", rows)
+		ah.sendTextWithKeyboard(chatID, tr.T("admin.trainings.empty"), rows)
 		return
 	}
 
@@ -121,59 +199,56 @@ func (ah *AdminHandler) ShowTrainingsAdmin(chatID int64) {
 	for i, t := range trainings {
 		// Кнопка для просмотра деталей
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("🏋️ %s (%d мин)", t.Title, t.Duration),
-				fmt.Sprintf("admin_view_training_%d", t.ID),
-			),
+			ah.button(tr.T("admin.trainings.item", t.Title, t.Duration), "view_training", t.ID),
 		))
 
 		// Кнопки действий
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✏️ Редактировать",
-				fmt.Sprintf("admin_edit_training_%d", t.ID)),
-			tgbotapi.NewInlineKeyboardButtonData("🗑️ Удалить",
-				fmt.Sprintf("admin_delete_training_%d", t.ID)),
+			ah.button(tr.T("admin.edit"), "edit_training", t.ID),
+			ah.button(tr.T("admin.program"), "program", t.ID),
+			ah.button(tr.T("admin.delete_training"), "del_training", t.ID),
 		))
 
 		// Разделитель (только между элементами)
 		if i < len(trainings)-1 {
 			separator := strings.Repeat("─", 20)
 			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData(separator, "noop"),
+				ah.button(separator, "noop"),
 			))
 		}
 	}
 
 	// Кнопка добавления новой тренировки
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData("➕ Добавить тренировку", "admin_add_training"),
+		ah.button(tr.T("admin.trainings.add"), "add_training"),
 	))
 
 	// Кнопка возврата в админ-панель
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад в админ-панель", "admin_panel"),
+		ah.button(tr.T("admin.back"), "panel"),
 	))
 
-	ah.sendTextWithKeyboard(chatID, fmt.Sprintf("🏋️ Тренировки (Admin) - всего: %d", len(trainings)), rows)
+	ah.sendTextWithKeyboard(chatID, tr.T("admin.trainings.title", len(trainings)), rows)
 }
 
 func (ah *AdminHandler) ShowNutritionAdmin(chatID int64) {
+	tr := ah.tr(chatID)
 	nutritions, err := ah.nutritionService.ListNutrition()
 	if err != nil {
-		ah.sendTextFunc(chatID, "❌ Ошибка при получении списка питания")
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.error"))
 		return
 	}
 
 	if len(nutritions) == 0 {
 		rows := [][]tgbotapi.InlineKeyboardButton{
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("➕ Добавить питание", "admin_add_nutrition"),
+				ah.button(tr.T("admin.nutrition.add"), "add_nutrition"),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад в админ-панель", "admin_panel"),
+				ah.button(tr.T("admin.back"), "panel"),
 			),
 		}
-		ah.sendTextWithKeyboard(chatID, "📭 Записей о питании пока нет", rows)
+		ah.sendTextWithKeyboard(chatID, tr.T("admin.nutrition.empty"), rows)
 		return
 	}
 
@@ -182,55 +257,47 @@ func (ah *AdminHandler) ShowNutritionAdmin(chatID int64) {
 	for _, n := range nutritions {
 		// Кнопка просмотра
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("🍎 %s (%d ккал)", n.Title, n.Calories),
-				fmt.Sprintf("admin_view_nutrition_%d", n.ID),
-			),
+			ah.button(tr.T("admin.nutrition.item", n.Title, n.Calories), "view_nutrition", n.ID),
 		))
 
 		// Кнопки действий
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				"✏️ Редактировать",
-				fmt.Sprintf("admin_edit_nutrition_%d", n.ID),
-			),
-			tgbotapi.NewInlineKeyboardButtonData(
-				"🗑 Удалить",
-				fmt.Sprintf("admin_delete_nutrition_%d", n.ID),
-			),
+			ah.button(tr.T("admin.edit"), "edit_nutrition", n.ID),
+			ah.button(tr.T("admin.delete"), "del_nutrition", n.ID),
 		))
 	}
 
 	// Кнопки управления
 	rows = append(rows,
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("➕ Добавить питание", "admin_add_nutrition"),
+			ah.button(tr.T("admin.nutrition.add"), "add_nutrition"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад в админ-панель", "admin_panel"),
+			ah.button(tr.T("admin.back"), "panel"),
 		),
 	)
 
-	ah.sendTextWithKeyboard(chatID, "🍎 Питание (Admin)", rows)
+	ah.sendTextWithKeyboard(chatID, tr.T("admin.nutrition.title"), rows)
 }
 
 func (ah *AdminHandler) ShowCategoriesAdmin(chatID int64) {
+	tr := ah.tr(chatID)
 	categories, err := ah.categoryService.ListCategories()
 	if err != nil {
-		ah.sendTextFunc(chatID, "❌ Ошибка при получении категорий")
+		ah.sendTextFunc(chatID, tr.T("admin.categories.error"))
 		return
 	}
 
 	if len(categories) == 0 {
 		rows := [][]tgbotapi.InlineKeyboardButton{
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("➕ Добавить категорию", "admin_add_category"),
+				ah.button(tr.T("admin.categories.add"), "add_category"),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад в админ-панель", "admin_panel"),
+				ah.button(tr.T("admin.back"), "panel"),
 			),
 		}
-		ah.sendTextWithKeyboard(chatID, "📭 Категорий пока нет", rows)
+		ah.sendTextWithKeyboard(chatID, tr.T("admin.categories.empty"), rows)
 		return
 	}
 
@@ -239,55 +306,48 @@ func (ah *AdminHandler) ShowCategoriesAdmin(chatID int64) {
 	for _, c := range categories {
 		// Кнопка просмотра
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("📂 %s (%s)", c.Name, c.Type),
-				fmt.Sprintf("admin_view_category_%d", c.ID),
-			),
+			ah.button(fmt.Sprintf("📂 %s (%s)", c.Name, c.Type), "view_category", c.ID),
 		))
 
 		// Кнопки действий
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				"✏️ Редактировать",
-				fmt.Sprintf("admin_edit_category_%d", c.ID),
-			),
-			tgbotapi.NewInlineKeyboardButtonData(
-				"🗑 Удалить",
-				fmt.Sprintf("admin_delete_category_%d", c.ID),
-			),
+			ah.button(tr.T("admin.edit"), "edit_category", c.ID),
+			ah.button(tr.T("admin.delete"), "del_category", c.ID),
 		))
 	}
 
 	// Управляющие кнопки
 	rows = append(rows,
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("➕ Добавить категорию", "admin_add_category"),
+			ah.button(tr.T("admin.categories.add"), "add_category"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад в админ-панель", "admin_panel"),
+			ah.button(tr.T("admin.back"), "panel"),
 		),
 	)
 
-	ah.sendTextWithKeyboard(chatID, "📂 Категории (Admin)", rows)
+	ah.sendTextWithKeyboard(chatID, tr.T("admin.categories.title"), rows)
 }
 
 func (ah *AdminHandler) ShowWeeklyMenusAdmin(chatID int64) {
+	tr := ah.tr(chatID)
 	menus, err := ah.nutritionService.ListWeeklyMenus()
 	if err != nil {
-		ah.sendTextFunc(chatID, "❌ Ошибка при получении меню: "+err.Error())
+		ah.sendTextFunc(chatID, tr.T("admin.menus.error", err))
 		return
 	}
 
 	if len(menus) == 0 {
 		rows := [][]tgbotapi.InlineKeyboardButton{
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("➕ Создать меню", "admin_add_weekly_menu"),
+				ah.button(tr.T("admin.menus.add"), "add_weekly_menu"),
+				ah.button(tr.T("admin.menus.generate"), "generate_menu"),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад в админ-панель", "admin_panel"),
+				ah.button(tr.T("admin.back"), "panel"),
 			),
 		}
-		ah.sendTextWithKeyboard(chatID, "📭 Недельных меню пока нет", rows)
+		ah.sendTextWithKeyboard(chatID, tr.T("admin.menus.empty"), rows)
 		return
 	}
 
@@ -297,21 +357,16 @@ func (ah *AdminHandler) ShowWeeklyMenusAdmin(chatID int64) {
 	activeMenu, err := ah.nutritionService.GetActiveWeeklyMenu()
 	if err == nil && activeMenu != nil {
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("✅ АКТИВНО: %s", activeMenu.Name),
-				fmt.Sprintf("admin_view_weekly_menu_%d", activeMenu.ID),
-			),
+			ah.button(tr.T("admin.menus.active_item", activeMenu.Name), "view_menu", activeMenu.ID),
 		))
 
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📋 Подробнее",
-				fmt.Sprintf("admin_view_weekly_menu_%d", activeMenu.ID)),
-			tgbotapi.NewInlineKeyboardButtonData("➕ Добавить день",
-				fmt.Sprintf("admin_add_day_to_menu_%d", activeMenu.ID)),
+			ah.button(tr.T("admin.menus.details"), "view_menu", activeMenu.ID),
+			ah.button(tr.T("admin.menus.add_day"), "add_day", activeMenu.ID),
 		))
 
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("────────────", "noop"),
+			ah.button("────────────", "noop"),
 		))
 	}
 
@@ -328,24 +383,18 @@ func (ah *AdminHandler) ShowWeeklyMenusAdmin(chatID int64) {
 		}
 
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("%s %s (%d ккал)", status, menu.Name, menu.TotalCalories),
-				fmt.Sprintf("admin_view_weekly_menu_%d", menu.ID),
-			),
+			ah.button(tr.T("admin.menus.item", status, menu.Name, menu.TotalCalories), "view_menu", menu.ID),
 		))
 
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📋 Подробнее",
-				fmt.Sprintf("admin_view_weekly_menu_%d", menu.ID)),
-			tgbotapi.NewInlineKeyboardButtonData("✅ Активировать",
-				fmt.Sprintf("admin_activate_menu_%d", menu.ID)),
-			tgbotapi.NewInlineKeyboardButtonData("🗑 Удалить",
-				fmt.Sprintf("admin_delete_weekly_menu_%d", menu.ID)),
+			ah.button(tr.T("admin.menus.details"), "view_menu", menu.ID),
+			ah.button(tr.T("admin.menus.activate"), "activate_menu", menu.ID),
+			ah.button(tr.T("admin.delete"), "del_menu", menu.ID),
 		))
 
 		if menu.ID != menus[len(menus)-1].ID {
 			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("────────────", "noop"),
+				ah.button("────────────", "noop"),
 			))
 		}
 	}
@@ -353,568 +402,368 @@ func (ah *AdminHandler) ShowWeeklyMenusAdmin(chatID int64) {
 	// Управляющие кнопки
 	rows = append(rows,
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("➕ Создать меню", "admin_add_weekly_menu"),
+			ah.button(tr.T("admin.menus.add"), "add_weekly_menu"),
+			ah.button(tr.T("admin.menus.generate"), "generate_menu"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад в админ-панель", "admin_panel"),
+			ah.button(tr.T("admin.back"), "panel"),
 		),
 	)
 
-	ah.sendTextWithKeyboard(chatID, "📅 Недельные меню (Admin)", rows)
+	ah.sendTextWithKeyboard(chatID, tr.T("admin.menus.title"), rows)
 }
 
 func (ah *AdminHandler) ShowWeeklyMenuDetails(chatID int64, menuID uint) {
+	tr := ah.tr(chatID)
 	menu, err := ah.nutritionService.GetFullWeeklyMenu(menuID)
 	if err != nil {
-		ah.sendTextFunc(chatID, "❌ Ошибка при получении меню: "+err.Error())
+		ah.sendTextFunc(chatID, tr.T("admin.menus.error", err))
 		return
 	}
 
-	msg := fmt.Sprintf("📅 *%s*\n\n", menu.Name)
-	if menu.Description != "" {
-		msg += fmt.Sprintf("Описание: %s\n\n", menu.Description)
-	}
-
-	msg += fmt.Sprintf("🍽 Всего калорий за неделю: *%d ккал*\n", menu.TotalCalories)
-	msg += fmt.Sprintf("Статус: ")
-	if menu.Active {
-		msg += "✅ *АКТИВНО*\n\n"
-	} else {
-		msg += "🔘 Неактивно\n\n"
-	}
-
-	if len(menu.Days) == 0 {
-		msg += "📭 Дни не добавлены\n"
-	} else {
-		msg += "📋 *Дни недели:*\n\n"
-
-		daysMap := make(map[int]models.MenuDay)
-		for _, day := range menu.Days {
-			daysMap[day.DayNumber] = day
-		}
-
-		for dayNum := 1; dayNum <= 7; dayNum++ {
-			if day, exists := daysMap[dayNum]; exists {
-				msg += fmt.Sprintf("*%d. %s* - %d ккал\n",
-					day.DayNumber, day.DayName, day.TotalCalories)
-
-				if len(day.Meals) > 0 {
-					for _, meal := range day.Meals {
-						// Проверяем, загружена ли информация о питании
-						if meal.Nutrition.ID != 0 {
-							msg += fmt.Sprintf("   🕐 %s: %s - %s (%d ккал)\n",
-								meal.MealTime, meal.MealType,
-								meal.Nutrition.Title, meal.Nutrition.Calories)
-						} else {
-							// Если питание не загружено, показываем только ID
-							msg += fmt.Sprintf("   🕐 %s: %s (ID питания: %d)\n",
-								meal.MealTime, meal.MealType, meal.NutritionID)
-						}
-					}
-				} else {
-					msg += "   📭 Приемы пищи не добавлены\n"
-				}
-				msg += "\n"
-			}
-		}
-	}
+	// Дни по порядку недели
+	days := slices.Clone(menu.Days)
+	slices.SortFunc(days, func(a, b models.MenuDay) int { return a.DayNumber - b.DayNumber })
 
 	// Кнопки управления
+	publish := ah.button(tr.T("admin.menu.publish"), "publish_menu", menuID, 1)
+	if menu.Published {
+		publish = ah.button(tr.T("admin.menu.unpublish"), "publish_menu", menuID, 0)
+	}
 	rows := [][]tgbotapi.InlineKeyboardButton{
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("➕ Добавить день",
-				fmt.Sprintf("admin_add_day_to_menu_%d", menuID)),
+			ah.button(tr.T("admin.menus.add_day"), "add_day", menuID),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✅ Активировать",
-				fmt.Sprintf("admin_activate_menu_%d", menuID)),
-			tgbotapi.NewInlineKeyboardButtonData("🗑 Удалить",
-				fmt.Sprintf("admin_delete_weekly_menu_%d", menuID)),
+			ah.button(tr.T("admin.menus.activate"), "activate_menu", menuID),
+			ah.button(tr.T("admin.delete"), "del_menu", menuID),
 		),
+		tgbotapi.NewInlineKeyboardRow(publish),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад к меню", "admin_weekly_menus"),
+			ah.button(tr.T("admin.menu.assign"), "assign_menu", menuID),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.menu.back"), "weekly_menus"),
 		),
 	}
 
-	ah.sendTextWithKeyboard(chatID, msg, rows)
+	ah.sendView(chatID, "admin.menu", render.AdminMenu{Menu: menu, Days: days}, rows)
 }
 
 func (ah *AdminHandler) ShowNutritionListForSelection(chatID int64) {
+	tr := ah.tr(chatID)
 	nutritionList, err := ah.nutritionService.ListNutrition()
 	if err != nil {
-		ah.sendTextFunc(chatID, "❌ Не удалось загрузить список блюд")
+		ah.sendTextFunc(chatID, tr.T("admin.foodlist.error"))
 		return
 	}
 
 	if len(nutritionList) == 0 {
-		ah.sendTextFunc(chatID, "🍎 Блюд пока нет. Сначала добавьте блюда через админ-панель питания.")
+		ah.sendTextFunc(chatID, tr.T("admin.foodlist.empty"))
 		return
 	}
 
-	// Экранируем спецсимволы для Markdown
-	escape := func(s string) string {
-		specialChars := []string{"_", "*", "[", "]", "(", ")", "~", "`", ">", "#", "+", "-", "=", "|", "{", "}", ".", "!"}
-		for _, c := range specialChars {
-			s = strings.ReplaceAll(s, c, "\\"+c)
-		}
-		return s
-	}
-
-	msg := "🍎 *Список блюд (ID - Название - Калории):*\n\n"
-	for _, n := range nutritionList {
-		msg += fmt.Sprintf("*%d*\\. %s \\- %d ккал\n",
-			n.ID, escape(n.Title), n.Calories)
-	}
-
-	msg += "\nПри добавлении приема пищи введите ID блюда из этого списка\\."
-	ah.sendTextFunc(chatID, msg)
+	ah.sendView(chatID, "admin.foodlist", nutritionList, nil)
 }
-func (ah *AdminHandler) HandleAdminCallback(callback *tgbotapi.CallbackQuery) {
-	data := callback.Data
-	chatID := callback.Message.Chat.ID
-
-	if data == "admin_cancel" {
-		ah.Fsm.DeleteState(callback.From.ID)
-		ah.sendTextFunc(callback.Message.Chat.ID, "❌ Действие отменено")
-		ah.ShowAdminPanel(callback.Message.Chat.ID)
-		return
-	}
 
-	// 1. Сначала проверяем зарегистрированные callback
-	if callbackFn, ok := ah.adminCallbacks[data]; ok {
-		callbackFn(callback)
-		return
-	}
+// ==================== ОБРАБОТЧИКИ КНОПОК ====================
 
-	// 2. Обработка недельных меню (переносим все if-блоки)
-	if strings.HasPrefix(data, "admin_view_weekly_menu_") {
-		idStr := strings.TrimPrefix(data, "admin_view_weekly_menu_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID меню")
-			return
-		}
-		ah.ShowWeeklyMenuDetails(chatID, uint(id))
-		return
+func (ah *AdminHandler) activateWeeklyMenu(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	if err := ah.nutritionService.ActivateWeeklyMenu(c.Uint(0)); err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.menu.activate_error", err))
+	} else {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.menu.activated"))
 	}
+	ah.ShowWeeklyMenusAdmin(c.ChatID)
+}
 
-	if strings.HasPrefix(data, "admin_add_day_to_menu_") {
-		idStr := strings.TrimPrefix(data, "admin_add_day_to_menu_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID меню")
-			return
-		}
-
-		state := &AdminState{
-			Action:   "add_day_to_menu",
-			EntityID: uint(id),
-			Step:     1,
-			TempData: make(map[string]interface{}),
-		}
-		ah.Fsm.SetState(callback.From.ID, state)
-		ah.sendTextFunc(chatID, "Введите номер дня (1-7, где 1 - понедельник):")
+func (ah *AdminHandler) publishWeeklyMenu(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	id, published := c.Uint(0), c.Uint(1) == 1
+	if err := ah.nutritionService.SetWeeklyMenuPublished(id, published); err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.error", err))
 		return
 	}
-
-	if strings.HasPrefix(data, "admin_activate_menu_") {
-		idStr := strings.TrimPrefix(data, "admin_activate_menu_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID меню")
-			return
-		}
-
-		err = ah.nutritionService.ActivateWeeklyMenu(uint(id))
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка активации: "+err.Error())
-		} else {
-			ah.sendTextFunc(chatID, "✅ Меню активировано")
-		}
-		ah.ShowWeeklyMenusAdmin(chatID)
-		return
+	if published {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.menu.published_done"))
+	} else {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.menu.unpublished_done"))
 	}
+	ah.ShowWeeklyMenuDetails(c.ChatID, id)
+}
 
-	if strings.HasPrefix(data, "admin_delete_weekly_menu_") {
-		idStr := strings.TrimPrefix(data, "admin_delete_weekly_menu_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID")
-			return
-		}
-
-		rows := [][]tgbotapi.InlineKeyboardButton{
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData(
-					"✅ Да, удалить",
-					fmt.Sprintf("admin_confirm_delete_weekly_menu_%d", id),
-				),
-				tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "admin_weekly_menus"),
-			),
-		}
-
-		ah.sendTextWithKeyboard(
-			chatID,
-			fmt.Sprintf("⚠️ Вы уверены, что хотите удалить недельное меню #%d?", id),
-			rows,
-		)
-		return
+func (ah *AdminHandler) confirmDeleteWeeklyMenu(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	id := c.Uint(0)
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.confirm_delete"), "del_menu_ok", id),
+			ah.button(tr.T("admin.cancel"), "weekly_menus"),
+		),
 	}
 
-	if strings.HasPrefix(data, "admin_confirm_delete_weekly_menu_") {
-		idStr := strings.TrimPrefix(data, "admin_confirm_delete_weekly_menu_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID")
-			return
-		}
-
-		err = ah.nutritionService.DeleteWeeklyMenu(uint(id))
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при удалении: "+err.Error())
-		} else {
-			ah.sendTextFunc(chatID, "✅ Недельное меню удалено")
-		}
+	ah.sendTextWithKeyboard(
+		c.ChatID,
+		tr.T("admin.menu.delete_confirm", id),
+		rows,
+	)
+}
 
-		ah.ShowWeeklyMenusAdmin(chatID)
-		return
+func (ah *AdminHandler) deleteWeeklyMenu(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	if err := ah.nutritionService.DeleteWeeklyMenu(c.Uint(0)); err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.delete_error", err))
+	} else {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.menu.deleted"))
 	}
+	ah.ShowWeeklyMenusAdmin(c.ChatID)
+}
 
-	// 3. Обработка тренировок
-	if strings.HasPrefix(data, "admin_view_training_") {
-		idStr := strings.TrimPrefix(data, "admin_view_training_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID тренировки")
-			return
-		}
-
-		training, err := ah.trainingService.GetTrainingByID(uint(id))
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Тренировка не найдена")
-			return
-		}
-
-		msg := fmt.Sprintf("🏋️ *%s*\n\nДлительность: %d мин\nID: %d",
-			training.Title, training.Duration, training.ID)
-		ah.sendTextFunc(chatID, msg)
+func (ah *AdminHandler) viewTraining(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	training, err := ah.trainingService.GetTrainingByID(c.Uint(0))
+	if err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.training.not_found"))
 		return
 	}
 
-	// 4. Обрабатываем префиксы для редактирования/удаления тренировок
-	if strings.HasPrefix(data, "admin_edit_training_") || strings.HasPrefix(data, "admin_delete_training_") {
-		parts := strings.Split(data, "_")
-		if len(parts) < 4 {
-			ah.sendTextFunc(chatID, "❌ Неверный формат команды")
-			return
-		}
-
-		idStr := parts[3]
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID тренировки")
-			return
-		}
+	msg := tr.T("admin.training.card",
+		training.Title, training.Duration, training.Weeks, training.ID)
+	ah.sendTextFunc(c.ChatID, msg)
+}
 
-		if strings.HasPrefix(data, "admin_edit_training_") {
-			state := &AdminState{
-				Action:   "edit_training",
-				EntityID: uint(id),
-				Step:     1,
-				TempData: make(map[string]interface{}),
-			}
-			ah.Fsm.SetState(callback.From.ID, state)
-			ah.sendTextFunc(chatID, "✏️ Введите новое название тренировки:")
-		} else {
-			rows := [][]tgbotapi.InlineKeyboardButton{
-				{
-					tgbotapi.NewInlineKeyboardButtonData("✅ Да, удалить",
-						fmt.Sprintf("admin_confirm_delete_training_%d", id)),
-					tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "admin_trainings"),
-				},
-			}
-			ah.sendTextWithKeyboard(chatID,
-				fmt.Sprintf("⚠️ Вы уверены, что хотите удалить тренировку #%d?", id),
-				rows)
-		}
-		return
+func (ah *AdminHandler) confirmDeleteTraining(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	id := c.Uint(0)
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		{
+			ah.button(tr.T("admin.confirm_delete"), "del_training_ok", id),
+			ah.button(tr.T("admin.cancel"), "trainings"),
+		},
 	}
+	ah.sendTextWithKeyboard(c.ChatID,
+		tr.T("admin.training.delete_confirm", id),
+		rows)
+}
 
-	// 5. Обрабатываем подтверждение удаления тренировки
-	if strings.HasPrefix(data, "admin_confirm_delete_training_") {
-		idStr := strings.TrimPrefix(data, "admin_confirm_delete_training_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID тренировки")
-			return
-		}
-
-		err = ah.trainingService.DeleteTraining(uint(id))
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при удалении тренировки: "+err.Error())
-		} else {
-			ah.sendTextFunc(chatID, "✅ Тренировка удалена")
-		}
-		ah.ShowTrainingsAdmin(chatID)
-		return
+func (ah *AdminHandler) deleteTraining(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	if err := ah.trainingService.DeleteTraining(c.Uint(0)); err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.training.delete_error", err))
+	} else {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.training.deleted"))
 	}
+	ah.ShowTrainingsAdmin(c.ChatID)
+}
 
-	// 6. Обработка питания
-	if strings.HasPrefix(data, "admin_edit_nutrition_") {
-		idStr := strings.TrimPrefix(data, "admin_edit_nutrition_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID")
-			return
-		}
-
-		nutrition, err := ah.nutritionService.GetNutritionByID(uint(id))
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Запись о питании не найдена")
-			return
-		}
-
-		ah.Fsm.SetState(callback.From.ID, &AdminState{
-			Action:   "edit_nutrition",
-			EntityID: uint(id),
-			Step:     1,
-			TempData: make(map[string]interface{}),
-		})
-
-		msg := fmt.Sprintf("✏️ Редактирование: %s\n\nТекущее название: %s\nВведите новое название:",
-			nutrition.Title, nutrition.Title)
-		ah.sendTextFunc(chatID, msg)
-		return
-
-	} else if strings.HasPrefix(data, "admin_edit_category_") {
-		idStr := strings.TrimPrefix(data, "admin_edit_category_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID")
-			return
-		}
-
-		category, err := ah.categoryService.GetCategoryByID(uint(id))
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Категория не найдена")
-			return
-		}
-
-		state := &AdminState{
-			Action:   "edit_category",
-			EntityID: uint(id),
-			Step:     1,
-			TempData: make(map[string]interface{}),
-		}
-		ah.Fsm.SetState(callback.From.ID, state)
-
-		msg := fmt.Sprintf("✏️ Редактирование: %s\n\nТекущее название: %s\nВведите новое название:",
-			category.Name, category.Name)
-		ah.sendTextFunc(chatID, msg)
+func (ah *AdminHandler) viewNutrition(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	n, err := ah.nutritionService.GetNutritionByID(c.Uint(0))
+	if err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.nutrition.not_found_short"))
 		return
 	}
 
-	if strings.HasPrefix(data, "admin_view_nutrition_") {
-		idStr := strings.TrimPrefix(data, "admin_view_nutrition_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID")
-			return
-		}
+	msg := tr.T(
+		"admin.nutrition.card",
+		n.Title,
+		n.Description,
+		n.Calories,
+		n.Protein,
+		n.Carbs,
+		n.Fats,
+		n.CategoryID,
+		formatTags(tr, service.DietTags, n.Diets),
+		formatTags(tr, service.AllergenTags, n.Allergens),
+		n.ID,
+	)
 
-		n, err := ah.nutritionService.GetNutritionByID(uint(id))
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Запись не найдена")
-			return
-		}
+	ah.sendTextFunc(c.ChatID, msg)
+}
 
-		msg := fmt.Sprintf(
-			"🍎 *%s*\n\n"+
-				"Описание: %s\n"+
-				"Калории: %d ккал\n"+
-				"Белки: %.1f г\n"+
-				"Углеводы: %.1f г\n"+
-				"Жиры: %.1f г\n"+
-				"ID категории: %d\n\n"+
-				"ID: %d",
-			n.Title,
-			n.Description,
-			n.Calories,
-			n.Protein,
-			n.Carbs,
-			n.Fats,
-			n.CategoryID,
-			n.ID,
-		)
-
-		ah.sendTextFunc(chatID, msg)
+func (ah *AdminHandler) editNutrition(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	id := c.Uint(0)
+	nutrition, err := ah.nutritionService.GetNutritionByID(id)
+	if err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.nutrition.not_found"))
 		return
 	}
 
-	if strings.HasPrefix(data, "admin_view_category_") {
-		idStr := strings.TrimPrefix(data, "admin_view_category_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID")
-			return
-		}
+	ah.Fsm.SetState(c.From.ID, &AdminState{
+		Action:   "edit_nutrition",
+		EntityID: id,
+		Step:     1,
+		TempData: make(fsm.TempData),
+	})
 
-		c, err := ah.categoryService.GetCategoryByID(uint(id))
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Категория не найдена")
-			return
-		}
+	msg := tr.T("admin.edit_title",
+		nutrition.Title, nutrition.Title)
+	ah.sendTextFunc(c.ChatID, msg)
+}
 
-		msg := fmt.Sprintf(
-			"📂 *%s*\n\n"+
-				"Описание: %s\n"+
-				"Тип: %s\n"+
-				"ID: %d",
-			c.Name,
-			c.Description,
-			c.Type,
-			c.ID,
-		)
-
-		ah.sendTextFunc(chatID, msg)
-		return
+func (ah *AdminHandler) confirmDeleteNutrition(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	id := c.Uint(0)
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.confirm_delete"), "del_nutrition_ok", id),
+			ah.button(tr.T("admin.cancel"), "nutrition"),
+		),
 	}
 
-	if strings.HasPrefix(data, "admin_delete_nutrition_") {
-		idStr := strings.TrimPrefix(data, "admin_delete_nutrition_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID")
-			return
-		}
+	ah.sendTextWithKeyboard(
+		c.ChatID,
+		tr.T("admin.nutrition.delete_confirm", id),
+		rows,
+	)
+}
 
-		rows := [][]tgbotapi.InlineKeyboardButton{
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData(
-					"✅ Да, удалить",
-					fmt.Sprintf("admin_confirm_delete_nutrition_%d", id),
-				),
-				tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "admin_nutrition"),
-			),
-		}
+func (ah *AdminHandler) deleteNutrition(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	if err := ah.nutritionService.DeleteNutrition(c.Uint(0)); err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.delete_error", err))
+	} else {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.nutrition.deleted"))
+	}
+	ah.ShowNutritionAdmin(c.ChatID)
+}
 
-		ah.sendTextWithKeyboard(
-			chatID,
-			fmt.Sprintf("⚠️ Вы уверены, что хотите удалить запись о питании #%d?", id),
-			rows,
-		)
+func (ah *AdminHandler) viewCategory(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	category, err := ah.categoryService.GetCategoryByID(c.Uint(0))
+	if err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.category.not_found"))
 		return
 	}
 
-	if strings.HasPrefix(data, "admin_confirm_delete_nutrition_") {
-		idStr := strings.TrimPrefix(data, "admin_confirm_delete_nutrition_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID")
-			return
-		}
+	msg := tr.T(
+		"admin.category.card",
+		category.Name,
+		category.Description,
+		category.Type,
+		category.ID,
+	)
 
-		err = ah.nutritionService.DeleteNutrition(uint(id))
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при удалении: "+err.Error())
-		} else {
-			ah.sendTextFunc(chatID, "✅ Запись о питании удалена")
-		}
+	ah.sendTextFunc(c.ChatID, msg)
+}
 
-		ah.ShowNutritionAdmin(chatID)
+func (ah *AdminHandler) editCategory(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	id := c.Uint(0)
+	category, err := ah.categoryService.GetCategoryByID(id)
+	if err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.category.not_found"))
 		return
 	}
 
-	if strings.HasPrefix(data, "admin_delete_category_") {
-		idStr := strings.TrimPrefix(data, "admin_delete_category_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID")
-			return
-		}
+	ah.Fsm.SetState(c.From.ID, &AdminState{
+		Action:   "edit_category",
+		EntityID: id,
+		Step:     1,
+		TempData: make(fsm.TempData),
+	})
 
-		rows := [][]tgbotapi.InlineKeyboardButton{
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData(
-					"✅ Да, удалить",
-					fmt.Sprintf("admin_confirm_delete_category_%d", id),
-				),
-				tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "admin_categories"),
-			),
-		}
+	msg := tr.T("admin.edit_title",
+		category.Name, category.Name)
+	ah.sendTextFunc(c.ChatID, msg)
+}
 
-		ah.sendTextWithKeyboard(
-			chatID,
-			fmt.Sprintf("⚠️ Вы уверены, что хотите удалить категорию #%d?", id),
-			rows,
-		)
-		return
+func (ah *AdminHandler) confirmDeleteCategory(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	id := c.Uint(0)
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.confirm_delete"), "del_category_ok", id),
+			ah.button(tr.T("admin.cancel"), "categories"),
+		),
 	}
 
-	if strings.HasPrefix(data, "admin_confirm_delete_category_") {
-		idStr := strings.TrimPrefix(data, "admin_confirm_delete_category_")
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Неверный ID")
-			return
-		}
-
-		err = ah.categoryService.DeleteCategory(uint(id))
-		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при удалении: "+err.Error())
-		} else {
-			ah.sendTextFunc(chatID, "✅ Категория удалена")
-		}
+	ah.sendTextWithKeyboard(
+		c.ChatID,
+		tr.T("admin.category.delete_confirm", id),
+		rows,
+	)
+}
 
-		ah.ShowCategoriesAdmin(chatID)
-		return
+func (ah *AdminHandler) deleteCategory(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	if err := ah.categoryService.DeleteCategory(c.Uint(0)); err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.delete_error", err))
+	} else {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.category.deleted"))
 	}
-
-	// Если команда не распознана
-	ah.sendTextFunc(chatID, "⚠️ Неизвестная команда")
+	ah.ShowCategoriesAdmin(c.ChatID)
 }
+
 func (ah *AdminHandler) StartAddTrainingFlow(chatID int64, userID int64) {
+	tr := ah.tr(chatID)
 	ah.Fsm.SetState(userID, &AdminState{
 		Action:   "add_training",
 		Step:     1,
-		TempData: make(map[string]interface{}),
+		TempData: make(fsm.TempData),
 	})
-	ah.sendTextFunc(chatID, "Введите название тренировки:")
+	ah.sendTextFunc(chatID, tr.T("admin.training.title_prompt"))
 }
 
 func (ah *AdminHandler) StartAddNutritionFlow(chatID int64, userID int64) {
+	tr := ah.tr(chatID)
 	ah.Fsm.SetState(userID, &AdminState{
 		Action:   "add_nutrition",
 		Step:     1,
-		TempData: make(map[string]interface{}),
+		TempData: make(fsm.TempData),
 	})
-	ah.sendTextFunc(chatID, "Введите название блюда/продукта:")
+	ah.sendTextFunc(chatID, tr.T("admin.nutrition.title_prompt"))
 }
 
 func (ah *AdminHandler) StartAddCategoryFlow(chatID int64, userID int64) {
+	tr := ah.tr(chatID)
 	ah.Fsm.SetState(userID, &AdminState{
 		Action:   "add_category",
 		Step:     1,
-		TempData: make(map[string]interface{}),
+		TempData: make(fsm.TempData),
+	})
+	ah.sendTextFunc(chatID, tr.T("admin.category.title_prompt"))
+}
+
+// StartAssignMenuFlow - назначение меню пользователю по Telegram ID
+func (ah *AdminHandler) StartAssignMenuFlow(chatID int64, userID int64, menuID uint) {
+	tr := ah.tr(chatID)
+	ah.Fsm.SetState(userID, &AdminState{
+		Action:   "assign_menu",
+		EntityID: menuID,
+		Step:     1,
+		TempData: make(fsm.TempData),
 	})
-	ah.sendTextFunc(chatID, "Введите название категории:")
+	ah.sendTextFunc(chatID, tr.T("admin.assign.prompt", menuID))
 }
 
 func (ah *AdminHandler) StartAddWeeklyMenuFlow(chatID int64, userID int64) {
+	tr := ah.tr(chatID)
 	ah.Fsm.SetState(userID, &AdminState{
 		Action:   "add_weekly_menu",
 		Step:     1,
-		TempData: make(map[string]interface{}),
+		TempData: make(fsm.TempData),
 	})
-	ah.sendTextFunc(chatID, "Введите название недельного меню:")
+	ah.sendTextFunc(chatID, tr.T("admin.menu.title_prompt"))
 }
 func NewAdminHandler(
 	trainingService *service.TrainingService,
 	nutritionService *service.NutritionService,
 	categoryService *service.CategoryService,
 	userService *service.UserService,
+	catalogService *service.CatalogService,
+	menuService *service.MenuAssignmentService,
+	generatorService *service.MenuGeneratorService,
+	programService *service.ProgramService,
+	adminFSM *AdminFSM,
+	callbacks *callback.Router,
+	files FileTransfer,
 	sendText func(int64, string),
 	sendTextWithKeyboard func(int64, string, [][]tgbotapi.InlineKeyboardButton),
+	sendView func(int64, string, any, [][]tgbotapi.InlineKeyboardButton),
+	tr func(int64) *i18n.Localizer,
 ) *AdminHandler {
 	// Исправленная версия:
 	handler := &AdminHandler{
@@ -922,10 +771,17 @@ func NewAdminHandler(
 		nutritionService:     nutritionService,
 		categoryService:      categoryService,
 		userService:          userService,
-		Fsm:                  NewAdminFSM(),
+		catalogService:       catalogService,
+		menuService:          menuService,
+		generatorService:     generatorService,
+		programService:       programService,
+		Fsm:                  adminFSM,
 		sendTextFunc:         sendText,
 		sendTextWithKeyboard: sendTextWithKeyboard,
-		adminCallbacks:       make(map[string]func(*tgbotapi.CallbackQuery)),
+		sendView:             sendView,
+		callbacks:            callbacks,
+		files:                files,
+		tr:                   tr,
 	}
 
 	handler.RegisterAdminCallbacks()
@@ -936,43 +792,47 @@ func NewAdminHandler(
 // ==================== МЕТОДЫ ДЛЯ СТАРТА ПОТОКОВ ====================
 
 func (h *AdminHandler) StartEditCategoryFlow(chatID, userID int64, categoryID uint) {
+	tr := h.tr(chatID)
 	h.Fsm.SetState(userID, &AdminState{
 		Action:   "edit_category",
 		EntityID: categoryID,
 		Step:     1,
-		TempData: make(map[string]interface{}),
+		TempData: make(fsm.TempData),
 	})
-	h.sendTextFunc(chatID, fmt.Sprintf("✏️ Редактирование категории #%d\nВведите новое название:", categoryID))
+	h.sendTextFunc(chatID, tr.T("admin.category.edit_prompt", categoryID))
 }
 
 func (h *AdminHandler) StartEditNutritionFlow(chatID, userID int64, nutritionID uint) {
+	tr := h.tr(chatID)
 	h.Fsm.SetState(userID, &AdminState{
 		Action:   "edit_nutrition",
 		EntityID: nutritionID,
 		Step:     1,
-		TempData: make(map[string]interface{}),
+		TempData: make(fsm.TempData),
 	})
-	h.sendTextFunc(chatID, fmt.Sprintf("✏️ Редактирование питания #%d\nВведите новое название:", nutritionID))
+	h.sendTextFunc(chatID, tr.T("admin.nutrition.edit_prompt", nutritionID))
 }
 
 func (h *AdminHandler) StartEditTrainingFlow(chatID, userID int64, trainingID uint) {
+	tr := h.tr(chatID)
 	h.Fsm.SetState(userID, &AdminState{
 		Action:   "edit_training",
 		EntityID: trainingID,
 		Step:     1,
-		TempData: make(map[string]interface{}),
+		TempData: make(fsm.TempData),
 	})
-	h.sendTextFunc(chatID, fmt.Sprintf("✏️ Редактирование тренировки #%d\nВведите новое название:", trainingID))
+	h.sendTextFunc(chatID, tr.T("admin.training.edit_prompt", trainingID))
 }
 
 func (h *AdminHandler) StartAddDayToMenuFlow(chatID, userID int64, menuID uint) {
+	tr := h.tr(chatID)
 	h.Fsm.SetState(userID, &AdminState{
 		Action:   "add_day_to_menu",
 		EntityID: menuID,
 		Step:     1,
-		TempData: make(map[string]interface{}),
+		TempData: make(fsm.TempData),
 	})
-	h.sendTextFunc(chatID, "Введите номер дня (1-7, где 1 - понедельник):")
+	h.sendTextFunc(chatID, tr.T("admin.day.prompt"))
 }
 
 // ==================== БАЗОВЫЕ МЕТОДЫ ====================
@@ -990,12 +850,13 @@ func (h *AdminHandler) DeleteState(userID int64) {
 }
 
 func (ah *AdminHandler) HandleAdminActions(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
 	log.Println("ADMIN FSM:", state.Action, "STEP:", state.Step, "TEXT:", text)
 
 	// Проверяем команду отмены
 	if text == "/cancel" || text == "отмена" || text == "cancel" {
 		ah.Fsm.DeleteState(userID)
-		ah.sendTextFunc(chatID, "❌ Действие отменено")
+		ah.sendTextFunc(chatID, tr.T("admin.cancelled"))
 		ah.ShowAdminPanel(chatID)
 		return
 	}
@@ -1026,94 +887,122 @@ func (ah *AdminHandler) HandleAdminActions(chatID, userID int64, state *AdminSta
 		ah.handleAddDayToMenu(chatID, userID, state, text)
 	case "add_meal_to_day":
 		ah.handleAddMealToDay(chatID, userID, state, text)
+	case "assign_menu":
+		ah.handleAssignMenu(chatID, userID, state, text)
+	case "generate_menu":
+		ah.handleGenerateMenu(chatID, userID, state, text)
+
+	// ==================== Программы тренировок ====================
+	case "add_exercise":
+		ah.handleAddExercise(chatID, userID, state, text)
+	case "program_weeks":
+		ah.handleProgramWeeks(chatID, userID, state, text)
+	case "add_program_day":
+		ah.handleAddProgramDay(chatID, userID, state, text)
+	case "add_program_exercise":
+		ah.handleAddProgramExercise(chatID, userID, state, text)
+
+	// ==================== Импорт каталога ====================
+	case "import":
+		if state.Step == 1 {
+			ah.sendTextFunc(chatID, tr.T("admin.import.send_file"))
+		} else {
+			ah.sendTextFunc(chatID, tr.T("admin.import.confirm_hint"))
+		}
 
 	default:
-		ah.sendTextFunc(chatID, "⚠️ Неизвестное действие")
+		ah.sendTextFunc(chatID, tr.T("admin.unknown_action"))
 		ah.Fsm.DeleteState(userID)
+		return
+	}
+
+	// Обработчики меняют state на месте. Если мастер не завершен,
+	// сохраняем прогресс, чтобы он пережил рестарт бота
+	if _, inProgress := ah.Fsm.GetState(userID); inProgress {
+		ah.Fsm.SetState(userID, state)
 	}
 }
 
 // ==================== ТРЕНИРОВКИ ====================
 
 func (ah *AdminHandler) handleAddTraining(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
 	if state.Step == 1 {
-		state.TempData["title"] = text
+		state.TempData.Set("title", text)
 		state.Step = 2
-		ah.sendTextFunc(chatID, "Введите длительность (минуты):")
+		ah.sendTextFunc(chatID, tr.T("admin.training.duration_prompt"))
 	} else if state.Step == 2 {
 		dur, err := strconv.Atoi(text)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Пожалуйста, введите число!")
+			ah.sendTextFunc(chatID, tr.T("admin.number_invalid"))
 			return
 		}
-		state.TempData["duration"] = dur
+		state.TempData.Set("duration", dur)
 		state.Step = 3
-		ah.sendTextFunc(chatID, "Введите ссылку на YouTube (или оставьте пустым):")
+		ah.sendTextFunc(chatID, tr.T("admin.training.video_prompt"))
 	} else if state.Step == 3 {
-		state.TempData["youtube_link"] = text
+		state.TempData.Set("youtube_link", text)
 		state.Step = 4
-		ah.sendTextFunc(chatID, "Введите описание тренировки (или оставьте пустым):")
+		ah.sendTextFunc(chatID, tr.T("admin.training.description_prompt"))
 	} else if state.Step == 4 {
-		state.TempData["description"] = text
+		state.TempData.Set("description", text)
 
-		categoryID := state.TempData["category_id"]
 		var catIDPtr *uint
-		if categoryID != nil {
-			if catID, ok := categoryID.(uint); ok && catID > 0 {
-				catIDPtr = &catID
-			}
+		if catID := state.TempData.Uint("category_id"); catID > 0 {
+			catIDPtr = &catID
 		}
 
 		_, err := ah.trainingService.CreateTraining(service.CreateTrainingDTO{
-			Title:       state.TempData["title"].(string),
-			Duration:    state.TempData["duration"].(int),
-			YouTubeLink: state.TempData["youtube_link"].(string),
-			Description: state.TempData["description"].(string),
+			Title:       state.TempData.String("title"),
+			Duration:    state.TempData.Int("duration"),
+			YouTubeLink: state.TempData.String("youtube_link"),
+			Description: state.TempData.String("description"),
 			CategoryID:  catIDPtr,
 		})
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при создании тренировки: "+err.Error())
+			ah.sendTextFunc(chatID, tr.T("admin.training.create_error", err))
 			return
 		}
 
-		ah.sendTextFunc(chatID, "✅ Тренировка создана")
+		ah.sendTextFunc(chatID, tr.T("admin.training.created"))
 		ah.Fsm.DeleteState(userID)
 		ah.ShowTrainingsAdmin(chatID)
 	}
 }
 
 func (ah *AdminHandler) handleEditTraining(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
 	if state.Step == 1 {
-		state.TempData["title"] = text
+		state.TempData.Set("title", text)
 		state.Step = 2
-		ah.sendTextFunc(chatID, "Введите новую длительность (минуты):")
+		ah.sendTextFunc(chatID, tr.T("admin.training.new_duration"))
 	} else if state.Step == 2 {
 		dur, err := strconv.Atoi(text)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Пожалуйста, введите число!")
+			ah.sendTextFunc(chatID, tr.T("admin.number_invalid"))
 			return
 		}
-		state.TempData["duration"] = dur
+		state.TempData.Set("duration", dur)
 		state.Step = 3
-		ah.sendTextFunc(chatID, "Введите новую ссылку на YouTube (или оставьте пустым):")
+		ah.sendTextFunc(chatID, tr.T("admin.training.new_video"))
 	} else if state.Step == 3 {
-		state.TempData["youtube_link"] = text
+		state.TempData.Set("youtube_link", text)
 		state.Step = 4
-		ah.sendTextFunc(chatID, "Введите новое описание (или оставьте пустым):")
+		ah.sendTextFunc(chatID, tr.T("admin.new_description_optional"))
 	} else if state.Step == 4 {
-		state.TempData["description"] = text
+		state.TempData.Set("description", text)
 
 		err := ah.trainingService.UpdateTraining(state.EntityID, service.UpdateTrainingDTO{
-			Title:       state.TempData["title"].(string),
-			Duration:    state.TempData["duration"].(int),
-			YouTubeLink: state.TempData["youtube_link"].(string),
-			Description: state.TempData["description"].(string),
+			Title:       state.TempData.String("title"),
+			Duration:    state.TempData.Int("duration"),
+			YouTubeLink: state.TempData.String("youtube_link"),
+			Description: state.TempData.String("description"),
 		})
 
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при обновлении тренировки: "+err.Error())
+			ah.sendTextFunc(chatID, tr.T("admin.training.update_error", err))
 		} else {
-			ah.sendTextFunc(chatID, "✅ Тренировка обновлена")
+			ah.sendTextFunc(chatID, tr.T("admin.training.updated"))
 		}
 		ah.Fsm.DeleteState(userID)
 		ah.ShowTrainingsAdmin(chatID)
@@ -1123,73 +1012,95 @@ func (ah *AdminHandler) handleEditTraining(chatID, userID int64, state *AdminSta
 // ==================== ПИТАНИЕ ====================
 
 func (ah *AdminHandler) handleAddNutrition(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
 	switch state.Step {
 	case 1:
-		state.TempData["title"] = text
+		state.TempData.Set("title", text)
 		state.Step = 2
-		ah.sendTextFunc(chatID, "Введите описание:")
+		ah.sendTextFunc(chatID, tr.T("admin.description_prompt"))
 	case 2:
-		state.TempData["description"] = text
+		state.TempData.Set("description", text)
 		state.Step = 3
-		ah.sendTextFunc(chatID, "Введите калорийность (ккал):")
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.calories_prompt"))
 	case 3:
 		calories, err := strconv.Atoi(text)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Введите число для калорийности!")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.calories_invalid"))
 			return
 		}
-		state.TempData["calories"] = calories
+		state.TempData.Set("calories", calories)
 		state.Step = 4
-		ah.sendTextFunc(chatID, "Введите белки (г):")
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.protein_prompt"))
 	case 4:
 		protein, err := strconv.ParseFloat(text, 64)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Введите число для белков!")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.protein_invalid"))
 			return
 		}
-		state.TempData["protein"] = protein
+		state.TempData.Set("protein", protein)
 		state.Step = 5
-		ah.sendTextFunc(chatID, "Введите углеводы (г):")
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.carbs_prompt"))
 	case 5:
 		carbs, err := strconv.ParseFloat(text, 64)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Введите число для углеводов!")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.carbs_invalid"))
 			return
 		}
-		state.TempData["carbs"] = carbs
+		state.TempData.Set("carbs", carbs)
 		state.Step = 6
-		ah.sendTextFunc(chatID, "Введите жиры (г):")
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.fats_prompt"))
 	case 6:
 		fats, err := strconv.ParseFloat(text, 64)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Введите число для жиров!")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.fats_invalid"))
 			return
 		}
-		state.TempData["fats"] = fats
+		state.TempData.Set("fats", fats)
 		state.Step = 7
-		ah.sendTextFunc(chatID, "Введите ID категории (или 0 если нет):")
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.category_prompt"))
 	case 7:
 		categoryID, err := strconv.Atoi(text)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Введите число для ID категории!")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.category_invalid"))
+			return
+		}
+		state.TempData.Set("category_id", uint(categoryID))
+		state.Step = 8
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.diets_prompt")+
+			tagListPrompt(tr, service.DietTags, 1, ""))
+	case 8:
+		diets, err := parseTagNumbers(tr, text, service.DietTags, 1)
+		if err != nil {
+			ah.sendTextFunc(chatID, "❌ "+tr.Error(err))
+			return
+		}
+		state.TempData.Set("diets", diets)
+		state.Step = 9
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.allergens_prompt")+
+			tagListPrompt(tr, service.AllergenTags, 1, ""))
+	case 9:
+		allergens, err := parseTagNumbers(tr, text, service.AllergenTags, 1)
+		if err != nil {
+			ah.sendTextFunc(chatID, "❌ "+tr.Error(err))
 			return
 		}
-		state.TempData["category_id"] = uint(categoryID)
 
 		_, err = ah.nutritionService.CreateNutrition(service.CreateNutritionDTO{
-			Title:       state.TempData["title"].(string),
-			Description: state.TempData["description"].(string),
-			Calories:    state.TempData["calories"].(int),
-			Protein:     state.TempData["protein"].(float64),
-			Carbs:       state.TempData["carbs"].(float64),
-			Fats:        state.TempData["fats"].(float64),
-			CategoryID:  state.TempData["category_id"].(uint),
+			Title:       state.TempData.String("title"),
+			Description: state.TempData.String("description"),
+			Calories:    state.TempData.Int("calories"),
+			Protein:     state.TempData.Float("protein"),
+			Carbs:       state.TempData.Float("carbs"),
+			Fats:        state.TempData.Float("fats"),
+			CategoryID:  state.TempData.Uint("category_id"),
+			Diets:       state.TempData.Int("diets"),
+			Allergens:   allergens,
 		})
 
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при создании питания: "+err.Error())
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.create_error", err))
 		} else {
-			ah.sendTextFunc(chatID, "✅ Запись о питании создана")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.created"))
 		}
 		ah.Fsm.DeleteState(userID)
 		ah.ShowNutritionAdmin(chatID)
@@ -1197,73 +1108,103 @@ func (ah *AdminHandler) handleAddNutrition(chatID, userID int64, state *AdminSta
 }
 
 func (ah *AdminHandler) handleEditNutrition(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
 	switch state.Step {
 	case 1:
-		state.TempData["title"] = text
+		state.TempData.Set("title", text)
 		state.Step = 2
-		ah.sendTextFunc(chatID, "Введите новое описание:")
+		ah.sendTextFunc(chatID, tr.T("admin.new_description"))
 	case 2:
-		state.TempData["description"] = text
+		state.TempData.Set("description", text)
 		state.Step = 3
-		ah.sendTextFunc(chatID, "Введите новую калорийность (ккал):")
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.new_calories"))
 	case 3:
 		calories, err := strconv.Atoi(text)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Введите число для калорийности!")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.calories_invalid"))
 			return
 		}
-		state.TempData["calories"] = calories
+		state.TempData.Set("calories", calories)
 		state.Step = 4
-		ah.sendTextFunc(chatID, "Введите новые белки (г):")
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.new_protein"))
 	case 4:
 		protein, err := strconv.ParseFloat(text, 64)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Введите число для белков!")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.protein_invalid"))
 			return
 		}
-		state.TempData["protein"] = protein
+		state.TempData.Set("protein", protein)
 		state.Step = 5
-		ah.sendTextFunc(chatID, "Введите новые углеводы (г):")
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.new_carbs"))
 	case 5:
 		carbs, err := strconv.ParseFloat(text, 64)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Введите число для углеводов!")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.carbs_invalid"))
 			return
 		}
-		state.TempData["carbs"] = carbs
+		state.TempData.Set("carbs", carbs)
 		state.Step = 6
-		ah.sendTextFunc(chatID, "Введите новые жиры (г):")
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.new_fats"))
 	case 6:
 		fats, err := strconv.ParseFloat(text, 64)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Введите число для жиров!")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.fats_invalid"))
 			return
 		}
-		state.TempData["fats"] = fats
+		state.TempData.Set("fats", fats)
 		state.Step = 7
-		ah.sendTextFunc(chatID, "Введите новый ID категории (или 0 если нет):")
+		ah.sendTextFunc(chatID, tr.T("admin.nutrition.new_category"))
 	case 7:
 		categoryID, err := strconv.Atoi(text)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Введите число для ID категории!")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.category_invalid"))
+			return
+		}
+		state.TempData.Set("category_id", uint(categoryID))
+		state.Step = 8
+		current := ""
+		if n, err := ah.nutritionService.GetNutritionByID(state.EntityID); err == nil {
+			current = tr.T("admin.current") + formatTags(tr, service.DietTags, n.Diets) + "\n"
+		}
+		ah.sendTextFunc(chatID, current+tr.T("admin.nutrition.diets_prompt")+
+			tagListPrompt(tr, service.DietTags, 1, ""))
+	case 8:
+		diets, err := parseTagNumbers(tr, text, service.DietTags, 1)
+		if err != nil {
+			ah.sendTextFunc(chatID, "❌ "+tr.Error(err))
+			return
+		}
+		state.TempData.Set("diets", diets)
+		state.Step = 9
+		current := ""
+		if n, err := ah.nutritionService.GetNutritionByID(state.EntityID); err == nil {
+			current = tr.T("admin.current") + formatTags(tr, service.AllergenTags, n.Allergens) + "\n"
+		}
+		ah.sendTextFunc(chatID, current+tr.T("admin.nutrition.allergens_prompt")+
+			tagListPrompt(tr, service.AllergenTags, 1, ""))
+	case 9:
+		allergens, err := parseTagNumbers(tr, text, service.AllergenTags, 1)
+		if err != nil {
+			ah.sendTextFunc(chatID, "❌ "+tr.Error(err))
 			return
 		}
-		state.TempData["category_id"] = uint(categoryID)
 
 		err = ah.nutritionService.UpdateNutrition(state.EntityID, service.UpdateNutritionDTO{
-			Title:       state.TempData["title"].(string),
-			Description: state.TempData["description"].(string),
-			Calories:    state.TempData["calories"].(int),
-			Protein:     state.TempData["protein"].(float64),
-			Carbs:       state.TempData["carbs"].(float64),
-			Fats:        state.TempData["fats"].(float64),
-			CategoryID:  state.TempData["category_id"].(uint),
+			Title:       state.TempData.String("title"),
+			Description: state.TempData.String("description"),
+			Calories:    state.TempData.Int("calories"),
+			Protein:     state.TempData.Float("protein"),
+			Carbs:       state.TempData.Float("carbs"),
+			Fats:        state.TempData.Float("fats"),
+			CategoryID:  state.TempData.Uint("category_id"),
+			Diets:       state.TempData.Int("diets"),
+			Allergens:   allergens,
 		})
 
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при обновлении питания: "+err.Error())
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.update_error", err))
 		} else {
-			ah.sendTextFunc(chatID, "✅ Запись о питании обновлена")
+			ah.sendTextFunc(chatID, tr.T("admin.nutrition.updated"))
 		}
 		ah.Fsm.DeleteState(userID)
 		ah.ShowNutritionAdmin(chatID)
@@ -1273,27 +1214,28 @@ func (ah *AdminHandler) handleEditNutrition(chatID, userID int64, state *AdminSt
 // ==================== КАТЕГОРИИ ====================
 
 func (ah *AdminHandler) handleAddCategory(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
 	switch state.Step {
 	case 1:
-		state.TempData["name"] = text
+		state.TempData.Set("name", text)
 		state.Step = 2
-		ah.sendTextFunc(chatID, "Введите описание категории:")
+		ah.sendTextFunc(chatID, tr.T("admin.category.description_prompt"))
 	case 2:
-		state.TempData["description"] = text
+		state.TempData.Set("description", text)
 		state.Step = 3
-		ah.sendTextFunc(chatID, "Введите тип (training/nutrition/general):")
+		ah.sendTextFunc(chatID, tr.T("admin.category.type_prompt"))
 	case 3:
-		state.TempData["type"] = text
+		state.TempData.Set("type", text)
 
 		_, err := ah.categoryService.CreateCategory(service.CreateCategoryDTO{
-			Name:        state.TempData["name"].(string),
-			Description: state.TempData["description"].(string),
-			Type:        state.TempData["type"].(string),
+			Name:        state.TempData.String("name"),
+			Description: state.TempData.String("description"),
+			Type:        state.TempData.String("type"),
 		})
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при создании категории: "+err.Error())
+			ah.sendTextFunc(chatID, tr.T("admin.category.create_error", err))
 		} else {
-			ah.sendTextFunc(chatID, "✅ Категория создана")
+			ah.sendTextFunc(chatID, tr.T("admin.category.created"))
 		}
 		ah.Fsm.DeleteState(userID)
 		ah.ShowCategoriesAdmin(chatID)
@@ -1301,27 +1243,28 @@ func (ah *AdminHandler) handleAddCategory(chatID, userID int64, state *AdminStat
 }
 
 func (ah *AdminHandler) handleEditCategory(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
 	switch state.Step {
 	case 1:
-		state.TempData["name"] = text
+		state.TempData.Set("name", text)
 		state.Step = 2
-		ah.sendTextFunc(chatID, "Введите новое описание:")
+		ah.sendTextFunc(chatID, tr.T("admin.new_description"))
 	case 2:
-		state.TempData["description"] = text
+		state.TempData.Set("description", text)
 		state.Step = 3
-		ah.sendTextFunc(chatID, "Введите новый тип (training/nutrition/general):")
+		ah.sendTextFunc(chatID, tr.T("admin.category.new_type"))
 	case 3:
-		state.TempData["type"] = text
+		state.TempData.Set("type", text)
 
 		err := ah.categoryService.UpdateCategory(state.EntityID, service.UpdateCategoryDTO{
-			Name:        state.TempData["name"].(string),
-			Description: state.TempData["description"].(string),
-			Type:        state.TempData["type"].(string),
+			Name:        state.TempData.String("name"),
+			Description: state.TempData.String("description"),
+			Type:        state.TempData.String("type"),
 		})
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при обновлении категории: "+err.Error())
+			ah.sendTextFunc(chatID, tr.T("admin.category.update_error", err))
 		} else {
-			ah.sendTextFunc(chatID, "✅ Категория обновлена")
+			ah.sendTextFunc(chatID, tr.T("admin.category.updated"))
 		}
 		ah.Fsm.DeleteState(userID)
 		ah.ShowCategoriesAdmin(chatID)
@@ -1331,65 +1274,88 @@ func (ah *AdminHandler) handleEditCategory(chatID, userID int64, state *AdminSta
 // ==================== НЕДЕЛЬНЫЕ МЕНЮ ====================
 
 func (ah *AdminHandler) handleAddWeeklyMenu(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
 	switch state.Step {
 	case 1:
-		state.TempData["name"] = text
+		state.TempData.Set("name", text)
 		state.Step = 2
-		ah.sendTextFunc(chatID, "Введите описание меню:")
+		ah.sendTextFunc(chatID, tr.T("admin.menu.description_prompt"))
 	case 2:
-		state.TempData["description"] = text
+		state.TempData.Set("description", text)
 
 		_, err := ah.nutritionService.CreateWeeklyMenu(service.CreateWeeklyMenuDTO{
-			Name:        state.TempData["name"].(string),
-			Description: state.TempData["description"].(string),
+			Name:        state.TempData.String("name"),
+			Description: state.TempData.String("description"),
 		})
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при создании меню: "+err.Error())
+			ah.sendTextFunc(chatID, tr.T("admin.menu.create_error", err))
 		} else {
-			ah.sendTextFunc(chatID, "✅ Недельное меню создано")
+			ah.sendTextFunc(chatID, tr.T("admin.menu.created"))
 		}
 		ah.Fsm.DeleteState(userID)
 		ah.ShowWeeklyMenusAdmin(chatID)
 	}
 }
 
+func (ah *AdminHandler) handleAssignMenu(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
+	telegramID, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	if err != nil {
+		ah.sendTextFunc(chatID, tr.T("admin.assign.invalid"))
+		return
+	}
+	user, err := ah.userService.GetUserByTelegramID(telegramID)
+	if err != nil {
+		ah.sendTextFunc(chatID, tr.T("admin.assign.not_found"))
+		return
+	}
+
+	if err := ah.menuService.AssignMenu(user.ID, state.EntityID, userID); err != nil {
+		ah.sendTextFunc(chatID, tr.T("admin.assign.error", err))
+	} else {
+		ah.sendTextFunc(chatID, tr.T("admin.assign.done", state.EntityID, telegramID))
+	}
+	ah.Fsm.DeleteState(userID)
+	ah.ShowWeeklyMenuDetails(chatID, state.EntityID)
+}
+
 func (ah *AdminHandler) handleAddDayToMenu(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
 	switch state.Step {
 	case 1:
 		dayNum, err := strconv.Atoi(text)
 		if err != nil || dayNum < 1 || dayNum > 7 {
-			ah.sendTextFunc(chatID, "❌ Введите номер дня от 1 до 7")
+			ah.sendTextFunc(chatID, tr.T("admin.day.invalid"))
 			return
 		}
-		state.TempData["day_number"] = dayNum
+		state.TempData.Set("day_number", dayNum)
 		state.Step = 2
 
 		// Автоматически определяем название дня
-		dayNames := []string{"Понедельник", "Вторник", "Среда", "Четверг", "Пятница", "Суббота", "Воскресенье"}
-		state.TempData["day_name"] = dayNames[dayNum-1]
-		ah.sendTextFunc(chatID, fmt.Sprintf("📅 День %d: %s\nТеперь вы можете добавить приемы пищи",
-			dayNum, state.TempData["day_name"].(string)))
+		state.TempData.Set("day_name", tr.T("weekday."+strconv.Itoa(dayNum)))
+		ah.sendTextFunc(chatID, tr.T("admin.day.created",
+			dayNum, state.TempData.String("day_name")))
 
 		// Создаем день
 		_, err = ah.nutritionService.AddDayToWeeklyMenu(service.AddDayToMenuDTO{
 			MenuID:    state.EntityID,
 			DayNumber: dayNum,
-			DayName:   state.TempData["day_name"].(string),
+			DayName:   state.TempData.String("day_name"),
 		})
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при добавлении дня: "+err.Error())
+			ah.sendTextFunc(chatID, tr.T("admin.day.error", err))
 			ah.Fsm.DeleteState(userID)
 			return
 		}
 		// Задаем вопрос о добавлении приема пищи
 		state.Step = 3
-		ah.sendTextFunc(chatID, "День добавлен! Хотите добавить прием пищи? (Да/Нет)")
+		ah.sendTextFunc(chatID, tr.T("admin.day.add_meal"))
 
 	case 3: // Это шаг для ответа на вопрос "Хотите добавить прием пищи?"
-		if strings.ToLower(text) == "да" {
+		if isYes(tr, text) {
 			state.Action = "add_meal_to_day"
 			state.Step = 1
-			ah.sendTextFunc(chatID, "Выберите тип приема пищи:\n1. Завтрак\n2. Обед\n3. Ужин\n4. Перекус")
+			ah.sendTextFunc(chatID, tr.T("admin.meal.type_prompt"))
 		} else {
 			ah.Fsm.DeleteState(userID)
 			ah.ShowWeeklyMenuDetails(chatID, state.EntityID)
@@ -1397,29 +1363,26 @@ func (ah *AdminHandler) handleAddDayToMenu(chatID, userID int64, state *AdminSta
 	}
 }
 
+// isYes - ответ «да» на языке администратора
+func isYes(tr *i18n.Localizer, text string) bool {
+	return strings.EqualFold(strings.TrimSpace(text), tr.T("admin.yes"))
+}
+
 func (ah *AdminHandler) handleAddMealToDay(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
 	switch state.Step {
 	case 1:
-		mealType := ""
-		switch text {
-		case "1":
-			mealType = "Завтрак"
-		case "2":
-			mealType = "Обед"
-		case "3":
-			mealType = "Ужин"
-		case "4":
-			mealType = "Перекус"
-		default:
-			mealType = text
+		mealType := text
+		if n, err := strconv.Atoi(text); err == nil && n >= 1 && n <= len(service.MealTypes) {
+			mealType = service.MealName(tr, service.MealTypes[n-1])
 		}
-		state.TempData["meal_type"] = mealType
+		state.TempData.Set("meal_type", mealType)
 		state.Step = 2
-		ah.sendTextFunc(chatID, "Введите время приема пищи (например, 09:00):")
+		ah.sendTextFunc(chatID, tr.T("admin.meal.time_prompt"))
 	case 2:
-		state.TempData["meal_time"] = text
+		state.TempData.Set("meal_time", text)
 		state.Step = 3
-		ah.sendTextFunc(chatID, "Введите ID блюда из списка питания (используйте /foodlist для просмотра):")
+		ah.sendTextFunc(chatID, tr.T("admin.meal.dish_prompt"))
 	case 3: // Когда запрашивается ID блюда
 		if text == "/foodlist" {
 			ah.ShowNutritionListForSelection(chatID)
@@ -1427,17 +1390,17 @@ func (ah *AdminHandler) handleAddMealToDay(chatID, userID int64, state *AdminSta
 		}
 		nutritionID, err := strconv.Atoi(text)
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Введите число для ID блюда. Используйте /foodlist для просмотра списка")
+			ah.sendTextFunc(chatID, tr.T("admin.meal.dish_invalid"))
 			return
 		}
-		state.TempData["nutrition_id"] = uint(nutritionID)
+		state.TempData.Set("nutrition_id", uint(nutritionID))
 		state.Step = 4
-		ah.sendTextFunc(chatID, "Введите заметки (или оставьте пустым):")
+		ah.sendTextFunc(chatID, tr.T("admin.meal.notes_prompt"))
 	case 4:
 		// Получаем ID последнего дня в меню
 		menu, err := ah.nutritionService.GetFullWeeklyMenu(state.EntityID)
 		if err != nil || len(menu.Days) == 0 {
-			ah.sendTextFunc(chatID, "❌ Ошибка при получении дней меню")
+			ah.sendTextFunc(chatID, tr.T("admin.meal.days_error"))
 			ah.Fsm.DeleteState(userID)
 			return
 		}
@@ -1447,26 +1410,26 @@ func (ah *AdminHandler) handleAddMealToDay(chatID, userID int64, state *AdminSta
 
 		_, err = ah.nutritionService.AddMealToDay(service.AddMealToDayDTO{
 			DayID:       lastDay.ID,
-			MealType:    state.TempData["meal_type"].(string),
-			MealTime:    state.TempData["meal_time"].(string),
-			NutritionID: state.TempData["nutrition_id"].(uint),
+			MealType:    state.TempData.String("meal_type"),
+			MealTime:    state.TempData.String("meal_time"),
+			NutritionID: state.TempData.Uint("nutrition_id"),
 			Notes:       text,
 		})
 
 		if err != nil {
-			ah.sendTextFunc(chatID, "❌ Ошибка при добавлении приема пищи: "+err.Error())
+			ah.sendTextFunc(chatID, tr.T("admin.meal.error", err))
 		} else {
-			ah.sendTextFunc(chatID, "✅ Прием пищи добавлен!")
+			ah.sendTextFunc(chatID, tr.T("admin.meal.added"))
 		}
 
 		// Спрашиваем, добавить еще один прием пищи
 		state.Step = 5
-		ah.sendTextFunc(chatID, "Хотите добавить еще один прием пищи в этот день? (Да/Нет)")
+		ah.sendTextFunc(chatID, tr.T("admin.meal.add_more"))
 
 	case 5:
-		if strings.ToLower(text) == "да" {
+		if isYes(tr, text) {
 			state.Step = 1 // Снова спрашиваем тип приема пищи
-			ah.sendTextFunc(chatID, "Выберите тип приема пищи:\n1. Завтрак\n2. Обед\n3. Ужин\n4. Перекус")
+			ah.sendTextFunc(chatID, tr.T("admin.meal.type_prompt"))
 		} else {
 			ah.Fsm.DeleteState(userID)
 			ah.ShowWeeklyMenuDetails(chatID, state.EntityID)
@@ -0,0 +1,544 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/callback"
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// registerProgramCallbacks - кнопки справочника упражнений и структуры программ
+func (ah *AdminHandler) registerProgramCallbacks() {
+	r := ah.callbacks
+	ns := callback.Admin
+
+	// Справочник упражнений
+	r.Handle(ns, "exercises", func(c *callback.Context) {
+		ah.ShowExercisesAdmin(c.ChatID)
+	})
+	r.Handle(ns, "add_exercise", func(c *callback.Context) {
+		ah.StartAddExerciseFlow(c.ChatID, c.From.ID)
+	})
+	r.Handle(ns, "view_exercise", ah.viewExercise, callback.Uint)
+	r.Handle(ns, "del_exercise", ah.confirmDeleteExercise, callback.Uint)
+	r.Handle(ns, "del_exercise_ok", ah.deleteExercise, callback.Uint)
+
+	// Программа тренировки: недели, дни и упражнения в них
+	r.Handle(ns, "program", func(c *callback.Context) {
+		ah.ShowProgramAdmin(c.ChatID, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "program_weeks", func(c *callback.Context) {
+		ah.StartProgramWeeksFlow(c.ChatID, c.From.ID, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "add_pday", func(c *callback.Context) {
+		ah.StartAddProgramDayFlow(c.ChatID, c.From.ID, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "repeat_week", ah.repeatProgramWeek, callback.Uint)
+	r.Handle(ns, "view_pday", func(c *callback.Context) {
+		ah.ShowProgramDayAdmin(c.ChatID, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "del_pday", ah.confirmDeleteProgramDay, callback.Uint)
+	r.Handle(ns, "del_pday_ok", ah.deleteProgramDay, callback.Uint)
+	r.Handle(ns, "add_pex", func(c *callback.Context) {
+		ah.StartAddProgramExerciseFlow(c.ChatID, c.From.ID, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "del_pex", ah.removeProgramExercise, callback.Uint, callback.Uint)
+}
+
+// ==================== УПРАЖНЕНИЯ ====================
+
+func (ah *AdminHandler) ShowExercisesAdmin(chatID int64) {
+	tr := ah.tr(chatID)
+	exercises, err := ah.programService.ListExercises()
+	if err != nil {
+		ah.sendTextFunc(chatID, tr.T("admin.exercises.error", err))
+		return
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for _, e := range exercises {
+		label := e.Name
+		if e.MuscleGroup != "" {
+			label += " (" + e.MuscleGroup + ")"
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			ah.button("💪 "+label, "view_exercise", e.ID),
+			ah.button("🗑", "del_exercise", e.ID),
+		))
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.exercises.add"), "add_exercise"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.back"), "panel"),
+		),
+	)
+
+	text := tr.T("admin.exercises.title", len(exercises))
+	if len(exercises) == 0 {
+		text = tr.T("admin.exercises.empty")
+	}
+	ah.sendTextWithKeyboard(chatID, text, rows)
+}
+
+func (ah *AdminHandler) viewExercise(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	e, err := ah.programService.GetExerciseByID(c.Uint(0))
+	if err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.exercise.not_found"))
+		return
+	}
+
+	msg := fmt.Sprintf("💪 *%s*\n\n", e.Name)
+	if e.MuscleGroup != "" {
+		msg += tr.T("admin.exercise.muscles", e.MuscleGroup)
+	}
+	if e.Equipment != "" {
+		msg += tr.T("admin.exercise.equipment", e.Equipment)
+	}
+	if e.Description != "" {
+		msg += "\n" + e.Description + "\n"
+	}
+	if e.YouTubeLink != "" {
+		msg += "\n🎥 " + e.YouTubeLink + "\n"
+	}
+	msg += fmt.Sprintf("\nID: %d", e.ID)
+	ah.sendTextFunc(c.ChatID, msg)
+}
+
+func (ah *AdminHandler) confirmDeleteExercise(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	id := c.Uint(0)
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		{
+			ah.button(tr.T("admin.confirm_delete"), "del_exercise_ok", id),
+			ah.button(tr.T("admin.cancel"), "exercises"),
+		},
+	}
+	ah.sendTextWithKeyboard(c.ChatID,
+		tr.T("admin.exercise.delete_confirm", id),
+		rows)
+}
+
+func (ah *AdminHandler) deleteExercise(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	if err := ah.programService.DeleteExercise(c.Uint(0)); err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.exercise.delete_error", err))
+	} else {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.exercise.deleted"))
+	}
+	ah.ShowExercisesAdmin(c.ChatID)
+}
+
+func (ah *AdminHandler) StartAddExerciseFlow(chatID, userID int64) {
+	tr := ah.tr(chatID)
+	ah.Fsm.SetState(userID, &AdminState{
+		Action:   "add_exercise",
+		Step:     1,
+		TempData: make(fsm.TempData),
+	})
+	ah.sendTextFunc(chatID, tr.T("admin.exercise.name_prompt"))
+}
+
+func (ah *AdminHandler) handleAddExercise(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
+	text = strings.TrimSpace(text)
+	// «-» в необязательных полях - оставить пустым
+	optional := text
+	if optional == "-" {
+		optional = ""
+	}
+
+	switch state.Step {
+	case 1:
+		if text == "" {
+			ah.sendTextFunc(chatID, tr.T("admin.exercise.name_empty"))
+			return
+		}
+		state.TempData.Set("name", text)
+		state.Step = 2
+		ah.sendTextFunc(chatID, tr.T("admin.exercise.muscles_prompt"))
+	case 2:
+		state.TempData.Set("muscle_group", optional)
+		state.Step = 3
+		ah.sendTextFunc(chatID, tr.T("admin.exercise.equipment_prompt"))
+	case 3:
+		state.TempData.Set("equipment", optional)
+		state.Step = 4
+		ah.sendTextFunc(chatID, tr.T("admin.exercise.description_prompt"))
+	case 4:
+		state.TempData.Set("description", optional)
+		state.Step = 5
+		ah.sendTextFunc(chatID, tr.T("admin.exercise.video_prompt"))
+	case 5:
+		exercise, err := ah.programService.CreateExercise(service.ExerciseDTO{
+			Name:        state.TempData.String("name"),
+			MuscleGroup: state.TempData.String("muscle_group"),
+			Equipment:   state.TempData.String("equipment"),
+			Description: state.TempData.String("description"),
+			YouTubeLink: optional,
+		})
+		ah.Fsm.DeleteState(userID)
+		if err != nil {
+			ah.sendTextFunc(chatID, tr.T("admin.exercise.create_error", err))
+		} else {
+			ah.sendTextFunc(chatID, tr.T("admin.exercise.created", exercise.Name, exercise.ID))
+		}
+		ah.ShowExercisesAdmin(chatID)
+	}
+}
+
+// ==================== ПРОГРАММЫ ====================
+
+// ShowProgramAdmin - недели и тренировочные дни программы
+func (ah *AdminHandler) ShowProgramAdmin(chatID int64, programID uint) {
+	tr := ah.tr(chatID)
+	program, err := ah.programService.GetProgram(programID)
+	if err != nil {
+		ah.sendTextFunc(chatID, tr.T("admin.training.not_found"))
+		return
+	}
+
+	msg := tr.T("admin.program.title", program.Title, program.Weeks)
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	if len(program.Days) == 0 {
+		msg += tr.T("admin.program.no_days")
+	}
+	for i := range program.Days {
+		day := &program.Days[i]
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.program.day_item", programDayLabel(tr, day, program.Weeks), len(day.Exercises)),
+				"view_pday", day.ID),
+		))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		ah.button(tr.T("admin.program.add_day"), "add_pday", program.ID),
+		ah.button(tr.T("admin.program.weeks", program.Weeks), "program_weeks", program.ID),
+	))
+	if program.Weeks > 1 && len(program.Days) > 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.program.repeat"), "repeat_week", program.ID),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		ah.button(tr.T("admin.program.back"), "trainings"),
+	))
+	ah.sendTextWithKeyboard(chatID, msg, rows)
+}
+
+// repeatProgramWeek копирует дни первой недели в пустые недели программы
+func (ah *AdminHandler) repeatProgramWeek(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	programID := c.Uint(0)
+	copied, err := ah.programService.RepeatWeek(programID, 1)
+	switch {
+	case err != nil:
+		ah.sendTextFunc(c.ChatID, tr.T("admin.program.repeat_error", err))
+	case copied == 0:
+		ah.sendTextFunc(c.ChatID, tr.T("admin.program.repeat_full"))
+	default:
+		ah.sendTextFunc(c.ChatID, tr.T("admin.program.repeated", copied))
+	}
+	ah.ShowProgramAdmin(c.ChatID, programID)
+}
+
+func (ah *AdminHandler) StartProgramWeeksFlow(chatID, userID int64, programID uint) {
+	tr := ah.tr(chatID)
+	ah.Fsm.SetState(userID, &AdminState{
+		Action:   "program_weeks",
+		Step:     1,
+		EntityID: programID,
+		TempData: make(fsm.TempData),
+	})
+	ah.sendTextFunc(chatID, tr.T("admin.program.weeks_prompt", service.MaxProgramWeeks))
+}
+
+func (ah *AdminHandler) handleProgramWeeks(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
+	weeks, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil {
+		ah.sendTextFunc(chatID, tr.T("admin.program.weeks_invalid", service.MaxProgramWeeks))
+		return
+	}
+	if err := ah.programService.SetWeeks(state.EntityID, weeks); err != nil {
+		ah.sendTextFunc(chatID, "❌ "+tr.Error(err))
+		return
+	}
+	ah.Fsm.DeleteState(userID)
+	ah.sendTextFunc(chatID, tr.T("admin.program.weeks_set", weeks))
+	ah.ShowProgramAdmin(chatID, state.EntityID)
+}
+
+func (ah *AdminHandler) StartAddProgramDayFlow(chatID, userID int64, programID uint) {
+	tr := ah.tr(chatID)
+	program, err := ah.trainingService.GetTrainingByID(programID)
+	if err != nil {
+		ah.sendTextFunc(chatID, tr.T("admin.training.not_found"))
+		return
+	}
+
+	state := &AdminState{
+		Action:   "add_program_day",
+		Step:     1,
+		EntityID: programID,
+		TempData: make(fsm.TempData),
+	}
+	// В программе на одну неделю номер недели не спрашиваем
+	if program.Weeks <= 1 {
+		state.TempData.Set("week", 1)
+		state.Step = 2
+	}
+	ah.Fsm.SetState(userID, state)
+
+	if state.Step == 1 {
+		ah.sendTextFunc(chatID, tr.T("admin.program.week_prompt", program.Weeks))
+		return
+	}
+	ah.sendTextFunc(chatID, programDayPrompt(tr))
+}
+
+func programDayPrompt(tr *i18n.Localizer) string {
+	msg := tr.T("admin.program.day_prompt")
+	for day := 1; day <= 7; day++ {
+		msg += fmt.Sprintf("%d. %s\n", day, tr.T("weekday."+strconv.Itoa(day)))
+	}
+	return msg
+}
+
+// programDayLabel - «Неделя 2 · Среда · Ноги» или короче, если программа на одну неделю
+func programDayLabel(tr *i18n.Localizer, day *models.ProgramDay, weeks int) string {
+	parts := []string{}
+	if weeks > 1 {
+		parts = append(parts, tr.T("admin.program.week", day.Week))
+	}
+	if day.DayNumber >= 1 && day.DayNumber <= 7 {
+		parts = append(parts, tr.T("weekday."+strconv.Itoa(day.DayNumber)))
+	}
+	if day.Title != "" {
+		parts = append(parts, day.Title)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (ah *AdminHandler) handleAddProgramDay(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
+	text = strings.TrimSpace(text)
+	switch state.Step {
+	case 1:
+		week, err := strconv.Atoi(text)
+		if err != nil || week < 1 {
+			ah.sendTextFunc(chatID, tr.T("admin.program.week_invalid"))
+			return
+		}
+		state.TempData.Set("week", week)
+		state.Step = 2
+		ah.sendTextFunc(chatID, programDayPrompt(tr))
+	case 2:
+		dayNum, err := strconv.Atoi(text)
+		if err != nil || dayNum < 1 || dayNum > 7 {
+			ah.sendTextFunc(chatID, tr.T("admin.day.invalid"))
+			return
+		}
+		state.TempData.Set("day_number", dayNum)
+		state.Step = 3
+		ah.sendTextFunc(chatID, tr.T("admin.program.day_title_prompt"))
+	case 3:
+		if text == "-" {
+			text = ""
+		}
+		day, err := ah.programService.AddDay(service.ProgramDayDTO{
+			ProgramID: state.EntityID,
+			Week:      state.TempData.Int("week"),
+			DayNumber: state.TempData.Int("day_number"),
+			Title:     text,
+		})
+		ah.Fsm.DeleteState(userID)
+		if err != nil {
+			ah.sendTextFunc(chatID, tr.T("admin.day.error", err))
+			ah.ShowProgramAdmin(chatID, state.EntityID)
+			return
+		}
+		ah.sendTextFunc(chatID, tr.T("admin.program.day_added"))
+		ah.ShowProgramDayAdmin(chatID, day.ID)
+	}
+}
+
+// ShowProgramDayAdmin - упражнения тренировочного дня с подходами и отдыхом
+func (ah *AdminHandler) ShowProgramDayAdmin(chatID int64, dayID uint) {
+	tr := ah.tr(chatID)
+	day, err := ah.programService.GetDay(dayID)
+	if err != nil {
+		ah.sendTextFunc(chatID, tr.T("admin.program.day_not_found"))
+		return
+	}
+	program, err := ah.trainingService.GetTrainingByID(day.ProgramID)
+	if err != nil {
+		ah.sendTextFunc(chatID, tr.T("admin.training.not_found"))
+		return
+	}
+
+	msg := fmt.Sprintf("📋 %s\n%s\n\n", program.Title, programDayLabel(tr, day, program.Weeks))
+	if len(day.Exercises) == 0 {
+		msg += tr.T("admin.program.no_exercises")
+	}
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for i, item := range day.Exercises {
+		msg += tr.T("admin.program.exercise", i+1, item.Exercise.Name, item.Sets, item.Reps,
+			render.Rest(tr, item.RestSeconds))
+		if item.Tempo != "" {
+			msg += tr.T("admin.program.tempo", item.Tempo)
+		}
+		if item.Weight != "" {
+			msg += ", " + item.Weight
+		}
+		msg += "\n"
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			ah.button(fmt.Sprintf("🗑 %d. %s", i+1, item.Exercise.Name), "del_pex", item.ID, day.ID),
+		))
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.program.add_exercise"), "add_pex", day.ID),
+			ah.button(tr.T("admin.program.delete_day"), "del_pday", day.ID),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			ah.button(tr.T("admin.program.back_to_program"), "program", day.ProgramID),
+		),
+	)
+	ah.sendTextWithKeyboard(chatID, msg, rows)
+}
+
+func (ah *AdminHandler) confirmDeleteProgramDay(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	id := c.Uint(0)
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		{
+			ah.button(tr.T("admin.confirm_delete"), "del_pday_ok", id),
+			ah.button(tr.T("admin.cancel"), "view_pday", id),
+		},
+	}
+	ah.sendTextWithKeyboard(c.ChatID, tr.T("admin.program.delete_day_confirm"), rows)
+}
+
+func (ah *AdminHandler) deleteProgramDay(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	day, err := ah.programService.GetDay(c.Uint(0))
+	if err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.program.day_not_found"))
+		return
+	}
+	if err := ah.programService.DeleteDay(day.ID); err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.program.delete_day_error", err))
+	} else {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.program.day_deleted"))
+	}
+	ah.ShowProgramAdmin(c.ChatID, day.ProgramID)
+}
+
+func (ah *AdminHandler) removeProgramExercise(c *callback.Context) {
+	tr := ah.tr(c.ChatID)
+	if err := ah.programService.RemoveExercise(c.Uint(0)); err != nil {
+		ah.sendTextFunc(c.ChatID, tr.T("admin.exercise.delete_error", err))
+	}
+	ah.ShowProgramDayAdmin(c.ChatID, c.Uint(1))
+}
+
+func (ah *AdminHandler) StartAddProgramExerciseFlow(chatID, userID int64, dayID uint) {
+	tr := ah.tr(chatID)
+	exercises, err := ah.programService.ListExercises()
+	if err != nil {
+		ah.sendTextFunc(chatID, tr.T("admin.exercises.error", err))
+		return
+	}
+	if len(exercises) == 0 {
+		ah.sendTextWithKeyboard(chatID, tr.T("admin.program.no_library"),
+			[][]tgbotapi.InlineKeyboardButton{
+				tgbotapi.NewInlineKeyboardRow(ah.button(tr.T("admin.exercises.add"), "add_exercise")),
+			})
+		return
+	}
+
+	ah.Fsm.SetState(userID, &AdminState{
+		Action:   "add_program_exercise",
+		Step:     1,
+		EntityID: dayID,
+		TempData: make(fsm.TempData),
+	})
+	msg := tr.T("admin.program.exercise_prompt")
+	for _, e := range exercises {
+		msg += fmt.Sprintf("%d. %s\n", e.ID, e.Name)
+	}
+	ah.sendTextFunc(chatID, msg)
+}
+
+func (ah *AdminHandler) handleAddProgramExercise(chatID, userID int64, state *AdminState, text string) {
+	tr := ah.tr(chatID)
+	text = strings.TrimSpace(text)
+	switch state.Step {
+	case 1:
+		exercise, err := ah.programService.FindExercise(text)
+		if err != nil {
+			ah.sendTextFunc(chatID, tr.T("admin.program.exercise_not_found"))
+			return
+		}
+		state.TempData.Set("exercise_id", exercise.ID)
+		state.Step = 2
+		ah.sendTextFunc(chatID, tr.T("admin.program.sets_prompt", exercise.Name))
+	case 2:
+		sets, reps, err := service.ParseSetsReps(text)
+		if err != nil {
+			ah.sendTextFunc(chatID, "❌ "+tr.Error(err))
+			return
+		}
+		state.TempData.Set("sets", sets)
+		state.TempData.Set("reps", reps)
+		state.Step = 3
+		ah.sendTextFunc(chatID, tr.T("admin.program.rest_prompt"))
+	case 3:
+		rest, err := strconv.Atoi(text)
+		if err != nil || rest < 0 {
+			ah.sendTextFunc(chatID, tr.T("admin.program.rest_invalid"))
+			return
+		}
+		state.TempData.Set("rest", rest)
+		state.Step = 4
+		ah.sendTextFunc(chatID, tr.T("admin.program.tempo_prompt"))
+	case 4:
+		if text == "-" {
+			text = ""
+		}
+		state.TempData.Set("tempo", text)
+		state.Step = 5
+		ah.sendTextFunc(chatID, tr.T("admin.program.weight_prompt"))
+	case 5:
+		if text == "-" {
+			text = ""
+		}
+		_, err := ah.programService.AddExercise(service.ProgramExerciseDTO{
+			DayID:       state.EntityID,
+			ExerciseID:  state.TempData.Uint("exercise_id"),
+			Sets:        state.TempData.Int("sets"),
+			Reps:        state.TempData.String("reps"),
+			RestSeconds: state.TempData.Int("rest"),
+			Tempo:       state.TempData.String("tempo"),
+			Weight:      text,
+		})
+		ah.Fsm.DeleteState(userID)
+		if err != nil {
+			ah.sendTextFunc(chatID, tr.T("admin.exercise.create_error", err))
+		} else {
+			ah.sendTextFunc(chatID, tr.T("admin.program.exercise_added"))
+		}
+		ah.ShowProgramDayAdmin(chatID, state.EntityID)
+	}
+}
@@ -1,37 +1,19 @@
 package admin
 
-// AdminState хранит состояние админ-панели
-type AdminState struct {
-	Action   string
-	Step     int
-	EntityID uint
-	TempData map[string]interface{}
-}
-
-// AdminFSM управляет состояниями всех админов
-type AdminFSM struct {
-	states map[int64]*AdminState
-}
+import (
+	"time"
 
-// Конструктор FSM
-func NewAdminFSM() *AdminFSM {
-	return &AdminFSM{
-		states: make(map[int64]*AdminState),
-	}
-}
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+)
 
-// Получить состояние
-func (fsm *AdminFSM) GetState(userID int64) (*AdminState, bool) {
-	state, exists := fsm.states[userID]
-	return state, exists
-}
+// AdminState хранит состояние админ-панели
+type AdminState = fsm.State
 
-// Установить состояние
-func (fsm *AdminFSM) SetState(userID int64, state *AdminState) {
-	fsm.states[userID] = state
-}
+// AdminFSM управляет состояниями всех админов
+type AdminFSM = fsm.Machine
 
-// Удалить состояние
-func (fsm *AdminFSM) DeleteState(userID int64) {
-	delete(fsm.states, userID)
+// Конструктор FSM. store определяет, где живут состояния
+// (fsm.NewMemoryStore или fsm.NewDBStore), ttl - через сколько брошенный мастер истекает
+func NewAdminFSM(store fsm.Store, ttl time.Duration) *AdminFSM {
+	return fsm.NewMachine(store, ttl)
 }
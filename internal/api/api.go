@@ -0,0 +1,262 @@
+// Package api - REST API админки для массового управления контентом:
+// тренировками и их программами, упражнениями, блюдами и их рецептами, категориями, недельными меню
+// и их назначением пользователям.
+// Все маршруты под /api/admin защищены basic auth, изменяющие запросы принимаются только в JSON.
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/server"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// Handler обслуживает REST API поверх тех же сервисов, что и бот
+type Handler struct {
+	trainingService  *service.TrainingService
+	nutritionService *service.NutritionService
+	categoryService  *service.CategoryService
+	recipeService    *service.RecipeService
+	userService      *service.UserService
+	menuService      *service.MenuAssignmentService
+	generatorService *service.MenuGeneratorService
+	programService   *service.ProgramService
+}
+
+func NewHandler(
+	trainingService *service.TrainingService,
+	nutritionService *service.NutritionService,
+	categoryService *service.CategoryService,
+	recipeService *service.RecipeService,
+	userService *service.UserService,
+	menuService *service.MenuAssignmentService,
+	generatorService *service.MenuGeneratorService,
+	programService *service.ProgramService,
+) *Handler {
+	return &Handler{
+		trainingService:  trainingService,
+		nutritionService: nutritionService,
+		categoryService:  categoryService,
+		recipeService:    recipeService,
+		userService:      userService,
+		menuService:      menuService,
+		generatorService: generatorService,
+		programService:   programService,
+	}
+}
+
+// Register подключает маршруты /api/admin к router под basic auth
+func (h *Handler) Register(router gin.IRouter, username, password string) error {
+	auth, err := server.BasicAuth(username, password)
+	if err != nil {
+		return err
+	}
+	useJSONFieldNames()
+
+	// Браузер подставляет сохраненный basic auth и на чужих сайтах, поэтому
+	// изменяющие запросы принимаются только со своего origin и только в JSON
+	group := router.Group("/api/admin", auth, server.SameOrigin, server.RequireJSON)
+
+	group.GET("/trainings", h.listTrainings)
+	group.POST("/trainings", h.createTraining)
+	group.GET("/trainings/:id", h.getTraining)
+	group.PUT("/trainings/:id", h.updateTraining)
+	group.DELETE("/trainings/:id", h.deleteTraining)
+	group.GET("/trainings/:id/program", h.getProgram)
+	group.PUT("/trainings/:id/weeks", h.setProgramWeeks)
+	group.POST("/trainings/:id/days", h.addProgramDay)
+	group.DELETE("/program-days/:id", h.deleteProgramDay)
+	group.POST("/program-days/:id/exercises", h.addProgramExercise)
+	group.DELETE("/program-exercises/:id", h.deleteProgramExercise)
+
+	group.GET("/exercises", h.listExercises)
+	group.POST("/exercises", h.createExercise)
+	group.GET("/exercises/:id", h.getExercise)
+	group.PUT("/exercises/:id", h.updateExercise)
+	group.DELETE("/exercises/:id", h.deleteExercise)
+
+	group.GET("/nutrition", h.listNutrition)
+	group.POST("/nutrition", h.createNutrition)
+	group.GET("/nutrition/:id", h.getNutrition)
+	group.PUT("/nutrition/:id", h.updateNutrition)
+	group.DELETE("/nutrition/:id", h.deleteNutrition)
+	group.GET("/nutrition/:id/recipe", h.getRecipe)
+	group.PUT("/nutrition/:id/recipe", h.setRecipe)
+
+	group.GET("/ingredients", h.listIngredients)
+	group.POST("/ingredients", h.createIngredient)
+	group.GET("/ingredients/:id", h.getIngredient)
+	group.PUT("/ingredients/:id", h.updateIngredient)
+	group.DELETE("/ingredients/:id", h.deleteIngredient)
+
+	group.GET("/categories", h.listCategories)
+	group.POST("/categories", h.createCategory)
+	group.GET("/categories/:id", h.getCategory)
+	group.PUT("/categories/:id", h.updateCategory)
+	group.DELETE("/categories/:id", h.deleteCategory)
+
+	group.GET("/weekly-menus", h.listWeeklyMenus)
+	group.POST("/weekly-menus", h.createWeeklyMenu)
+	group.POST("/weekly-menus/generate", h.generateWeeklyMenu)
+	group.GET("/weekly-menus/:id", h.getWeeklyMenu)
+	group.DELETE("/weekly-menus/:id", h.deleteWeeklyMenu)
+	group.POST("/weekly-menus/:id/activate", h.activateWeeklyMenu)
+	group.POST("/weekly-menus/:id/publish", h.publishWeeklyMenu)
+	group.POST("/weekly-menus/:id/unpublish", h.unpublishWeeklyMenu)
+	group.POST("/weekly-menus/:id/days", h.addMenuDay)
+	group.DELETE("/menu-days/:id", h.deleteMenuDay)
+	group.POST("/menu-days/:id/meals", h.addDayMeal)
+	group.DELETE("/menu-meals/:id", h.deleteDayMeal)
+
+	group.GET("/users/:telegram_id/menu", h.getUserMenu)
+	group.PUT("/users/:telegram_id/menu", h.assignUserMenu)
+
+	return nil
+}
+
+// errorResponse - тело ответа с ошибкой. Error - текст на основном языке каталога,
+// Code - ключ сообщения в каталоге. Fields заполняется при ошибках валидации
+type errorResponse struct {
+	Error  string            `json:"error"`
+	Code   string            `json:"code,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// newErrorResponse - ответ с сообщением key из каталога
+func newErrorResponse(key string, args ...any) errorResponse {
+	return errorResponse{Error: i18n.Default().T(key, args...), Code: key}
+}
+
+// Ошибки валидации называют поля так же, как они называются в JSON
+var registerTagNames sync.Once
+
+func useJSONFieldNames() {
+	registerTagNames.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	})
+}
+
+// bindJSON разбирает тело запроса в req. При ошибке отвечает 400 и возвращает false
+func bindJSON(c *gin.Context, req any) bool {
+	err := c.ShouldBindJSON(req)
+	if err == nil {
+		return true
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		fields := make(map[string]string, len(fieldErrs))
+		for _, fe := range fieldErrs {
+			fields[fe.Field()] = validationMessage(fe)
+		}
+		resp := newErrorResponse("error.api.validation")
+		resp.Fields = fields
+		c.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return false
+	}
+	c.AbortWithStatusJSON(http.StatusBadRequest, newErrorResponse("error.api.json", err.Error()))
+	return false
+}
+
+// validationMessage - понятное описание нарушенного правила
+func validationMessage(fe validator.FieldError) string {
+	tr := i18n.Default()
+	switch fe.Tag() {
+	case "required":
+		return tr.T("error.api.field.required")
+	case "min", "gte":
+		if fe.Kind() == reflect.String {
+			return tr.T("error.api.field.min_len", fe.Param())
+		}
+		return tr.T("error.api.field.min", fe.Param())
+	case "max", "lte":
+		if fe.Kind() == reflect.String {
+			return tr.T("error.api.field.max_len", fe.Param())
+		}
+		return tr.T("error.api.field.max", fe.Param())
+	case "gt":
+		return tr.T("error.api.field.gt", fe.Param())
+	case "oneof":
+		return tr.T("error.api.field.oneof", fe.Param())
+	case "url":
+		return tr.T("error.api.field.url")
+	default:
+		return tr.T("error.api.field.invalid")
+	}
+}
+
+// fieldError отвечает 400 с ошибкой err одного поля
+func fieldError(c *gin.Context, field string, err error) {
+	resp := newErrorResponse("error.api.validation")
+	resp.Fields = map[string]string{field: i18n.Default().Error(err)}
+	c.AbortWithStatusJSON(http.StatusBadRequest, resp)
+}
+
+// parseID читает положительный числовой параметр пути :id
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, newErrorResponse("error.invalid_id"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// notFound отвечает 404 с сообщением key, если err - отсутствие записи, иначе 500
+func notFound(c *gin.Context, err error, key string, args ...any) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, newErrorResponse(key, args...))
+		return
+	}
+	internalError(c, err)
+}
+
+// rejected отвечает 400 с сообщением сервиса: сервисы возвращают ошибки проверки из каталога.
+// Code - ключ первой из них
+func rejected(c *gin.Context, err error) {
+	resp := errorResponse{Error: i18n.Default().Error(err)}
+	var e *i18n.Error
+	if errors.As(err, &e) {
+		resp.Code = e.Key
+	}
+	c.AbortWithStatusJSON(http.StatusBadRequest, resp)
+}
+
+func internalError(c *gin.Context, err error) {
+	log.Printf("[api] %s %s: %v", c.Request.Method, c.FullPath(), err)
+	c.AbortWithStatusJSON(http.StatusInternalServerError, newErrorResponse("error.api.internal"))
+}
+
+// checkCategory проверяет, что категория из запроса существует
+func (h *Handler) checkCategory(c *gin.Context, field string, id uint) bool {
+	if _, err := h.categoryService.GetCategoryByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			fieldError(c, field, i18n.NewError("error.category.not_found"))
+		} else {
+			internalError(c, err)
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	testUser     = "admin"
+	testPassword = "s3cret"
+)
+
+var errDB = errors.New("соединение с базой потеряно")
+
+// Репозитории в памяти. Методы, которые тесты не вызывают, остаются от
+// встроенного nil-интерфейса и паникуют
+type fakeTrainingRepo struct {
+	repository.TrainingRepository
+	trainings map[uint]*models.TrainingProgram
+	err       error // Ошибка базы для всех запросов
+}
+
+func (r *fakeTrainingRepo) FindAll() ([]*models.TrainingProgram, error) {
+	var all []*models.TrainingProgram
+	for _, t := range r.trainings {
+		all = append(all, t)
+	}
+	return all, r.err
+}
+
+func (r *fakeTrainingRepo) FindByID(id uint) (*models.TrainingProgram, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	t, ok := r.trainings[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return t, nil
+}
+
+func (r *fakeTrainingRepo) Create(t *models.TrainingProgram) (*models.TrainingProgram, error) {
+	t.ID = uint(len(r.trainings) + 100)
+	r.trainings[t.ID] = t
+	return t, nil
+}
+
+func (r *fakeTrainingRepo) Update(*models.TrainingProgram) error { return nil }
+
+func (r *fakeTrainingRepo) Delete(id uint) error {
+	delete(r.trainings, id)
+	return nil
+}
+
+type fakeCategoryRepo struct {
+	repository.CategoryRepository
+	categories map[uint]*models.Category
+}
+
+func (r *fakeCategoryRepo) FindByID(id uint) (*models.Category, error) {
+	cat, ok := r.categories[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return cat, nil
+}
+
+type fakeUserRepo struct {
+	repository.UserRepository
+}
+
+func (r *fakeUserRepo) FindByTelegramID(int64) (*models.User, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+// newTestRouter - API с тренировкой 1 и категорией 1; остальные сервисы не нужны:
+// запросы к ним отклоняются раньше
+func newTestRouter(t *testing.T, trainings *fakeTrainingRepo) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	if trainings == nil {
+		trainings = &fakeTrainingRepo{}
+	}
+	if trainings.trainings == nil {
+		training := &models.TrainingProgram{Title: "Фулбади", Duration: 45, Weeks: 1}
+		training.ID = 1
+		trainings.trainings = map[uint]*models.TrainingProgram{1: training}
+	}
+	category := &models.Category{Name: "Силовые", Type: "training"}
+	category.ID = 1
+
+	h := NewHandler(
+		service.NewTrainingService(trainings),
+		nil,
+		service.NewCategoryService(&fakeCategoryRepo{categories: map[uint]*models.Category{1: category}}),
+		nil,
+		service.NewUserService(&fakeUserRepo{}),
+		nil, nil, nil,
+	)
+	router := gin.New()
+	if err := h.Register(router, testUser, testPassword); err != nil {
+		t.Fatal(err)
+	}
+	return router
+}
+
+// request выполняет запрос от клиента API: basic auth, без Origin и cookie
+func request(router http.Handler, method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.SetBasicAuth(testUser, testPassword)
+	for name, value := range header {
+		if value == "" {
+			req.Header.Del(name)
+		} else {
+			req.Header.Set(name, value)
+		}
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// Проверки до обращения к сервисам срабатывают на каждом маршруте
+func TestRoutesStatus(t *testing.T) {
+	router := newTestRouter(t, nil)
+	for _, route := range router.Routes() {
+		path := strings.NewReplacer(":id", "1", ":telegram_id", "1").Replace(route.Path)
+		badID := strings.NewReplacer(":id", "abc", ":telegram_id", "abc").Replace(route.Path)
+		modifies := route.Method != http.MethodGet
+
+		t.Run(route.Method+" "+route.Path, func(t *testing.T) {
+			tests := []struct {
+				name   string
+				path   string
+				body   string
+				header map[string]string
+				want   int
+				skip   bool
+			}{
+				{name: "без авторизации", path: path, header: map[string]string{"Authorization": ""}, want: http.StatusUnauthorized},
+				{name: "неверный пароль", path: path, header: map[string]string{"Authorization": "Basic YWRtaW46d3Jvbmc="}, want: http.StatusUnauthorized},
+				{name: "чужой сайт", path: path, body: "{}", header: map[string]string{"Origin": "https://evil.example"}, want: http.StatusForbidden, skip: !modifies},
+				{name: "cookie без Origin", path: path, body: "{}", header: map[string]string{"Cookie": "session=1"}, want: http.StatusForbidden, skip: !modifies},
+				{name: "не JSON", path: path, body: "{}", header: map[string]string{"Content-Type": "text/plain"}, want: http.StatusUnsupportedMediaType, skip: !modifies},
+				{name: "неверный ID", path: badID, body: "{}", want: http.StatusBadRequest, skip: badID == route.Path},
+			}
+			for _, tc := range tests {
+				if tc.skip {
+					continue
+				}
+				rec := request(router, route.Method, tc.path, tc.body, tc.header)
+				if rec.Code != tc.want {
+					t.Errorf("%s: статус %d, want %d: %s", tc.name, rec.Code, tc.want, rec.Body)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlerStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		dbErr    error
+		want     int
+		wantCode string // Ключ ошибки в ответе
+	}{
+		{name: "список", method: http.MethodGet, path: "/api/admin/trainings", want: http.StatusOK},
+		{name: "тренировка", method: http.MethodGet, path: "/api/admin/trainings/1", want: http.StatusOK},
+		{name: "нет тренировки", method: http.MethodGet, path: "/api/admin/trainings/99", want: http.StatusNotFound, wantCode: "error.training.not_found"},
+		{name: "нулевой ID", method: http.MethodGet, path: "/api/admin/trainings/0", want: http.StatusBadRequest, wantCode: "error.invalid_id"},
+		{name: "ошибка базы", method: http.MethodGet, path: "/api/admin/trainings/1", dbErr: errDB, want: http.StatusInternalServerError, wantCode: "error.api.internal"},
+		{name: "создание", method: http.MethodPost, path: "/api/admin/trainings", body: `{"title":"Кардио","duration":30,"category_id":1}`, want: http.StatusCreated},
+		{name: "создание без названия", method: http.MethodPost, path: "/api/admin/trainings", body: `{"duration":30}`, want: http.StatusBadRequest, wantCode: "error.api.validation"},
+		{name: "создание с неизвестной категорией", method: http.MethodPost, path: "/api/admin/trainings", body: `{"title":"Кардио","duration":30,"category_id":7}`, want: http.StatusBadRequest, wantCode: "error.api.validation"},
+		{name: "некорректный JSON", method: http.MethodPost, path: "/api/admin/trainings", body: `{"title":`, want: http.StatusBadRequest, wantCode: "error.api.json"},
+		{name: "изменение", method: http.MethodPut, path: "/api/admin/trainings/1", body: `{"title":"Кардио","duration":30}`, want: http.StatusOK},
+		{name: "изменение несуществующей", method: http.MethodPut, path: "/api/admin/trainings/99", body: `{"title":"Кардио","duration":30}`, want: http.StatusNotFound, wantCode: "error.training.not_found"},
+		{name: "удаление", method: http.MethodDelete, path: "/api/admin/trainings/1", want: http.StatusNoContent},
+		{name: "удаление несуществующей", method: http.MethodDelete, path: "/api/admin/trainings/99", want: http.StatusNotFound, wantCode: "error.training.not_found"},
+		{name: "категория", method: http.MethodGet, path: "/api/admin/categories/1", want: http.StatusOK},
+		{name: "нет категории", method: http.MethodGet, path: "/api/admin/categories/2", want: http.StatusNotFound, wantCode: "error.category.not_found"},
+		{name: "неизвестный тип категории", method: http.MethodPost, path: "/api/admin/categories", body: `{"name":"Йога","type":"sport"}`, want: http.StatusBadRequest, wantCode: "error.api.validation"},
+		{name: "неверный Telegram ID", method: http.MethodGet, path: "/api/admin/users/abc/menu", want: http.StatusBadRequest, wantCode: "error.api.telegram_id"},
+		{name: "нет пользователя", method: http.MethodGet, path: "/api/admin/users/5/menu", want: http.StatusNotFound, wantCode: "error.user.not_found"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newTestRouter(t, &fakeTrainingRepo{err: tc.dbErr})
+			rec := request(router, tc.method, tc.path, tc.body, nil)
+			if rec.Code != tc.want {
+				t.Fatalf("статус %d, want %d: %s", rec.Code, tc.want, rec.Body)
+			}
+			if tc.wantCode == "" {
+				return
+			}
+			var resp errorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("тело ошибки %q: %v", rec.Body, err)
+			}
+			if resp.Code != tc.wantCode || resp.Error == "" {
+				t.Errorf("ошибка %+v, want ключ %s", resp, tc.wantCode)
+			}
+		})
+	}
+}
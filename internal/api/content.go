@@ -0,0 +1,440 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ==================== ТРЕНИРОВКИ ====================
+
+type trainingRequest struct {
+	Title       string `json:"title" binding:"required,max=100"`
+	Description string `json:"description"`
+	Difficulty  string `json:"difficulty" binding:"max=50"`
+	Duration    int    `json:"duration" binding:"required,min=1,max=600"` // минуты
+	CategoryID  *uint  `json:"category_id"`
+	YouTubeLink string `json:"youtube_link" binding:"omitempty,url"`
+}
+
+type trainingResponse struct {
+	ID          uint      `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Difficulty  string    `json:"difficulty"`
+	Duration    int       `json:"duration"`
+	CategoryID  *uint     `json:"category_id"`
+	YouTubeLink string    `json:"youtube_link"`
+	Weeks       int       `json:"weeks"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newTrainingResponse(t *models.TrainingProgram) trainingResponse {
+	return trainingResponse{
+		ID:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		Difficulty:  t.Difficulty,
+		Duration:    t.Duration,
+		CategoryID:  t.CategoryID,
+		YouTubeLink: t.YouTubeLink,
+		Weeks:       t.Weeks,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+func (h *Handler) listTrainings(c *gin.Context) {
+	trainings, err := h.trainingService.ListTrainings()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	resp := make([]trainingResponse, 0, len(trainings))
+	for _, t := range trainings {
+		resp = append(resp, newTrainingResponse(t))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) getTraining(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	training, err := h.trainingService.GetTrainingByID(id)
+	if err != nil {
+		notFound(c, err, "error.training.not_found")
+		return
+	}
+	c.JSON(http.StatusOK, newTrainingResponse(training))
+}
+
+func (h *Handler) createTraining(c *gin.Context) {
+	var req trainingRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if req.CategoryID != nil && !h.checkCategory(c, "category_id", *req.CategoryID) {
+		return
+	}
+
+	training, err := h.trainingService.CreateTraining(service.CreateTrainingDTO{
+		Title:       req.Title,
+		Duration:    req.Duration,
+		Difficulty:  req.Difficulty,
+		Description: req.Description,
+		CategoryID:  req.CategoryID,
+		YouTubeLink: req.YouTubeLink,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newTrainingResponse(training))
+}
+
+func (h *Handler) updateTraining(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req trainingRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, err := h.trainingService.GetTrainingByID(id); err != nil {
+		notFound(c, err, "error.training.not_found")
+		return
+	}
+	if req.CategoryID != nil && !h.checkCategory(c, "category_id", *req.CategoryID) {
+		return
+	}
+
+	err := h.trainingService.UpdateTraining(id, service.UpdateTrainingDTO{
+		Title:       req.Title,
+		Duration:    req.Duration,
+		Difficulty:  req.Difficulty,
+		Description: req.Description,
+		CategoryID:  req.CategoryID,
+		YouTubeLink: req.YouTubeLink,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	h.getTraining(c)
+}
+
+func (h *Handler) deleteTraining(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.trainingService.GetTrainingByID(id); err != nil {
+		notFound(c, err, "error.training.not_found")
+		return
+	}
+	if err := h.trainingService.DeleteTraining(id); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ==================== ПИТАНИЕ ====================
+
+type nutritionRequest struct {
+	Title       string  `json:"title" binding:"required,max=255"`
+	Description string  `json:"description"`
+	Calories    int     `json:"calories" binding:"min=0,max=10000"`
+	Protein     float64 `json:"protein" binding:"min=0,max=1000"`
+	Carbs       float64 `json:"carbs" binding:"min=0,max=1000"`
+	Fats        float64 `json:"fats" binding:"min=0,max=1000"`
+	CategoryID  uint    `json:"category_id" binding:"required"`
+	// Ключи диет и аллергенов; при обновлении null - оставить как есть
+	Diets     []string `json:"diets"`
+	Allergens []string `json:"allergens"`
+}
+
+type nutritionResponse struct {
+	ID          uint      `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Calories    int       `json:"calories"`
+	Protein     float64   `json:"protein"`
+	Carbs       float64   `json:"carbs"`
+	Fats        float64   `json:"fats"`
+	CategoryID  uint      `json:"category_id"`
+	Diets       []string  `json:"diets"`
+	Allergens   []string  `json:"allergens"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newNutritionResponse(p *models.NutritionPlan) nutritionResponse {
+	return nutritionResponse{
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		Calories:    p.Calories,
+		Protein:     p.Protein,
+		Carbs:       p.Carbs,
+		Fats:        p.Fats,
+		CategoryID:  p.CategoryID,
+		Diets:       service.TagKeys(service.DietTags, p.Diets),
+		Allergens:   service.TagKeys(service.AllergenTags, p.Allergens),
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+func (h *Handler) listNutrition(c *gin.Context) {
+	plans, err := h.nutritionService.ListNutrition()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	resp := make([]nutritionResponse, 0, len(plans))
+	for _, p := range plans {
+		resp = append(resp, newNutritionResponse(p))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) getNutrition(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	plan, err := h.nutritionService.GetNutritionByID(id)
+	if err != nil {
+		notFound(c, err, "error.dish.not_found")
+		return
+	}
+	c.JSON(http.StatusOK, newNutritionResponse(plan))
+}
+
+func (h *Handler) createNutrition(c *gin.Context) {
+	var req nutritionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if !h.checkCategory(c, "category_id", req.CategoryID) {
+		return
+	}
+	diets, ok := parseTags(c, "diets", service.DietTags, req.Diets)
+	if !ok {
+		return
+	}
+	allergens, ok := parseTags(c, "allergens", service.AllergenTags, req.Allergens)
+	if !ok {
+		return
+	}
+
+	plan, err := h.nutritionService.CreateNutrition(service.CreateNutritionDTO{
+		Title:       req.Title,
+		Description: req.Description,
+		Calories:    req.Calories,
+		Protein:     req.Protein,
+		Carbs:       req.Carbs,
+		Fats:        req.Fats,
+		CategoryID:  req.CategoryID,
+		Diets:       diets,
+		Allergens:   allergens,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newNutritionResponse(plan))
+}
+
+func (h *Handler) updateNutrition(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req nutritionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	plan, err := h.nutritionService.GetNutritionByID(id)
+	if err != nil {
+		notFound(c, err, "error.dish.not_found")
+		return
+	}
+	if !h.checkCategory(c, "category_id", req.CategoryID) {
+		return
+	}
+	diets, allergens := plan.Diets, plan.Allergens
+	if req.Diets != nil {
+		if diets, ok = parseTags(c, "diets", service.DietTags, req.Diets); !ok {
+			return
+		}
+	}
+	if req.Allergens != nil {
+		if allergens, ok = parseTags(c, "allergens", service.AllergenTags, req.Allergens); !ok {
+			return
+		}
+	}
+
+	err = h.nutritionService.UpdateNutrition(id, service.UpdateNutritionDTO{
+		Title:       req.Title,
+		Description: req.Description,
+		Calories:    req.Calories,
+		Protein:     req.Protein,
+		Carbs:       req.Carbs,
+		Fats:        req.Fats,
+		CategoryID:  req.CategoryID,
+		Diets:       diets,
+		Allergens:   allergens,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	h.getNutrition(c)
+}
+
+// parseTags собирает маску тегов из ключей запроса; неизвестный ключ - ошибка поля
+func parseTags(c *gin.Context, field string, tags []service.DietTag, keys []string) (int, bool) {
+	mask, err := service.ParseTagKeys(tags, keys)
+	if err != nil {
+		fieldError(c, field, err)
+		return 0, false
+	}
+	return mask, true
+}
+
+func (h *Handler) deleteNutrition(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.nutritionService.GetNutritionByID(id); err != nil {
+		notFound(c, err, "error.dish.not_found")
+		return
+	}
+	if err := h.nutritionService.DeleteNutrition(id); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ==================== КАТЕГОРИИ ====================
+
+type categoryRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+	Type        string `json:"type" binding:"required,oneof=training nutrition general"`
+}
+
+type categoryResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newCategoryResponse(cat *models.Category) categoryResponse {
+	return categoryResponse{
+		ID:          cat.ID,
+		Name:        cat.Name,
+		Description: cat.Description,
+		Type:        cat.Type,
+		CreatedAt:   cat.CreatedAt,
+		UpdatedAt:   cat.UpdatedAt,
+	}
+}
+
+func (h *Handler) listCategories(c *gin.Context) {
+	categories, err := h.categoryService.ListCategories()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	resp := make([]categoryResponse, 0, len(categories))
+	for _, cat := range categories {
+		resp = append(resp, newCategoryResponse(cat))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) getCategory(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	category, err := h.categoryService.GetCategoryByID(id)
+	if err != nil {
+		notFound(c, err, "error.category.not_found")
+		return
+	}
+	c.JSON(http.StatusOK, newCategoryResponse(category))
+}
+
+func (h *Handler) createCategory(c *gin.Context) {
+	var req categoryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	category, err := h.categoryService.CreateCategory(service.CreateCategoryDTO{
+		Name:        req.Name,
+		Description: req.Description,
+		Type:        req.Type,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newCategoryResponse(category))
+}
+
+func (h *Handler) updateCategory(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req categoryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, err := h.categoryService.GetCategoryByID(id); err != nil {
+		notFound(c, err, "error.category.not_found")
+		return
+	}
+	err := h.categoryService.UpdateCategory(id, service.UpdateCategoryDTO{
+		Name:        req.Name,
+		Description: req.Description,
+		Type:        req.Type,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	h.getCategory(c)
+}
+
+func (h *Handler) deleteCategory(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.categoryService.GetCategoryByID(id); err != nil {
+		notFound(c, err, "error.category.not_found")
+		return
+	}
+	if err := h.categoryService.DeleteCategory(id); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
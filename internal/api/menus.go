@@ -0,0 +1,383 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ==================== НЕДЕЛЬНЫЕ МЕНЮ ====================
+
+type weeklyMenuRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+}
+
+type menuDayRequest struct {
+	DayNumber int    `json:"day_number" binding:"required,min=1,max=7"`
+	DayName   string `json:"day_name" binding:"max=20"` // Пусто - по номеру дня
+}
+
+type dayMealRequest struct {
+	MealType    string `json:"meal_type" binding:"required,max=50"`
+	MealTime    string `json:"meal_time" binding:"max=50"`
+	NutritionID uint   `json:"nutrition_id" binding:"required"`
+	Notes       string `json:"notes"`
+}
+
+// generateMenuRequest - параметры генератора; нулевые поля - значения по умолчанию
+type generateMenuRequest struct {
+	Name         string   `json:"name" binding:"max=255"`
+	Calories     int      `json:"calories" binding:"required,min=800,max=6000"`
+	ProteinPct   int      `json:"protein_pct" binding:"min=0,max=100"`
+	FatsPct      int      `json:"fats_pct" binding:"min=0,max=100"`
+	CarbsPct     int      `json:"carbs_pct" binding:"min=0,max=100"`
+	MealTypes    []string `json:"meal_types" binding:"max=6,dive,required,max=50"`
+	ExcludeIDs   []uint   `json:"exclude_ids"`
+	ExcludeWords []string `json:"exclude_words"`
+	Diets        []string `json:"diets"`             // Ключи диет, которым должны соответствовать блюда
+	Allergens    []string `json:"exclude_allergens"` // Ключи аллергенов, которых не должно быть
+	MaxRepeats   int      `json:"max_repeats" binding:"min=0,max=7"`
+	Seed         int64    `json:"seed"`
+}
+
+// generatedMenuResponse - сохраненное меню, seed для повтора и КБЖУ дней против нормы
+type generatedMenuResponse struct {
+	Menu   weeklyMenuResponse `json:"menu"`
+	Seed   int64              `json:"seed"`
+	Target macrosResponse     `json:"target"`
+	Days   []macrosResponse   `json:"days"`
+}
+
+type macrosResponse struct {
+	Calories int     `json:"calories"`
+	Protein  float64 `json:"protein"`
+	Carbs    float64 `json:"carbs"`
+	Fats     float64 `json:"fats"`
+}
+
+func newMacrosResponse(m service.Macros) macrosResponse {
+	return macrosResponse{Calories: m.Calories, Protein: m.Protein, Carbs: m.Carbs, Fats: m.Fats}
+}
+
+type weeklyMenuResponse struct {
+	ID            uint              `json:"id"`
+	Name          string            `json:"name"`
+	Description   string            `json:"description"`
+	TotalCalories int               `json:"total_calories"`
+	Active        bool              `json:"active"`
+	Published     bool              `json:"published"`
+	GeneratorSeed int64             `json:"generator_seed,omitempty"` // Seed генератора; нет - меню составлено вручную
+	Days          []menuDayResponse `json:"days,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+type menuDayResponse struct {
+	ID            uint              `json:"id"`
+	MenuID        uint              `json:"menu_id"`
+	DayNumber     int               `json:"day_number"`
+	DayName       string            `json:"day_name"`
+	TotalCalories int               `json:"total_calories"`
+	Meals         []dayMealResponse `json:"meals"`
+}
+
+type dayMealResponse struct {
+	ID          uint               `json:"id"`
+	DayID       uint               `json:"day_id"`
+	MealType    string             `json:"meal_type"`
+	MealTime    string             `json:"meal_time"`
+	NutritionID uint               `json:"nutrition_id"`
+	Nutrition   *nutritionResponse `json:"nutrition,omitempty"`
+	Notes       string             `json:"notes"`
+}
+
+func newWeeklyMenuResponse(menu *models.WeeklyMenu) weeklyMenuResponse {
+	resp := weeklyMenuResponse{
+		ID:            menu.ID,
+		Name:          menu.Name,
+		Description:   menu.Description,
+		TotalCalories: menu.TotalCalories,
+		Active:        menu.Active,
+		Published:     menu.Published,
+		GeneratorSeed: menu.GeneratorSeed,
+		CreatedAt:     menu.CreatedAt,
+		UpdatedAt:     menu.UpdatedAt,
+	}
+	for i := range menu.Days {
+		resp.Days = append(resp.Days, newMenuDayResponse(&menu.Days[i]))
+	}
+	return resp
+}
+
+func newMenuDayResponse(day *models.MenuDay) menuDayResponse {
+	resp := menuDayResponse{
+		ID:            day.ID,
+		MenuID:        day.MenuID,
+		DayNumber:     day.DayNumber,
+		DayName:       day.DayName,
+		TotalCalories: day.TotalCalories,
+		Meals:         make([]dayMealResponse, 0, len(day.Meals)),
+	}
+	for i := range day.Meals {
+		resp.Meals = append(resp.Meals, newDayMealResponse(&day.Meals[i]))
+	}
+	return resp
+}
+
+func newDayMealResponse(meal *models.DayMeal) dayMealResponse {
+	resp := dayMealResponse{
+		ID:          meal.ID,
+		DayID:       meal.DayID,
+		MealType:    meal.MealType,
+		MealTime:    meal.MealTime,
+		NutritionID: meal.NutritionID,
+		Notes:       meal.Notes,
+	}
+	// Блюдо подгружается только в полном меню
+	if meal.Nutrition.ID != 0 {
+		nutrition := newNutritionResponse(&meal.Nutrition)
+		resp.Nutrition = &nutrition
+	}
+	return resp
+}
+
+func (h *Handler) listWeeklyMenus(c *gin.Context) {
+	menus, err := h.nutritionService.ListWeeklyMenus()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	resp := make([]weeklyMenuResponse, 0, len(menus))
+	for _, menu := range menus {
+		resp = append(resp, newWeeklyMenuResponse(menu))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// getWeeklyMenu отдает меню вместе с днями и приемами пищи
+func (h *Handler) getWeeklyMenu(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	menu, err := h.nutritionService.GetFullWeeklyMenu(id)
+	if err != nil {
+		notFound(c, err, "error.menu.not_found")
+		return
+	}
+	c.JSON(http.StatusOK, newWeeklyMenuResponse(menu))
+}
+
+func (h *Handler) createWeeklyMenu(c *gin.Context) {
+	var req weeklyMenuRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	menu, err := h.nutritionService.CreateWeeklyMenu(service.CreateWeeklyMenuDTO{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newWeeklyMenuResponse(menu))
+}
+
+// generateWeeklyMenu собирает меню на 7 дней из каталога под норму калорий и БЖУ
+func (h *Handler) generateWeeklyMenu(c *gin.Context) {
+	var req generateMenuRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	diets, ok := parseTags(c, "diets", service.DietTags, req.Diets)
+	if !ok {
+		return
+	}
+	allergens, ok := parseTags(c, "exclude_allergens", service.AllergenTags, req.Allergens)
+	if !ok {
+		return
+	}
+	result, err := h.generatorService.Generate(service.GenerateMenuDTO{
+		Name:         req.Name,
+		Calories:     req.Calories,
+		ProteinPct:   req.ProteinPct,
+		FatsPct:      req.FatsPct,
+		CarbsPct:     req.CarbsPct,
+		MealTypes:    req.MealTypes,
+		ExcludeIDs:   req.ExcludeIDs,
+		ExcludeWords: req.ExcludeWords,
+		Restrictions: models.DishFilter{Diets: diets, Allergens: allergens},
+		MaxRepeats:   req.MaxRepeats,
+		Seed:         req.Seed,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+
+	resp := generatedMenuResponse{
+		Menu:   newWeeklyMenuResponse(result.Menu),
+		Seed:   result.Seed,
+		Target: newMacrosResponse(result.Target),
+		Days:   make([]macrosResponse, 0, len(result.Days)),
+	}
+	for _, day := range result.Days {
+		resp.Days = append(resp.Days, newMacrosResponse(day))
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *Handler) deleteWeeklyMenu(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.nutritionService.GetWeeklyMenuByID(id); err != nil {
+		notFound(c, err, "error.menu.not_found")
+		return
+	}
+	if err := h.nutritionService.DeleteWeeklyMenu(id); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// activateWeeklyMenu делает меню активным, снимая активность с остальных
+func (h *Handler) activateWeeklyMenu(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.nutritionService.GetWeeklyMenuByID(id); err != nil {
+		notFound(c, err, "error.menu.not_found")
+		return
+	}
+	if err := h.nutritionService.ActivateWeeklyMenu(id); err != nil {
+		internalError(c, err)
+		return
+	}
+	h.getWeeklyMenu(c)
+}
+
+// publishWeeklyMenu открывает меню для выбора пользователями
+func (h *Handler) publishWeeklyMenu(c *gin.Context) {
+	h.setWeeklyMenuPublished(c, true)
+}
+
+// unpublishWeeklyMenu скрывает меню из выбора; назначенным пользователям оно остается
+func (h *Handler) unpublishWeeklyMenu(c *gin.Context) {
+	h.setWeeklyMenuPublished(c, false)
+}
+
+func (h *Handler) setWeeklyMenuPublished(c *gin.Context, published bool) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.nutritionService.GetWeeklyMenuByID(id); err != nil {
+		notFound(c, err, "error.menu.not_found")
+		return
+	}
+	if err := h.nutritionService.SetWeeklyMenuPublished(id, published); err != nil {
+		internalError(c, err)
+		return
+	}
+	h.getWeeklyMenu(c)
+}
+
+func (h *Handler) addMenuDay(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req menuDayRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, err := h.nutritionService.GetWeeklyMenuByID(id); err != nil {
+		notFound(c, err, "error.menu.not_found")
+		return
+	}
+
+	day, err := h.nutritionService.AddDayToWeeklyMenu(service.AddDayToMenuDTO{
+		MenuID:    id,
+		DayNumber: req.DayNumber,
+		DayName:   req.DayName,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newMenuDayResponse(day))
+}
+
+func (h *Handler) deleteMenuDay(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.nutritionService.GetMenuDayByID(id); err != nil {
+		notFound(c, err, "error.menu_day.not_found")
+		return
+	}
+	if err := h.nutritionService.DeleteDayFromMenu(id); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) addDayMeal(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req dayMealRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, err := h.nutritionService.GetMenuDayByID(id); err != nil {
+		notFound(c, err, "error.menu_day.not_found")
+		return
+	}
+	if _, err := h.nutritionService.GetNutritionByID(req.NutritionID); err != nil {
+		fieldError(c, "nutrition_id", i18n.NewError("error.dish.not_found"))
+		return
+	}
+
+	meal, err := h.nutritionService.AddMealToDay(service.AddMealToDayDTO{
+		DayID:       id,
+		MealType:    req.MealType,
+		MealTime:    req.MealTime,
+		NutritionID: req.NutritionID,
+		Notes:       req.Notes,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newDayMealResponse(meal))
+}
+
+func (h *Handler) deleteDayMeal(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.nutritionService.GetDayMealByID(id); err != nil {
+		notFound(c, err, "error.meal.not_found")
+		return
+	}
+	if err := h.nutritionService.DeleteMealFromDay(id); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,354 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ==================== УПРАЖНЕНИЯ ====================
+
+type exerciseRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	MuscleGroup string `json:"muscle_group" binding:"max=100"`
+	Equipment   string `json:"equipment" binding:"max=100"`
+	Description string `json:"description"`
+	YouTubeLink string `json:"youtube_link" binding:"omitempty,url"`
+}
+
+type exerciseResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	MuscleGroup string    `json:"muscle_group"`
+	Equipment   string    `json:"equipment"`
+	Description string    `json:"description"`
+	YouTubeLink string    `json:"youtube_link"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newExerciseResponse(e *models.Exercise) exerciseResponse {
+	return exerciseResponse{
+		ID:          e.ID,
+		Name:        e.Name,
+		MuscleGroup: e.MuscleGroup,
+		Equipment:   e.Equipment,
+		Description: e.Description,
+		YouTubeLink: e.YouTubeLink,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
+
+func (r exerciseRequest) dto() service.ExerciseDTO {
+	return service.ExerciseDTO{
+		Name:        r.Name,
+		MuscleGroup: r.MuscleGroup,
+		Equipment:   r.Equipment,
+		Description: r.Description,
+		YouTubeLink: r.YouTubeLink,
+	}
+}
+
+func (h *Handler) listExercises(c *gin.Context) {
+	exercises, err := h.programService.ListExercises()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	resp := make([]exerciseResponse, 0, len(exercises))
+	for _, e := range exercises {
+		resp = append(resp, newExerciseResponse(e))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) getExercise(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	exercise, err := h.programService.GetExerciseByID(id)
+	if err != nil {
+		notFound(c, err, "error.exercise.not_found")
+		return
+	}
+	c.JSON(http.StatusOK, newExerciseResponse(exercise))
+}
+
+func (h *Handler) createExercise(c *gin.Context) {
+	var req exerciseRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	exercise, err := h.programService.CreateExercise(req.dto())
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newExerciseResponse(exercise))
+}
+
+// updateExercise заменяет упражнение целиком
+func (h *Handler) updateExercise(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req exerciseRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, err := h.programService.GetExerciseByID(id); err != nil {
+		notFound(c, err, "error.exercise.not_found")
+		return
+	}
+	if err := h.programService.UpdateExercise(id, req.dto()); err != nil {
+		rejected(c, err)
+		return
+	}
+	h.getExercise(c)
+}
+
+func (h *Handler) deleteExercise(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.programService.GetExerciseByID(id); err != nil {
+		notFound(c, err, "error.exercise.not_found")
+		return
+	}
+	if err := h.programService.DeleteExercise(id); err != nil {
+		c.AbortWithStatusJSON(http.StatusConflict, errorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ==================== ПРОГРАММЫ ====================
+
+type programWeeksRequest struct {
+	Weeks int `json:"weeks" binding:"required,min=1"`
+}
+
+type programDayRequest struct {
+	Week      int    `json:"week"` // 0 - первая неделя
+	DayNumber int    `json:"day_number" binding:"required,min=1,max=7"`
+	Title     string `json:"title" binding:"max=100"`
+}
+
+// programExerciseRequest - упражнение дня: подходы, повторы, отдых, темп и вес
+type programExerciseRequest struct {
+	ExerciseID  uint   `json:"exercise_id" binding:"required"`
+	Sets        int    `json:"sets" binding:"required,min=1"`
+	Reps        string `json:"reps" binding:"required,max=20"`
+	RestSeconds int    `json:"rest_seconds" binding:"min=0"`
+	Tempo       string `json:"tempo" binding:"max=20"`
+	Weight      string `json:"weight" binding:"max=100"`
+}
+
+type programResponse struct {
+	TrainingID uint                 `json:"training_id"`
+	Title      string               `json:"title"`
+	Weeks      int                  `json:"weeks"`
+	Days       []programDayResponse `json:"days"`
+}
+
+type programDayResponse struct {
+	ID        uint                      `json:"id"`
+	Week      int                       `json:"week"`
+	DayNumber int                       `json:"day_number"`
+	Title     string                    `json:"title"`
+	Exercises []programExerciseResponse `json:"exercises"`
+}
+
+type programExerciseResponse struct {
+	ID           uint   `json:"id"`
+	ExerciseID   uint   `json:"exercise_id"`
+	ExerciseName string `json:"exercise_name,omitempty"`
+	Position     int    `json:"position"`
+	Sets         int    `json:"sets"`
+	Reps         string `json:"reps"`
+	RestSeconds  int    `json:"rest_seconds"`
+	Tempo        string `json:"tempo"`
+	Weight       string `json:"weight"`
+}
+
+func newProgramResponse(p *models.TrainingProgram) programResponse {
+	resp := programResponse{
+		TrainingID: p.ID,
+		Title:      p.Title,
+		Weeks:      p.Weeks,
+		Days:       make([]programDayResponse, 0, len(p.Days)),
+	}
+	for i := range p.Days {
+		resp.Days = append(resp.Days, newProgramDayResponse(&p.Days[i]))
+	}
+	return resp
+}
+
+func newProgramDayResponse(d *models.ProgramDay) programDayResponse {
+	resp := programDayResponse{
+		ID:        d.ID,
+		Week:      d.Week,
+		DayNumber: d.DayNumber,
+		Title:     d.Title,
+		Exercises: make([]programExerciseResponse, 0, len(d.Exercises)),
+	}
+	for i := range d.Exercises {
+		resp.Exercises = append(resp.Exercises, newProgramExerciseResponse(&d.Exercises[i]))
+	}
+	return resp
+}
+
+func newProgramExerciseResponse(e *models.ProgramExercise) programExerciseResponse {
+	return programExerciseResponse{
+		ID:           e.ID,
+		ExerciseID:   e.ExerciseID,
+		ExerciseName: e.Exercise.Name,
+		Position:     e.Position,
+		Sets:         e.Sets,
+		Reps:         e.Reps,
+		RestSeconds:  e.RestSeconds,
+		Tempo:        e.Tempo,
+		Weight:       e.Weight,
+	}
+}
+
+// getProgram - недели, дни и упражнения тренировки
+func (h *Handler) getProgram(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	program, err := h.programService.GetProgram(id)
+	if err != nil {
+		notFound(c, err, "error.training.not_found")
+		return
+	}
+	c.JSON(http.StatusOK, newProgramResponse(program))
+}
+
+func (h *Handler) setProgramWeeks(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req programWeeksRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, err := h.trainingService.GetTrainingByID(id); err != nil {
+		notFound(c, err, "error.training.not_found")
+		return
+	}
+	if err := h.programService.SetWeeks(id, req.Weeks); err != nil {
+		rejected(c, err)
+		return
+	}
+	h.getProgram(c)
+}
+
+func (h *Handler) addProgramDay(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req programDayRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, err := h.trainingService.GetTrainingByID(id); err != nil {
+		notFound(c, err, "error.training.not_found")
+		return
+	}
+	if req.Week == 0 {
+		req.Week = 1
+	}
+
+	day, err := h.programService.AddDay(service.ProgramDayDTO{
+		ProgramID: id,
+		Week:      req.Week,
+		DayNumber: req.DayNumber,
+		Title:     req.Title,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newProgramDayResponse(day))
+}
+
+func (h *Handler) deleteProgramDay(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.programService.GetDay(id); err != nil {
+		notFound(c, err, "error.program.day_not_found")
+		return
+	}
+	if err := h.programService.DeleteDay(id); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) addProgramExercise(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req programExerciseRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, err := h.programService.GetDay(id); err != nil {
+		notFound(c, err, "error.program.day_not_found")
+		return
+	}
+	if _, err := h.programService.GetExerciseByID(req.ExerciseID); err != nil {
+		fieldError(c, "exercise_id", i18n.NewError("error.exercise.not_found"))
+		return
+	}
+
+	item, err := h.programService.AddExercise(service.ProgramExerciseDTO{
+		DayID:       id,
+		ExerciseID:  req.ExerciseID,
+		Sets:        req.Sets,
+		Reps:        req.Reps,
+		RestSeconds: req.RestSeconds,
+		Tempo:       req.Tempo,
+		Weight:      req.Weight,
+	})
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	if exercise, err := h.programService.GetExerciseByID(item.ExerciseID); err == nil {
+		item.Exercise = *exercise
+	}
+	c.JSON(http.StatusCreated, newProgramExerciseResponse(item))
+}
+
+func (h *Handler) deleteProgramExercise(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.programService.GetProgramExercise(id); err != nil {
+		notFound(c, err, "error.program.exercise_not_found")
+		return
+	}
+	if err := h.programService.RemoveExercise(id); err != nil {
+		internalError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
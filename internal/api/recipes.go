@@ -0,0 +1,233 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ==================== ИНГРЕДИЕНТЫ ====================
+
+// ingredientRequest - пищевая ценность на 100 г
+type ingredientRequest struct {
+	Name     string  `json:"name" binding:"required,max=255"`
+	Section  string  `json:"section" binding:"max=64"`
+	Calories float64 `json:"calories" binding:"min=0,max=900"`
+	Protein  float64 `json:"protein" binding:"min=0,max=100"`
+	Carbs    float64 `json:"carbs" binding:"min=0,max=100"`
+	Fats     float64 `json:"fats" binding:"min=0,max=100"`
+}
+
+type ingredientResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Section   string    `json:"section"`
+	Calories  float64   `json:"calories"`
+	Protein   float64   `json:"protein"`
+	Carbs     float64   `json:"carbs"`
+	Fats      float64   `json:"fats"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newIngredientResponse(i *models.Ingredient) ingredientResponse {
+	return ingredientResponse{
+		ID:        i.ID,
+		Name:      i.Name,
+		Section:   i.Section,
+		Calories:  i.Calories,
+		Protein:   i.Protein,
+		Carbs:     i.Carbs,
+		Fats:      i.Fats,
+		CreatedAt: i.CreatedAt,
+		UpdatedAt: i.UpdatedAt,
+	}
+}
+
+func (r ingredientRequest) dto() service.IngredientDTO {
+	return service.IngredientDTO{
+		Name:     r.Name,
+		Section:  r.Section,
+		Calories: r.Calories,
+		Protein:  r.Protein,
+		Carbs:    r.Carbs,
+		Fats:     r.Fats,
+	}
+}
+
+func (h *Handler) listIngredients(c *gin.Context) {
+	ingredients, err := h.recipeService.ListIngredients()
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	resp := make([]ingredientResponse, 0, len(ingredients))
+	for _, i := range ingredients {
+		resp = append(resp, newIngredientResponse(i))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) getIngredient(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	ingredient, err := h.recipeService.GetIngredientByID(id)
+	if err != nil {
+		notFound(c, err, "error.ingredient.not_found", id)
+		return
+	}
+	c.JSON(http.StatusOK, newIngredientResponse(ingredient))
+}
+
+func (h *Handler) createIngredient(c *gin.Context) {
+	var req ingredientRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	ingredient, err := h.recipeService.CreateIngredient(req.dto())
+	if err != nil {
+		rejected(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newIngredientResponse(ingredient))
+}
+
+// updateIngredient заменяет ингредиент целиком; блюда с ним пересчитываются
+func (h *Handler) updateIngredient(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req ingredientRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, err := h.recipeService.GetIngredientByID(id); err != nil {
+		notFound(c, err, "error.ingredient.not_found", id)
+		return
+	}
+	if err := h.recipeService.UpdateIngredient(id, req.dto()); err != nil {
+		rejected(c, err)
+		return
+	}
+	h.getIngredient(c)
+}
+
+func (h *Handler) deleteIngredient(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.recipeService.GetIngredientByID(id); err != nil {
+		notFound(c, err, "error.ingredient.not_found", id)
+		return
+	}
+	if err := h.recipeService.DeleteIngredient(id); err != nil {
+		c.AbortWithStatusJSON(http.StatusConflict, errorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ==================== РЕЦЕПТЫ ====================
+
+type recipeRequest struct {
+	Steps string              `json:"steps"`
+	Items []recipeItemRequest `json:"items" binding:"max=50,dive"`
+}
+
+type recipeItemRequest struct {
+	IngredientID uint    `json:"ingredient_id" binding:"required"`
+	Grams        float64 `json:"grams" binding:"gt=0,max=5000"`
+}
+
+// recipeResponse - состав блюда и КБЖУ, посчитанные по нему
+type recipeResponse struct {
+	NutritionID uint                 `json:"nutrition_id"`
+	Steps       []string             `json:"steps"`
+	Items       []recipeItemResponse `json:"items"`
+	Calories    int                  `json:"calories"`
+	Protein     float64              `json:"protein"`
+	Carbs       float64              `json:"carbs"`
+	Fats        float64              `json:"fats"`
+}
+
+type recipeItemResponse struct {
+	IngredientID uint    `json:"ingredient_id"`
+	Ingredient   string  `json:"ingredient"`
+	Grams        float64 `json:"grams"`
+	Calories     int     `json:"calories"`
+}
+
+func newRecipeResponse(plan *models.NutritionPlan, items []*models.RecipeItem) recipeResponse {
+	resp := recipeResponse{
+		NutritionID: plan.ID,
+		Steps:       service.RecipeSteps(plan.Steps),
+		Items:       make([]recipeItemResponse, 0, len(items)),
+		Calories:    plan.Calories,
+		Protein:     plan.Protein,
+		Carbs:       plan.Carbs,
+		Fats:        plan.Fats,
+	}
+	if resp.Steps == nil {
+		resp.Steps = []string{}
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, recipeItemResponse{
+			IngredientID: item.IngredientID,
+			Ingredient:   item.Ingredient.Name,
+			Grams:        item.Grams,
+			Calories:     service.ItemCalories(item),
+		})
+	}
+	return resp
+}
+
+func (h *Handler) getRecipe(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	plan, err := h.nutritionService.GetNutritionByID(id)
+	if err != nil {
+		notFound(c, err, "error.dish.not_found")
+		return
+	}
+	items, err := h.recipeService.GetRecipe(id)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newRecipeResponse(plan, items))
+}
+
+// setRecipe заменяет состав и шаги блюда целиком и пересчитывает его КБЖУ
+func (h *Handler) setRecipe(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req recipeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if _, err := h.nutritionService.GetNutritionByID(id); err != nil {
+		notFound(c, err, "error.dish.not_found")
+		return
+	}
+
+	dto := service.SetRecipeDTO{Steps: req.Steps}
+	for _, item := range req.Items {
+		dto.Items = append(dto.Items, service.RecipeItemDTO{IngredientID: item.IngredientID, Grams: item.Grams})
+	}
+	if _, err := h.recipeService.SetRecipe(id, dto); err != nil {
+		rejected(c, err)
+		return
+	}
+	h.getRecipe(c)
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ==================== МЕНЮ ПОЛЬЗОВАТЕЛЕЙ ====================
+
+// userMenuRequest - назначение меню; 0 - вернуть пользователя к общему меню
+type userMenuRequest struct {
+	MenuID *uint `json:"menu_id" binding:"required"`
+}
+
+// userMenuResponse - меню, которое пользователь видит сейчас, и история назначений
+type userMenuResponse struct {
+	TelegramID int64                    `json:"telegram_id"`
+	Menu       *weeklyMenuResponse      `json:"menu"`
+	Source     string                   `json:"source"` // user, admin, auto или common - общее меню
+	History    []menuAssignmentResponse `json:"history"`
+}
+
+type menuAssignmentResponse struct {
+	MenuID     uint      `json:"menu_id"`
+	MenuName   string    `json:"menu_name,omitempty"`
+	Source     string    `json:"source"`
+	AssignedBy int64     `json:"assigned_by,omitempty"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// parseTelegramID находит пользователя по :telegram_id, иначе отвечает 400 или 404
+func (h *Handler) parseTelegramID(c *gin.Context) (*models.User, bool) {
+	telegramID, err := strconv.ParseInt(c.Param("telegram_id"), 10, 64)
+	if err != nil || telegramID == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, newErrorResponse("error.api.telegram_id"))
+		return nil, false
+	}
+	user, err := h.userService.GetUserByTelegramID(telegramID)
+	if err != nil {
+		notFound(c, err, "error.user.not_found")
+		return nil, false
+	}
+	return user, true
+}
+
+func (h *Handler) getUserMenu(c *gin.Context) {
+	user, ok := h.parseTelegramID(c)
+	if !ok {
+		return
+	}
+	menu, err := h.menuService.CurrentMenu(user.ID)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	history, err := h.menuService.History(user.ID, 0)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	resp := userMenuResponse{
+		TelegramID: user.TelegramID,
+		Source:     "common",
+		History:    make([]menuAssignmentResponse, 0, len(history)),
+	}
+	if menu != nil {
+		menuResp := newWeeklyMenuResponse(menu)
+		resp.Menu = &menuResp
+	}
+	if len(history) > 0 && history[0].MenuID != 0 && menu != nil && menu.ID == history[0].MenuID {
+		resp.Source = history[0].Source
+	}
+
+	names := map[uint]string{}
+	for _, assignment := range history {
+		if _, known := names[assignment.MenuID]; !known && assignment.MenuID != 0 {
+			if m, err := h.nutritionService.GetWeeklyMenuByID(assignment.MenuID); err == nil {
+				names[assignment.MenuID] = m.Name
+			} else {
+				names[assignment.MenuID] = ""
+			}
+		}
+		resp.History = append(resp.History, menuAssignmentResponse{
+			MenuID:     assignment.MenuID,
+			MenuName:   names[assignment.MenuID],
+			Source:     assignment.Source,
+			AssignedBy: assignment.AssignedBy,
+			AssignedAt: assignment.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// assignUserMenu назначает пользователю меню, в том числе неопубликованное
+func (h *Handler) assignUserMenu(c *gin.Context) {
+	user, ok := h.parseTelegramID(c)
+	if !ok {
+		return
+	}
+	var req userMenuRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if *req.MenuID != 0 {
+		if _, err := h.nutritionService.GetWeeklyMenuByID(*req.MenuID); err != nil {
+			notFound(c, err, "error.menu.not_found")
+			return
+		}
+	}
+	if err := h.menuService.AssignMenu(user.ID, *req.MenuID, 0); err != nil {
+		rejected(c, err)
+		return
+	}
+	h.getUserMenu(c)
+}
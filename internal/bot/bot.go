@@ -1,13 +1,21 @@
 package bot
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/alenapavlenkko/telegramfitnes/internal/admin"
+	"github.com/alenapavlenkko/telegramfitnes/internal/callback"
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
 	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
 	"github.com/alenapavlenkko/telegramfitnes/internal/service"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -19,13 +27,38 @@ type BotApp struct {
 	Admins   []int64
 	Handlers map[string]func(tgbotapi.Update)
 
-	trainingService  *service.TrainingService
-	nutritionService *service.NutritionService
-	categoryService  *service.CategoryService
-	userService      *service.UserService
+	trainingService    *service.TrainingService
+	nutritionService   *service.NutritionService
+	categoryService    *service.CategoryService
+	userService        *service.UserService
+	workoutService     *service.WorkoutService
+	foodDiaryService   *service.FoodDiaryService
+	measurementService *service.MeasurementService
+	reminderService    *service.ReminderService
+	recipeService      *service.RecipeService
+	shoppingService    *service.ShoppingService
+	menuService        *service.MenuAssignmentService
+	programService     *service.ProgramService
+	searchService      *service.SearchService
+
+	// Inline-кнопки пользователей и админов
+	callbacks *callback.Router
+
+	// Состояния пользовательских диалогов (запись тренировки и т.п.)
+	userFSM *fsm.Machine
+
+	// Шаблоны сообщений пользователям и каталог остальных текстов на разных языках
+	renderer *render.Renderer
+	catalog  *i18n.Catalog
+
+	// Язык ответов по ID чата (см. rememberLocale)
+	locales sync.Map
 
 	// Админ-панель
 	adminHandler *admin.AdminHandler
+
+	// Прием апдейтов через вебхук (nil - long polling)
+	webhook *webhookReceiver
 }
 
 // Конструктор бота
@@ -35,21 +68,62 @@ func NewBotApp(
 	nutritionService *service.NutritionService,
 	categoryService *service.CategoryService,
 	userService *service.UserService,
+	workoutService *service.WorkoutService,
+	foodDiaryService *service.FoodDiaryService,
+	measurementService *service.MeasurementService,
+	reminderService *service.ReminderService,
+	catalogService *service.CatalogService,
+	recipeService *service.RecipeService,
+	shoppingService *service.ShoppingService,
+	menuService *service.MenuAssignmentService,
+	generatorService *service.MenuGeneratorService,
+	programService *service.ProgramService,
+	searchService *service.SearchService,
+	adminFSM *admin.AdminFSM,
+	userFSM *fsm.Machine,
+	callbacks *callback.Router,
 	adminIDs []int64,
 ) (*BotApp, error) {
+	catalog, err := i18n.New()
+	if err != nil {
+		return nil, err
+	}
+	renderer, err := render.New(catalog)
+	if err != nil {
+		return nil, err
+	}
+
 	botAPI, err := tgbotapi.NewBotAPI(token)
 	if err != nil {
 		return nil, err
 	}
 
 	bot := &BotApp{
-		API:              botAPI,
-		Admins:           adminIDs,
-		trainingService:  trainingService,
-		nutritionService: nutritionService,
-		categoryService:  categoryService,
-		userService:      userService,
-	}
+		API:                botAPI,
+		Admins:             adminIDs,
+		trainingService:    trainingService,
+		nutritionService:   nutritionService,
+		categoryService:    categoryService,
+		userService:        userService,
+		workoutService:     workoutService,
+		foodDiaryService:   foodDiaryService,
+		measurementService: measurementService,
+		reminderService:    reminderService,
+		recipeService:      recipeService,
+		shoppingService:    shoppingService,
+		menuService:        menuService,
+		programService:     programService,
+		searchService:      searchService,
+		userFSM:            userFSM,
+		renderer:           renderer,
+		catalog:            catalog,
+		callbacks:          callbacks,
+	}
+
+	bot.callbacks.Guard(callback.Admin, func(u *tgbotapi.User) bool {
+		return bot.isAuthorized(u.ID, "admin")
+	})
+	bot.registerUserCallbacks()
 
 	// Создаем админ-хендлер с функцией отправки сообщений
 	bot.adminHandler = admin.NewAdminHandler(
@@ -57,10 +131,19 @@ func NewBotApp(
 		nutritionService,
 		categoryService,
 		userService,
-		bot.sendText, // передаем функцию отправки сообщений
+		catalogService,
+		menuService,
+		generatorService,
+		programService,
+		adminFSM,
+		callbacks,
+		bot,
+		bot.sendMarkdown, // передаем функцию отправки сообщений
 		func(chatID int64, text string, rows [][]tgbotapi.InlineKeyboardButton) {
 			bot.sendTextWithKeyboard(chatID, text, rows)
 		},
+		bot.sendView,
+		bot.tr,
 	)
 
 	// Добавьте проверку после создания
@@ -72,40 +155,146 @@ func NewBotApp(
 	return bot, nil
 }
 
-// Запуск бота
-func (b *BotApp) Run() {
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
-	updates := b.API.GetUpdatesChan(u)
-	log.Println("🤖 Bot started")
-
-	for update := range updates {
-		// Обработка CallbackQuery
-		if update.CallbackQuery != nil {
-			callback := update.CallbackQuery
-
-			// Проверяем, админ или обычный пользователь
-			if b.isAuthorized(callback.From.ID, "admin") {
-				b.adminHandler.HandleAdminCallback(callback)
-			} else {
-				// Обычные пользователи не должны получать callback
-				b.answerCallback(callback.ID, "⛔ Доступ запрещен")
+// DispatchConfig - параметры параллельной обработки апдейтов
+type DispatchConfig struct {
+	Workers         int           // Количество воркеров
+	QueueSize       int           // Размер очереди каждого воркера
+	ShutdownTimeout time.Duration // Сколько ждать обработки очередей при остановке
+}
+
+// Запуск бота. Апдейты берутся из вебхука, если он включен через EnableWebhook,
+// иначе через long polling. Работает, пока не отменен ctx, затем дожидается
+// обработки уже принятых апдейтов
+func (b *BotApp) Run(ctx context.Context, cfg DispatchConfig) {
+	dispatcher := NewDispatcher(cfg.Workers, cfg.QueueSize, b.handleUpdate)
+	dispatcher.Start()
+
+	var updates <-chan tgbotapi.Update
+	mode := ModeWebhook
+	if b.webhook != nil {
+		updates = b.webhook.updates
+	} else {
+		mode = ModePolling
+		// getUpdates не работает, пока установлен вебхук
+		if _, err := b.API.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+			log.Printf("⚠️ Failed to delete webhook: %v", err)
+		}
+		u := tgbotapi.NewUpdate(0)
+		u.Timeout = 60
+		updates = b.API.GetUpdatesChan(u)
+	}
+	log.Printf("🤖 Bot started (mode=%s, workers=%d, queue=%d)", mode, cfg.Workers, cfg.QueueSize)
+
+	// Апдейт, который не успел встать в очередь до сигнала остановки
+	var pending *tgbotapi.Update
+receive:
+	for {
+		select {
+		case <-ctx.Done():
+			break receive
+		case update, ok := <-updates:
+			if !ok {
+				break receive
+			}
+			if err := dispatcher.Submit(ctx, update); err != nil {
+				pending = &update
+				break receive
 			}
-			continue
 		}
+	}
+
+	log.Println("🛑 Stopping bot, draining update queues...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	submit := func(update tgbotapi.Update) {
+		if err := dispatcher.Submit(shutdownCtx, update); err != nil {
+			log.Printf("⚠️ Update %d dropped on shutdown: %v", update.UpdateID, err)
+		}
+	}
+	if pending != nil {
+		submit(*pending)
+	}
+	if b.webhook != nil {
+		// Апдейты, уже принятые вебхуком (Telegram получил 200), не теряем
+		b.webhook.shutdown(submit)
+	} else {
+		b.API.StopReceivingUpdates()
+		drainUpdates(updates, submit)
+	}
+
+	if err := dispatcher.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️ Shutdown timed out, some updates were not processed: %v", err)
+		return
+	}
+	log.Println("✅ Bot stopped")
+}
 
-		if update.Message == nil {
-			continue
+// drainUpdates передает диспетчеру апдейты, которые библиотека уже положила в канал.
+// Их offset подтвержден следующим запросом getUpdates, и Telegram их больше не пришлет.
+// Апдейты, которых в канале еще нет, не подтверждены: они придут снова после перезапуска
+func drainUpdates(updates <-chan tgbotapi.Update, submit func(tgbotapi.Update)) {
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			submit(update)
+		default:
+			return
 		}
+	}
+}
 
-		// Обработка команд
-		if update.Message.IsCommand() {
-			b.handleCommand(update)
-			continue
+// handleUpdate обрабатывает один апдейт. Вызывается из воркеров параллельно
+// для разных чатов, поэтому не должен трогать общее состояние без синхронизации
+func (b *BotApp) handleUpdate(update tgbotapi.Update) {
+	// Обработка CallbackQuery
+	if update.CallbackQuery != nil {
+		if update.CallbackQuery.Message != nil {
+			b.rememberLocale(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.From)
 		}
+		b.handleCallback(update.CallbackQuery)
+		return
+	}
+
+	// Поиск в inline-режиме: «@бот запрос» в любом чате
+	if update.InlineQuery != nil {
+		b.handleInlineQuery(update.InlineQuery)
+		return
+	}
+
+	if update.Message == nil || update.Message.From == nil {
+		return
+	}
+	b.rememberLocale(update.Message.Chat.ID, update.Message.From)
 
-		// Обработка обычных сообщений
-		b.handleRegularMessage(update)
+	// Обработка команд
+	if update.Message.IsCommand() {
+		b.handleCommand(update)
+		return
+	}
+
+	// Обработка обычных сообщений
+	b.handleRegularMessage(update)
+}
+
+// handleCallback направляет нажатие inline-кнопки через роутер и отвечает Telegram
+func (b *BotApp) handleCallback(query *tgbotapi.CallbackQuery) {
+	if query.Message == nil {
+		b.answerCallback(query.ID, "")
+		return
+	}
+
+	err := b.callbacks.Dispatch(query)
+	switch {
+	case err == nil:
+		b.answerCallback(query.ID, "")
+	case errors.Is(err, callback.ErrForbidden):
+		b.answerCallback(query.ID, b.tr(query.Message.Chat.ID).T("callback.forbidden"))
+	default:
+		log.Printf("[handleCallback] userID=%d, data='%s': %v", query.From.ID, query.Data, err)
+		b.answerCallback(query.ID, b.tr(query.Message.Chat.ID).T("callback.expired"))
 	}
 }
 
@@ -123,41 +312,62 @@ func (b *BotApp) isAdmin(userID int64) bool {
 func (b *BotApp) handleCommand(update tgbotapi.Update) {
 	cmd := update.Message.Command()
 	chatID := update.Message.Chat.ID
+	tr := b.tr(chatID)
 
 	switch cmd {
 	case "start":
-		_, err := b.authenticateUser(update)
+		user, err := b.authenticateUser(update.Message.From)
 		if err != nil {
-			b.sendText(chatID, "❌ Ошибка авторизации")
+			b.sendText(chatID, tr.T("error.auth"))
+			return
+		}
+		// Язык перечитывается из профиля; новому пользователю в него сохраняется язык Telegram
+		b.refreshLocale(chatID, update.Message.From)
+
+		// Переход по ссылке из карточки, которой поделились в другом чате
+		if payload := update.Message.CommandArguments(); payload != "" && b.openDeepLink(chatID, update.Message.From, payload) {
+			if !user.HasTargets() {
+				b.showMainMenu(chatID)
+				b.startOnboarding(chatID, update.Message.From)
+			}
 			return
 		}
 
 		// Отправляем приветственное сообщение
-		b.sendText(chatID, "👋 Рад вас видеть! Сейчас открою главное меню...")
+		b.sendText(chatID, tr.T("start.greeting"))
 		b.showMainMenu(chatID)
-	case "help":
-		helpMsg := `📚 *Помощь по использованию Fitness Bot*
 
-*Основные команды:*
-/start - Главное меню
-/help - Эта справка
-/admin - Панель администратора (только для админов)
-
-*Как пользоваться:*
-1. Используйте кнопки меню для навигации
-2. Тренировки - выбирайте программы упражнений
-3. Питание - изучайте планы питания
-4. Категории - фильтруйте контент по темам
-
-*Для администраторов:*
-• Добавляйте новые тренировки и блюда
-• Создавайте недельные меню
-• Управляйте категориями
-• Активируйте меню для пользователей
-
-*Поддержка:* Если возникли проблемы, свяжитесь с администратором.`
-
-		b.sendText(chatID, helpMsg)
+		// Новым пользователям предлагаем рассчитать норму калорий
+		if !user.HasTargets() {
+			b.startOnboarding(chatID, update.Message.From)
+		}
+	case "profile":
+		b.showProfile(chatID, update.Message.From)
+	case "measure":
+		b.startMeasure(chatID, update.Message.From)
+	case "reminders":
+		b.showReminderSettings(chatID, 0, update.Message.From)
+	case "shopping":
+		b.showShoppingList(chatID, 0, update.Message.From, 0)
+	case "diet":
+		b.showDietSettings(chatID, 0, update.Message.From)
+	case "language":
+		b.showLanguageSettings(chatID, 0, update.Message.From)
+	case "search":
+		if query := update.Message.CommandArguments(); query != "" {
+			b.runSearch(chatID, update.Message.From, query)
+		} else {
+			b.startSearch(chatID, update.Message.From)
+		}
+	case "progress":
+		days, ok := parseProgressDays(update.Message.CommandArguments())
+		if !ok {
+			b.sendText(chatID, tr.T("progress.usage"))
+			return
+		}
+		b.sendProgress(chatID, update.Message.From, days)
+	case "help":
+		b.sendText(chatID, b.render(tr, "help", nil))
 	case "admin":
 		log.Printf("[DEBUG] Admin command received from user ID: %d", update.Message.From.ID)
 		log.Printf("[DEBUG] Admin list: %v", b.Admins)
@@ -165,12 +375,16 @@ func (b *BotApp) handleCommand(update tgbotapi.Update) {
 		log.Printf("[DEBUG] Admin handler: %v", b.adminHandler)
 
 		if !b.isAuthorized(int64(update.Message.From.ID), "admin") {
-			b.sendText(chatID, "⛔ Недостаточно прав")
+			b.sendText(chatID, tr.T("error.forbidden"))
 			return
 		}
 
 		log.Printf("[DEBUG] Calling ShowAdminPanel for chat %d", chatID)
 		b.adminHandler.ShowAdminPanel(chatID)
+	case "cancel":
+		b.userFSM.DeleteState(update.Message.From.ID)
+		b.sendText(chatID, tr.T("dialog.cancelled"))
+		b.showMainMenu(chatID)
 	case "checkdb":
 		if b.isAdmin(int64(update.Message.From.ID)) {
 			b.checkDatabase(chatID)
@@ -185,7 +399,7 @@ func (b *BotApp) handleCommand(update tgbotapi.Update) {
 		log.Printf("[TEST] ChatID: %d", chatID)
 
 		// Просто отправьте тестовое сообщение
-		b.sendText(chatID, "Тест работает! Бот активен.")
+		b.sendText(chatID, tr.T("test.ok"))
 
 		// Попробуйте вызвать админ-панель напрямую
 		if b.adminHandler != nil {
@@ -195,33 +409,26 @@ func (b *BotApp) handleCommand(update tgbotapi.Update) {
 			b.sendText(chatID, "AdminHandler is nil!")
 		}
 	default:
-		b.sendText(chatID, "Неизвестная команда. Используйте /help")
+		b.sendText(chatID, tr.T("command.unknown"))
 	}
 }
 
 func (b *BotApp) checkDatabase(chatID int64) {
+	tr := b.tr(chatID)
 	trainings, err := b.trainingService.ListTrainings()
 	if err != nil {
-		b.sendText(chatID, "❌ Ошибка БД: "+err.Error())
+		b.sendFailure(chatID, tr.T("db.error"), err)
 		return
 	}
 
 	if len(trainings) == 0 {
-		b.sendText(chatID, "📭 В БД нет тренировок")
+		b.sendText(chatID, tr.T("db.empty"))
 		return
 	}
 
-	msg := fmt.Sprintf("✅ В БД найдено %d тренировок:\n\n", len(trainings))
-	for i, t := range trainings {
-		// Используем рефлексию для безопасного доступа к полям
-		title := t.Title
-		duration := t.Duration
-
-		msg += fmt.Sprintf("%d. %s - %d мин\n", i+1, title, duration)
-	}
-
-	b.sendText(chatID, msg)
+	b.sendText(chatID, b.render(tr, "db_check", trainings))
 }
+
 func (b *BotApp) handleRegularMessage(update tgbotapi.Update) {
 	userID := int64(update.Message.From.ID)
 	chatID := update.Message.Chat.ID
@@ -231,6 +438,10 @@ func (b *BotApp) handleRegularMessage(update tgbotapi.Update) {
 
 	// 1. Сначала проверяем состояние админ-панели
 	state, isAdminAction := b.adminHandler.GetState(userID)
+	if isAdminAction && update.Message.Document != nil {
+		b.adminHandler.HandleAdminDocument(chatID, userID, state, update.Message.Document)
+		return
+	}
 	if isAdminAction {
 		log.Println("Admin action detected")
 		// АДМИНСКИЕ ДЕЙСТВИЯ
@@ -238,112 +449,123 @@ func (b *BotApp) handleRegularMessage(update tgbotapi.Update) {
 		return
 	}
 
-	// 2. Проверяем, является ли пользователь админом
+	// 2. Пользователь отвечает на вопрос бота (например, длительность тренировки)
+	//    Кнопка главного меню прерывает диалог и обрабатывается как обычно
+	if userState, inDialog := b.userFSM.GetState(userID); inDialog {
+		if _, isMenu := b.mainMenuKey(text); !isMenu {
+			b.handleUserState(chatID, update.Message.From, userState, text)
+			return
+		}
+		b.userFSM.DeleteState(userID)
+	}
+
+	// 3. Проверяем, является ли пользователь админом
 	if b.isAdmin(userID) {
 		log.Println("Admin regular message")
 		// Админ, но не в режиме админ-панели
-		b.handleAdminRegularMessage(chatID, text)
+		b.handleAdminRegularMessage(chatID, update.Message.From, text)
 		return
 	}
 
-	// 3. ОБЫЧНЫЕ ПОЛЬЗОВАТЕЛИ
+	// 4. ОБЫЧНЫЕ ПОЛЬЗОВАТЕЛИ
 	log.Println("User action")
-	b.handleUserActions(chatID, text)
+	b.handleUserActions(chatID, update.Message.From, text)
 }
 
 // Обработка обычных сообщений админа (не в админ-панели)
-func (b *BotApp) handleAdminRegularMessage(chatID int64, text string) {
+func (b *BotApp) handleAdminRegularMessage(chatID int64, from *tgbotapi.User, text string) {
 	log.Printf("[handleAdminRegularMessage] chatID=%d, text='%s'", chatID, text)
 	// Админ может вводить специальные команды
 	switch text {
 	case "/panel":
 		b.adminHandler.ShowAdminPanel(chatID)
+		return
 	case "/trainings":
 		b.adminHandler.ShowTrainingsAdmin(chatID)
+		return
 	case "/nutrition":
 		b.adminHandler.ShowNutritionAdmin(chatID)
+		return
 	case "/categories":
 		b.adminHandler.ShowCategoriesAdmin(chatID)
-	case "🏋️ Тренировки":
-		// Админ тоже может смотреть тренировки как обычный пользователь
-		log.Println("[handleAdminRegularMessage] Showing trainings for admin")
-		b.showTrainingsForUser(chatID)
-	case "🍎 Питание":
-		log.Println("[handleAdminRegularMessage] Showing nutrition for admin")
-		b.showNutritionForUser(chatID)
-	case "📅 Недельное меню":
-		b.showWeeklyMenuForUser(chatID)
-	case "📂 Категории":
-		log.Println("[handleAdminRegularMessage] Showing categories for admin")
-		b.showCategoriesForUser(chatID)
-	case "ℹ️ Помощь":
-		b.sendText(chatID, "🏃‍♀️ Fitness Bot Помощь:\n\nВыберите раздел в меню:\n"+
-			"• Тренировки - программы упражнений\n"+
-			"• Питание - планы питания\n"+
-			"• Категории - фильтрация контента\n\n"+
-			"Используйте /start для возврата в меню")
+		return
 	case "/foodlist":
 		b.adminHandler.ShowNutritionListForSelection(chatID)
-	default:
+		return
+	}
+
+	// Админ тоже может пользоваться разделами как обычный пользователь
+	key, _ := b.mainMenuKey(text)
+	switch key {
+	case "menu.help":
+		b.sendText(chatID, b.tr(chatID).T("help.admin"))
+	case "":
 		// Если админ просто что-то пишет, показываем главное меню
 		b.showMainMenu(chatID)
+	default:
+		b.handleMainMenu(chatID, from, key)
 	}
 }
 
 // Обработка действий обычных пользователей
-func (b *BotApp) handleUserActions(chatID int64, text string) {
+func (b *BotApp) handleUserActions(chatID int64, from *tgbotapi.User, text string) {
 	log.Printf("[handleUserActions] chatID=%d, text='%s'", chatID, text)
 
-	switch text {
-	case "🏋️ Тренировки":
-		log.Println("[handleUserActions] Calling showTrainingsForUser")
-		b.showTrainingsForUser(chatID)
-	case "🍎 Питание":
-		log.Println("[handleUserActions] Calling showNutritionForUser")
-		b.showNutritionForUser(chatID)
-	case "📅 Недельное меню":
-		log.Println("[handleUserActions] Calling showWeeklyMenuForUser")
-		b.showWeeklyMenuForUser(chatID)
-	case "📂 Категории":
-		log.Println("[handleUserActions] Calling showCategoriesForUser")
-		b.showCategoriesForUser(chatID)
-	case "ℹ️ Помощь":
-		helpMsg := `📚 *Помощь по использованию Fitness Bot*
-
-*Как пользоваться ботом:*
-🏋️ *Тренировки* - Готовые программы упражнений с видео
-🍎 *Питание* - Планы питания и недельные меню
-📂 *Категории* - Фильтрация контента по темам
-
-*Основные команды:*
-/start - Главное меню
-/help - Подробная справка
-
-*Советы:*
-• Регулярно проверяйте обновления
-• Составляйте свое меню на неделю
-• Следуйте программам тренировок
-
-*Нужна помощь?*
-Используйте команду /help для подробной информации
-или свяжитесь с администратором.`
-
-		b.sendText(chatID, helpMsg)
-	case "/testtrainings":
+	if text == "/testtrainings" {
 		b.testTrainings(chatID)
+		return
+	}
+
+	key, ok := b.mainMenuKey(text)
+	if !ok {
+		b.showMainMenu(chatID)
+		return
+	}
+	b.handleMainMenu(chatID, from, key)
+}
+
+// handleMainMenu открывает раздел по ключу кнопки главного меню (см. mainMenuKey)
+func (b *BotApp) handleMainMenu(chatID int64, from *tgbotapi.User, key string) {
+	log.Printf("[handleMainMenu] chatID=%d, key=%s", chatID, key)
+
+	switch key {
+	case "menu.trainings":
+		b.showTrainingsForUser(chatID, 0, 0)
+	case "menu.nutrition":
+		b.showNutritionForUser(chatID, 0, from, 0)
+	case "menu.weekly_menu":
+		b.showWeeklyMenuForUser(chatID, 0, from, 0)
+	case "menu.categories":
+		b.showCategoriesForUser(chatID, 0)
+	case "menu.workouts":
+		b.showWorkoutHistory(chatID, from)
+	case "menu.diary":
+		b.showFoodDiary(chatID, from)
+	case "menu.measurements":
+		b.showMeasurements(chatID, from)
+	case "menu.reminders":
+		b.showReminderSettings(chatID, 0, from)
+	case "menu.profile":
+		b.showProfile(chatID, from)
+	case "menu.help":
+		b.sendText(chatID, b.render(b.tr(chatID), "menu_help", nil))
 	default:
 		b.showMainMenu(chatID)
 	}
 }
 
 // Методы для пользователей
-func (b *BotApp) showTrainingsForUser(chatID int64) {
-	log.Printf("[showTrainingsForUser] START for chatID=%d", chatID)
+
+// showTrainingsForUser - каталог тренировок по страницам с кнопкой «Выполнено» у каждой.
+// Если messageID не 0, страница обновляется на месте
+func (b *BotApp) showTrainingsForUser(chatID int64, messageID int, page int) {
+	log.Printf("[showTrainingsForUser] START for chatID=%d, page=%d", chatID, page)
+	tr := b.tr(chatID)
 
 	trainings, err := b.trainingService.ListTrainings()
 	if err != nil {
 		log.Printf("[showTrainingsForUser] ERROR loading trainings: %v", err)
-		b.sendText(chatID, "❌ Не удалось загрузить тренировки")
+		b.sendText(chatID, tr.T("trainings.error"))
 		return
 	}
 
@@ -351,191 +573,172 @@ func (b *BotApp) showTrainingsForUser(chatID int64) {
 
 	if len(trainings) == 0 {
 		log.Println("[showTrainingsForUser] No trainings found")
-		b.sendText(chatID, "🏋️ Тренировок пока нет. Следите за обновлениями!")
+		b.sendText(chatID, tr.T("trainings.empty"))
 		return
 	}
 
-	escape := func(s string) string {
-		specialChars := []string{"_", "*", "[", "]", "(", ")", "~", "`", ">", "#", "+", "-", "=", "|", "{", "}", ".", "!"}
-		for _, c := range specialChars {
-			s = strings.ReplaceAll(s, c, "\\"+c)
-		}
-		return s
-	}
-
-	msg := "🏋️ *Доступные тренировки:*\n\n"
+	items := make([]pageItem, 0, len(trainings))
 	for i, t := range trainings {
-		log.Printf("[showTrainingsForUser] Processing training %d: ID=%d, Title='%s'",
-			i+1, t.ID, t.Title)
-
-		msg += fmt.Sprintf("%d. *%s* - %d мин\n", i+1, escape(t.Title), t.Duration)
-		if t.Description != "" {
-			msg += fmt.Sprintf("   %s\n", escape(t.Description))
-		}
-		if t.YouTubeLink != "" {
-			link := strings.ReplaceAll(t.YouTubeLink, "[", "\\[")
-			link = strings.ReplaceAll(link, "]", "\\]")
-			msg += fmt.Sprintf("   🎥 [Смотреть на YouTube](%s)\n", link)
+		text := b.render(tr, "trainings.item", render.TrainingItem{Number: i + 1, Training: t})
+
+		// Кнопка «Выполнено» для каждой тренировки
+		row := tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("trainings.done", i+1, t.Title), "done", t.ID),
+		)
+		// Тренировки с расписанными днями открываются по упражнениям
+		if b.hasProgram(t.ID) {
+			row = append(row, b.userButton(tr.T("trainings.program"), "prog", t.ID, 1))
 		}
-		msg += "\n"
+		items = append(items, pageItem{text: text, rows: [][]tgbotapi.InlineKeyboardButton{row}})
 	}
 
-	log.Printf("[showTrainingsForUser] Sending message of length %d", len(msg))
-	b.sendText(chatID, msg)
+	pages := paginate(b.render(tr, "trainings.header", nil), items, b.render(tr, "trainings.footer", nil), messageLimit)
+	b.sendPage(chatID, messageID, pages, page, "trs_pg", nil)
 }
 
-func (b *BotApp) showNutritionForUser(chatID int64) {
-	nutritionList, err := b.nutritionService.ListNutrition()
+// showNutritionForUser - каталог блюд по страницам с учетом ограничений питания.
+// Если messageID не 0, страница обновляется на месте
+func (b *BotApp) showNutritionForUser(chatID int64, messageID int, from *tgbotapi.User, page int) {
+	tr := b.tr(chatID)
+
+	// Блюда, которые пользователь исключил, не показываем
+	filter := b.userRestrictions(from)
+	nutritionList, err := b.nutritionService.ListNutritionFor(filter)
 	if err != nil {
-		b.sendText(chatID, "❌ Не удалось загрузить планы питания")
+		b.sendText(chatID, tr.T("nutrition.error"))
 		return
 	}
 
 	if len(nutritionList) == 0 {
-		b.sendText(chatID, "🍎 Планов питания пока нет. Следите за обновлениями!")
+		if !filter.Empty() {
+			b.sendText(chatID, tr.T("nutrition.empty_filtered"))
+			return
+		}
+		b.sendText(chatID, tr.T("nutrition.empty"))
 		return
 	}
 
-	msg := "🍎 *Планы питания:*\n\n"
+	// Если норма рассчитана, показываем долю блюда в ней
+	user := b.userWithTargets(from)
+
+	header := render.NutritionHeader{Filtered: !filter.Empty()}
+	if user != nil {
+		header.CalorieTarget = user.CalorieTarget
+	}
+
+	items := make([]pageItem, 0, len(nutritionList))
 	for i, n := range nutritionList {
-		msg += fmt.Sprintf("%d. *%s* - %d ккал\n", i+1, n.Title, n.Calories)
-		if n.Description != "" {
-			msg += fmt.Sprintf("   %s\n", n.Description)
+		item := render.DishItem{Number: i + 1, Dish: n}
+		if user != nil {
+			item.Share = budgetShare(n.Calories, user.CalorieTarget)
 		}
-		msg += fmt.Sprintf("   Б:%.1fг, У:%.1fг, Ж:%.1fг\n\n", n.Protein, n.Carbs, n.Fats)
+		items = append(items, pageItem{text: b.render(tr, "nutrition.item", item)})
 	}
 
-	b.sendText(chatID, msg)
+	pages := paginate(b.render(tr, "nutrition.header", header), items, "", messageLimit)
+	b.sendPage(chatID, messageID, pages, page, "nus_pg", nil)
 }
 
-func (b *BotApp) showCategoriesForUser(chatID int64) {
-	categories, err := b.categoryService.ListCategories()
+// render собирает текст сообщения по шаблону на языке tr. Ошибка шаблона - ошибка в коде,
+// поэтому пользователь получает общее сообщение, а подробности уходят в лог
+func (b *BotApp) render(tr *i18n.Localizer, name string, data any) string {
+	text, err := b.renderer.Render(tr.Locale(), name, data)
 	if err != nil {
-		b.sendText(chatID, "❌ Не удалось загрузить категории")
-		return
+		log.Printf("[render] %s: ERROR: %v", name, err)
+		return tr.T("error.render")
 	}
+	return text
+}
 
-	if len(categories) == 0 {
-		b.sendText(chatID, "📂 Категорий пока нет")
+// sendView отправляет сообщение по шаблону name на языке чата, с кнопками, если они есть
+func (b *BotApp) sendView(chatID int64, name string, data any, rows [][]tgbotapi.InlineKeyboardButton) {
+	text := b.render(b.tr(chatID), name, data)
+	if len(rows) == 0 {
+		b.sendText(chatID, text)
 		return
 	}
+	b.sendWithKeyboard(chatID, text, rows)
+}
 
-	// Группируем по типам
-	trainingCats := []string{}
-	nutritionCats := []string{}
-	generalCats := []string{}
-
-	for _, c := range categories {
-		switch c.Type {
-		case "training":
-			trainingCats = append(trainingCats, c.Name)
-		case "nutrition":
-			nutritionCats = append(nutritionCats, c.Name)
-		default:
-			generalCats = append(generalCats, c.Name)
-		}
-	}
+// sendFailure сообщает об ошибке вместе с ее текстом (например, ошибкой проверки из сервиса)
+func (b *BotApp) sendFailure(chatID int64, message string, err error) {
+	tr := b.tr(chatID)
+	b.sendText(chatID, b.render(tr, "failure", render.Failure{Message: message, Reason: tr.Error(err)}))
+}
 
-	msg := "📂 *Категории:*\n\n"
+// Отправка сообщений. Текст - HTML, собранный через render или из каталога сообщений:
+// данные пользователей в нем уже экранированы
+func (b *BotApp) sendText(chatID int64, text string) {
+	log.Printf("[sendText] chatID=%d, text length=%d", chatID, len(text))
 
-	if len(trainingCats) > 0 {
-		msg += "🏋️ *Тренировки:*\n"
-		for _, name := range trainingCats {
-			msg += fmt.Sprintf("• %s\n", name)
+	// Слишком длинный текст отправляем несколькими сообщениями
+	if textLength(text) > messageLimit {
+		for _, part := range splitHTML(text, messageLimit) {
+			b.sendText(chatID, part)
 		}
-		msg += "\n"
+		return
 	}
 
-	if len(nutritionCats) > 0 {
-		msg += "🍎 *Питание:*\n"
-		for _, name := range nutritionCats {
-			msg += fmt.Sprintf("• %s\n", name)
-		}
-		msg += "\n"
-	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = render.ParseMode
 
-	if len(generalCats) > 0 {
-		msg += "📋 *Общие:*\n"
-		for _, name := range generalCats {
-			msg += fmt.Sprintf("• %s\n", name)
-		}
+	if _, err := b.API.Send(msg); err != nil {
+		log.Printf("[sendText] ERROR: %v", err)
+	} else {
+		log.Printf("[sendText] SUCCESS")
 	}
-
-	b.sendText(chatID, msg)
 }
 
-// Отправка сообщений
-func (b *BotApp) sendText(chatID int64, text string) {
-	log.Printf("[sendText] chatID=%d, text length=%d", chatID, len(text))
-
+// sendMarkdown отправляет текст в разметке Markdown (v1), а если она не разобралась -
+// без разметки. Так пишет сообщения админ-панель
+func (b *BotApp) sendMarkdown(chatID int64, text string) {
 	msg := tgbotapi.NewMessage(chatID, text)
-
-	// Для обычных сообщений тоже включаем Markdown
-	// Но экранируем спецсимволы в тексте пользователя
-	msg.ParseMode = "Markdown"
+	msg.ParseMode = tgbotapi.ModeMarkdown
 
 	if _, err := b.API.Send(msg); err != nil {
-		log.Printf("[sendText] ERROR: %v", err)
+		log.Printf("[sendMarkdown] ERROR: %v", err)
 
-		// Если Markdown вызывает ошибку, пробуем отправить без него
-		msg2 := tgbotapi.NewMessage(chatID, text)
-		msg2.ParseMode = ""
-		if _, err2 := b.API.Send(msg2); err2 != nil {
-			log.Printf("[sendText] ERROR without Markdown: %v", err2)
+		msg.ParseMode = ""
+		if _, err2 := b.API.Send(msg); err2 != nil {
+			log.Printf("[sendMarkdown] ERROR without Markdown: %v", err2)
 		}
-	} else {
-		log.Printf("[sendText] SUCCESS")
 	}
 }
 
+// editMessage заменяет текст и кнопки сообщения. Длинные списки нужно заранее
+// разбивать на страницы (paginate): сообщение нельзя заменить несколькими
 func (b *BotApp) editMessage(chatID int64, messageID int, text string, rows [][]tgbotapi.InlineKeyboardButton) {
+	if textLength(text) > messageLimit {
+		log.Printf("[editMessage] text length %d exceeds the limit, truncating", textLength(text))
+		text = splitHTML(text, messageLimit)[0]
+	}
+
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
 	editMsg := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, keyboard)
-	editMsg.ParseMode = "Markdown"
-	b.API.Send(editMsg)
+	editMsg.ParseMode = render.ParseMode
+
+	if _, err := b.API.Send(editMsg); err != nil {
+		// Повторное нажатие той же кнопки - не ошибка
+		if strings.Contains(err.Error(), "message is not modified") {
+			return
+		}
+		log.Printf("[editMessage] ERROR: %v", err)
+	}
 }
 
 // Главное меню
 func (b *BotApp) showMainMenu(chatID int64) {
-	welcomeMsg := `🏃‍♀️ *Добро пожаловать в Fitness Bot!*
-
-🌟 *Ваш персональный помощник на пути к здоровью и красоте!*
-
-🎯 *Просто нажмите кнопку:*
-
-🏋️ *Тренировки* → Готовые программы упражнений с видеоуроками
-🍎 *Питание* → Планы питания с подсчетом калорий
-📂 *Категории* → Удобная навигация по материалам
-ℹ️ *Помощь* → Инструкция и справка
-
-📅 *Что вас ждет:*
-✅ Ежедневные тренировки с пошаговыми инструкциями
-✅ Сбалансированное питание с учетом КБЖУ
-✅ Недельные меню - готовый рацион на 7 дней
-✅ Регулярные обновления - новый контент каждый день
-
-🚀 *Начните прямо сейчас!*
-1. Выберите раздел в меню ниже 👇
-2. Изучайте программы тренировок
-3. Составляйте свое идеальное меню
-4. Достигайте результатов вместе с нами!
-
----
-
-*"Путь в тысячу миль начинается с первого шага"*
-*Сделайте свой первый шаг к здоровью прямо сейчас!* 💪`
+	tr := b.tr(chatID)
+	welcomeMsg := b.render(tr, "welcome", nil)
+	button := func(key string) tgbotapi.KeyboardButton {
+		return tgbotapi.NewKeyboardButton(tr.T(key))
+	}
 	keyboard := tgbotapi.NewReplyKeyboard(
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🏋️ Тренировки"),
-			tgbotapi.NewKeyboardButton("🍎 Питание"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("📅 Недельное меню"),
-			tgbotapi.NewKeyboardButton("📂 Категории"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("ℹ️ Помощь"),
-		),
+		tgbotapi.NewKeyboardButtonRow(button("menu.trainings"), button("menu.nutrition")),
+		tgbotapi.NewKeyboardButtonRow(button("menu.weekly_menu"), button("menu.categories")),
+		tgbotapi.NewKeyboardButtonRow(button("menu.diary"), button("menu.workouts")),
+		tgbotapi.NewKeyboardButtonRow(button("menu.measurements"), button("menu.profile")),
+		tgbotapi.NewKeyboardButtonRow(button("menu.reminders"), button("menu.help")),
 	)
 
 	// Добавляем настройки клавиатуры
@@ -544,11 +747,12 @@ func (b *BotApp) showMainMenu(chatID int64) {
 
 	msg := tgbotapi.NewMessage(chatID, welcomeMsg)
 	msg.ReplyMarkup = keyboard
-	msg.ParseMode = "Markdown"
+	msg.ParseMode = render.ParseMode
 
 	b.API.Send(msg)
 }
 
+// sendTextWithKeyboard отправляет текст без разметки с inline-клавиатурой (для админ-панели)
 func (b *BotApp) sendTextWithKeyboard(chatID int64, text string, rows [][]tgbotapi.InlineKeyboardButton) {
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
 	msg := tgbotapi.NewMessage(chatID, text)
@@ -556,6 +760,36 @@ func (b *BotApp) sendTextWithKeyboard(chatID int64, text string, rows [][]tgbota
 	b.API.Send(msg)
 }
 
+// sendOrEdit обновляет сообщение messageID, а если его нет (0) - отправляет новое
+func (b *BotApp) sendOrEdit(chatID int64, messageID int, text string, rows [][]tgbotapi.InlineKeyboardButton) {
+	if messageID != 0 {
+		b.editMessage(chatID, messageID, text, rows)
+		return
+	}
+	b.sendWithKeyboard(chatID, text, rows)
+}
+
+// sendWithKeyboard - как sendText, но с inline-клавиатурой
+func (b *BotApp) sendWithKeyboard(chatID int64, text string, rows [][]tgbotapi.InlineKeyboardButton) {
+	// Длинный текст уходит несколькими сообщениями, кнопки - под последним
+	if textLength(text) > messageLimit {
+		parts := splitHTML(text, messageLimit)
+		for _, part := range parts[:len(parts)-1] {
+			b.sendText(chatID, part)
+		}
+		text = parts[len(parts)-1]
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	msg.ParseMode = render.ParseMode
+
+	if _, err := b.API.Send(msg); err != nil {
+		log.Printf("[sendWithKeyboard] ERROR: %v", err)
+	}
+}
+
 func (b *BotApp) answerCallback(callbackID string, text string) {
 	b.API.Request(tgbotapi.NewCallback(callbackID, text))
 }
@@ -574,9 +808,8 @@ func ParseAdminIDs(ids string) []int64 {
 	return result
 }
 
-func (b *BotApp) authenticateUser(update tgbotapi.Update) (*models.User, error) {
-	tgUser := update.Message.From
-
+// authenticateUser находит пользователя по Telegram ID или регистрирует нового
+func (b *BotApp) authenticateUser(tgUser *tgbotapi.User) (*models.User, error) {
 	user, err := b.userService.GetUserByTelegramID(int64(tgUser.ID))
 	if err == nil {
 		return user, nil
@@ -606,21 +839,6 @@ func (b *BotApp) isAuthorized(userID int64, requiredRole string) bool {
 	return user.Role == requiredRole
 }
 
-// Отправка Markdown-сообщений с экранированием спецсимволов
-func (b *BotApp) sendMarkdown(chatID int64, text string) {
-	// Экранируем спецсимволы MarkdownV2
-	escape := func(s string) string {
-		specialChars := []string{"_", "*", "[", "]", "(", ")", "~", "`", ">", "#", "+", "-", "=", "|", "{", "}", ".", "!"}
-		for _, c := range specialChars {
-			s = strings.ReplaceAll(s, c, "\\"+c)
-		}
-		return s
-	}
-	msg := tgbotapi.NewMessage(chatID, escape(text))
-	msg.ParseMode = "MarkdownV2"
-	b.API.Send(msg)
-}
-
 func (b *BotApp) testTrainings(chatID int64) {
 	log.Println("[testTrainings] Using hardcoded data")
 
@@ -639,19 +857,12 @@ func (b *BotApp) testTrainings(chatID int64) {
 		},
 	}
 
-	msg := "🏋️ *ТЕСТ: Доступные тренировки:*\n\n"
+	tr := b.tr(chatID)
+	items := []string{tr.T("test.trainings")}
 	for i, t := range trainings {
-		msg += fmt.Sprintf("%d. *%s* - %d мин\n", i+1, t.Title, t.Duration)
-		if t.Description != "" {
-			msg += fmt.Sprintf("   %s\n", t.Description)
-		}
-		if t.YouTubeLink != "" {
-			msg += fmt.Sprintf("   🎥 Ссылка на YouTube\n")
-		}
-		msg += "\n"
+		items = append(items, b.render(tr, "trainings.item", render.TrainingItem{Number: i + 1, Training: t}))
 	}
-
-	b.sendText(chatID, msg)
+	b.sendText(chatID, joinSections(items...))
 }
 
 func testTrainingFlow(bot *BotApp, chatID int64) {
@@ -662,55 +873,21 @@ func testTrainingFlow(bot *BotApp, chatID int64) {
 	log.Printf("Service result: %d trainings, error: %v", len(trainings), err)
 
 	// 2. Пробуем отправить простое сообщение
-	bot.sendText(chatID, "Тестовое сообщение 123")
+	bot.sendText(chatID, bot.tr(chatID).T("test.message"))
 
 	// 3. Пробуем показать тренировки
-	bot.showTrainingsForUser(chatID)
+	bot.showTrainingsForUser(chatID, 0, 0)
 }
-func (b *BotApp) showNutritionListForSelection(chatID int64) {
-	nutritionList, err := b.nutritionService.ListNutrition()
-	if err != nil {
-		b.sendText(chatID, "❌ Не удалось загрузить список блюд")
-		return
-	}
 
-	if len(nutritionList) == 0 {
-		b.sendText(chatID, "🍎 Блюд пока нет. Сначала добавьте блюда через админ-панель питания.")
-		return
-	}
+// showWeeklyMenuForUser - недельное меню пользователя по страницам, по дню на элемент.
+// Если messageID не 0, страница обновляется на месте
+func (b *BotApp) showWeeklyMenuForUser(chatID int64, messageID int, from *tgbotapi.User, page int) {
+	log.Printf("[showWeeklyMenuForUser] START for chatID=%d, page=%d", chatID, page)
+	tr := b.tr(chatID)
 
-	// Экранируем спецсимволы для Markdown
-	escape := func(s string) string {
-		specialChars := []string{"_", "*", "[", "]", "(", ")", "~", "`", ">", "#", "+", "-", "=", "|", "{", "}", ".", "!"}
-		for _, c := range specialChars {
-			s = strings.ReplaceAll(s, c, "\\"+c)
-		}
-		return s
-	}
-
-	msg := "🍎 *Список блюд (ID - Название - Калории):*\n\n"
-	for _, n := range nutritionList {
-		msg += fmt.Sprintf("*%d*\\. %s \\- %d ккал\n",
-			n.ID, escape(n.Title), n.Calories)
-	}
-
-	msg += "\nПри добавлении приема пищи введите ID блюда из этого списка\\."
-	b.sendText(chatID, msg)
-}
-
-func (b *BotApp) showWeeklyMenuForUser(chatID int64) {
-	log.Printf("[showWeeklyMenuForUser] START for chatID=%d", chatID)
-
-	// Получаем активное недельное меню
-	activeMenu, err := b.nutritionService.GetActiveWeeklyMenu()
-	if err != nil {
-		log.Printf("[showWeeklyMenuForUser] ERROR: %v", err)
-		b.sendText(chatID, "❌ Не удалось загрузить недельное меню")
-		return
-	}
-
-	if activeMenu == nil {
-		b.sendText(chatID, "📭 Активное недельное меню еще не создано.\nОжидайте обновлений от администратора!")
+	// Получаем меню пользователя: личное или общее активное
+	user, activeMenu, ok := b.loadUserMenu(chatID, from)
+	if !ok {
 		return
 	}
 
@@ -718,23 +895,21 @@ func (b *BotApp) showWeeklyMenuForUser(chatID int64) {
 	fullMenu, err := b.nutritionService.GetFullWeeklyMenu(activeMenu.ID)
 	if err != nil {
 		log.Printf("[showWeeklyMenuForUser] ERROR loading full menu: %v", err)
-		b.sendText(chatID, "📅 *"+activeMenu.Name+"*\n\n"+activeMenu.Description)
+		b.sendText(chatID, b.render(tr, "menu.header", render.MenuHeader{Menu: activeMenu, Source: b.menuSourceLine(tr, user)}))
 		return
 	}
 
-	// Формируем красивое сообщение
-	msg := fmt.Sprintf("📅 *%s*\n\n", fullMenu.Name)
-	if fullMenu.Description != "" {
-		msg += fmt.Sprintf("%s\n\n", fullMenu.Description)
+	// Если норма рассчитана, сравниваем с ней каждый день
+	hasTargets := user.HasTargets()
+	header := render.MenuHeader{Menu: fullMenu, Source: b.menuSourceLine(tr, user)}
+	if hasTargets {
+		header.CalorieTarget = user.CalorieTarget
 	}
 
-	msg += fmt.Sprintf("🍽 Всего калорий за неделю: *%d ккал*\n\n", fullMenu.TotalCalories)
-
+	items := []pageItem{}
 	if len(fullMenu.Days) == 0 {
-		msg += "📭 Дни меню еще не добавлены\n"
+		items = append(items, pageItem{text: b.render(tr, "menu.empty", nil)})
 	} else {
-		msg += "📋 *Рацион на неделю:*\n\n"
-
 		// Группируем дни по номерам для удобного доступа
 		daysMap := make(map[int]models.MenuDay)
 		for _, day := range fullMenu.Days {
@@ -744,29 +919,48 @@ func (b *BotApp) showWeeklyMenuForUser(chatID int64) {
 		// Показываем дни от 1 до 7
 		for dayNum := 1; dayNum <= 7; dayNum++ {
 			if day, exists := daysMap[dayNum]; exists {
-				msg += fmt.Sprintf("*%d. %s* - %d ккал\n",
-					day.DayNumber, day.DayName, day.TotalCalories)
-
-				if len(day.Meals) > 0 {
-					for _, meal := range day.Meals {
-						if meal.Nutrition.ID != 0 {
-							msg += fmt.Sprintf("   🕐 %s: %s - %s (%d ккал)\n",
-								meal.MealTime, meal.MealType,
-								meal.Nutrition.Title, meal.Nutrition.Calories)
-							if meal.Notes != "" {
-								msg += fmt.Sprintf("     📝 %s\n", meal.Notes)
-							}
-						}
-					}
-				} else {
-					msg += "   📭 Приемы пищи не добавлены\n"
+				view := render.MenuDay{Day: day}
+				if hasTargets {
+					view.Share = budgetShare(day.TotalCalories, user.CalorieTarget)
 				}
-				msg += "\n"
+				items = append(items, pageItem{text: b.render(tr, "menu.day", view)})
 			}
 		}
 	}
 
-	msg += "\n🍎 *Приятного аппетита!* 🍴"
+	if conflicts := service.MenuConflicts(fullMenu, user.Restrictions()); len(conflicts) > 0 {
+		items = append(items, pageItem{text: b.render(tr, "menu.conflicts", conflictLines(tr, conflicts))})
+	}
 
-	b.sendText(chatID, msg)
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("weekly.pdf"), "menu_pdf"),
+			b.userButton(tr.T("weekly.shopping"), "shop"),
+		),
+		tgbotapi.NewInlineKeyboardRow(b.userButton(tr.T("weekly.choose_other"), "menu_list")),
+	}
+	pages := paginate(b.render(tr, "menu.header", header), items, b.render(tr, "menu.footer", nil), messageLimit)
+	b.sendPage(chatID, messageID, pages, page, "menu_pg", rows)
+}
+
+// sendWeeklyMenuPDF отправляет недельное меню пользователя PDF-документом
+func (b *BotApp) sendWeeklyMenuPDF(chatID int64, from *tgbotapi.User) {
+	user, activeMenu, ok := b.loadUserMenu(chatID, from)
+	if !ok {
+		return
+	}
+
+	tr := b.tr(chatID)
+	data, err := b.nutritionService.WeeklyMenuPDF(activeMenu.ID, user.CalorieTarget, tr)
+	if err != nil {
+		log.Printf("[sendWeeklyMenuPDF] ERROR: %v", err)
+		b.sendText(chatID, tr.T("weekly.pdf_error"))
+		return
+	}
+
+	name := fmt.Sprintf("menu-%s.pdf", time.Now().Format("2006-01-02"))
+	if err := b.SendDocument(chatID, name, data, "📅 "+activeMenu.Name); err != nil {
+		log.Printf("[sendWeeklyMenuPDF] send ERROR: %v", err)
+		b.sendText(chatID, tr.T("weekly.pdf_send_error"))
+	}
 }
@@ -0,0 +1,242 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Сколько элементов категории показывать на одной странице
+const categoryPageSize = 5
+
+// Значки типов категорий в порядке показа
+var categoryTypeIcons = []struct{ key, icon string }{
+	{"training", "🏋️"},
+	{"nutrition", "🍎"},
+	{"general", "📋"},
+}
+
+// showCategoriesForUser - список категорий кнопками.
+// Если messageID не 0, сообщение обновляется на месте
+func (b *BotApp) showCategoriesForUser(chatID int64, messageID int) {
+	tr := b.tr(chatID)
+	categories, err := b.categoryService.ListCategories()
+	if err != nil {
+		b.sendText(chatID, tr.T("category.list_error"))
+		return
+	}
+
+	if len(categories) == 0 {
+		b.sendText(chatID, tr.T("category.list_empty"))
+		return
+	}
+
+	// Группируем по типам, неизвестный тип считаем общим
+	byType := map[string][]*models.Category{}
+	for _, c := range categories {
+		kind := c.Type
+		if kind != "training" && kind != "nutrition" {
+			kind = "general"
+		}
+		byType[kind] = append(byType[kind], c)
+	}
+
+	msg := b.render(tr, "categories", nil)
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for _, t := range categoryTypeIcons {
+		for _, c := range byType[t.key] {
+			label := fmt.Sprintf("%s · %s", t.icon, c.Name)
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				b.userButton(label, "cat", c.ID),
+			))
+		}
+	}
+	b.sendOrEdit(chatID, messageID, msg, rows)
+}
+
+// showCategory открывает категорию: тренировки, блюда или выбор раздела для общей
+func (b *BotApp) showCategory(chatID int64, messageID int, from *tgbotapi.User, categoryID uint) {
+	tr := b.tr(chatID)
+	category, err := b.categoryService.GetCategoryByID(categoryID)
+	if err != nil {
+		b.sendText(chatID, tr.T("category.not_found"))
+		return
+	}
+
+	switch category.Type {
+	case "training":
+		b.showCategoryTrainings(chatID, messageID, category.ID, 0)
+	case "nutrition":
+		b.showCategoryNutrition(chatID, messageID, from, category.ID, 0)
+	default:
+		msg := b.render(tr, "category", category)
+		rows := [][]tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardRow(
+				b.userButton(tr.T("menu.trainings"), "cat_tr", category.ID, 0),
+				b.userButton(tr.T("menu.nutrition"), "cat_nu", category.ID, 0),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				b.userButton(tr.T("category.back"), "cats"),
+			),
+		}
+		b.sendOrEdit(chatID, messageID, msg, rows)
+	}
+}
+
+// showCategoryTrainings - страница тренировок категории
+func (b *BotApp) showCategoryTrainings(chatID int64, messageID int, categoryID uint, page int) {
+	tr := b.tr(chatID)
+	category, err := b.categoryService.GetCategoryByID(categoryID)
+	if err != nil {
+		b.sendText(chatID, tr.T("category.not_found"))
+		return
+	}
+	trainings, total, err := b.trainingService.ListTrainingsByCategory(categoryID, page, categoryPageSize)
+	if err != nil {
+		log.Printf("[showCategoryTrainings] ERROR: %v", err)
+		b.sendText(chatID, tr.T("trainings.error"))
+		return
+	}
+
+	msg := b.render(tr, "category.trainings", render.CategoryPage{Category: category, Total: total})
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for _, t := range trainings {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("category.training_button", t.Title, t.Duration), "tr", t.ID, categoryID, page),
+		))
+	}
+	if nav := b.pageButtons("cat_tr", categoryID, page, total); len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.userButton(tr.T("category.back"), "cats"),
+	))
+	b.sendOrEdit(chatID, messageID, msg, rows)
+}
+
+// showCategoryNutrition - страница блюд категории, подходящих под ограничения пользователя
+func (b *BotApp) showCategoryNutrition(chatID int64, messageID int, from *tgbotapi.User, categoryID uint, page int) {
+	tr := b.tr(chatID)
+	category, err := b.categoryService.GetCategoryByID(categoryID)
+	if err != nil {
+		b.sendText(chatID, tr.T("category.not_found"))
+		return
+	}
+	filter := b.userRestrictions(from)
+	plans, total, err := b.nutritionService.ListNutritionByCategory(categoryID, filter, page, categoryPageSize)
+	if err != nil {
+		log.Printf("[showCategoryNutrition] ERROR: %v", err)
+		b.sendText(chatID, tr.T("category.dishes_error"))
+		return
+	}
+
+	msg := b.render(tr, "category.nutrition", render.CategoryPage{Category: category, Total: total, Filtered: !filter.Empty()})
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for _, n := range plans {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("category.dish_button", n.Title, n.Calories), "nu", n.ID, categoryID, page),
+		))
+	}
+	if nav := b.pageButtons("cat_nu", categoryID, page, total); len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.userButton(tr.T("category.back"), "cats"),
+	))
+	b.sendOrEdit(chatID, messageID, msg, rows)
+}
+
+// showTrainingCard - карточка тренировки с возвратом на страницу категории
+func (b *BotApp) showTrainingCard(chatID int64, messageID int, trainingID, categoryID uint, page int) {
+	tr := b.tr(chatID)
+	training, err := b.trainingService.GetTrainingByID(trainingID)
+	if err != nil {
+		b.sendText(chatID, tr.T("training.not_found"))
+		return
+	}
+
+	card := render.TrainingCard{Training: training, HasProgram: b.hasProgram(training.ID)}
+	actions := tgbotapi.NewInlineKeyboardRow(
+		b.userButton(tr.T("training.done"), "done", training.ID),
+	)
+	if card.HasProgram {
+		actions = append(tgbotapi.NewInlineKeyboardRow(b.userButton(tr.T("trainings.program"), "prog", training.ID, 1)), actions...)
+	}
+	rows := [][]tgbotapi.InlineKeyboardButton{actions}
+	// Из поиска карточка открывается без категории - возвращаться некуда
+	if categoryID != 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("common.back"), "cat_tr", categoryID, page),
+		))
+	}
+	b.sendOrEdit(chatID, messageID, b.render(tr, "training.card", card), rows)
+}
+
+// showNutritionCard - карточка блюда с долей дневной нормы
+func (b *BotApp) showNutritionCard(chatID int64, messageID int, from *tgbotapi.User, nutritionID, categoryID uint, page int) {
+	tr := b.tr(chatID)
+	dish, err := b.nutritionService.GetNutritionByID(nutritionID)
+	if err != nil {
+		b.sendText(chatID, tr.T("dish.not_found"))
+		return
+	}
+
+	card := render.DishCard{
+		Dish:      dish,
+		Conflicts: conflictReasons(tr, service.DishConflicts(dish, b.userRestrictions(from))),
+		Steps:     service.RecipeSteps(dish.Steps),
+	}
+	if user := b.userWithTargets(from); user != nil {
+		card.Share = budgetShare(dish.Calories, user.CalorieTarget)
+	}
+	if card.Recipe, err = b.recipeService.GetRecipe(dish.ID); err != nil {
+		log.Printf("[showNutritionCard] recipe ERROR: %v", err)
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("dish.to_diary"), "diary_dish", dish.ID),
+		),
+	}
+	if categoryID != 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("common.back"), "cat_nu", categoryID, page),
+		))
+	}
+	b.sendOrEdit(chatID, messageID, b.render(tr, "dish.card", card), rows)
+}
+
+// addDishToDiary начинает запись в дневник с уже выбранным блюдом
+func (b *BotApp) addDishToDiary(chatID int64, from *tgbotapi.User, nutritionID uint) {
+	state := &fsm.State{
+		Action:   "diary_add",
+		Step:     1,
+		TempData: make(fsm.TempData),
+	}
+	b.selectDiaryDish(chatID, from, state, nutritionID)
+}
+
+// pageButtons - кнопки «назад/вперед» для страницы page категории categoryID
+func (b *BotApp) pageButtons(action string, categoryID uint, page int, total int64) []tgbotapi.InlineKeyboardButton {
+	pages := int((total + categoryPageSize - 1) / categoryPageSize)
+	if pages <= 1 {
+		return nil
+	}
+
+	row := []tgbotapi.InlineKeyboardButton{}
+	if page > 0 {
+		row = append(row, b.userButton("◀️", action, categoryID, page-1))
+	}
+	row = append(row, b.userButton(fmt.Sprintf("%d/%d", page+1, pages), "noop"))
+	if page < pages-1 {
+		row = append(row, b.userButton("▶️", action, categoryID, page+1))
+	}
+	return row
+}
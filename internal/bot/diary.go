@@ -0,0 +1,223 @@
+package bot
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Варианты порций на кнопках (в десятых долях, чтобы обойтись без точки в callback)
+var diaryPortionButtons = []int{5, 10, 15, 20}
+
+// showFoodDiary - дневник питания за сегодня с промежуточными итогами
+func (b *BotApp) showFoodDiary(chatID int64, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	day, err := b.foodDiaryService.GetDay(user.ID, b.userNow(user.ID))
+	if err != nil {
+		log.Printf("[showFoodDiary] ERROR: %v", err)
+		b.sendText(chatID, tr.T("diary.error"))
+		return
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for i, e := range day.Entries {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("diary.delete_button", i+1, e.Title), "diary_del", e.ID),
+		))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.userButton(tr.T("diary.add"), "diary_add"),
+		b.userButton(tr.T("diary.summary"), "diary_summary"),
+	))
+
+	b.sendWithKeyboard(chatID, b.render(tr, "diary", render.Diary{Day: day, User: user}), rows)
+}
+
+// startDiaryAdd начинает диалог добавления блюда в дневник
+func (b *BotApp) startDiaryAdd(chatID int64, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	b.userFSM.SetState(from.ID, &fsm.State{
+		Action:   "diary_add",
+		Step:     1,
+		TempData: make(fsm.TempData),
+	})
+	b.sendText(chatID, tr.T("diary.add_prompt"))
+}
+
+// handleDiaryAdd - текстовые ответы в диалоге добавления блюда
+func (b *BotApp) handleDiaryAdd(chatID int64, from *tgbotapi.User, state *fsm.State, text string) {
+	tr := b.tr(chatID)
+	switch state.Step {
+	case 1:
+		dishes, err := b.foodDiaryService.FindDishes(text, 8)
+		if err != nil {
+			b.sendText(chatID, tr.T("diary.query_invalid"))
+			return
+		}
+		if len(dishes) == 0 {
+			b.sendText(chatID, tr.T("diary.not_found"))
+			return
+		}
+		if len(dishes) == 1 {
+			b.selectDiaryDish(chatID, from, state, dishes[0].ID)
+			return
+		}
+
+		rows := [][]tgbotapi.InlineKeyboardButton{}
+		for _, d := range dishes {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				b.userButton(tr.T("diary.dish_button", d.Title, d.Calories), "diary_pick", d.ID),
+			))
+		}
+		b.sendWithKeyboard(chatID, tr.T("diary.choose"), rows)
+	case 2:
+		portion, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(text), ",", "."), 64)
+		if err != nil || portion <= 0 || portion > 10 {
+			b.sendText(chatID, tr.T("diary.portion_invalid"))
+			return
+		}
+		b.finishDiaryAdd(chatID, from, state, portion)
+	}
+}
+
+// selectDiaryDish запоминает блюдо и спрашивает размер порции
+func (b *BotApp) selectDiaryDish(chatID int64, from *tgbotapi.User, state *fsm.State, nutritionID uint) {
+	tr := b.tr(chatID)
+	dish, err := b.nutritionService.GetNutritionByID(nutritionID)
+	if err != nil {
+		b.sendText(chatID, tr.T("dish.not_found"))
+		return
+	}
+
+	view := render.DiaryDish{Dish: dish}
+	if user := b.userWithTargets(from); user != nil {
+		view.Share = budgetShare(dish.Calories, user.CalorieTarget)
+	}
+
+	state.EntityID = dish.ID
+	state.Step = 2
+	b.userFSM.SetState(from.ID, state)
+
+	row := []tgbotapi.InlineKeyboardButton{}
+	for _, p := range diaryPortionButtons {
+		row = append(row, b.userButton("× "+formatPortion(float64(p)/10), "diary_portion", p))
+	}
+	b.sendWithKeyboard(chatID, b.render(tr, "diary.dish", view), [][]tgbotapi.InlineKeyboardButton{row})
+}
+
+// finishDiaryAdd записывает блюдо и показывает промежуточный итог дня
+func (b *BotApp) finishDiaryAdd(chatID int64, from *tgbotapi.User, state *fsm.State, portion float64) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	entry, err := b.foodDiaryService.AddEntry(service.AddDiaryEntryDTO{
+		UserID:      user.ID,
+		NutritionID: state.EntityID,
+		Portion:     portion,
+		Date:        b.userNow(user.ID),
+	})
+	if err != nil {
+		b.sendFailure(chatID, tr.T("diary.add_error"), err)
+		return
+	}
+	b.userFSM.DeleteState(from.ID)
+
+	view := render.DiaryAdded{Entry: entry, User: user}
+	if day, err := b.foodDiaryService.GetDay(user.ID, b.userNow(user.ID)); err == nil {
+		view.Day = day
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("diary.add_more"), "diary_add"),
+			b.userButton(tr.T("diary.open"), "diary"),
+		),
+	}
+	b.sendWithKeyboard(chatID, b.render(tr, "diary.added", view), rows)
+}
+
+// deleteDiaryEntry удаляет запись и показывает обновленный дневник
+func (b *BotApp) deleteDiaryEntry(chatID int64, from *tgbotapi.User, entryID uint) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	if err := b.foodDiaryService.DeleteEntry(user.ID, entryID); err != nil {
+		b.sendText(chatID, tr.T("diary.entry_not_found"))
+		return
+	}
+	b.sendText(chatID, tr.T("diary.deleted"))
+	b.showFoodDiary(chatID, from)
+}
+
+// showDiarySummary - итог дня: КБЖУ, доли макронутриентов в калориях и самые калорийные блюда
+func (b *BotApp) showDiarySummary(chatID int64, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	day, err := b.foodDiaryService.GetDay(user.ID, b.userNow(user.ID))
+	if err != nil {
+		b.sendText(chatID, tr.T("diary.error"))
+		return
+	}
+
+	b.sendText(chatID, b.render(tr, "diary.summary", diarySummary(day, user)))
+}
+
+// diarySummary считает для итога дня доли БЖУ в калориях, самое калорийное
+// блюдо и, если у пользователя рассчитана норма, долю нормы
+func diarySummary(day *service.DiaryDay, user *models.User) render.DiarySummary {
+	view := render.DiarySummary{Diary: render.Diary{Day: day, User: user}}
+	if len(day.Entries) == 0 {
+		return view
+	}
+
+	t := day.Totals
+	if user != nil && user.HasTargets() {
+		view.Share = budgetShare(t.Calories, user.CalorieTarget)
+	}
+
+	// Доли БЖУ в энергии: 4 ккал/г белков и углеводов, 9 ккал/г жиров
+	if energy := t.Protein*4 + t.Carbs*4 + t.Fats*9; energy > 0 {
+		view.ProteinShare = t.Protein * 4 / energy * 100
+		view.FatsShare = t.Fats * 9 / energy * 100
+		view.CarbsShare = t.Carbs * 4 / energy * 100
+	}
+
+	view.Top = day.Entries[0]
+	for _, e := range day.Entries[1:] {
+		if e.Calories > view.Top.Calories {
+			view.Top = e
+		}
+	}
+	return view
+}
+
+// formatPortion печатает порцию без лишних нулей: 1, 1.5, 0.75
+func formatPortion(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
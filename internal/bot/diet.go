@@ -0,0 +1,134 @@
+package bot
+
+import (
+	"log"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// userRestrictions - ограничения питания пользователя; пустые, если пользователь не найден
+func (b *BotApp) userRestrictions(from *tgbotapi.User) models.DishFilter {
+	if from == nil {
+		return models.DishFilter{}
+	}
+	user, err := b.userService.GetUserByTelegramID(from.ID)
+	if err != nil {
+		return models.DishFilter{}
+	}
+	return user.Restrictions()
+}
+
+// showDietSettings - экран ограничений питания: диеты и исключенные аллергены.
+// Если messageID не 0, экран обновляется на месте
+func (b *BotApp) showDietSettings(chatID int64, messageID int, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	b.sendOrEdit(chatID, messageID, b.render(tr, "diet.settings", b.dietSettingsView(user)), b.dietSettingsButtons(tr, user))
+}
+
+// updateDietSettings переключает диету или аллерген и перерисовывает экран
+func (b *BotApp) updateDietSettings(chatID int64, messageID int, from *tgbotapi.User, change func(diets, allergens int) (int, int)) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	diets, allergens := change(user.Diets, user.ExcludedAllergens)
+	if _, err := b.userService.SetDietRestrictions(from.ID, diets, allergens); err != nil {
+		log.Printf("[updateDietSettings] ERROR: %v", err)
+		b.sendText(chatID, tr.T("diet.save_error"))
+		return
+	}
+	b.showDietSettings(chatID, messageID, from)
+}
+
+// dietSettingsView - ограничения пользователя и число конфликтов с его меню
+func (b *BotApp) dietSettingsView(user *models.User) render.DietSettings {
+	view := render.DietSettings{User: user}
+	if menu, err := b.menuService.CurrentMenu(user.ID); err == nil && menu != nil {
+		conflicts, err := b.menuService.Conflicts(menu.ID, user.Restrictions())
+		if err != nil {
+			log.Printf("[dietSettingsView] ERROR: %v", err)
+		}
+		view.Conflicts = len(conflicts)
+	}
+	return view
+}
+
+// dietSettingsButtons - переключатели диет и аллергенов
+func (b *BotApp) dietSettingsButtons(tr *i18n.Localizer, user *models.User) [][]tgbotapi.InlineKeyboardButton {
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	row := []tgbotapi.InlineKeyboardButton{}
+	for i, tag := range service.DietTags {
+		label := tr.T("tag." + tag.Key)
+		if user.Diets&tag.Bit != 0 {
+			label = "✅ " + label
+		}
+		row = append(row, b.userButton(label, "diet_d", i))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = []tgbotapi.InlineKeyboardButton{}
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	row = []tgbotapi.InlineKeyboardButton{}
+	for i, tag := range service.AllergenTags {
+		name := strings.ToLower(tr.T("tag." + tag.Key))
+		label := tr.T("diet.allergen_allowed", name)
+		if user.ExcludedAllergens&tag.Bit != 0 {
+			label = "🚫 " + name
+		}
+		row = append(row, b.userButton(label, "diet_a", i))
+		if len(row) == 3 {
+			rows = append(rows, row)
+			row = []tgbotapi.InlineKeyboardButton{}
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.userButton(tr.T("weekly.choose"), "menu_list"),
+	))
+	return rows
+}
+
+// conflictLines - приемы пищи меню, которые пользователь исключил, с коротким названием дня
+func conflictLines(tr *i18n.Localizer, conflicts []service.MenuConflict) []render.ConflictLine {
+	lines := make([]render.ConflictLine, 0, len(conflicts))
+	for _, c := range conflicts {
+		lines = append(lines, render.ConflictLine{
+			Day:          weekdayShort(tr, c.DayNumber),
+			Reasons:      conflictReasons(tr, c.Conflict),
+			MenuConflict: c,
+		})
+	}
+	return lines
+}
+
+// conflictReasons - подписи причин, по которым блюдо не подходит пользователю
+func conflictReasons(tr *i18n.Localizer, conflict service.DishConflict) []string {
+	var reasons []string
+	for _, label := range render.TagLabels(tr, service.AllergenTags, conflict.Allergens) {
+		reasons = append(reasons, strings.ToLower(label))
+	}
+	for _, label := range render.TagLabels(tr, service.DietTags, conflict.Diets) {
+		reasons = append(reasons, tr.T("diet.not_marked", strings.ToLower(label)))
+	}
+	return reasons
+}
@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Dispatcher раздает апдейты пулу воркеров.
+// Апдейты одного чата всегда попадают в очередь одного и того же воркера,
+// поэтому внутри чата порядок сохраняется, а разные чаты обрабатываются параллельно.
+type Dispatcher struct {
+	queues []chan tgbotapi.Update
+	handle func(tgbotapi.Update)
+
+	wg        sync.WaitGroup
+	startOnce sync.Once
+	closeOnce sync.Once
+}
+
+// NewDispatcher создает диспетчер с workers воркерами и очередью queueSize на каждого
+func NewDispatcher(workers, queueSize int, handle func(tgbotapi.Update)) *Dispatcher {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+
+	queues := make([]chan tgbotapi.Update, workers)
+	for i := range queues {
+		queues[i] = make(chan tgbotapi.Update, queueSize)
+	}
+
+	return &Dispatcher{
+		queues: queues,
+		handle: handle,
+	}
+}
+
+// Start запускает воркеры
+func (d *Dispatcher) Start() {
+	d.startOnce.Do(func() {
+		for i, queue := range d.queues {
+			d.wg.Add(1)
+			go d.worker(i, queue)
+		}
+	})
+}
+
+// Submit ставит апдейт в очередь его чата. Если очередь заполнена - ждет места,
+// притормаживая прием новых апдейтов, но не дольше ctx: тогда возвращает его ошибку
+func (d *Dispatcher) Submit(ctx context.Context, update tgbotapi.Update) error {
+	key := updateChatID(update)
+	idx := int(uint64(key) % uint64(len(d.queues)))
+	select {
+	case d.queues[idx] <- update:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown перестает принимать апдейты и ждет, пока воркеры разберут очереди.
+// Если ctx истекает раньше - возвращает его ошибку, не дожидаясь воркеров.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.closeOnce.Do(func() {
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) worker(id int, queue <-chan tgbotapi.Update) {
+	defer d.wg.Done()
+
+	for update := range queue {
+		d.safeHandle(id, update)
+	}
+}
+
+// safeHandle не дает панике в одном обработчике остановить воркер
+func (d *Dispatcher) safeHandle(worker int, update tgbotapi.Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[Dispatcher] worker %d: panic on update %d: %v\n%s",
+				worker, update.UpdateID, r, debug.Stack())
+		}
+	}()
+	d.handle(update)
+}
+
+// updateChatID возвращает ключ, по которому апдейты упорядочиваются
+func updateChatID(update tgbotapi.Update) int64 {
+	if chat := update.FromChat(); chat != nil {
+		return chat.ID
+	}
+	if user := update.SentFrom(); user != nil {
+		return user.ID
+	}
+	return 0
+}
@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func chatUpdate(id int, chatID int64) tgbotapi.Update {
+	return tgbotapi.Update{UpdateID: id, Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: chatID}}}
+}
+
+// Полная очередь не держит Submit дольше ctx
+func TestDispatcherSubmitDeadline(t *testing.T) {
+	d := NewDispatcher(1, 1, func(tgbotapi.Update) {})
+	// Воркеры не запущены: первый апдейт занимает очередь, второму места нет
+	if err := d.Submit(context.Background(), chatUpdate(1, 1)); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := d.Submit(ctx, chatUpdate(2, 1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Submit в полную очередь = %v, want DeadlineExceeded", err)
+	}
+}
+
+// Апдейты одного чата обрабатываются по порядку, Shutdown дожидается всех
+func TestDispatcherOrderAndShutdown(t *testing.T) {
+	var mu sync.Mutex
+	got := map[int64][]int{}
+	d := NewDispatcher(3, 2, func(update tgbotapi.Update) {
+		mu.Lock()
+		defer mu.Unlock()
+		chat := update.Message.Chat.ID
+		got[chat] = append(got[chat], update.UpdateID)
+	})
+	d.Start()
+	for i := range 30 {
+		if err := d.Submit(context.Background(), chatUpdate(i, int64(i%4))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for chat, ids := range got {
+		for i := 1; i < len(ids); i++ {
+			if ids[i] < ids[i-1] {
+				t.Errorf("чат %d: нарушен порядок %v", chat, ids)
+			}
+		}
+		total += len(ids)
+	}
+	if total != 30 {
+		t.Errorf("обработано %d апдейтов, want 30", total)
+	}
+}
+
+// При остановке опроса апдейты, уже лежащие в канале, передаются диспетчеру
+func TestDrainUpdates(t *testing.T) {
+	tests := []struct {
+		name   string
+		queued int
+		closed bool
+	}{
+		{name: "пустой канал", queued: 0},
+		{name: "апдейты в буфере", queued: 5},
+		{name: "закрытый канал с остатком", queued: 3, closed: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			updates := make(chan tgbotapi.Update, 10)
+			for i := range tc.queued {
+				updates <- chatUpdate(i, 1)
+			}
+			if tc.closed {
+				close(updates)
+			}
+			var submitted []int
+			drainUpdates(updates, func(update tgbotapi.Update) {
+				submitted = append(submitted, update.UpdateID)
+			})
+			if len(submitted) != tc.queued {
+				t.Errorf("передано %v, want %d апдейтов", submitted, tc.queued)
+			}
+		})
+	}
+}
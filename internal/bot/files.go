@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxDownloadSize - предел размера файла, который бот скачивает у Telegram
+const maxDownloadSize = 10 << 20
+
+var fileClient = &http.Client{Timeout: 30 * time.Second}
+
+// SendDocument отправляет файл документом
+func (b *BotApp) SendDocument(chatID int64, name string, data []byte, caption string) error {
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	doc.Caption = caption
+	_, err := b.API.Send(doc)
+	return err
+}
+
+// DownloadFile скачивает присланный пользователем файл
+func (b *BotApp) DownloadFile(fileID string) ([]byte, error) {
+	url, err := b.API.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить файл: %w", err)
+	}
+	resp, err := fileClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось скачать файл: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("не удалось скачать файл: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось скачать файл: %w", err)
+	}
+	if len(data) > maxDownloadSize {
+		return nil, fmt.Errorf("файл больше %d МБ", maxDownloadSize>>20)
+	}
+	return data, nil
+}
@@ -0,0 +1,126 @@
+package bot
+
+import (
+	"log"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Автоматический язык в кнопках выбора - язык клиента Telegram
+const localeAuto = "auto"
+
+// chatLocale - язык ответов в чате и язык Telegram, по которому он определен
+type chatLocale struct {
+	locale       string
+	languageCode string
+}
+
+// rememberLocale определяет язык пользователя from и запоминает его для чата chatID.
+// Вызывается в начале обработки апдейта, поэтому весь ответ собирается на одном языке.
+// Профиль читается, только если язык чата еще не известен или сменился язык Telegram
+func (b *BotApp) rememberLocale(chatID int64, from *tgbotapi.User) {
+	if cached, ok := b.locales.Load(chatID); ok && cached.(chatLocale).languageCode == from.LanguageCode {
+		return
+	}
+	b.refreshLocale(chatID, from)
+}
+
+// refreshLocale заново определяет язык чата по профилю: на /start и после выбора языка
+func (b *BotApp) refreshLocale(chatID int64, from *tgbotapi.User) {
+	b.locales.Store(chatID, chatLocale{locale: b.localeOf(from), languageCode: from.LanguageCode})
+}
+
+// localeOf - язык пользователя: выбранный командой /language, иначе язык Telegram.
+// Изменившийся язык Telegram сохраняется, чтобы на нем приходили напоминания
+func (b *BotApp) localeOf(from *tgbotapi.User) string {
+	user, err := b.userService.GetUserByTelegramID(from.ID)
+	if err != nil {
+		return b.catalog.Match(from.LanguageCode)
+	}
+	if from.LanguageCode != "" && user.LanguageCode != from.LanguageCode {
+		if err := b.userService.SetLanguageCode(user, from.LanguageCode); err != nil {
+			log.Printf("[localeOf] ERROR: %v", err)
+		}
+	}
+	return b.userLocale(user)
+}
+
+// userLocale - язык из профиля пользователя
+func (b *BotApp) userLocale(user *models.User) string {
+	if b.catalog.Supports(user.Locale) {
+		return user.Locale
+	}
+	return b.catalog.Match(user.LanguageCode)
+}
+
+// tr - переводчик для чата. Если в чате еще не было апдейтов (например, напоминание
+// после перезапуска), язык берется из профиля: в личном чате ID чата равен ID пользователя
+func (b *BotApp) tr(chatID int64) *i18n.Localizer {
+	if cached, ok := b.locales.Load(chatID); ok {
+		return b.catalog.Localizer(cached.(chatLocale).locale)
+	}
+	cached := chatLocale{locale: i18n.DefaultLocale}
+	if user, err := b.userService.GetUserByTelegramID(chatID); err == nil {
+		cached = chatLocale{locale: b.userLocale(user), languageCode: user.LanguageCode}
+	}
+	b.locales.Store(chatID, cached)
+	return b.catalog.Localizer(cached.locale)
+}
+
+// mainMenuKey - ключ кнопки главного меню с текстом text. Кнопки узнаются на любом
+// языке: клавиатура в чате остается прежней, пока бот не пришлет новую
+func (b *BotApp) mainMenuKey(text string) (string, bool) {
+	return b.catalog.Find("menu.", text)
+}
+
+// showLanguageSettings - выбор языка интерфейса. Если messageID не 0, экран обновляется на месте
+func (b *BotApp) showLanguageSettings(chatID int64, messageID int, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	current := tr.T("language.auto")
+	if b.catalog.Supports(user.Locale) {
+		current = b.catalog.Localizer(user.Locale).T("language.name")
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, locale := range b.catalog.Locales() {
+		label := b.catalog.Localizer(locale).T("language.name")
+		if user.Locale == locale {
+			label = "✅ " + label
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(b.userButton(label, "lang", locale)))
+	}
+	auto := tr.T("language.auto")
+	if !b.catalog.Supports(user.Locale) {
+		auto = "✅ " + auto
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(b.userButton(auto, "lang", localeAuto)))
+
+	b.sendOrEdit(chatID, messageID, tr.T("language.title", current), rows)
+}
+
+// setLanguage сохраняет выбранный язык и присылает главное меню с новыми подписями кнопок
+func (b *BotApp) setLanguage(chatID int64, messageID int, from *tgbotapi.User, locale string) {
+	if locale == localeAuto {
+		locale = ""
+	} else if !b.catalog.Supports(locale) {
+		return
+	}
+
+	if _, err := b.userService.SetLocale(from.ID, locale); err != nil {
+		log.Printf("[setLanguage] ERROR: %v", err)
+		b.sendText(chatID, b.tr(chatID).T("language.error"))
+		return
+	}
+	b.refreshLocale(chatID, from)
+
+	b.showLanguageSettings(chatID, messageID, from)
+	b.showMainMenu(chatID)
+}
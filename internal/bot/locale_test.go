@@ -0,0 +1,103 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gorm.io/gorm"
+)
+
+// countingUserRepo - профили в памяти со счетчиком чтений и записей
+type countingUserRepo struct {
+	repository.UserRepository
+	users          map[int64]*models.User
+	reads, updates int
+}
+
+func (r *countingUserRepo) FindByTelegramID(telegramID int64) (*models.User, error) {
+	r.reads++
+	user, ok := r.users[telegramID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *countingUserRepo) Update(user *models.User) error {
+	r.updates++
+	copied := *user
+	r.users[user.TelegramID] = &copied
+	return nil
+}
+
+func TestRememberLocale(t *testing.T) {
+	catalog, err := i18n.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &countingUserRepo{users: map[int64]*models.User{
+		7: {TelegramID: 7, LanguageCode: "ru"},
+	}}
+	b := &BotApp{userService: service.NewUserService(repo), catalog: catalog}
+	from := &tgbotapi.User{ID: 7, LanguageCode: "ru"}
+
+	steps := []struct {
+		name         string
+		languageCode string
+		refresh      bool // Как после /start или /language
+		wantReads    int  // Чтений профиля с начала теста
+		wantUpdates  int
+		wantLocale   string
+	}{
+		{name: "первый апдейт", languageCode: "ru", wantReads: 1, wantLocale: "ru"},
+		{name: "тот же язык Telegram", languageCode: "ru", wantReads: 1, wantLocale: "ru"},
+		{name: "сменился язык Telegram", languageCode: "en", wantReads: 2, wantUpdates: 1, wantLocale: "en"},
+		{name: "после смены", languageCode: "en", wantReads: 2, wantUpdates: 1, wantLocale: "en"},
+		{name: "/start", languageCode: "en", refresh: true, wantReads: 3, wantUpdates: 1, wantLocale: "en"},
+	}
+	for _, step := range steps {
+		from.LanguageCode = step.languageCode
+		if step.refresh {
+			b.refreshLocale(7, from)
+		} else {
+			b.rememberLocale(7, from)
+		}
+		if repo.reads != step.wantReads || repo.updates != step.wantUpdates {
+			t.Errorf("%s: чтений %d, записей %d, want %d и %d", step.name, repo.reads, repo.updates, step.wantReads, step.wantUpdates)
+		}
+		if got := b.tr(7).Locale(); got != step.wantLocale {
+			t.Errorf("%s: язык %s, want %s", step.name, got, step.wantLocale)
+		}
+	}
+	if code := repo.users[7].LanguageCode; code != "en" {
+		t.Errorf("в профиле язык Telegram %q, want en", code)
+	}
+}
+
+// Язык чата, где еще не было апдейтов, берется из профиля один раз
+func TestTrWithoutUpdates(t *testing.T) {
+	catalog, err := i18n.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &countingUserRepo{users: map[int64]*models.User{
+		7: {TelegramID: 7, LanguageCode: "ru", Locale: "en"},
+	}}
+	b := &BotApp{userService: service.NewUserService(repo), catalog: catalog}
+
+	for range 3 {
+		if got := b.tr(7).Locale(); got != "en" {
+			t.Errorf("язык %s, want en из профиля", got)
+		}
+	}
+	// Апдейт с тем же языком Telegram не перечитывает профиль
+	b.rememberLocale(7, &tgbotapi.User{ID: 7, LanguageCode: "ru"})
+	if repo.reads != 1 {
+		t.Errorf("профиль прочитан %d раз, want 1", repo.reads)
+	}
+}
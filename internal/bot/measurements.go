@@ -0,0 +1,259 @@
+package bot
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gorm.io/gorm"
+)
+
+// Период графиков прогресса по умолчанию
+const defaultProgressDays = 90
+
+// Шаги диалога замеров: ключ в TempData, вопрос - сообщение measure.prompt.<ключ>
+var measureSteps = []string{"weight", "waist", "hips", "chest"}
+
+// showMeasurements - «Замеры»: последний замер и изменения за месяц
+func (b *BotApp) showMeasurements(chatID int64, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("measure.add"), "meas_add"),
+			b.userButton(tr.T("measure.chart"), "progress"),
+		),
+	}
+
+	latest, err := b.measurementService.GetLatest(user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		b.sendWithKeyboard(chatID,
+			tr.T("measure.empty"),
+			[][]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardRow(
+				b.userButton(tr.T("measure.add"), "meas_add"),
+			)})
+		return
+	}
+	if err != nil {
+		log.Printf("[showMeasurements] ERROR: %v", err)
+		b.sendText(chatID, tr.T("measure.error"))
+		return
+	}
+
+	view := render.Measurements{Latest: latest}
+
+	monthAgo := time.Now().AddDate(0, -1, 0)
+	if history, err := b.measurementService.ListSince(user.ID, monthAgo); err == nil && len(history) > 1 {
+		view.Changes = measurementChanges(tr, history)
+	}
+	b.sendWithKeyboard(chatID, b.render(tr, "measurements", view), rows)
+}
+
+// startMeasure начинает диалог записи замеров
+func (b *BotApp) startMeasure(chatID int64, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	b.userFSM.SetState(from.ID, &fsm.State{
+		Action:   "measure",
+		Step:     1,
+		TempData: make(fsm.TempData),
+	})
+	b.sendText(chatID, tr.T("measure.start"))
+	b.askMeasureStep(chatID, 1)
+}
+
+func (b *BotApp) askMeasureStep(chatID int64, step int) {
+	tr := b.tr(chatID)
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("measure.skip"), "meas_skip"),
+		),
+	}
+	b.sendWithKeyboard(chatID, tr.T("measure.prompt."+measureSteps[step-1]), rows)
+}
+
+// handleMeasure - текстовые ответы в диалоге замеров
+func (b *BotApp) handleMeasure(chatID int64, from *tgbotapi.User, state *fsm.State, text string) {
+	tr := b.tr(chatID)
+	value := strings.ReplaceAll(strings.TrimSpace(text), ",", ".")
+	if value == "-" {
+		b.nextMeasureStep(chatID, from, state)
+		return
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number <= 0 {
+		b.sendText(chatID, tr.T("measure.invalid"))
+		return
+	}
+	state.TempData.Set(measureSteps[state.Step-1], number)
+	b.nextMeasureStep(chatID, from, state)
+}
+
+// nextMeasureStep переходит к следующему вопросу или сохраняет замер
+func (b *BotApp) nextMeasureStep(chatID int64, from *tgbotapi.User, state *fsm.State) {
+	if state.Step < len(measureSteps) {
+		state.Step++
+		b.userFSM.SetState(from.ID, state)
+		b.askMeasureStep(chatID, state.Step)
+		return
+	}
+	b.finishMeasure(chatID, from, state)
+}
+
+// finishMeasure сохраняет замер и обновляет вес в профиле
+func (b *BotApp) finishMeasure(chatID int64, from *tgbotapi.User, state *fsm.State) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	measurement, err := b.measurementService.AddMeasurement(service.AddMeasurementDTO{
+		UserID:   user.ID,
+		WeightKg: state.TempData.Float("weight"),
+		WaistCm:  state.TempData.Float("waist"),
+		HipsCm:   state.TempData.Float("hips"),
+		ChestCm:  state.TempData.Float("chest"),
+	})
+	if err != nil {
+		b.userFSM.DeleteState(from.ID)
+		b.sendText(chatID, b.render(tr, "failure", render.Failure{
+			Message: tr.T("measure.save_error"),
+			Reason:  tr.Error(err),
+			Hint:    tr.T("measure.restart"),
+		}))
+		return
+	}
+	b.userFSM.DeleteState(from.ID)
+
+	view := render.MeasureSaved{Measurement: measurement}
+	if measurement.WeightKg > 0 {
+		updated, err := b.userService.UpdateWeight(from.ID, measurement.WeightKg)
+		if err != nil {
+			log.Printf("[finishMeasure] UpdateWeight ERROR: %v", err)
+		} else if updated.HasTargets() {
+			view.CalorieTarget = updated.CalorieTarget
+		}
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("measure.chart"), "progress"),
+		),
+	}
+	b.sendWithKeyboard(chatID, b.render(tr, "measure.saved", view), rows)
+}
+
+// sendProgress отправляет графики замеров за последние days дней (0 - за все время)
+func (b *BotApp) sendProgress(chatID int64, from *tgbotapi.User, days int) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	var since time.Time
+	period := tr.T("progress.all_time")
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days)
+		period = tr.T("progress.days", days)
+	}
+
+	charts, err := b.measurementService.RenderProgress(user.ID, since, chartLabels(tr))
+	if errors.Is(err, service.ErrNoProgressData) {
+		b.sendText(chatID, tr.T("progress.empty", period))
+		return
+	}
+	if err != nil {
+		log.Printf("[sendProgress] ERROR: %v", err)
+		b.sendText(chatID, tr.T("progress.error"))
+		return
+	}
+
+	for _, c := range charts {
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: "progress.png", Bytes: c.PNG})
+		photo.Caption = tr.T("progress.caption", c.Title, period)
+		if _, err := b.API.Send(photo); err != nil {
+			log.Printf("[sendProgress] send ERROR: %v", err)
+		}
+	}
+}
+
+// chartLabels - подписи графиков прогресса
+func chartLabels(tr *i18n.Localizer) service.ChartLabels {
+	return service.ChartLabels{
+		Weight:  tr.T("progress.chart.weight"),
+		Girths:  tr.T("progress.chart.girths"),
+		Waist:   tr.T("progress.chart.waist"),
+		Hips:    tr.T("progress.chart.hips"),
+		Chest:   tr.T("progress.chart.chest"),
+		Kg:      tr.T("progress.chart.kg"),
+		Cm:      tr.T("progress.chart.cm"),
+		Decimal: tr.T("progress.chart.decimal"),
+	}
+}
+
+// parseProgressDays разбирает аргумент /progress: число дней или «all»
+func parseProgressDays(args string) (int, bool) {
+	args = strings.TrimSpace(strings.ToLower(args))
+	switch args {
+	case "":
+		return defaultProgressDays, true
+	case "all", "все":
+		return 0, true
+	}
+	days, err := strconv.Atoi(args)
+	if err != nil || days <= 0 || days > 3650 {
+		return 0, false
+	}
+	return days, true
+}
+
+// measurementChanges - разница между первым и последним заполненным значением
+func measurementChanges(tr *i18n.Localizer, history []*models.BodyMeasurement) []string {
+	fields := []struct {
+		key   string
+		value func(*models.BodyMeasurement) float64
+	}{
+		{"weight", func(m *models.BodyMeasurement) float64 { return m.WeightKg }},
+		{"waist", func(m *models.BodyMeasurement) float64 { return m.WaistCm }},
+		{"hips", func(m *models.BodyMeasurement) float64 { return m.HipsCm }},
+		{"chest", func(m *models.BodyMeasurement) float64 { return m.ChestCm }},
+	}
+
+	var lines []string
+	for _, f := range fields {
+		var first, last float64
+		for _, m := range history {
+			if v := f.value(m); v > 0 {
+				if first == 0 {
+					first = v
+				}
+				last = v
+			}
+		}
+		if first == 0 {
+			continue
+		}
+		lines = append(lines, tr.T("measure.change."+f.key, last-first))
+	}
+	if len(lines) == 0 {
+		return []string{tr.T("measure.no_changes")}
+	}
+	return lines
+}
@@ -0,0 +1,337 @@
+package bot
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// messageLimit - максимальная длина текста сообщения в Telegram (в UTF-16 символах).
+// Длина считается вместе с разметкой, поэтому после ее разбора текст только короче
+const messageLimit = 4096
+
+// pageItem - элемент длинного списка: текст и кнопки, которые должны
+// оказаться на одной странице с ним
+type pageItem struct {
+	text string
+	rows [][]tgbotapi.InlineKeyboardButton
+}
+
+// listPage - готовая страница списка
+type listPage struct {
+	text string
+	rows [][]tgbotapi.InlineKeyboardButton
+}
+
+// pageSeparator разделяет заголовок, элементы списка и подвал
+const pageSeparator = "\n\n"
+
+// paginate раскладывает элементы по страницам не длиннее limit. Заголовок и подвал
+// повторяются на каждой странице, элемент длиннее страницы делится splitHTML
+func paginate(header string, items []pageItem, footer string, limit int) []listPage {
+	// "x" занимает место списка, чтобы учесть разделители вокруг него
+	room := limit - textLength(joinSections(header, "x", footer)) + 1
+	if room < limit/4 {
+		// Заголовок и подвал не должны вытеснять сам список
+		room = limit / 4
+	}
+
+	var pages []listPage
+	current := listPage{}
+	flush := func() {
+		if current.text == "" && len(current.rows) == 0 {
+			return
+		}
+		current.text = joinSections(header, current.text, footer)
+		pages = append(pages, current)
+		current = listPage{}
+	}
+
+	for _, item := range items {
+		parts := []string{item.text}
+		if textLength(item.text) > room {
+			parts = splitHTML(item.text, room)
+		}
+		for i, part := range parts {
+			if current.text != "" && textLength(current.text)+len(pageSeparator)+textLength(part) > room {
+				flush()
+			}
+			current.text = joinSections(current.text, part)
+			if i == len(parts)-1 {
+				current.rows = append(current.rows, item.rows...)
+			} else {
+				flush()
+			}
+		}
+	}
+	flush()
+
+	if len(pages) == 0 {
+		pages = append(pages, listPage{text: joinSections(header, footer)})
+	}
+	return pages
+}
+
+// joinSections склеивает непустые части текста через pageSeparator
+func joinSections(sections ...string) string {
+	nonEmpty := make([]string, 0, len(sections))
+	for _, s := range sections {
+		if s != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	return strings.Join(nonEmpty, pageSeparator)
+}
+
+// sendPage показывает страницу page списка с кнопками «◀️ n/N ▶️», которые
+// вызывают action с номером страницы. Если messageID не 0, сообщение
+// обновляется на месте. extra - кнопки под навигацией на каждой странице
+func (b *BotApp) sendPage(chatID int64, messageID int, pages []listPage, page int, action string, extra [][]tgbotapi.InlineKeyboardButton) {
+	if page < 0 || page >= len(pages) {
+		page = 0
+	}
+	rows := append([][]tgbotapi.InlineKeyboardButton{}, pages[page].rows...)
+	if len(pages) > 1 {
+		nav := []tgbotapi.InlineKeyboardButton{}
+		if page > 0 {
+			nav = append(nav, b.userButton("◀️", action, page-1))
+		}
+		nav = append(nav, b.userButton(fmt.Sprintf("%d/%d", page+1, len(pages)), "noop"))
+		if page < len(pages)-1 {
+			nav = append(nav, b.userButton("▶️", action, page+1))
+		}
+		rows = append(rows, nav)
+	}
+	rows = append(rows, extra...)
+	b.sendOrEdit(chatID, messageID, pages[page].text, rows)
+}
+
+// textLength - длина текста так, как ее считает Telegram: в UTF-16 символах
+func textLength(s string) int {
+	n := 0
+	for _, r := range s {
+		if r > 0xFFFF {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// splitHTML делит текст с HTML-разметкой на части не длиннее limit.
+// Части режутся по абзацам, строкам или пробелам, а если их нет - посреди слова,
+// но никогда внутри тега или HTML-сущности. Теги, открытые на границе,
+// закрываются в конце части и открываются заново (с атрибутами) в следующей.
+// Каждая часть забирает хотя бы один символ текста, поэтому цикл конечен
+func splitHTML(text string, limit int) []string {
+	var parts []string
+	for textLength(text) > limit {
+		head, tail := splitHTMLOnce(text, limit)
+		if hasContent(head) {
+			parts = append(parts, head)
+		}
+		if !hasContent(tail) {
+			// Остались только теги: их пары уже закрыты в head
+			return parts
+		}
+		text = tail
+	}
+	if text != "" {
+		parts = append(parts, text)
+	}
+	return parts
+}
+
+// splitHTMLOnce отрезает от text первую часть не длиннее limit вместе с
+// закрывающими тегами. Место под них освобождается, сдвигая разрез назад.
+// В часть всегда попадает хотя бы один символ или сущность
+func splitHTMLOnce(text string, limit int) (string, string) {
+	room := limit
+	for {
+		cut := htmlCut(text, room)
+		if !hasContent(text[:cut]) {
+			cut = firstContentEnd(text)
+		}
+		// Закрывающие теги сразу за разрезом забираем в часть: они заменяют
+		// добавленные закрывающие и не оставляют пустых тегов в следующей
+		for cut < len(text) {
+			size, tag := htmlStep(text[cut:])
+			if !strings.HasPrefix(tag, "</") {
+				break
+			}
+			cut += size
+		}
+		open := openTags(text[:cut])
+		head := strings.TrimRight(text[:cut], "\n")
+		tail := strings.TrimLeft(text[cut:], "\n")
+
+		var closing, opening string
+		for i := len(open) - 1; i >= 0; i-- {
+			closing += "</" + open[i].name + ">"
+		}
+		for _, tag := range open {
+			opening += tag.raw
+		}
+		if textLength(head)+textLength(closing) > limit && room > 1 {
+			room = max(room-max(textLength(closing), 1), 1)
+			continue
+		}
+		// Заново открытые теги занимают место следующей части. Если они вместе
+		// с закрывающими берут больше половины лимита, следующая часть может не
+		// вместить ни одного символа - такие теги не переносим
+		if textLength(opening)+textLength(closing) >= limit/2 {
+			opening = ""
+		}
+		return head + closing, dropUnopened(opening + tail)
+	}
+}
+
+// dropUnopened убирает закрывающие теги, для которых в text нет открывающего:
+// они остаются, когда открытые на границе теги не переносятся в следующую часть
+func dropUnopened(text string) string {
+	var sb strings.Builder
+	var open []htmlTag
+	for i := 0; i < len(text); {
+		size, tag := htmlStep(text[i:])
+		if name, closing := strings.CutPrefix(tag, "</"); closing {
+			name = strings.TrimSuffix(name, ">")
+			if !slices.ContainsFunc(open, func(t htmlTag) bool { return t.name == name }) {
+				i += size
+				continue
+			}
+		}
+		open = applyTag(open, tag)
+		sb.WriteString(text[i : i+size])
+		i += size
+	}
+	return sb.String()
+}
+
+// hasContent - есть ли в text что-то кроме тегов
+func hasContent(text string) bool {
+	for i := 0; i < len(text); {
+		size, tag := htmlStep(text[i:])
+		if tag == "" {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+// firstContentEnd - позиция сразу после первого символа или сущности text вместе
+// с тегами перед ним; если текста нет - длина text
+func firstContentEnd(text string) int {
+	for i := 0; i < len(text); {
+		size, tag := htmlStep(text[i:])
+		i += size
+		if tag == "" {
+			return i
+		}
+	}
+	return len(text)
+}
+
+// htmlTag - открытый тег: имя и исходный текст вместе с атрибутами
+type htmlTag struct {
+	name string
+	raw  string
+}
+
+// htmlStep - длина в байтах неделимого фрагмента в начале text: тега,
+// сущности вроде &amp; или одного символа. tag - текст тега, если это тег
+func htmlStep(text string) (size int, tag string) {
+	switch text[0] {
+	case '<':
+		if end := strings.IndexByte(text, '>'); end > 0 {
+			return end + 1, text[:end+1]
+		}
+	case '&':
+		if end := strings.IndexByte(text, ';'); end > 0 && end <= 10 {
+			return end + 1, ""
+		}
+	}
+	_, size = utf8.DecodeRuneInString(text)
+	return size, ""
+}
+
+// applyTag обновляет стек открытых тегов
+func applyTag(open []htmlTag, tag string) []htmlTag {
+	if tag == "" {
+		return open
+	}
+	if name, closing := strings.CutPrefix(tag, "</"); closing {
+		name = strings.TrimSuffix(name, ">")
+		for i := len(open) - 1; i >= 0; i-- {
+			if open[i].name == name {
+				return open[:i]
+			}
+		}
+		return open
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(tag, "<"), ">")
+	if i := strings.IndexAny(name, " \t\n"); i >= 0 {
+		name = name[:i]
+	}
+	return append(open, htmlTag{name: name, raw: tag})
+}
+
+// htmlCut - байтовая позиция, по которой можно разрезать text, чтобы
+// первая часть была не длиннее limit. Предпочитает границы абзацев, затем
+// строк и пробелов, если часть при этом занимает хотя бы половину лимита.
+// Сразу после открывающего тега не режет, чтобы не оставлять пустых тегов
+func htmlCut(text string, limit int) int {
+	var paragraph, line, space, char int
+	afterOpen := false
+	length := 0
+	for i := 0; i < len(text); {
+		if i > 0 && !afterOpen {
+			switch {
+			case text[i-1] == '\n' && i > 1 && text[i-2] == '\n':
+				paragraph = i
+			case text[i-1] == '\n':
+				line = i
+			case text[i-1] == ' ':
+				space = i
+			}
+			char = i
+		}
+
+		size, tag := htmlStep(text[i:])
+		length += textLength(text[i : i+size])
+		if length > limit {
+			break
+		}
+		afterOpen = tag != "" && !strings.HasPrefix(tag, "</")
+		i += size
+	}
+
+	for _, cut := range []int{paragraph, line, space} {
+		if cut > 0 && textLength(text[:cut]) >= limit/2 {
+			return cut
+		}
+	}
+	for _, cut := range []int{max(paragraph, line, space), char} {
+		if cut > 0 {
+			return cut
+		}
+	}
+	// Первый фрагмент длиннее лимита - отдаем его целиком, чтобы не зациклиться
+	size, _ := htmlStep(text)
+	return size
+}
+
+// openTags - теги, оставшиеся открытыми в конце text
+func openTags(text string) []htmlTag {
+	var open []htmlTag
+	for i := 0; i < len(text); {
+		size, tag := htmlStep(text[i:])
+		open = applyTag(open, tag)
+		i += size
+	}
+	return open
+}
@@ -0,0 +1,253 @@
+package bot
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// visibleText - текст без тегов и переводов строк: их splitHTML убирает на границах частей
+func visibleText(text string) string {
+	var sb strings.Builder
+	for i := 0; i < len(text); {
+		size, tag := htmlStep(text[i:])
+		if tag == "" && text[i] != '\n' {
+			sb.WriteString(text[i : i+size])
+		}
+		i += size
+	}
+	return sb.String()
+}
+
+// checkBalanced - каждый закрывающий тег закрывает последний открытый, и в конце открытых нет
+func checkBalanced(t *testing.T, part string) {
+	t.Helper()
+	var open []string
+	for i := 0; i < len(part); {
+		size, tag := htmlStep(part[i:])
+		i += size
+		if tag == "" {
+			continue
+		}
+		if name, closing := strings.CutPrefix(tag, "</"); closing {
+			name = strings.TrimSuffix(name, ">")
+			if len(open) == 0 || open[len(open)-1] != name {
+				t.Errorf("тег </%s> не закрывает открытый %v: %q", name, open, part)
+				return
+			}
+			open = open[:len(open)-1]
+			continue
+		}
+		open = append(open, applyTag(nil, tag)[0].name)
+	}
+	if len(open) > 0 {
+		t.Errorf("не закрыты теги %v: %q", open, part)
+	}
+}
+
+// checkEntities - каждая сущность в части целая
+func checkEntities(t *testing.T, part string) {
+	t.Helper()
+	for i := 0; i < len(part); i++ {
+		if part[i] != '&' {
+			continue
+		}
+		end := strings.IndexByte(part[i:], ';')
+		if end < 0 || end > 10 {
+			t.Errorf("разрезана сущность в %q", part)
+			return
+		}
+	}
+}
+
+// checkParts проверяет свойства частей, которые обещает splitHTML. limit 0 - длину не проверять:
+// когда сами вложенные теги длиннее лимита, уложиться в него нельзя
+func checkParts(t *testing.T, text string, parts []string, limit int) {
+	t.Helper()
+	for _, part := range parts {
+		if n := textLength(part); limit > 0 && n > limit {
+			t.Errorf("часть длиной %d больше лимита %d: %q", n, limit, part)
+		}
+		if !utf8.ValidString(part) {
+			t.Errorf("разрезан символ: %q", part)
+		}
+		checkBalanced(t, part)
+		checkEntities(t, part)
+	}
+	if got, want := visibleText(strings.Join(parts, "")), visibleText(text); got != want {
+		t.Errorf("текст частей не совпадает с исходным:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestSplitHTML(t *testing.T) {
+	long := strings.Repeat("слово ", 30)
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		first string // Начало второй части, если важно
+		// Испорченная разметка: проверяется только, что деление закончилось и текст не потерян
+		garbled bool
+	}{
+		{name: "короткий текст", text: "<b>коротко</b>", limit: 100},
+		{name: "по абзацам", text: strings.Repeat("абзац текста\n\n", 20), limit: 50},
+		{name: "длинное слово", text: strings.Repeat("я", 250), limit: 40},
+		{name: "сущности", text: strings.Repeat("a &amp; b &lt; c ", 40), limit: 23},
+		{name: "эмодзи", text: strings.Repeat("💪🏋️ тренировка ", 30), limit: 21},
+		{name: "суррогатные пары подряд", text: strings.Repeat("😀", 100), limit: 15},
+		{name: "вложенные теги", text: "<b>жирный <i>" + long + "</i></b>", limit: 60, first: "<b><i>"},
+		{
+			name:  "ссылка с длинным адресом",
+			text:  `<a href="https://example.com/` + strings.Repeat("x", 30) + `">` + long + "</a>",
+			limit: 160,
+			first: `<a href="https://example.com/`,
+		},
+		{
+			name:  "теги длиннее половины лимита не переносятся",
+			text:  `<a href="https://example.com/` + strings.Repeat("x", 40) + `"><b>` + long + "</b></a>",
+			limit: 100,
+		},
+		{name: "теги без текста после разреза", text: "<b>" + strings.Repeat("a", 30) + "</b><i></i>", limit: 20},
+		{name: "незакрытый тег", text: "<b>" + long, limit: 40, first: "<b>", garbled: true},
+		{name: "лишний закрывающий тег", text: long + "</b>" + long, limit: 40},
+		{name: "обрывок тега", text: "<b " + long + " > " + long, limit: 40, garbled: true},
+		{name: "маленький лимит с вложенностью", text: "<b><i><u>" + strings.Repeat("ab ", 40) + "</u></i></b>", limit: 24},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			parts := splitHTML(tc.text, tc.limit)
+			if len(parts) == 0 {
+				t.Fatal("нет частей")
+			}
+			if tc.garbled {
+				if got, want := visibleText(strings.Join(parts, "")), visibleText(tc.text); got != want {
+					t.Errorf("потерян текст: %q", parts)
+				}
+			} else {
+				checkParts(t, tc.text, parts, tc.limit)
+			}
+			if tc.first != "" {
+				if len(parts) < 2 || !strings.HasPrefix(parts[1], tc.first) {
+					t.Errorf("вторая часть не начинается с %q: %q", tc.first, parts)
+				}
+			}
+		})
+	}
+}
+
+// randomHTML - вложенная разметка из ссылок, жирного текста, эмодзи, сущностей и длинных слов
+func randomHTML(rng *rand.Rand, depth int) string {
+	var sb strings.Builder
+	for range 3 + rng.IntN(8) {
+		switch rng.IntN(7) {
+		case 0:
+			if depth < 3 {
+				sb.WriteString(`<a href="https://t.me/` + strings.Repeat("u", rng.IntN(4)) + `">`)
+				sb.WriteString(randomHTML(rng, depth+1))
+				sb.WriteString("</a>")
+			}
+		case 1:
+			if depth < 3 {
+				sb.WriteString("<b>" + randomHTML(rng, depth+1) + "</b>")
+			}
+		case 2:
+			sb.WriteString("💪🏽 ")
+		case 3:
+			sb.WriteString("&amp; ")
+		case 4:
+			sb.WriteString(strings.Repeat("длинноеслово", 1+rng.IntN(6)))
+		case 5:
+			sb.WriteString("\n\n")
+		default:
+			sb.WriteString("текст ")
+		}
+	}
+	return sb.String()
+}
+
+func TestSplitHTMLGenerated(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range 300 {
+		text := randomHTML(rng, 0)
+		for _, limit := range []int{20, 45, 100, 1024} {
+			parts := splitHTML(text, limit)
+			if limit >= 100 {
+				checkParts(t, text, parts, limit)
+			} else {
+				checkParts(t, text, parts, 0)
+			}
+			if t.Failed() {
+				t.Fatalf("вход %d, лимит %d: %q", i, limit, text)
+			}
+		}
+	}
+}
+
+// Испорченная разметка: деление заканчивается и не теряет текст даже при маленьком лимите
+func TestSplitHTMLGarbled(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	pieces := []string{"<", ">", "&", ";", "</b>", "<i>", "&amp", "😀"}
+	for i := range 200 {
+		text := []rune(randomHTML(rng, 0))
+		for range 1 + rng.IntN(5) {
+			at := rng.IntN(len(text) + 1)
+			text = append(text[:at], append([]rune(pieces[rng.IntN(len(pieces))]), text[at:]...)...)
+		}
+		for _, limit := range []int{10, 20, 100} {
+			parts := splitHTML(string(text), limit)
+			for _, part := range parts {
+				if !utf8.ValidString(part) {
+					t.Fatalf("вход %d, лимит %d: разрезан символ в %q", i, limit, part)
+				}
+			}
+			if got, want := visibleText(strings.Join(parts, "")), visibleText(string(text)); got != want {
+				t.Fatalf("вход %d, лимит %d: потерян текст в %q", i, limit, string(text))
+			}
+		}
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	button := tgbotapi.NewInlineKeyboardButtonData("ok", "ok")
+	row := tgbotapi.NewInlineKeyboardRow(button)
+	items := []pageItem{
+		{text: "<b>первый</b>", rows: [][]tgbotapi.InlineKeyboardButton{row}},
+		{text: "<i>" + strings.Repeat("длинный элемент ", 30) + "</i>", rows: [][]tgbotapi.InlineKeyboardButton{row}},
+		{text: "третий &amp; последний"},
+	}
+	const limit = 120
+	pages := paginate("<b>Заголовок</b>", items, "подвал", limit)
+	if len(pages) < 3 {
+		t.Fatalf("страниц %d, want хотя бы 3", len(pages))
+	}
+	rows := 0
+	var texts []string
+	for _, page := range pages {
+		if n := textLength(page.text); n > limit {
+			t.Errorf("страница длиной %d больше лимита: %q", n, page.text)
+		}
+		if !strings.HasPrefix(page.text, "<b>Заголовок</b>") || !strings.HasSuffix(page.text, "подвал") {
+			t.Errorf("нет заголовка или подвала: %q", page.text)
+		}
+		checkBalanced(t, page.text)
+		rows += len(page.rows)
+		texts = append(texts, strings.TrimSuffix(strings.TrimPrefix(page.text, "<b>Заголовок</b>"), "подвал"))
+	}
+	if rows != 2 {
+		t.Errorf("кнопок на страницах %d, want 2", rows)
+	}
+	var want string
+	for _, item := range items {
+		want += item.text
+	}
+	if got := visibleText(strings.Join(texts, "")); strings.ReplaceAll(got, " ", "") != strings.ReplaceAll(visibleText(want), " ", "") {
+		t.Errorf("текст страниц не совпадает:\n got %q\nwant %q", got, visibleText(want))
+	}
+
+	if empty := paginate("заголовок", nil, "подвал", limit); len(empty) != 1 || empty[0].text != "заголовок\n\nподвал" {
+		t.Errorf("пустой список: %+v", empty)
+	}
+}
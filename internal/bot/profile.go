@@ -0,0 +1,216 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Уровни активности в порядке кнопок, подписи - profile.activity.<уровень>
+var activityLevels = []string{"sedentary", "light", "moderate", "active", "very_active"}
+
+// Цели в порядке кнопок, подписи - profile.goal.<цель>
+var goals = []string{"lose", "maintain", "gain"}
+
+// startOnboarding начинает анкету для расчета дневной нормы
+func (b *BotApp) startOnboarding(chatID int64, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	b.userFSM.SetState(from.ID, &fsm.State{
+		Action:   "onboarding",
+		Step:     1,
+		TempData: make(fsm.TempData),
+	})
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("profile.male"), "ob_sex", "male"),
+			b.userButton(tr.T("profile.female"), "ob_sex", "female"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("measure.skip"), "ob_skip"),
+		),
+	}
+	b.sendWithKeyboard(chatID,
+		tr.T("onboarding.start"), rows)
+}
+
+// handleOnboarding - текстовые ответы анкеты (возраст, рост, вес)
+func (b *BotApp) handleOnboarding(chatID int64, from *tgbotapi.User, state *fsm.State, text string) {
+	tr := b.tr(chatID)
+	value := strings.ReplaceAll(strings.TrimSpace(text), ",", ".")
+
+	switch state.Step {
+	case 1:
+		switch strings.ToLower(value) {
+		case "м", "муж", "мужской", "m", "male":
+			b.setOnboardingSex(chatID, from, state, "male")
+		case "ж", "жен", "женский", "f", "female":
+			b.setOnboardingSex(chatID, from, state, "female")
+		default:
+			b.sendText(chatID, tr.T("onboarding.sex_invalid"))
+		}
+	case 2:
+		age, err := strconv.Atoi(value)
+		if err != nil || age < 14 || age > 100 {
+			b.sendText(chatID, tr.T("onboarding.age_invalid"))
+			return
+		}
+		state.TempData.Set("age", age)
+		state.Step = 3
+		b.userFSM.SetState(from.ID, state)
+		b.sendText(chatID, tr.T("onboarding.height"))
+	case 3:
+		height, err := strconv.ParseFloat(value, 64)
+		if err != nil || height < 100 || height > 250 {
+			b.sendText(chatID, tr.T("onboarding.height_invalid"))
+			return
+		}
+		state.TempData.Set("height", height)
+		state.Step = 4
+		b.userFSM.SetState(from.ID, state)
+		b.sendText(chatID, tr.T("onboarding.weight"))
+	case 4:
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight < 30 || weight > 300 {
+			b.sendText(chatID, tr.T("onboarding.weight_invalid"))
+			return
+		}
+		state.TempData.Set("weight", weight)
+		state.Step = 5
+		b.userFSM.SetState(from.ID, state)
+
+		rows := [][]tgbotapi.InlineKeyboardButton{}
+		for _, level := range activityLevels {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				b.userButton(tr.T("profile.activity."+level), "ob_act", level),
+			))
+		}
+		b.sendWithKeyboard(chatID, tr.T("onboarding.activity"), rows)
+	default:
+		b.sendText(chatID, tr.T("onboarding.choose"))
+	}
+}
+
+// setOnboardingSex - шаг 1 -> 2
+func (b *BotApp) setOnboardingSex(chatID int64, from *tgbotapi.User, state *fsm.State, sex string) {
+	tr := b.tr(chatID)
+	state.TempData.Set("sex", sex)
+	state.Step = 2
+	b.userFSM.SetState(from.ID, state)
+	b.sendText(chatID, tr.T("onboarding.age"))
+}
+
+// setOnboardingActivity - шаг 5 -> 6
+func (b *BotApp) setOnboardingActivity(chatID int64, from *tgbotapi.User, state *fsm.State, activity string) {
+	tr := b.tr(chatID)
+	if _, ok := service.ActivityFactors[activity]; !ok {
+		return
+	}
+	state.TempData.Set("activity", activity)
+	state.Step = 6
+	b.userFSM.SetState(from.ID, state)
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for _, goal := range goals {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("profile.goal."+goal), "ob_goal", goal),
+		))
+	}
+	b.sendWithKeyboard(chatID, tr.T("onboarding.goal"), rows)
+}
+
+// finishOnboarding - шаг 6: считаем и сохраняем нормы
+func (b *BotApp) finishOnboarding(chatID int64, from *tgbotapi.User, state *fsm.State, goal string) {
+	tr := b.tr(chatID)
+	if _, err := b.authenticateUser(from); err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	user, err := b.userService.UpdateProfile(from.ID, service.ProfileDTO{
+		Sex:           state.TempData.String("sex"),
+		Age:           state.TempData.Int("age"),
+		HeightCm:      state.TempData.Float("height"),
+		WeightKg:      state.TempData.Float("weight"),
+		ActivityLevel: state.TempData.String("activity"),
+		Goal:          goal,
+	})
+	if err != nil {
+		log.Printf("[finishOnboarding] ERROR: %v", err)
+		b.sendFailure(chatID, tr.T("profile.save_error"), err)
+		return
+	}
+	b.userFSM.DeleteState(from.ID)
+
+	view := render.OnboardingDone{User: user}
+
+	// Новому пользователю сразу подбираем меню под норму
+	if current, err := b.menuService.CurrentAssignment(user.ID); err == nil && current == nil {
+		if menu, err := b.menuService.AssignByTarget(user); err != nil {
+			log.Printf("[finishOnboarding] auto menu ERROR: %v", err)
+		} else if menu != nil {
+			view.MenuName = menu.Name
+		}
+	}
+	b.sendText(chatID, b.render(tr, "onboarding.done", view))
+}
+
+// showProfile - команда /profile
+func (b *BotApp) showProfile(chatID int64, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+	if !user.HasTargets() {
+		b.startOnboarding(chatID, from)
+		return
+	}
+
+	view := render.Profile{
+		User:     user,
+		Sex:      tr.T("profile.sex.male"),
+		Activity: tr.T("profile.activity." + user.ActivityLevel),
+		Goal:     tr.T("profile.goal." + user.Goal),
+	}
+	if user.Sex == "female" {
+		view.Sex = tr.T("profile.sex.female")
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("profile.refill"), "ob_start"),
+			b.userButton(tr.T("profile.diet"), "diet"),
+		),
+	}
+	b.sendWithKeyboard(chatID, b.render(tr, "profile", view), rows)
+}
+
+// userWithTargets возвращает пользователя, если у него рассчитана норма, иначе nil
+func (b *BotApp) userWithTargets(from *tgbotapi.User) *models.User {
+	if from == nil {
+		return nil
+	}
+	user, err := b.userService.GetUserByTelegramID(from.ID)
+	if err != nil || !user.HasTargets() {
+		return nil
+	}
+	return user
+}
+
+// budgetShare - какую долю дневной нормы составляют calories, например "23%"
+func budgetShare(calories, target int) string {
+	if target <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%%", math.Round(float64(calories)*100/float64(target)))
+}
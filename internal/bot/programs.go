@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// hasProgram - расписаны ли у тренировки дни программы
+func (b *BotApp) hasProgram(trainingID uint) bool {
+	ok, err := b.programService.HasDays(trainingID)
+	if err != nil {
+		log.Printf("[hasProgram] ERROR: %v", err)
+	}
+	return ok
+}
+
+// showProgram - тренировочные дни одной недели программы с переключением недель.
+// Если messageID не 0, экран обновляется на месте
+func (b *BotApp) showProgram(chatID int64, messageID int, programID uint, week int) {
+	tr := b.tr(chatID)
+	program, err := b.programService.GetProgram(programID)
+	if err != nil {
+		b.sendText(chatID, tr.T("training.not_found"))
+		return
+	}
+	if week < 1 || week > program.Weeks {
+		week = 1
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for i := range program.Days {
+		day := &program.Days[i]
+		if day.Week != week {
+			continue
+		}
+		label := tr.T("weekday." + strconv.Itoa(day.DayNumber))
+		if day.Title != "" {
+			label += " · " + day.Title
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("program.day_button", truncateLabel(label, 40), len(day.Exercises)), "pday", day.ID, 0),
+		))
+	}
+	msg := b.render(tr, "program", render.Program{Program: program, Week: week, Empty: len(rows) == 0})
+
+	if program.Weeks > 1 {
+		nav := []tgbotapi.InlineKeyboardButton{}
+		if week > 1 {
+			nav = append(nav, b.userButton(tr.T("program.prev_week", week-1), "prog", program.ID, week-1))
+		}
+		if week < program.Weeks {
+			nav = append(nav, b.userButton(tr.T("program.next_week", week+1), "prog", program.ID, week+1))
+		}
+		rows = append(rows, nav)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.userButton(tr.T("training.done"), "done", program.ID),
+	))
+	b.sendOrEdit(chatID, messageID, msg, rows)
+}
+
+// showProgramExercise - карточка упражнения index тренировочного дня.
+// На последней карточке тренировку можно отметить выполненной
+func (b *BotApp) showProgramExercise(chatID int64, messageID int, dayID uint, index int) {
+	tr := b.tr(chatID)
+	day, err := b.programService.GetDay(dayID)
+	if err != nil {
+		b.sendText(chatID, tr.T("program.day_not_found"))
+		return
+	}
+	back := b.userButton(tr.T("program.back"), "prog", day.ProgramID, day.Week)
+	if len(day.Exercises) == 0 {
+		b.sendOrEdit(chatID, messageID, tr.T("program.day_empty"),
+			[][]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardRow(back)})
+		return
+	}
+	if index < 0 || index >= len(day.Exercises) {
+		index = 0
+	}
+
+	msg := b.render(tr, "program.exercise", render.ProgramExercise{
+		Item:   &day.Exercises[index],
+		Number: index + 1,
+		Total:  len(day.Exercises),
+	})
+
+	nav := []tgbotapi.InlineKeyboardButton{}
+	if index > 0 {
+		nav = append(nav, b.userButton(tr.T("program.prev"), "pday", day.ID, index-1))
+	}
+	if index < len(day.Exercises)-1 {
+		nav = append(nav, b.userButton(tr.T("program.next"), "pday", day.ID, index+1))
+	} else {
+		nav = append(nav, b.userButton(tr.T("program.finish"), "done", day.ProgramID))
+	}
+	rows := [][]tgbotapi.InlineKeyboardButton{nav, tgbotapi.NewInlineKeyboardRow(back)}
+	b.sendOrEdit(chatID, messageID, msg, rows)
+}
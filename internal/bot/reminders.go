@@ -0,0 +1,281 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// weekdayShort - короткое название дня недели с номером MenuDay.DayNumber
+func weekdayShort(tr *i18n.Localizer, day int) string {
+	if day < 1 || day > 7 {
+		return strconv.Itoa(day)
+	}
+	return tr.T("weekday.short." + strconv.Itoa(day))
+}
+
+// Часовые пояса, которые предлагаются кнопками (подписи - reminders.tz.<город>).
+// Любой другой можно ввести текстом
+var reminderTimezones = []struct{ zone, city string }{
+	{"Europe/Kaliningrad", "kaliningrad"},
+	{"Europe/Moscow", "moscow"},
+	{"Europe/Samara", "samara"},
+	{"Asia/Yekaterinburg", "yekaterinburg"},
+	{"Asia/Omsk", "omsk"},
+	{"Asia/Novosibirsk", "novosibirsk"},
+	{"Asia/Irkutsk", "irkutsk"},
+	{"Asia/Yakutsk", "yakutsk"},
+	{"Asia/Vladivostok", "vladivostok"},
+	{"Asia/Kamchatka", "kamchatka"},
+}
+
+// SendReminder отправляет напоминание от планировщика на языке пользователя
+func (b *BotApp) SendReminder(reminder service.Reminder) error {
+	tr := b.tr(reminder.TelegramID)
+	if reminder.Kind == service.ReminderDiary {
+		return b.sendDiaryReminder(tr, reminder)
+	}
+	name := "reminder.workout"
+	if reminder.Kind == service.ReminderMeal && reminder.Meal != nil {
+		name = "reminder.meal"
+	}
+	msg := tgbotapi.NewMessage(reminder.TelegramID, b.render(tr, name, reminder))
+	msg.ParseMode = render.ParseMode
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("reminders.settings"), "rem"),
+		),
+	)
+	_, err := b.API.Send(msg)
+	return err
+}
+
+// sendDiaryReminder присылает итог дня по дневнику питания. День считается
+// в часовом поясе пользователя; если за день ничего не записано, итог не приходит
+func (b *BotApp) sendDiaryReminder(tr *i18n.Localizer, reminder service.Reminder) error {
+	user, err := b.userService.GetUserByTelegramID(reminder.TelegramID)
+	if err != nil {
+		return err
+	}
+	day, err := b.foodDiaryService.GetDay(user.ID, b.userNow(user.ID))
+	if err != nil {
+		return err
+	}
+	if len(day.Entries) == 0 {
+		return nil
+	}
+
+	msg := tgbotapi.NewMessage(reminder.TelegramID, b.render(tr, "diary.summary", diarySummary(day, user)))
+	msg.ParseMode = render.ParseMode
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("diary.open"), "diary"),
+			b.userButton(tr.T("reminders.settings"), "rem"),
+		),
+	)
+	_, err = b.API.Send(msg)
+	return err
+}
+
+// showReminderSettings - экран настроек напоминаний.
+// Если messageID не 0, экран обновляется на месте
+func (b *BotApp) showReminderSettings(chatID int64, messageID int, from *tgbotapi.User) {
+	settings, ok := b.loadReminderSettings(chatID, from)
+	if !ok {
+		return
+	}
+
+	tr := b.tr(chatID)
+	b.sendOrEdit(chatID, messageID, b.render(tr, "reminders", reminderView(tr, settings)), b.reminderButtons(tr, settings))
+}
+
+// updateReminderSettings применяет изменение и перерисовывает экран
+func (b *BotApp) updateReminderSettings(chatID int64, messageID int, from *tgbotapi.User, change func(*models.ReminderSettings)) {
+	settings, ok := b.loadReminderSettings(chatID, from)
+	if !ok {
+		return
+	}
+
+	change(settings)
+	if err := b.reminderService.SaveSettings(settings); err != nil {
+		b.sendFailure(chatID, b.tr(chatID).T("reminders.save_error"), err)
+		return
+	}
+	b.showReminderSettings(chatID, messageID, from)
+}
+
+func (b *BotApp) loadReminderSettings(chatID int64, from *tgbotapi.User) (*models.ReminderSettings, bool) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return nil, false
+	}
+	settings, err := b.reminderService.GetSettings(user.ID)
+	if err != nil {
+		log.Printf("[loadReminderSettings] ERROR: %v", err)
+		b.sendText(chatID, tr.T("reminders.load_error"))
+		return nil, false
+	}
+	return settings, true
+}
+
+// showTimezonePicker - выбор часового пояса кнопками
+func (b *BotApp) showTimezonePicker(chatID int64, from *tgbotapi.User) {
+	b.userFSM.SetState(from.ID, &fsm.State{
+		Action:   "reminders",
+		Step:     3,
+		TempData: make(fsm.TempData),
+	})
+
+	tr := b.tr(chatID)
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for i, tz := range reminderTimezones {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("reminders.tz."+tz.city), "rem_tz_set", i),
+		))
+	}
+	b.sendWithKeyboard(chatID, tr.T("reminders.tz_prompt"), rows)
+}
+
+// startReminderInput начинает ввод времени тренировки (шаг 1) или тихих часов (шаг 2)
+func (b *BotApp) startReminderInput(chatID int64, from *tgbotapi.User, step int) {
+	b.userFSM.SetState(from.ID, &fsm.State{
+		Action:   "reminders",
+		Step:     step,
+		TempData: make(fsm.TempData),
+	})
+
+	tr := b.tr(chatID)
+	if step == 1 {
+		b.sendText(chatID, tr.T("reminders.time_prompt"))
+		return
+	}
+	b.sendText(chatID, tr.T("reminders.quiet_prompt"))
+}
+
+// handleReminderInput - текстовые ответы в настройках напоминаний
+func (b *BotApp) handleReminderInput(chatID int64, from *tgbotapi.User, state *fsm.State, text string) {
+	tr := b.tr(chatID)
+	value := strings.TrimSpace(text)
+
+	var change func(*models.ReminderSettings)
+	switch state.Step {
+	case 1:
+		minutes, ok := service.ParseClock(value)
+		if !ok {
+			b.sendText(chatID, tr.T("reminders.time_invalid"))
+			return
+		}
+		change = func(s *models.ReminderSettings) { s.WorkoutTime = formatClock(minutes) }
+	case 2:
+		if value == "-" {
+			change = func(s *models.ReminderSettings) { s.QuietFrom, s.QuietTo = "", "" }
+			break
+		}
+		parts := strings.Split(value, "-")
+		if len(parts) != 2 {
+			b.sendText(chatID, tr.T("reminders.quiet_invalid"))
+			return
+		}
+		quietFrom, okFrom := service.ParseClock(parts[0])
+		quietTo, okTo := service.ParseClock(parts[1])
+		if !okFrom || !okTo {
+			b.sendText(chatID, tr.T("reminders.quiet_invalid"))
+			return
+		}
+		change = func(s *models.ReminderSettings) {
+			s.QuietFrom, s.QuietTo = formatClock(quietFrom), formatClock(quietTo)
+		}
+	case 3:
+		change = func(s *models.ReminderSettings) { s.Timezone = value }
+	default:
+		b.userFSM.DeleteState(from.ID)
+		return
+	}
+
+	b.userFSM.DeleteState(from.ID)
+	b.updateReminderSettings(chatID, 0, from, change)
+}
+
+// reminderView - настройки напоминаний с подписями дней тренировок и тихих часов
+func reminderView(tr *i18n.Localizer, s *models.ReminderSettings) render.Reminders {
+	var days []string
+	for day := 1; day <= 7; day++ {
+		if service.HasWorkoutDay(s.WorkoutDays, day) {
+			days = append(days, weekdayShort(tr, day))
+		}
+	}
+	workouts := tr.T("reminders.no_workouts")
+	if len(days) > 0 {
+		workouts = tr.T("reminders.workouts_at", strings.Join(days, ", "), s.WorkoutTime)
+	}
+	quiet := tr.T("reminders.quiet_off")
+	if s.QuietFrom != "" {
+		quiet = s.QuietFrom + "-" + s.QuietTo
+	}
+	diary := tr.T("reminders.diary_off")
+	if s.DiaryTime != "" {
+		diary = tr.T("reminders.diary_at", s.DiaryTime)
+	}
+
+	return render.Reminders{
+		Settings:    s,
+		Workouts:    workouts,
+		Quiet:       quiet,
+		Diary:       diary,
+		MealLead:    int(service.MealReminderLead.Minutes()),
+		WorkoutLead: int(service.WorkoutReminderLead.Minutes()),
+	}
+}
+
+// reminderButtons - переключатели, дни тренировок и поля настроек
+func (b *BotApp) reminderButtons(tr *i18n.Localizer, s *models.ReminderSettings) [][]tgbotapi.InlineKeyboardButton {
+	dayRow := []tgbotapi.InlineKeyboardButton{}
+	for day := 1; day <= 7; day++ {
+		label := weekdayShort(tr, day)
+		if service.HasWorkoutDay(s.WorkoutDays, day) {
+			label = "✅" + label
+		}
+		dayRow = append(dayRow, b.userButton(label, "rem_day", day))
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("reminders.meals_toggle", onOff(tr, s.MealsEnabled)), "rem_meals"),
+			b.userButton(tr.T("reminders.workouts_toggle", onOff(tr, s.WorkoutsEnabled)), "rem_workouts"),
+		),
+		dayRow,
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("reminders.diary_toggle", onOff(tr, s.DiaryTime != "")), "rem_diary"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("reminders.time"), "rem_time"),
+			b.userButton(tr.T("reminders.quiet"), "rem_quiet"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("reminders.tz"), "rem_tz"),
+		),
+	}
+	return rows
+}
+
+func onOff(tr *i18n.Localizer, enabled bool) string {
+	if enabled {
+		return tr.T("common.on")
+	}
+	return tr.T("common.off")
+}
+
+// formatClock - минуты от начала суток в "ЧЧ:ММ"
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
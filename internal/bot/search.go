@@ -0,0 +1,183 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Сколько результатов показывать в чате и в inline-режиме
+const (
+	searchResultLimit = 10
+	inlineResultLimit = 20
+
+	// Сколько секунд Telegram может отдавать inline-выдачу из кэша
+	inlineCacheSeconds = 60
+)
+
+// startSearch спрашивает поисковый запрос, если он не указан после /search
+func (b *BotApp) startSearch(chatID int64, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	b.userFSM.SetState(from.ID, &fsm.State{
+		Action:   "search",
+		Step:     1,
+		TempData: make(fsm.TempData),
+	})
+	b.sendText(chatID, tr.T("search.prompt"))
+}
+
+func (b *BotApp) handleSearchInput(chatID int64, from *tgbotapi.User, text string) {
+	tr := b.tr(chatID)
+	if _, err := service.NormalizeSearchQuery(text); err != nil {
+		b.sendText(chatID, b.render(tr, "failure", render.Failure{Message: tr.Error(err)}))
+		return
+	}
+	b.userFSM.DeleteState(from.ID)
+	b.runSearch(chatID, from, text)
+}
+
+// runSearch показывает тренировки и блюда по запросу кнопками, открывающими карточки
+func (b *BotApp) runSearch(chatID int64, from *tgbotapi.User, query string) {
+	tr := b.tr(chatID)
+	query, err := service.NormalizeSearchQuery(query)
+	if err != nil {
+		b.sendText(chatID, b.render(tr, "failure", render.Failure{Message: tr.Error(err)}))
+		return
+	}
+	filter := b.userRestrictions(from)
+	hits, err := b.searchService.Search(query, filter, searchResultLimit)
+	if err != nil {
+		log.Printf("[runSearch] ERROR: %v", err)
+		b.sendText(chatID, tr.T("search.error"))
+		return
+	}
+
+	view := render.SearchResults{Query: query, Hits: hits, Filtered: !filter.Empty()}
+	if len(hits) == 0 {
+		b.sendText(chatID, b.render(tr, "search.empty", view))
+		return
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for i, hit := range hits {
+		action := "sr_tr"
+		if hit.Kind == models.SearchNutrition {
+			action = "sr_nu"
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			b.userButton(fmt.Sprintf("%d. %s", i+1, truncateLabel(hit.Title, 40)), action, hit.ID),
+		))
+	}
+	b.sendWithKeyboard(chatID, b.render(tr, "search.results", view), rows)
+}
+
+// handleInlineQuery отвечает на «@бот запрос» в любом чате карточками тренировок и блюд,
+// которыми можно поделиться. Пустой запрос листает весь каталог, страницы - через offset
+func (b *BotApp) handleInlineQuery(query *tgbotapi.InlineQuery) {
+	offset, err := strconv.Atoi(query.Offset)
+	if err != nil {
+		offset = 0
+	}
+	filter := b.userRestrictions(query.From)
+	// Карточки уходят в чужие чаты, где язык не запомнен, поэтому он берется у автора
+	tr := b.catalog.Localizer(b.localeOf(query.From))
+
+	var hits []models.SearchHit
+	var more bool
+	switch text := strings.TrimSpace(query.Query); {
+	case text == "":
+		hits, more, err = b.searchService.Browse(filter, offset, inlineResultLimit)
+	case len([]rune(text)) < service.MinSearchQuery:
+		// Ждем, пока пользователь допишет запрос
+	default:
+		hits, more, err = b.searchService.SearchPage(text, filter, offset, inlineResultLimit)
+	}
+	if err != nil {
+		log.Printf("[handleInlineQuery] userID=%d, query='%s': %v", query.From.ID, query.Query, err)
+	}
+
+	results := make([]interface{}, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, b.inlineSearchResult(tr, hit))
+	}
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		Results:       results,
+		CacheTime:     inlineCacheSeconds,
+		// Выдача зависит от ограничений питания пользователя, поэтому
+		// Telegram не должен отдавать ее из кэша другим пользователям
+		IsPersonal: true,
+	}
+	if more {
+		answer.NextOffset = strconv.Itoa(offset + len(hits))
+	}
+	if _, err := b.API.Request(answer); err != nil {
+		log.Printf("[handleInlineQuery] ERROR answering: %v", err)
+	}
+}
+
+// inlineSearchResult - результат inline-режима: при выборе в чат отправляется карточка
+// с кнопкой, открывающей ее в боте
+func (b *BotApp) inlineSearchResult(tr *i18n.Localizer, hit models.SearchHit) tgbotapi.InlineQueryResultArticle {
+	article := tgbotapi.NewInlineQueryResultArticleHTML(fmt.Sprintf("%s:%d", hit.Kind, hit.ID), hit.Title, b.render(tr, "share.card", hit))
+
+	description := tr.T("search.calories", hit.Calories)
+	if hit.Kind == models.SearchTraining {
+		description = tr.T("search.duration", hit.Duration)
+	}
+	if hit.Category != "" {
+		description += " · " + hit.Category
+	}
+	article.Description = description
+
+	if link := b.deepLink(hit); link != "" {
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(tr.T("search.open"), link),
+		))
+		article.ReplyMarkup = &keyboard
+	}
+	return article
+}
+
+// Параметры ссылки t.me/бот?start=...: tr_ID - тренировка, nu_ID - блюдо
+var deepLinkPrefixes = map[string]string{
+	models.SearchTraining:  "tr_",
+	models.SearchNutrition: "nu_",
+}
+
+// deepLink - ссылка, открывающая карточку в личном чате с ботом
+func (b *BotApp) deepLink(hit models.SearchHit) string {
+	if b.API.Self.UserName == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/%s?start=%s%d", b.API.Self.UserName, deepLinkPrefixes[hit.Kind], hit.ID)
+}
+
+// openDeepLink показывает карточку из параметра /start. false - параметр не распознан
+func (b *BotApp) openDeepLink(chatID int64, from *tgbotapi.User, payload string) bool {
+	for kind, prefix := range deepLinkPrefixes {
+		rest, found := strings.CutPrefix(payload, prefix)
+		if !found {
+			continue
+		}
+		id, err := strconv.ParseUint(rest, 10, 64)
+		if err != nil || id == 0 {
+			return false
+		}
+		if kind == models.SearchTraining {
+			b.showTrainingCard(chatID, 0, uint(id), 0, 0)
+		} else {
+			b.showNutritionCard(chatID, 0, from, uint(id), 0, 0)
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Сколько позиций списка покупок показывается кнопками на одной странице
+const shoppingPageSize = 16
+
+// showShoppingList - список покупок по недельному меню пользователя. Если списка
+// еще нет или он собран по другому меню, собирается новый с прежними днями и порциями.
+// Если messageID не 0, экран обновляется на месте
+func (b *BotApp) showShoppingList(chatID int64, messageID int, from *tgbotapi.User, page int) {
+	user, menu, ok := b.loadUserMenu(chatID, from)
+	if !ok {
+		return
+	}
+
+	list, err := b.shoppingService.GetList(user.ID)
+	if err == nil && (list == nil || list.MenuID != menu.ID) {
+		days, servings := service.AllMenuDays, 1
+		if list != nil {
+			days, servings = list.Days, list.Servings
+		}
+		list, err = b.shoppingService.BuildList(user.ID, menu.ID, days, servings)
+	}
+	if err != nil {
+		log.Printf("[showShoppingList] ERROR: %v", err)
+		b.sendText(chatID, b.tr(chatID).T("shopping.build_error"))
+		return
+	}
+
+	text, rows := b.formatShoppingList(b.tr(chatID), menu, list, page)
+	b.sendOrEdit(chatID, messageID, text, rows)
+}
+
+// rebuildShoppingList пересобирает список с измененными днями или порциями
+func (b *BotApp) rebuildShoppingList(chatID int64, messageID int, from *tgbotapi.User, change func(days, servings *int)) {
+	user, menu, ok := b.loadUserMenu(chatID, from)
+	if !ok {
+		return
+	}
+
+	list, err := b.shoppingService.GetList(user.ID)
+	if err != nil {
+		log.Printf("[rebuildShoppingList] ERROR: %v", err)
+		b.sendText(chatID, b.tr(chatID).T("shopping.load_error"))
+		return
+	}
+	days, servings := service.AllMenuDays, 1
+	if list != nil {
+		days, servings = list.Days, list.Servings
+	}
+	change(&days, &servings)
+
+	list, err = b.shoppingService.BuildList(user.ID, menu.ID, days, servings)
+	if err != nil {
+		b.sendFailure(chatID, b.tr(chatID).T("shopping.rebuild_error"), err)
+		return
+	}
+	text, rows := b.formatShoppingList(b.tr(chatID), menu, list, 0)
+	b.sendOrEdit(chatID, messageID, text, rows)
+}
+
+// toggleShoppingItem отмечает позицию купленной и перерисовывает текущую страницу
+func (b *BotApp) toggleShoppingItem(chatID int64, messageID int, from *tgbotapi.User, itemID uint, page int) {
+	b.updateShoppingList(chatID, messageID, from, page, func(userID uint) (*models.ShoppingList, error) {
+		return b.shoppingService.ToggleItem(userID, itemID)
+	})
+}
+
+// uncheckShoppingList снимает все отметки о купленном
+func (b *BotApp) uncheckShoppingList(chatID int64, messageID int, from *tgbotapi.User) {
+	b.updateShoppingList(chatID, messageID, from, 0, func(userID uint) (*models.ShoppingList, error) {
+		return b.shoppingService.UncheckAll(userID)
+	})
+}
+
+// updateShoppingList меняет отметки в сохраненном списке. Если меню пользователя
+// сменилось, вместо устаревшего списка показывается новый
+func (b *BotApp) updateShoppingList(chatID int64, messageID int, from *tgbotapi.User, page int, change func(userID uint) (*models.ShoppingList, error)) {
+	user, menu, ok := b.loadUserMenu(chatID, from)
+	if !ok {
+		return
+	}
+
+	list, err := change(user.ID)
+	if err != nil || list.MenuID != menu.ID {
+		if err != nil {
+			log.Printf("[updateShoppingList] ERROR: %v", err)
+		}
+		b.showShoppingList(chatID, messageID, from, 0)
+		return
+	}
+	text, rows := b.formatShoppingList(b.tr(chatID), menu, list, page)
+	b.sendOrEdit(chatID, messageID, text, rows)
+}
+
+// formatShoppingList - текст и кнопки списка покупок. Позиции сгруппированы
+// по отделам магазина, кнопки отметок разбиты на страницы
+func (b *BotApp) formatShoppingList(tr *i18n.Localizer, menu *models.WeeklyMenu, list *models.ShoppingList, page int) (string, [][]tgbotapi.InlineKeyboardButton) {
+	var days []string
+	for day := 1; day <= 7; day++ {
+		if list.Days&(1<<(day-1)) != 0 {
+			days = append(days, weekdayShort(tr, day))
+		}
+	}
+	if list.Days == service.AllMenuDays {
+		days = []string{tr.T("shopping.whole_week")}
+	}
+
+	checked := 0
+	for _, item := range list.Items {
+		if item.Checked {
+			checked++
+		}
+	}
+
+	view := render.ShoppingList{Menu: menu, List: list, Days: strings.Join(days, ", "), Checked: checked}
+	for _, item := range list.Items {
+		name := service.SectionName(tr, item.Section)
+		if n := len(view.Sections); n == 0 || view.Sections[n-1].Name != name {
+			view.Sections = append(view.Sections, render.ShoppingSection{Name: name})
+		}
+		section := &view.Sections[len(view.Sections)-1]
+		section.Items = append(section.Items, render.ShoppingLine{
+			Name:    item.Name,
+			Amount:  formatShoppingAmount(tr, item),
+			Checked: item.Checked,
+		})
+	}
+	text := b.render(tr, "shopping", view)
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+
+	pages := (len(list.Items) + shoppingPageSize - 1) / shoppingPageSize
+	if page < 0 || page >= pages {
+		page = 0
+	}
+	start := page * shoppingPageSize
+	end := min(start+shoppingPageSize, len(list.Items))
+	var row []tgbotapi.InlineKeyboardButton
+	for _, item := range list.Items[start:end] {
+		label := "⬜ " + item.Name
+		if item.Checked {
+			label = "✅ " + item.Name
+		}
+		row = append(row, b.userButton(truncateLabel(label, 32), "shop_chk", item.ID, page))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	if pages > 1 {
+		nav := []tgbotapi.InlineKeyboardButton{}
+		if page > 0 {
+			nav = append(nav, b.userButton("◀️", "shop_pg", page-1))
+		}
+		nav = append(nav, b.userButton(fmt.Sprintf("%d/%d", page+1, pages), "noop"))
+		if page < pages-1 {
+			nav = append(nav, b.userButton("▶️", "shop_pg", page+1))
+		}
+		rows = append(rows, nav)
+	}
+
+	dayRow := []tgbotapi.InlineKeyboardButton{}
+	for day := 1; day <= 7; day++ {
+		label := weekdayShort(tr, day)
+		if list.Days&(1<<(day-1)) != 0 {
+			label = "✅" + label
+		}
+		dayRow = append(dayRow, b.userButton(label, "shop_day", day))
+	}
+	rows = append(rows, dayRow)
+
+	less, more := b.userButton("➖", "noop"), b.userButton("➕", "noop")
+	if list.Servings > 1 {
+		less = b.userButton("➖", "shop_srv", list.Servings-1)
+	}
+	if list.Servings < service.MaxServings {
+		more = b.userButton("➕", "shop_srv", list.Servings+1)
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(less, b.userButton(tr.T("shopping.servings", list.Servings), "noop"), more),
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("shopping.all_days"), "shop_all"),
+			b.userButton(tr.T("shopping.uncheck"), "shop_clear"),
+		),
+		tgbotapi.NewInlineKeyboardRow(b.userButton(tr.T("shopping.rebuild"), "shop_build")),
+	)
+	return text, rows
+}
+
+// formatShoppingAmount - количество для списка: граммы, от килограмма - кг, или порции
+func formatShoppingAmount(tr *i18n.Localizer, item models.ShoppingItem) string {
+	if item.Unit == service.UnitPortions {
+		return tr.T("shopping.portions", strconv.FormatFloat(item.Amount, 'f', -1, 64))
+	}
+	if item.Unit != service.UnitGrams {
+		return fmt.Sprintf("%s %s", strconv.FormatFloat(item.Amount, 'f', -1, 64), item.Unit)
+	}
+	grams := math.Round(item.Amount)
+	if grams >= 1000 {
+		return tr.T("shopping.kilograms", strconv.FormatFloat(math.Round(grams/100)/10, 'f', -1, 64))
+	}
+	return tr.T("shopping.grams", grams)
+}
+
+// truncateLabel укорачивает подпись кнопки до limit символов
+func truncateLabel(label string, limit int) string {
+	runes := []rune(label)
+	if len(runes) <= limit {
+		return label
+	}
+	return string(runes[:limit-1]) + "…"
+}
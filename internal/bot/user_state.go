@@ -0,0 +1,324 @@
+package bot
+
+import (
+	"log"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/callback"
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// registerUserCallbacks регистрирует обработчики пользовательских inline-кнопок
+func (b *BotApp) registerUserCallbacks() {
+	r := b.callbacks
+	ns := callback.User
+
+	r.Handle(ns, "noop", func(c *callback.Context) {})
+
+	// Онбординг
+	r.Handle(ns, "ob_start", func(c *callback.Context) {
+		b.startOnboarding(c.ChatID, c.From)
+	})
+	r.Handle(ns, "ob_skip", func(c *callback.Context) {
+		b.userFSM.DeleteState(c.From.ID)
+		b.sendText(c.ChatID, b.tr(c.ChatID).T("onboarding.skipped"))
+	})
+	r.Handle(ns, "ob_sex", b.onboardingStep(1, func(c *callback.Context, state *fsm.State) {
+		b.setOnboardingSex(c.ChatID, c.From, state, c.String(0))
+	}), callback.String)
+	r.Handle(ns, "ob_act", b.onboardingStep(5, func(c *callback.Context, state *fsm.State) {
+		b.setOnboardingActivity(c.ChatID, c.From, state, c.String(0))
+	}), callback.String)
+	r.Handle(ns, "ob_goal", b.onboardingStep(6, func(c *callback.Context, state *fsm.State) {
+		b.finishOnboarding(c.ChatID, c.From, state, c.String(0))
+	}), callback.String)
+
+	// Недельное меню
+	r.Handle(ns, "menu_pg", func(c *callback.Context) {
+		b.showWeeklyMenuForUser(c.ChatID, c.MessageID, c.From, c.Int(0))
+	}, callback.Uint)
+	r.Handle(ns, "menu_pdf", func(c *callback.Context) {
+		b.sendWeeklyMenuPDF(c.ChatID, c.From)
+	})
+	r.Handle(ns, "menu_list", func(c *callback.Context) {
+		b.showMenuPicker(c.ChatID, c.From)
+	})
+	r.Handle(ns, "menu_pick", func(c *callback.Context) {
+		b.chooseMenu(c.ChatID, c.From, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "menu_auto", func(c *callback.Context) {
+		b.autoAssignMenu(c.ChatID, c.From)
+	})
+
+	// Список покупок
+	r.Handle(ns, "shop", func(c *callback.Context) {
+		b.showShoppingList(c.ChatID, 0, c.From, 0)
+	})
+	r.Handle(ns, "shop_pg", func(c *callback.Context) {
+		b.showShoppingList(c.ChatID, c.MessageID, c.From, c.Int(0))
+	}, callback.Uint)
+	r.Handle(ns, "shop_chk", func(c *callback.Context) {
+		b.toggleShoppingItem(c.ChatID, c.MessageID, c.From, c.Uint(0), c.Int(1))
+	}, callback.Uint, callback.Uint)
+	r.Handle(ns, "shop_day", func(c *callback.Context) {
+		day := c.Uint(0)
+		if day < 1 || day > 7 {
+			return
+		}
+		b.rebuildShoppingList(c.ChatID, c.MessageID, c.From, func(days, servings *int) {
+			*days ^= 1 << (day - 1)
+		})
+	}, callback.Uint)
+	r.Handle(ns, "shop_all", func(c *callback.Context) {
+		b.rebuildShoppingList(c.ChatID, c.MessageID, c.From, func(days, servings *int) {
+			*days = service.AllMenuDays
+		})
+	})
+	r.Handle(ns, "shop_srv", func(c *callback.Context) {
+		b.rebuildShoppingList(c.ChatID, c.MessageID, c.From, func(days, servings *int) {
+			*servings = c.Int(0)
+		})
+	}, callback.Uint)
+	r.Handle(ns, "shop_build", func(c *callback.Context) {
+		b.rebuildShoppingList(c.ChatID, c.MessageID, c.From, func(days, servings *int) {})
+	})
+	r.Handle(ns, "shop_clear", func(c *callback.Context) {
+		b.uncheckShoppingList(c.ChatID, c.MessageID, c.From)
+	})
+
+	// Дневник питания
+	r.Handle(ns, "diary", func(c *callback.Context) {
+		b.showFoodDiary(c.ChatID, c.From)
+	})
+	r.Handle(ns, "diary_add", func(c *callback.Context) {
+		b.startDiaryAdd(c.ChatID, c.From)
+	})
+	r.Handle(ns, "diary_summary", func(c *callback.Context) {
+		b.showDiarySummary(c.ChatID, c.From)
+	})
+	r.Handle(ns, "diary_pick", b.withState("diary_add", 0,
+		"diary.pick_expired",
+		func(c *callback.Context, state *fsm.State) {
+			b.selectDiaryDish(c.ChatID, c.From, state, c.Uint(0))
+		}), callback.Uint)
+	r.Handle(ns, "diary_portion", b.withState("diary_add", 2,
+		"diary.pick_expired",
+		func(c *callback.Context, state *fsm.State) {
+			b.finishDiaryAdd(c.ChatID, c.From, state, float64(c.Uint(0))/10)
+		}), callback.Uint)
+	r.Handle(ns, "diary_del", func(c *callback.Context) {
+		b.deleteDiaryEntry(c.ChatID, c.From, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "diary_dish", func(c *callback.Context) {
+		b.addDishToDiary(c.ChatID, c.From, c.Uint(0))
+	}, callback.Uint)
+
+	// Замеры
+	r.Handle(ns, "meas_add", func(c *callback.Context) {
+		b.startMeasure(c.ChatID, c.From)
+	})
+	r.Handle(ns, "meas_skip", b.withState("measure", 0,
+		"measure.expired",
+		func(c *callback.Context, state *fsm.State) {
+			b.nextMeasureStep(c.ChatID, c.From, state)
+		}))
+	r.Handle(ns, "progress", func(c *callback.Context) {
+		b.sendProgress(c.ChatID, c.From, defaultProgressDays)
+	})
+
+	// Напоминания
+	r.Handle(ns, "rem", func(c *callback.Context) {
+		b.showReminderSettings(c.ChatID, 0, c.From)
+	})
+	r.Handle(ns, "rem_meals", func(c *callback.Context) {
+		b.updateReminderSettings(c.ChatID, c.MessageID, c.From, func(s *models.ReminderSettings) {
+			s.MealsEnabled = !s.MealsEnabled
+		})
+	})
+	r.Handle(ns, "rem_workouts", func(c *callback.Context) {
+		b.updateReminderSettings(c.ChatID, c.MessageID, c.From, func(s *models.ReminderSettings) {
+			s.WorkoutsEnabled = !s.WorkoutsEnabled
+		})
+	})
+	r.Handle(ns, "rem_diary", func(c *callback.Context) {
+		b.updateReminderSettings(c.ChatID, c.MessageID, c.From, func(s *models.ReminderSettings) {
+			if s.DiaryTime == "" {
+				s.DiaryTime = service.DefaultDiaryTime
+			} else {
+				s.DiaryTime = ""
+			}
+		})
+	})
+	r.Handle(ns, "rem_day", func(c *callback.Context) {
+		day := c.Uint(0)
+		if day < 1 || day > 7 {
+			return
+		}
+		b.updateReminderSettings(c.ChatID, c.MessageID, c.From, func(s *models.ReminderSettings) {
+			s.WorkoutDays ^= 1 << (day - 1)
+		})
+	}, callback.Uint)
+	r.Handle(ns, "rem_time", func(c *callback.Context) {
+		b.startReminderInput(c.ChatID, c.From, 1)
+	})
+	r.Handle(ns, "rem_quiet", func(c *callback.Context) {
+		b.startReminderInput(c.ChatID, c.From, 2)
+	})
+	r.Handle(ns, "rem_tz", func(c *callback.Context) {
+		b.showTimezonePicker(c.ChatID, c.From)
+	})
+	r.Handle(ns, "rem_tz_set", func(c *callback.Context) {
+		idx := c.Uint(0)
+		if int(idx) >= len(reminderTimezones) {
+			return
+		}
+		b.userFSM.DeleteState(c.From.ID)
+		b.updateReminderSettings(c.ChatID, 0, c.From, func(s *models.ReminderSettings) {
+			s.Timezone = reminderTimezones[idx].zone
+		})
+	}, callback.Uint)
+
+	// Ограничения питания
+	r.Handle(ns, "diet", func(c *callback.Context) {
+		b.showDietSettings(c.ChatID, 0, c.From)
+	})
+	r.Handle(ns, "diet_d", func(c *callback.Context) {
+		idx := c.Uint(0)
+		if int(idx) >= len(service.DietTags) {
+			return
+		}
+		b.updateDietSettings(c.ChatID, c.MessageID, c.From, func(diets, allergens int) (int, int) {
+			return diets ^ service.DietTags[idx].Bit, allergens
+		})
+	}, callback.Uint)
+	r.Handle(ns, "diet_a", func(c *callback.Context) {
+		idx := c.Uint(0)
+		if int(idx) >= len(service.AllergenTags) {
+			return
+		}
+		b.updateDietSettings(c.ChatID, c.MessageID, c.From, func(diets, allergens int) (int, int) {
+			return diets, allergens ^ service.AllergenTags[idx].Bit
+		})
+	}, callback.Uint)
+
+	// Каталоги тренировок и блюд по страницам
+	r.Handle(ns, "trs_pg", func(c *callback.Context) {
+		b.showTrainingsForUser(c.ChatID, c.MessageID, c.Int(0))
+	}, callback.Uint)
+	r.Handle(ns, "nus_pg", func(c *callback.Context) {
+		b.showNutritionForUser(c.ChatID, c.MessageID, c.From, c.Int(0))
+	}, callback.Uint)
+
+	// Категории
+	r.Handle(ns, "cats", func(c *callback.Context) {
+		b.showCategoriesForUser(c.ChatID, c.MessageID)
+	})
+	r.Handle(ns, "cat", func(c *callback.Context) {
+		b.showCategory(c.ChatID, c.MessageID, c.From, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "cat_tr", func(c *callback.Context) {
+		b.showCategoryTrainings(c.ChatID, c.MessageID, c.Uint(0), c.Int(1))
+	}, callback.Uint, callback.Uint)
+	r.Handle(ns, "cat_nu", func(c *callback.Context) {
+		b.showCategoryNutrition(c.ChatID, c.MessageID, c.From, c.Uint(0), c.Int(1))
+	}, callback.Uint, callback.Uint)
+	r.Handle(ns, "tr", func(c *callback.Context) {
+		b.showTrainingCard(c.ChatID, c.MessageID, c.Uint(0), c.Uint(1), c.Int(2))
+	}, callback.Uint, callback.Uint, callback.Uint)
+	r.Handle(ns, "nu", func(c *callback.Context) {
+		b.showNutritionCard(c.ChatID, c.MessageID, c.From, c.Uint(0), c.Uint(1), c.Int(2))
+	}, callback.Uint, callback.Uint, callback.Uint)
+
+	// Поиск: карточки открываются новым сообщением, список результатов остается
+	r.Handle(ns, "sr_tr", func(c *callback.Context) {
+		b.showTrainingCard(c.ChatID, 0, c.Uint(0), 0, 0)
+	}, callback.Uint)
+	r.Handle(ns, "sr_nu", func(c *callback.Context) {
+		b.showNutritionCard(c.ChatID, 0, c.From, c.Uint(0), 0, 0)
+	}, callback.Uint)
+
+	// Программы тренировок: дни недели и упражнения по одному
+	r.Handle(ns, "prog", func(c *callback.Context) {
+		b.showProgram(c.ChatID, c.MessageID, c.Uint(0), c.Int(1))
+	}, callback.Uint, callback.Uint)
+	r.Handle(ns, "pday", func(c *callback.Context) {
+		b.showProgramExercise(c.ChatID, c.MessageID, c.Uint(0), c.Int(1))
+	}, callback.Uint, callback.Uint)
+
+	// Язык интерфейса
+	r.Handle(ns, "lang", func(c *callback.Context) {
+		b.setLanguage(c.ChatID, c.MessageID, c.From, c.String(0))
+	}, callback.String)
+
+	// Журнал тренировок
+	r.Handle(ns, "done", func(c *callback.Context) {
+		b.startLogWorkout(c.ChatID, c.From, c.Uint(0))
+	}, callback.Uint)
+	r.Handle(ns, "dur", b.withState("log_workout", 1,
+		"workout.expired",
+		func(c *callback.Context, state *fsm.State) {
+			b.askWorkoutEffort(c.ChatID, c.From, state, int(c.Uint(0)))
+		}), callback.Uint)
+	r.Handle(ns, "rpe", b.withState("log_workout", 2,
+		"workout.expired",
+		func(c *callback.Context, state *fsm.State) {
+			b.finishLogWorkout(c.ChatID, c.From, state, int(c.Uint(0)))
+		}), callback.Uint)
+}
+
+// withState пропускает нажатие, только если пользователь находится в диалоге action
+// на шаге step (0 - на любом шаге), иначе отвечает сообщением expired из каталога
+func (b *BotApp) withState(action string, step int, expired string, handle func(*callback.Context, *fsm.State)) callback.HandlerFunc {
+	return func(c *callback.Context) {
+		state, exists := b.userFSM.GetState(c.From.ID)
+		if !exists || state.Action != action || (step != 0 && state.Step != step) {
+			b.sendText(c.ChatID, b.tr(c.ChatID).T(expired))
+			return
+		}
+		handle(c, state)
+	}
+}
+
+// onboardingStep - withState для кнопок анкеты
+func (b *BotApp) onboardingStep(step int, handle func(*callback.Context, *fsm.State)) callback.HandlerFunc {
+	return b.withState("onboarding", step, "onboarding.expired", handle)
+}
+
+// userButton - кнопка из пространства пользовательских обработчиков
+func (b *BotApp) userButton(text, action string, params ...any) tgbotapi.InlineKeyboardButton {
+	return b.callbacks.Button(text, callback.User, action, params...)
+}
+
+// handleUserState ведет пользовательский диалог по шагам
+func (b *BotApp) handleUserState(chatID int64, from *tgbotapi.User, state *fsm.State, text string) {
+	log.Println("USER FSM:", state.Action, "STEP:", state.Step, "TEXT:", text)
+
+	// Слово для отмены понимается на любом языке
+	if _, cancel := b.catalog.Find("dialog.cancel_word", strings.ToLower(text)); cancel {
+		b.userFSM.DeleteState(from.ID)
+		b.sendText(chatID, b.tr(chatID).T("dialog.cancelled"))
+		b.showMainMenu(chatID)
+		return
+	}
+
+	switch state.Action {
+	case "log_workout":
+		b.handleLogWorkout(chatID, from, state, text)
+	case "diary_add":
+		b.handleDiaryAdd(chatID, from, state, text)
+	case "onboarding":
+		b.handleOnboarding(chatID, from, state, text)
+	case "measure":
+		b.handleMeasure(chatID, from, state, text)
+	case "reminders":
+		b.handleReminderInput(chatID, from, state, text)
+	case "search":
+		b.handleSearchInput(chatID, from, text)
+	default:
+		b.userFSM.DeleteState(from.ID)
+		b.showMainMenu(chatID)
+	}
+}
@@ -0,0 +1,156 @@
+package bot
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Режимы получения апдейтов
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+)
+
+// secretTokenHeader - заголовок, в котором Telegram присылает секрет вебхука
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// Telegram допускает в секрете только A-Z, a-z, 0-9, _ и -, длиной 1-256
+var secretTokenRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// WebhookConfig - параметры приема апдейтов через вебхук
+type WebhookConfig struct {
+	URL    string // Публичный адрес, который вызывает Telegram (https://host/path)
+	Path   string // Путь маршрута в gin, например "/telegram/webhook"
+	Secret string // Секрет, который Telegram передает в заголовке
+}
+
+// webhookReceiver принимает апдейты по HTTP и складывает их в тот же канал,
+// из которого Run раздает апдейты диспетчеру
+type webhookReceiver struct {
+	secret  string
+	updates chan tgbotapi.Update
+	done    chan struct{}
+
+	mu       sync.RWMutex
+	stopped  bool
+	inflight sync.WaitGroup // Обработчики, которые еще могут положить апдейт в updates
+}
+
+func newWebhookReceiver(secret string, buffer int) *webhookReceiver {
+	return &webhookReceiver{
+		secret:  secret,
+		updates: make(chan tgbotapi.Update, buffer),
+		done:    make(chan struct{}),
+	}
+}
+
+// EnableWebhook регистрирует обработчик вебхука в router и сообщает Telegram адрес.
+// После этого Run получает апдейты из вебхука вместо long polling
+func (b *BotApp) EnableWebhook(router gin.IRouter, cfg WebhookConfig) error {
+	if cfg.URL == "" {
+		return fmt.Errorf("не задан WEBHOOK_URL")
+	}
+	if !secretTokenRe.MatchString(cfg.Secret) {
+		return fmt.Errorf("WEBHOOK_SECRET должен состоять из 1-256 символов A-Z, a-z, 0-9, _ или -")
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/telegram/webhook"
+	}
+
+	receiver := newWebhookReceiver(cfg.Secret, b.API.Buffer)
+	router.POST(cfg.Path, receiver.handle)
+
+	// В библиотеке нет поля secret_token, поэтому вызываем метод напрямую
+	params := tgbotapi.Params{}
+	params.AddNonEmpty("url", cfg.URL)
+	params.AddNonEmpty("secret_token", cfg.Secret)
+	if _, err := b.API.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("не удалось установить вебхук: %w", err)
+	}
+
+	b.webhook = receiver
+	log.Printf("🌐 Webhook enabled at %s", cfg.URL)
+	return nil
+}
+
+// handle проверяет секрет и передает апдейт в общую очередь
+func (w *webhookReceiver) handle(c *gin.Context) {
+	token := c.GetHeader(secretTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(w.secret)) != 1 {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	var update tgbotapi.Update
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid update"})
+		return
+	}
+
+	if !w.enter() {
+		// Бот останавливается - Telegram повторит доставку позже
+		c.AbortWithStatus(http.StatusServiceUnavailable)
+		return
+	}
+	defer w.inflight.Done()
+
+	// 200 отвечаем, только если апдейт попал в очередь: shutdown дождется
+	// этого обработчика и передаст апдейт диспетчеру
+	select {
+	case w.updates <- update:
+		c.Status(http.StatusOK)
+	case <-w.done:
+		c.AbortWithStatus(http.StatusServiceUnavailable)
+	case <-c.Request.Context().Done():
+		c.AbortWithStatus(http.StatusServiceUnavailable)
+	}
+}
+
+// enter регистрирует обработчик, если прием апдейтов еще не остановлен
+func (w *webhookReceiver) enter() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.stopped {
+		return false
+	}
+	w.inflight.Add(1)
+	return true
+}
+
+// shutdown перестает принимать новые апдейты и передает в submit все уже принятые,
+// в том числе от обработчиков, которые еще не завершились
+func (w *webhookReceiver) shutdown(submit func(tgbotapi.Update)) {
+	w.mu.Lock()
+	w.stopped = true
+	close(w.done)
+	w.mu.Unlock()
+
+	idle := make(chan struct{})
+	go func() {
+		w.inflight.Wait()
+		close(idle)
+	}()
+	for {
+		select {
+		case update := <-w.updates:
+			submit(update)
+		case <-idle:
+			// Новых отправителей больше нет - забираем остаток очереди
+			for {
+				select {
+				case update := <-w.updates:
+					submit(update)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
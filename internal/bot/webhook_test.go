@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const testSecret = "test_secret-1"
+
+func newTestWebhook(t *testing.T, buffer int) (*webhookReceiver, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	receiver := newWebhookReceiver(testSecret, buffer)
+	router := gin.New()
+	router.POST("/telegram/webhook", receiver.handle)
+	return receiver, router
+}
+
+func postUpdate(router http.Handler, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(secretTokenHeader, secret)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// Записанный апдейт от Telegram: сообщение /start
+func recordedUpdate(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile("testdata/update_message.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWebhookHandle(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		body     string
+		want     int
+		enqueued bool
+	}{
+		{name: "без секрета", body: "{}", want: http.StatusUnauthorized},
+		{name: "неверный секрет", secret: "wrong", body: "{}", want: http.StatusUnauthorized},
+		{name: "неверный JSON", secret: testSecret, body: `{"update_id":`, want: http.StatusBadRequest},
+		{name: "апдейт", secret: testSecret, body: "", want: http.StatusOK, enqueued: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver, router := newTestWebhook(t, 1)
+			body := tc.body
+			if body == "" {
+				body = recordedUpdate(t)
+			}
+			rec := postUpdate(router, tc.secret, body)
+			if rec.Code != tc.want {
+				t.Fatalf("статус %d, want %d", rec.Code, tc.want)
+			}
+			select {
+			case update := <-receiver.updates:
+				if !tc.enqueued {
+					t.Fatalf("апдейт %d не должен попасть в очередь", update.UpdateID)
+				}
+				if update.UpdateID != 815342001 || update.Message == nil || update.Message.Text != "/start" ||
+					update.Message.From.LanguageCode != "ru" {
+					t.Errorf("апдейт разобран неверно: %+v", update)
+				}
+			default:
+				if tc.enqueued {
+					t.Fatal("апдейт не попал в очередь")
+				}
+			}
+		})
+	}
+}
+
+// После остановки вебхук отвечает 503, чтобы Telegram повторил доставку
+func TestWebhookAfterShutdown(t *testing.T) {
+	receiver, router := newTestWebhook(t, 1)
+	receiver.shutdown(func(tgbotapi.Update) {})
+
+	if rec := postUpdate(router, testSecret, recordedUpdate(t)); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("статус %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if len(receiver.updates) != 0 {
+		t.Error("апдейт попал в очередь после остановки")
+	}
+}
+
+// Каждый апдейт, на который ответили 200, передается диспетчеру, даже если
+// обработчик ждал места в очереди во время остановки
+func TestWebhookShutdownDrainsAccepted(t *testing.T) {
+	receiver, router := newTestWebhook(t, 1)
+	body := recordedUpdate(t)
+
+	const requests = 20
+	codes := make(chan int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- postUpdate(router, testSecret, body).Code
+		}()
+	}
+
+	var mu sync.Mutex
+	submitted := 0
+	receiver.shutdown(func(tgbotapi.Update) {
+		mu.Lock()
+		submitted++
+		mu.Unlock()
+	})
+	wg.Wait()
+	close(codes)
+
+	accepted := 0
+	for code := range codes {
+		switch code {
+		case http.StatusOK:
+			accepted++
+		case http.StatusServiceUnavailable:
+		default:
+			t.Errorf("неожиданный статус %d", code)
+		}
+	}
+	if submitted != accepted {
+		t.Errorf("передано диспетчеру %d, а принято с ответом 200 %d", submitted, accepted)
+	}
+}
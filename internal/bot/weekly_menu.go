@@ -0,0 +1,152 @@
+package bot
+
+import (
+	"log"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// loadUserMenu - пользователь и его недельное меню: личное или общее активное.
+// Если меню нет, сообщает об этом и предлагает выбрать из опубликованных
+func (b *BotApp) loadUserMenu(chatID int64, from *tgbotapi.User) (*models.User, *models.WeeklyMenu, bool) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return nil, nil, false
+	}
+	menu, err := b.menuService.MenuForUser(user)
+	if err != nil {
+		log.Printf("[loadUserMenu] ERROR: %v", err)
+		b.sendText(chatID, tr.T("weekly.error"))
+		return nil, nil, false
+	}
+	if menu == nil {
+		rows := [][]tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardRow(b.userButton(tr.T("weekly.choose"), "menu_list")),
+		}
+		b.sendWithKeyboard(chatID, tr.T("weekly.none"), rows)
+		return nil, nil, false
+	}
+	return user, menu, true
+}
+
+// menuSourceLine - откуда у пользователя это меню
+func (b *BotApp) menuSourceLine(tr *i18n.Localizer, user *models.User) string {
+	current, err := b.menuService.CurrentAssignment(user.ID)
+	if err != nil || current == nil || current.MenuID == 0 {
+		return tr.T("weekly.source_common")
+	}
+	switch current.Source {
+	case service.AssignedAuto:
+		return tr.T("weekly.source_auto")
+	case service.AssignedByAdmin:
+		return tr.T("weekly.source_admin")
+	default:
+		return tr.T("weekly.source_user")
+	}
+}
+
+// showMenuPicker - опубликованные меню со средней калорийностью дня.
+// Меню с блюдами, которые пользователь исключил, не предлагаются
+func (b *BotApp) showMenuPicker(chatID int64, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+	published, err := b.nutritionService.ListPublishedWeeklyMenus()
+	if err != nil {
+		log.Printf("[showMenuPicker] ERROR: %v", err)
+		b.sendText(chatID, tr.T("weekly.list_error"))
+		return
+	}
+
+	var menus []*models.WeeklyMenu
+	for _, menu := range published {
+		conflicts, err := b.menuService.Conflicts(menu.ID, user.Restrictions())
+		if err != nil {
+			log.Printf("[showMenuPicker] ERROR: %v", err)
+			continue
+		}
+		if len(conflicts) == 0 {
+			menus = append(menus, menu)
+		}
+	}
+	hidden := len(published) - len(menus)
+
+	currentID := uint(0)
+	if current, err := b.menuService.CurrentAssignment(user.ID); err == nil && current != nil {
+		currentID = current.MenuID
+	}
+
+	picker := render.MenuPicker{Empty: len(menus) == 0, Hidden: hidden}
+	if user.HasTargets() {
+		picker.CalorieTarget = user.CalorieTarget
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for _, menu := range menus {
+		label := menu.Name
+		if daily, err := b.nutritionService.AverageDailyCalories(menu.ID); err == nil && daily > 0 {
+			label = tr.T("weekly.menu_button", menu.Name, daily)
+		}
+		if menu.ID == currentID {
+			label = "✅ " + label
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			b.userButton(truncateLabel(label, 60), "menu_pick", menu.ID),
+		))
+	}
+	common := tr.T("weekly.common")
+	if currentID == 0 {
+		common = "✅ " + common
+	}
+	bottom := tgbotapi.NewInlineKeyboardRow(b.userButton(common, "menu_pick", 0))
+	if user.HasTargets() && len(menus) > 0 {
+		bottom = append(bottom, b.userButton(tr.T("weekly.auto"), "menu_auto"))
+	}
+	rows = append(rows, bottom)
+
+	b.sendWithKeyboard(chatID, b.render(tr, "menu.picker", picker), rows)
+}
+
+// chooseMenu назначает пользователю выбранное меню (0 - общее) и показывает его
+func (b *BotApp) chooseMenu(chatID int64, from *tgbotapi.User, menuID uint) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+	if err := b.menuService.ChooseMenu(user, menuID); err != nil {
+		b.sendFailure(chatID, tr.T("weekly.choose_error"), err)
+		return
+	}
+	b.showWeeklyMenuForUser(chatID, 0, from, 0)
+}
+
+// autoAssignMenu подбирает меню по дневной норме пользователя
+func (b *BotApp) autoAssignMenu(chatID int64, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+	menu, err := b.menuService.AssignByTarget(user)
+	if err != nil {
+		b.sendFailure(chatID, tr.T("weekly.auto_error"), err)
+		return
+	}
+	if menu == nil {
+		b.sendText(chatID, tr.T("weekly.auto_empty"))
+		return
+	}
+	b.showWeeklyMenuForUser(chatID, 0, from, 0)
+}
@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/fsm"
+	"github.com/alenapavlenkko/telegramfitnes/internal/render"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// startLogWorkout - пользователь нажал «Выполнено» под тренировкой
+func (b *BotApp) startLogWorkout(chatID int64, from *tgbotapi.User, trainingID uint) {
+	tr := b.tr(chatID)
+	training, err := b.trainingService.GetTrainingByID(trainingID)
+	if err != nil {
+		b.sendText(chatID, tr.T("training.not_found"))
+		return
+	}
+
+	b.userFSM.SetState(from.ID, &fsm.State{
+		Action:   "log_workout",
+		Step:     1,
+		EntityID: training.ID,
+		TempData: make(fsm.TempData),
+	})
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			b.userButton(tr.T("workout.planned_duration", training.Duration), "dur", training.Duration),
+		),
+	}
+	b.sendWithKeyboard(chatID, b.render(tr, "workout.start", render.WorkoutStart{Training: training}), rows)
+}
+
+// handleLogWorkout - текстовые ответы в диалоге записи тренировки
+func (b *BotApp) handleLogWorkout(chatID int64, from *tgbotapi.User, state *fsm.State, text string) {
+	tr := b.tr(chatID)
+	switch state.Step {
+	case 1:
+		minutes, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil || minutes <= 0 || minutes > 600 {
+			b.sendText(chatID, tr.T("workout.duration_invalid"))
+			return
+		}
+		b.askWorkoutEffort(chatID, from, state, minutes)
+	case 2:
+		effort, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil || effort < 1 || effort > 10 {
+			b.sendText(chatID, tr.T("workout.effort_invalid"))
+			return
+		}
+		b.finishLogWorkout(chatID, from, state, effort)
+	}
+}
+
+// askWorkoutEffort запоминает длительность и спрашивает ощущаемую нагрузку
+func (b *BotApp) askWorkoutEffort(chatID int64, from *tgbotapi.User, state *fsm.State, minutes int) {
+	tr := b.tr(chatID)
+	state.TempData.Set("duration", minutes)
+	state.Step = 2
+	b.userFSM.SetState(from.ID, state)
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for start := 1; start <= 10; start += 5 {
+		row := []tgbotapi.InlineKeyboardButton{}
+		for effort := start; effort < start+5; effort++ {
+			row = append(row, b.userButton(strconv.Itoa(effort), "rpe", effort))
+		}
+		rows = append(rows, row)
+	}
+	b.sendWithKeyboard(chatID, tr.T("workout.effort"), rows)
+}
+
+// finishLogWorkout сохраняет тренировку и показывает итог недели
+func (b *BotApp) finishLogWorkout(chatID int64, from *tgbotapi.User, state *fsm.State, effort int) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	workout, err := b.workoutService.LogWorkout(service.LogWorkoutDTO{
+		UserID:     user.ID,
+		TrainingID: state.EntityID,
+		Duration:   state.TempData.Int("duration"),
+		Effort:     effort,
+	})
+	if err != nil {
+		log.Printf("[finishLogWorkout] ERROR: %v", err)
+		b.sendFailure(chatID, tr.T("workout.save_error"), err)
+		return
+	}
+	b.userFSM.DeleteState(from.ID)
+
+	view := render.WorkoutLogged{Workout: workout}
+	if week, err := b.workoutService.GetWeeklyTotals(user.ID, b.userNow(user.ID)); err == nil {
+		view.Week = &week
+	}
+	b.sendText(chatID, b.render(tr, "workout.logged", view))
+}
+
+// showWorkoutHistory - «Мои тренировки»: итоги недели и месяца и последние записи
+func (b *BotApp) showWorkoutHistory(chatID int64, from *tgbotapi.User) {
+	tr := b.tr(chatID)
+	user, err := b.authenticateUser(from)
+	if err != nil {
+		b.sendText(chatID, tr.T("error.auth"))
+		return
+	}
+
+	now := b.userNow(user.ID)
+	week, err := b.workoutService.GetWeeklyTotals(user.ID, now)
+	if err != nil {
+		b.sendText(chatID, tr.T("workouts.error"))
+		return
+	}
+	month, err := b.workoutService.GetMonthlyTotals(user.ID, now)
+	if err != nil {
+		b.sendText(chatID, tr.T("workouts.error"))
+		return
+	}
+	recent, err := b.workoutService.ListRecentWorkouts(user.ID, 10)
+	if err != nil {
+		b.sendText(chatID, tr.T("workouts.error"))
+		return
+	}
+
+	if len(recent) == 0 {
+		b.sendText(chatID, tr.T("workouts.empty", tr.T("menu.trainings"), tr.T("training.done")))
+		return
+	}
+
+	b.sendText(chatID, b.render(tr, "workouts", render.WorkoutHistory{Week: week, Month: month, Recent: recent}))
+}
+
+// userNow - текущее время в часовом поясе пользователя: неделя и месяц
+// начинаются в его полночь, как и дни в напоминаниях
+func (b *BotApp) userNow(userID uint) time.Time {
+	return time.Now().In(b.reminderService.Location(userID))
+}
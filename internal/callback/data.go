@@ -0,0 +1,150 @@
+// Package callback кодирует данные inline-кнопок и направляет нажатия обработчикам.
+//
+// Формат данных: "<пространство>:<действие>[:<параметр>...][:<подпись>]",
+// например "a:del_training:42". Подпись - укороченный HMAC-SHA256 остальной строки,
+// добавляется, если задан секрет.
+package callback
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxDataLen - ограничение Telegram на длину callback_data в байтах
+const MaxDataLen = 64
+
+// Длина подписи в символах base64 (48 бит)
+const signatureLen = 8
+
+const separator = ":"
+
+// Namespace - пространство кнопок. От него зависит, кому доступен обработчик
+type Namespace string
+
+const (
+	User  Namespace = "u"
+	Admin Namespace = "a"
+)
+
+// Ошибки разбора данных
+var (
+	ErrMalformed    = errors.New("callback: неверный формат данных")
+	ErrBadSignature = errors.New("callback: неверная подпись")
+	ErrTooLong      = errors.New("callback: данные длиннее 64 байт")
+)
+
+// Data - разобранные данные кнопки
+type Data struct {
+	Namespace Namespace
+	Action    string
+	Params    []string
+}
+
+// Codec кодирует и разбирает данные кнопок, при наличии ключа - с подписью
+type Codec struct {
+	key []byte
+}
+
+// NewCodec создает кодек. Пустой secret отключает подпись
+func NewCodec(secret string) *Codec {
+	c := &Codec{}
+	if secret != "" {
+		c.key = []byte(secret)
+	}
+	return c
+}
+
+// Signed - подписываются ли данные
+func (c *Codec) Signed() bool {
+	return len(c.key) > 0
+}
+
+// Encode собирает данные кнопки. Параметры приводятся к строке через fmt и
+// не должны содержать ":"
+func (c *Codec) Encode(ns Namespace, action string, params ...any) (string, error) {
+	if ns == "" || action == "" || strings.Contains(action, separator) {
+		return "", ErrMalformed
+	}
+
+	parts := make([]string, 0, len(params)+2)
+	parts = append(parts, string(ns), action)
+	for _, p := range params {
+		s := fmt.Sprint(p)
+		if s == "" || strings.Contains(s, separator) {
+			return "", fmt.Errorf("%w: параметр %q", ErrMalformed, s)
+		}
+		parts = append(parts, s)
+	}
+
+	data := strings.Join(parts, separator)
+	if c.Signed() {
+		data += separator + c.sign(data)
+	}
+	if len(data) > MaxDataLen {
+		return "", fmt.Errorf("%w: %q", ErrTooLong, data)
+	}
+	return data, nil
+}
+
+// Decode разбирает и, если включена подпись, проверяет данные кнопки
+func (c *Codec) Decode(raw string) (Data, error) {
+	if len(raw) > MaxDataLen {
+		return Data{}, ErrTooLong
+	}
+
+	payload := raw
+	if c.Signed() {
+		idx := strings.LastIndex(raw, separator)
+		if idx < 0 {
+			return Data{}, ErrBadSignature
+		}
+		payload = raw[:idx]
+		if !hmac.Equal([]byte(raw[idx+1:]), []byte(c.sign(payload))) {
+			return Data{}, ErrBadSignature
+		}
+	}
+
+	parts := strings.Split(payload, separator)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return Data{}, ErrMalformed
+	}
+	return Data{
+		Namespace: Namespace(parts[0]),
+		Action:    parts[1],
+		Params:    parts[2:],
+	}, nil
+}
+
+func (c *Codec) sign(payload string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))[:signatureLen]
+}
+
+// Kind - тип параметра обработчика
+type Kind int
+
+const (
+	String Kind = iota
+	Uint
+	Int
+)
+
+// validate проверяет, что строковый параметр соответствует типу
+func (k Kind) validate(value string) bool {
+	switch k {
+	case Uint:
+		_, err := strconv.ParseUint(value, 10, 64)
+		return err == nil
+	case Int:
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	default:
+		return value != ""
+	}
+}
@@ -0,0 +1,115 @@
+package callback
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		ns     Namespace
+		action string
+		params []any
+		want   []string
+	}{
+		{"без параметров", "secret", User, "menu", nil, []string{}},
+		{"числа и строки", "secret", Admin, "del_training", []any{42, uint(7), -3, "ru"}, []string{"42", "7", "-3", "ru"}},
+		{"без подписи", "", User, "page", []any{2}, []string{"2"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			codec := NewCodec(tc.secret)
+			raw, err := codec.Encode(tc.ns, tc.action, tc.params...)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			got, err := codec.Decode(raw)
+			if err != nil {
+				t.Fatalf("Decode(%q): %v", raw, err)
+			}
+			want := Data{Namespace: tc.ns, Action: tc.action, Params: tc.want}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Decode(%q) = %+v, want %+v", raw, got, want)
+			}
+		})
+	}
+}
+
+func TestCodecEncodeErrors(t *testing.T) {
+	codec := NewCodec("secret")
+	tests := []struct {
+		name   string
+		ns     Namespace
+		action string
+		params []any
+		want   error
+	}{
+		{"нет пространства", "", "menu", nil, ErrMalformed},
+		{"нет действия", User, "", nil, ErrMalformed},
+		{"разделитель в действии", User, "a:b", nil, ErrMalformed},
+		{"разделитель в параметре", User, "menu", []any{"a:b"}, ErrMalformed},
+		{"пустой параметр", User, "menu", []any{""}, ErrMalformed},
+		// Данные не обрезаются до 64 байт: обрезанная кнопка вызвала бы не то действие
+		{"длиннее 64 байт", User, "menu", []any{strings.Repeat("x", 60)}, ErrTooLong},
+		{"длиннее 64 байт с подписью", User, "menu", []any{strings.Repeat("x", 50)}, ErrTooLong},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := codec.Encode(tc.ns, tc.action, tc.params...)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("Encode() = %q, %v, want %v", raw, err, tc.want)
+			}
+			if raw != "" {
+				t.Errorf("вместе с ошибкой возвращены данные %q", raw)
+			}
+		})
+	}
+
+	// Без подписи те же 50 байт параметра укладываются в лимит
+	if _, err := NewCodec("").Encode(User, "menu", strings.Repeat("x", 50)); err != nil {
+		t.Errorf("Encode без подписи: %v", err)
+	}
+}
+
+func TestCodecDecodeErrors(t *testing.T) {
+	signed := NewCodec("secret")
+	valid, err := signed.Encode(Admin, "del_training", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := strings.LastIndex(valid, separator)
+	payload, signature := valid[:idx], valid[idx+1:]
+	otherKey, _ := NewCodec("other").Encode(Admin, "del_training", 42)
+
+	tests := []struct {
+		name  string
+		codec *Codec
+		raw   string
+		want  error
+	}{
+		{"подменен параметр", signed, strings.Replace(valid, ":42:", ":43:", 1), ErrBadSignature},
+		{"подменена подпись", signed, payload + separator + strings.Repeat("A", len(signature)), ErrBadSignature},
+		{"подпись другим ключом", signed, otherKey, ErrBadSignature},
+		{"обрезанная подпись", signed, valid[:len(valid)-1], ErrBadSignature},
+		{"данные без подписи", signed, payload, ErrBadSignature},
+		{"старый формат без разделителя", signed, "delete_training_42", ErrBadSignature},
+		{"пустые данные", signed, "", ErrBadSignature},
+		{"длиннее 64 байт", signed, strings.Repeat("a", MaxDataLen+1), ErrTooLong},
+		{"только пространство", NewCodec(""), "u", ErrMalformed},
+		{"пустое действие", NewCodec(""), "u:", ErrMalformed},
+		{"пустое пространство", NewCodec(""), ":menu", ErrMalformed},
+		{"старый формат без подписи", NewCodec(""), "delete_training_42", ErrMalformed},
+		{"подпись без данных", signed, separator + signed.sign(""), ErrMalformed},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, err := tc.codec.Decode(tc.raw); !errors.Is(err, tc.want) {
+				t.Errorf("Decode(%q) = %+v, %v, want %v", tc.raw, got, err, tc.want)
+			}
+		})
+	}
+}
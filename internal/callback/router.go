@@ -0,0 +1,140 @@
+package callback
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Ошибки маршрутизации
+var (
+	ErrNotFound  = errors.New("callback: обработчик не найден")
+	ErrForbidden = errors.New("callback: нет доступа")
+	ErrParams    = errors.New("callback: неверные параметры")
+)
+
+// Context - нажатие кнопки с уже проверенными параметрами
+type Context struct {
+	Query     *tgbotapi.CallbackQuery
+	From      *tgbotapi.User
+	ChatID    int64
+	MessageID int
+	Data      Data
+}
+
+// String - i-й параметр как строка
+func (c *Context) String(i int) string {
+	if i < 0 || i >= len(c.Data.Params) {
+		return ""
+	}
+	return c.Data.Params[i]
+}
+
+// Uint - i-й параметр как число. Тип проверен роутером, поэтому ошибки нет
+func (c *Context) Uint(i int) uint {
+	v, _ := strconv.ParseUint(c.String(i), 10, 64)
+	return uint(v)
+}
+
+// Int - i-й параметр как знаковое число
+func (c *Context) Int(i int) int {
+	v, _ := strconv.Atoi(c.String(i))
+	return v
+}
+
+// HandlerFunc обрабатывает нажатие кнопки
+type HandlerFunc func(c *Context)
+
+type routeKey struct {
+	ns     Namespace
+	action string
+}
+
+type route struct {
+	handler HandlerFunc
+	params  []Kind
+}
+
+// Router направляет нажатия обработчикам по пространству и действию
+type Router struct {
+	codec  *Codec
+	routes map[routeKey]route
+	guards map[Namespace]func(*tgbotapi.User) bool
+}
+
+// NewRouter создает роутер поверх кодека
+func NewRouter(codec *Codec) *Router {
+	return &Router{
+		codec:  codec,
+		routes: make(map[routeKey]route),
+		guards: make(map[Namespace]func(*tgbotapi.User) bool),
+	}
+}
+
+// Guard задает проверку доступа ко всему пространству ns
+func (r *Router) Guard(ns Namespace, allow func(*tgbotapi.User) bool) {
+	r.guards[ns] = allow
+}
+
+// Handle регистрирует обработчик действия. params - ожидаемые типы параметров
+func (r *Router) Handle(ns Namespace, action string, handler HandlerFunc, params ...Kind) {
+	key := routeKey{ns: ns, action: action}
+	if _, exists := r.routes[key]; exists {
+		panic("callback: повторная регистрация " + string(ns) + separator + action)
+	}
+	r.routes[key] = route{handler: handler, params: params}
+}
+
+// Data кодирует данные кнопки. Ошибка кодирования - ошибка программиста,
+// поэтому она логируется, а кнопка становится пустышкой
+func (r *Router) Data(ns Namespace, action string, params ...any) string {
+	data, err := r.codec.Encode(ns, action, params...)
+	if err != nil {
+		log.Printf("[callback] cannot encode %s:%s %v: %v", ns, action, params, err)
+		data, _ = r.codec.Encode(User, "noop")
+	}
+	return data
+}
+
+// Button - inline-кнопка с закодированными данными
+func (r *Router) Button(text string, ns Namespace, action string, params ...any) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(text, r.Data(ns, action, params...))
+}
+
+// Dispatch разбирает данные нажатия, проверяет доступ и параметры и вызывает обработчик
+func (r *Router) Dispatch(query *tgbotapi.CallbackQuery) error {
+	data, err := r.codec.Decode(query.Data)
+	if err != nil {
+		return err
+	}
+
+	rt, ok := r.routes[routeKey{ns: data.Namespace, action: data.Action}]
+	if !ok {
+		return ErrNotFound
+	}
+	if allow, ok := r.guards[data.Namespace]; ok && !allow(query.From) {
+		return ErrForbidden
+	}
+	if len(data.Params) != len(rt.params) {
+		return ErrParams
+	}
+	for i, kind := range rt.params {
+		if !kind.validate(data.Params[i]) {
+			return ErrParams
+		}
+	}
+
+	ctx := &Context{
+		Query: query,
+		From:  query.From,
+		Data:  data,
+	}
+	if query.Message != nil {
+		ctx.ChatID = query.Message.Chat.ID
+		ctx.MessageID = query.Message.MessageID
+	}
+	rt.handler(ctx)
+	return nil
+}
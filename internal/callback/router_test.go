@@ -0,0 +1,126 @@
+package callback
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const adminID = 1
+
+// testRouter - роутер с подписью, admin-пространством только для adminID и
+// обработчиками, которые записывают вызов в calls
+func testRouter(calls *[]*Context) *Router {
+	r := NewRouter(NewCodec("secret"))
+	r.Guard(Admin, func(u *tgbotapi.User) bool { return u != nil && u.ID == adminID })
+	record := func(c *Context) { *calls = append(*calls, c) }
+	r.Handle(User, "menu", record)
+	r.Handle(User, "day", record, Uint, String)
+	r.Handle(User, "shift", record, Int)
+	r.Handle(Admin, "del_training", record, Uint)
+	return r
+}
+
+func query(data string, userID int64) *tgbotapi.CallbackQuery {
+	return &tgbotapi.CallbackQuery{
+		ID:      "q",
+		From:    &tgbotapi.User{ID: userID},
+		Data:    data,
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+}
+
+func TestRouterDispatch(t *testing.T) {
+	var calls []*Context
+	r := testRouter(&calls)
+	codec := r.codec
+	encode := func(ns Namespace, action string, params ...any) string {
+		t.Helper()
+		data, err := codec.Encode(ns, action, params...)
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name   string
+		data   string
+		userID int64
+		want   error
+	}{
+		{"без параметров", encode(User, "menu"), 2, nil},
+		{"параметры разных типов", encode(User, "day", 3, "lunch"), 2, nil},
+		{"отрицательное число", encode(User, "shift", -1), 2, nil},
+		{"админ", encode(Admin, "del_training", 42), adminID, nil},
+		{"не админ", encode(Admin, "del_training", 42), 2, ErrForbidden},
+		{"неизвестное действие", encode(User, "unknown"), 2, ErrNotFound},
+		{"неизвестное пространство", encode("x", "menu"), 2, ErrNotFound},
+		{"не хватает параметров", encode(User, "day", 3), 2, ErrParams},
+		{"лишний параметр", encode(User, "menu", 1), 2, ErrParams},
+		{"не число", encode(User, "day", "three", "lunch"), 2, ErrParams},
+		{"отрицательное вместо беззнакового", encode(Admin, "del_training", -42), adminID, ErrParams},
+		{"подмена подписанных данных", strings.Replace(encode(Admin, "del_training", 42), "42", "43", 1), adminID, ErrBadSignature},
+		{"данные старой версии бота", "delete_training_42", adminID, ErrBadSignature},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls = nil
+			err := r.Dispatch(query(tc.data, tc.userID))
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("Dispatch(%q) = %v, want %v", tc.data, err, tc.want)
+			}
+			wantCalls := 0
+			if tc.want == nil {
+				wantCalls = 1
+			}
+			if len(calls) != wantCalls {
+				t.Errorf("обработчик вызван %d раз, want %d", len(calls), wantCalls)
+			}
+		})
+	}
+}
+
+func TestRouterContext(t *testing.T) {
+	var calls []*Context
+	r := testRouter(&calls)
+	if err := r.Dispatch(query(r.Data(User, "day", 3, "lunch"), 2)); err != nil {
+		t.Fatal(err)
+	}
+	c := calls[0]
+	if c.ChatID != 100 || c.MessageID != 5 || c.From.ID != 2 {
+		t.Errorf("контекст: chat %d, message %d, from %d", c.ChatID, c.MessageID, c.From.ID)
+	}
+	if c.Uint(0) != 3 || c.String(1) != "lunch" {
+		t.Errorf("параметры: %d, %q", c.Uint(0), c.String(1))
+	}
+	if c.String(5) != "" || c.Uint(-1) != 0 {
+		t.Error("параметр за пределами списка не пустой")
+	}
+}
+
+// Ошибка кодирования не ломает клавиатуру: кнопка становится пустышкой
+func TestRouterDataFallback(t *testing.T) {
+	r := NewRouter(NewCodec("secret"))
+	got := r.Data(User, "menu", strings.Repeat("x", MaxDataLen))
+	data, err := r.codec.Decode(got)
+	if err != nil {
+		t.Fatalf("Decode(%q): %v", got, err)
+	}
+	if data.Namespace != User || data.Action != "noop" || len(data.Params) != 0 {
+		t.Errorf("Data() = %+v, want пустышку u:noop", data)
+	}
+}
+
+func TestRouterHandleTwice(t *testing.T) {
+	r := NewRouter(NewCodec(""))
+	r.Handle(User, "menu", func(*Context) {})
+	defer func() {
+		if recover() == nil {
+			t.Error("повторная регистрация без паники")
+		}
+	}()
+	r.Handle(User, "menu", func(*Context) {})
+}
@@ -0,0 +1,256 @@
+// Package chart рисует простые линейные графики в PNG без внешних зависимостей.
+// Результат зависит только от входных данных, поэтому одинаковые данные
+// всегда дают побайтно одинаковую картинку.
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point - значение в момент времени
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series - одна линия графика
+type Series struct {
+	Name   string
+	Color  color.RGBA
+	Points []Point
+}
+
+// Options - параметры отрисовки
+type Options struct {
+	Title   string
+	Unit    string // Подпись единиц по оси Y, например "кг"
+	Decimal string // Десятичный разделитель подписей оси Y; по умолчанию точка
+	Width   int
+	Height  int
+}
+
+// Палитра линий по умолчанию
+var (
+	Blue   = color.RGBA{0x1f, 0x77, 0xb4, 0xff}
+	Orange = color.RGBA{0xff, 0x7f, 0x0e, 0xff}
+	Green  = color.RGBA{0x2c, 0xa0, 0x2c, 0xff}
+	Red    = color.RGBA{0xd6, 0x27, 0x28, 0xff}
+)
+
+var (
+	background = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	gridColor  = color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+	axisColor  = color.RGBA{0x60, 0x60, 0x60, 0xff}
+	textColor  = color.RGBA{0x20, 0x20, 0x20, 0xff}
+)
+
+// Отступы области построения
+const (
+	marginLeft   = 64
+	marginRight  = 24
+	marginTop    = 64
+	marginBottom = 48
+
+	gridLines = 5
+	maxXTicks = 6
+)
+
+// Render рисует линейный график. Пустые серии пропускаются,
+// если точек нет совсем - возвращается ошибка
+func Render(opts Options, series ...Series) (*image.RGBA, error) {
+	if opts.Width <= 0 {
+		opts.Width = 800
+	}
+	if opts.Height <= 0 {
+		opts.Height = 480
+	}
+	if opts.Width < marginLeft+marginRight+50 || opts.Height < marginTop+marginBottom+50 {
+		return nil, fmt.Errorf("слишком маленький размер графика %dx%d", opts.Width, opts.Height)
+	}
+
+	drawn := make([]Series, 0, len(series))
+	for _, s := range series {
+		if len(s.Points) == 0 {
+			continue
+		}
+		points := append([]Point(nil), s.Points...)
+		sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+		s.Points = points
+		drawn = append(drawn, s)
+	}
+	if len(drawn) == 0 {
+		return nil, fmt.Errorf("нет данных для графика")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	plot := image.Rect(marginLeft, marginTop, opts.Width-marginRight, opts.Height-marginBottom)
+	minT, maxT, minV, maxV := bounds(drawn)
+	minV, maxV, step := niceRange(minV, maxV)
+
+	// Если все точки в один день, растягиваем ось X на сутки, чтобы не делить на ноль
+	if !maxT.After(minT) {
+		minT = minT.Add(-12 * time.Hour)
+		maxT = maxT.Add(12 * time.Hour)
+	}
+	span := maxT.Sub(minT).Seconds()
+
+	toX := func(t time.Time) float32 {
+		return float32(plot.Min.X) + float32(t.Sub(minT).Seconds()/span)*float32(plot.Dx())
+	}
+	toY := func(v float64) float32 {
+		return float32(plot.Max.Y) - float32((v-minV)/(maxV-minV))*float32(plot.Dy())
+	}
+
+	// Сетка и подписи оси Y
+	for i := 0; i <= gridLines; i++ {
+		v := minV + step*float64(i)
+		if v > maxV+step/2 {
+			break
+		}
+		y := toY(v)
+		fillLine(img, float32(plot.Min.X), y, float32(plot.Max.X), y, 1, gridColor)
+		label := formatValue(v, step, opts.Decimal)
+		drawText(img, label, plot.Min.X-8-textWidth(label), int(y)+4, textColor)
+	}
+
+	// Подписи оси X - не больше maxXTicks дат, равномерно по точкам
+	for _, t := range xTicks(drawn, maxXTicks) {
+		x := toX(t)
+		fillLine(img, x, float32(plot.Max.Y), x, float32(plot.Max.Y)+4, 1, axisColor)
+		label := t.Format("02.01")
+		drawText(img, label, int(x)-textWidth(label)/2, plot.Max.Y+20, textColor)
+	}
+
+	// Оси
+	fillLine(img, float32(plot.Min.X), float32(plot.Min.Y), float32(plot.Min.X), float32(plot.Max.Y), 1, axisColor)
+	fillLine(img, float32(plot.Min.X), float32(plot.Max.Y), float32(plot.Max.X), float32(plot.Max.Y), 1, axisColor)
+
+	// Линии и точки
+	for _, s := range drawn {
+		for i := 1; i < len(s.Points); i++ {
+			a, b := s.Points[i-1], s.Points[i]
+			fillLine(img, toX(a.Time), toY(a.Value), toX(b.Time), toY(b.Value), 2.5, s.Color)
+		}
+		for _, p := range s.Points {
+			fillCircle(img, toX(p.Time), toY(p.Value), 4, s.Color)
+		}
+	}
+
+	// Заголовок, единицы и легенда
+	drawText(img, opts.Title, marginLeft, 24, textColor)
+	if opts.Unit != "" {
+		drawText(img, opts.Unit, marginLeft-8-textWidth(opts.Unit), marginTop-12, axisColor)
+	}
+	x := marginLeft
+	for _, s := range drawn {
+		fillRect(img, image.Rect(x, 36, x+12, 48), s.Color)
+		drawText(img, s.Name, x+18, 47, textColor)
+		x += 18 + textWidth(s.Name) + 24
+	}
+
+	return img, nil
+}
+
+// RenderPNG рисует график и кодирует его в PNG
+func RenderPNG(opts Options, series ...Series) ([]byte, error) {
+	img, err := Render(opts, series...)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("не удалось закодировать PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// bounds - общий диапазон времени и значений по всем сериям
+func bounds(series []Series) (minT, maxT time.Time, minV, maxV float64) {
+	minV, maxV = math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for _, p := range s.Points {
+			if minT.IsZero() || p.Time.Before(minT) {
+				minT = p.Time
+			}
+			if p.Time.After(maxT) {
+				maxT = p.Time
+			}
+			minV = math.Min(minV, p.Value)
+			maxV = math.Max(maxV, p.Value)
+		}
+	}
+	return minT, maxT, minV, maxV
+}
+
+// niceRange расширяет диапазон до круглых границ с шагом 1, 2 или 5 × 10^n
+func niceRange(minV, maxV float64) (lo, hi, step float64) {
+	if maxV-minV < 1e-9 {
+		minV -= 1
+		maxV += 1
+	}
+	raw := (maxV - minV) / gridLines
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	step = magnitude * 10
+	for _, m := range []float64{1, 2, 5} {
+		if m*magnitude >= raw {
+			step = m * magnitude
+			break
+		}
+	}
+	lo = math.Floor(minV/step) * step
+	hi = math.Ceil(maxV/step) * step
+	return lo, hi, step
+}
+
+// formatValue печатает подпись оси с точностью шага и десятичным разделителем decimal
+func formatValue(v, step float64, decimal string) string {
+	decimals := 0
+	if step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step)))
+	}
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if s == "-0" {
+		s = "0"
+	}
+	if decimal == "" {
+		return s
+	}
+	return strings.Replace(s, ".", decimal, 1)
+}
+
+// xTicks выбирает не больше limit различных дат для подписей оси X
+func xTicks(series []Series, limit int) []time.Time {
+	seen := map[string]bool{}
+	var days []time.Time
+	for _, s := range series {
+		for _, p := range s.Points {
+			key := p.Time.Format("2006-01-02")
+			if !seen[key] {
+				seen[key] = true
+				days = append(days, p.Time)
+			}
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	if len(days) <= limit {
+		return days
+	}
+
+	ticks := make([]time.Time, 0, limit)
+	for i := 0; i < limit; i++ {
+		ticks = append(ticks, days[i*(len(days)-1)/(limit-1)])
+	}
+	return ticks
+}
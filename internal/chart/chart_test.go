@@ -0,0 +1,156 @@
+package chart
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// go test ./internal/chart -update перезаписывает testdata/*.png
+var update = flag.Bool("update", false, "перезаписать golden-картинки")
+
+var testDay = time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+// points - значения раз в неделю начиная с testDay
+func points(values ...float64) []Point {
+	result := make([]Point, len(values))
+	for i, v := range values {
+		result[i] = Point{Time: testDay.AddDate(0, 0, 7*i), Value: v}
+	}
+	return result
+}
+
+// Каждый график сверяется с testdata/<name>.png попиксельно
+func TestRenderGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		series []Series
+	}{
+		{
+			name:   "single",
+			opts:   Options{Title: "Вес", Unit: "кг", Decimal: ","},
+			series: []Series{{Name: "Вес", Color: Blue, Points: points(64.2, 63.8, 63.9, 63.1, 62.7)}},
+		},
+		{
+			name: "two_series",
+			opts: Options{Title: "Обхваты", Unit: "см", Decimal: ","},
+			series: []Series{
+				{Name: "Талия", Color: Orange, Points: points(74, 73.5, 72, 71.5)},
+				{Name: "Бедра", Color: Green, Points: points(98, 97, 97.5, 96)},
+			},
+		},
+		{
+			name:   "one_point",
+			opts:   Options{Title: "Weight", Unit: "kg"},
+			series: []Series{{Name: "Weight", Color: Blue, Points: points(61.5)}},
+		},
+		{
+			name:   "flat",
+			opts:   Options{Title: "Вес", Unit: "кг", Decimal: ",", Width: 400, Height: 300},
+			series: []Series{{Name: "Вес", Color: Blue, Points: points(60, 60, 60)}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := RenderPNG(tc.opts, tc.series...)
+			if err != nil {
+				t.Fatalf("RenderPNG: %v", err)
+			}
+			golden := filepath.Join("testdata", tc.name+".png")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("нет эталона, запустите с -update: %v", err)
+			}
+			if diff := pixelDiff(t, got, want); diff > 0 {
+				t.Errorf("%s: отличается пикселей: %d", golden, diff)
+			}
+		})
+	}
+}
+
+// Одинаковые данные дают побайтно одинаковую картинку
+func TestRenderDeterministic(t *testing.T) {
+	series := Series{Name: "Вес", Color: Blue, Points: points(64, 63, 62)}
+	first, err := RenderPNG(Options{Title: "Вес"}, series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := RenderPNG(Options{Title: "Вес"}, series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("повторная отрисовка дала другую картинку")
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		v, step float64
+		decimal string
+		want    string
+	}{
+		{"целое при шаге 1", 64, 1, ",", "64"},
+		{"запятая", 63.5, 0.5, ",", "63,5"},
+		{"точка по умолчанию", 63.5, 0.5, "", "63.5"},
+		{"точка", 0.25, 0.05, ".", "0.25"},
+		{"без минуса у нуля", -0.2, 1, ",", "0"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := formatValue(tc.v, tc.step, tc.decimal); got != tc.want {
+				t.Errorf("formatValue(%v, %v, %q) = %q, want %q", tc.v, tc.step, tc.decimal, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	if _, err := Render(Options{}, Series{Name: "пусто"}); err == nil {
+		t.Error("график без точек должен быть ошибкой")
+	}
+	if _, err := Render(Options{Width: 100, Height: 100}, Series{Points: points(1)}); err == nil {
+		t.Error("слишком маленький график должен быть ошибкой")
+	}
+}
+
+// pixelDiff - число различающихся пикселей двух PNG; разный размер - ошибка теста
+func pixelDiff(t *testing.T, got, want []byte) int {
+	t.Helper()
+	decode := func(data []byte) image.Image {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("не удалось разобрать PNG: %v", err)
+		}
+		return img
+	}
+	a, b := decode(got), decode(want)
+	if a.Bounds() != b.Bounds() {
+		t.Fatalf("размер %v, ожидался %v", a.Bounds(), b.Bounds())
+	}
+	diff := 0
+	for y := a.Bounds().Min.Y; y < a.Bounds().Max.Y; y++ {
+		for x := a.Bounds().Min.X; x < a.Bounds().Max.X; x++ {
+			r1, g1, b1, a1 := a.At(x, y).RGBA()
+			r2, g2, b2, a2 := b.At(x, y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				diff++
+			}
+		}
+	}
+	return diff
+}
@@ -0,0 +1,100 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// Шрифт встроен в бинарник, поэтому отрисовка не зависит от системных шрифтов
+var (
+	faceOnce sync.Once
+	face     font.Face
+)
+
+func labelFace() font.Face {
+	faceOnce.Do(func() {
+		parsed, err := opentype.Parse(goregular.TTF)
+		if err != nil {
+			panic("chart: встроенный шрифт поврежден: " + err.Error())
+		}
+		face, err = opentype.NewFace(parsed, &opentype.FaceOptions{
+			Size:    13,
+			DPI:     72,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			panic("chart: не удалось создать начертание: " + err.Error())
+		}
+	})
+	return face
+}
+
+// drawText пишет строку так, что (x, y) - начало базовой линии
+func drawText(img *image.RGBA, text string, x, y int, c color.RGBA) {
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: labelFace(),
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// textWidth - ширина строки в пикселях
+func textWidth(text string) int {
+	return font.MeasureString(labelFace(), text).Ceil()
+}
+
+// fillLine рисует отрезок толщиной width со сглаживанием
+func fillLine(img *image.RGBA, x0, y0, x1, y1, width float32, c color.RGBA) {
+	dx, dy := x1-x0, y1-y0
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		return
+	}
+	// Нормаль к отрезку длиной в половину толщины
+	nx, ny := -dy/length*width/2, dx/length*width/2
+
+	r := newRasterizer(img)
+	r.MoveTo(x0+nx, y0+ny)
+	r.LineTo(x1+nx, y1+ny)
+	r.LineTo(x1-nx, y1-ny)
+	r.LineTo(x0-nx, y0-ny)
+	r.ClosePath()
+	r.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{})
+}
+
+// fillCircle рисует закрашенный круг, аппроксимированный многоугольником
+func fillCircle(img *image.RGBA, cx, cy, radius float32, c color.RGBA) {
+	const segments = 24
+
+	r := newRasterizer(img)
+	r.MoveTo(cx+radius, cy)
+	for i := 1; i < segments; i++ {
+		angle := 2 * math.Pi * float64(i) / segments
+		r.LineTo(cx+radius*float32(math.Cos(angle)), cy+radius*float32(math.Sin(angle)))
+	}
+	r.ClosePath()
+	r.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{})
+}
+
+// fillRect закрашивает прямоугольник без сглаживания
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	draw.Draw(img, rect, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+func newRasterizer(img *image.RGBA) *vector.Rasterizer {
+	size := img.Bounds().Size()
+	r := vector.NewRasterizer(size.X, size.Y)
+	r.DrawOp = draw.Over
+	return r
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// searchMigrations - расширение pg_trgm и индексы, которые использует поиск:
+// полнотекстовый по названию и описанию и триграммный по названию
+var searchMigrations = []string{
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE INDEX IF NOT EXISTS idx_training_programs_fts ON training_programs
+		USING GIN (to_tsvector('russian', COALESCE(title, '') || ' ' || COALESCE(description, '')))`,
+	`CREATE INDEX IF NOT EXISTS idx_training_programs_title_trgm ON training_programs USING GIN (title gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_nutrition_plans_fts ON nutrition_plans
+		USING GIN (to_tsvector('russian', COALESCE(title, '') || ' ' || COALESCE(description, '')))`,
+	`CREATE INDEX IF NOT EXISTS idx_nutrition_plans_title_trgm ON nutrition_plans USING GIN (title gin_trgm_ops)`,
+}
+
+// EnableSearch подготавливает БД к поиску. Вызывается после AutoMigrateTables
+func EnableSearch(db *gorm.DB) error {
+	for _, stmt := range searchMigrations {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to prepare search: %w", err)
+		}
+	}
+	log.Println("✅ Search indexes ready")
+	return nil
+}
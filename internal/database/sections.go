@@ -0,0 +1,23 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// sectionTables - таблицы, в которых хранится отдел магазина
+var sectionTables = []string{"ingredients", "shopping_items"}
+
+// RenameStoreSections заменяет названия отделов магазина, сохраненные текстом,
+// на ключи: keys - ключ отдела по названию. Повторный запуск ничего не меняет
+func RenameStoreSections(db *gorm.DB, keys map[string]string) error {
+	for _, table := range sectionTables {
+		for name, key := range keys {
+			if err := db.Table(table).Where("section = ?", name).Update("section", key).Error; err != nil {
+				return fmt.Errorf("failed to rename store section in %s: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
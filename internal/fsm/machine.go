@@ -0,0 +1,82 @@
+package fsm
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DefaultTTL - сколько живет брошенный мастер, если не задано иное
+const DefaultTTL = 24 * time.Hour
+
+// Machine управляет состояниями диалогов поверх выбранного хранилища.
+// Каждое сохранение продлевает жизнь состояния на ttl.
+type Machine struct {
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// Конструктор FSM
+func NewMachine(store Store, ttl time.Duration) *Machine {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Machine{
+		store: store,
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+// Получить состояние
+func (m *Machine) GetState(userID int64) (*State, bool) {
+	state, ok, err := m.store.Load(userID, m.now())
+	if err != nil {
+		log.Printf("[FSM] failed to load state for user %d: %v", userID, err)
+		return nil, false
+	}
+	if ok && state.TempData == nil {
+		state.TempData = make(TempData)
+	}
+	return state, ok
+}
+
+// Установить состояние
+func (m *Machine) SetState(userID int64, state *State) {
+	if state.TempData == nil {
+		state.TempData = make(TempData)
+	}
+	state.ExpiresAt = m.now().Add(m.ttl)
+
+	if err := m.store.Save(userID, state); err != nil {
+		log.Printf("[FSM] failed to save state for user %d: %v", userID, err)
+	}
+}
+
+// Удалить состояние
+func (m *Machine) DeleteState(userID int64) {
+	if err := m.store.Delete(userID); err != nil {
+		log.Printf("[FSM] failed to delete state for user %d: %v", userID, err)
+	}
+}
+
+// RunJanitor периодически удаляет истекшие состояния, пока не отменен ctx
+func (m *Machine) RunJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := m.store.DeleteExpired(m.now())
+			if err != nil {
+				log.Printf("[FSM] janitor error: %v", err)
+			} else if deleted > 0 {
+				log.Printf("[FSM] janitor removed %d expired states", deleted)
+			}
+		}
+	}
+}
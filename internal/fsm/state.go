@@ -0,0 +1,93 @@
+package fsm
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// State - состояние многошагового диалога пользователя
+type State struct {
+	Action    string
+	Step      int
+	EntityID  uint
+	TempData  TempData
+	ExpiresAt time.Time
+}
+
+// TempData - промежуточные данные мастера.
+// Значения хранятся в JSON, поэтому типы не теряются при сохранении в БД:
+// записанный int читается как int, uint как uint и т.д.
+type TempData map[string]json.RawMessage
+
+// Set записывает значение по ключу
+func (d TempData) Set(key string, value interface{}) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	d[key] = raw
+}
+
+// Has проверяет наличие ключа
+func (d TempData) Has(key string) bool {
+	_, ok := d[key]
+	return ok
+}
+
+// String возвращает строку или "" если ключа нет
+func (d TempData) String(key string) string {
+	var v string
+	d.get(key, &v)
+	return v
+}
+
+// Int возвращает целое число или 0 если ключа нет
+func (d TempData) Int(key string) int {
+	var v int
+	d.get(key, &v)
+	return v
+}
+
+// Uint возвращает беззнаковое число или 0 если ключа нет
+func (d TempData) Uint(key string) uint {
+	var v uint
+	d.get(key, &v)
+	return v
+}
+
+// Float возвращает дробное число или 0 если ключа нет
+func (d TempData) Float(key string) float64 {
+	var v float64
+	d.get(key, &v)
+	return v
+}
+
+// Bool возвращает флаг или false если ключа нет
+func (d TempData) Bool(key string) bool {
+	var v bool
+	d.get(key, &v)
+	return v
+}
+
+func (d TempData) get(key string, dst interface{}) {
+	raw, ok := d[key]
+	if !ok {
+		return
+	}
+	_ = json.Unmarshal(raw, dst)
+}
+
+// clone - глубокая копия, чтобы хранилище не делило данные с вызывающим кодом
+func (d TempData) clone() TempData {
+	c := make(TempData, len(d))
+	for k, v := range d {
+		c[k] = append(json.RawMessage(nil), v...)
+	}
+	return c
+}
+
+func (s *State) clone() *State {
+	c := *s
+	c.TempData = s.TempData.clone()
+	return &c
+}
@@ -0,0 +1,147 @@
+package fsm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Store - хранилище состояний диалогов
+type Store interface {
+	// Load возвращает состояние, если оно есть и не истекло к моменту now
+	Load(userID int64, now time.Time) (*State, bool, error)
+	Save(userID int64, state *State) error
+	Delete(userID int64) error
+	// DeleteExpired удаляет брошенные состояния и возвращает их количество
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// ==================== В ПАМЯТИ ====================
+
+// MemoryStore хранит состояния в памяти процесса (теряются при рестарте)
+type MemoryStore struct {
+	mu     sync.Mutex
+	states map[int64]*State
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: make(map[int64]*State)}
+}
+
+func (s *MemoryStore) Load(userID int64, now time.Time) (*State, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[userID]
+	if !ok {
+		return nil, false, nil
+	}
+	if !state.ExpiresAt.IsZero() && !now.Before(state.ExpiresAt) {
+		delete(s.states, userID)
+		return nil, false, nil
+	}
+	return state.clone(), true, nil
+}
+
+func (s *MemoryStore) Save(userID int64, state *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[userID] = state.clone()
+	return nil
+}
+
+func (s *MemoryStore) Delete(userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, userID)
+	return nil
+}
+
+func (s *MemoryStore) DeleteExpired(now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for userID, state := range s.states {
+		if !state.ExpiresAt.IsZero() && !now.Before(state.ExpiresAt) {
+			delete(s.states, userID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// ==================== В БАЗЕ ДАННЫХ ====================
+
+// DBStore хранит состояния в PostgreSQL и переживает рестарт бота.
+// Scope разделяет состояния разных подсистем в одной таблице.
+type DBStore struct {
+	repo  repository.FSMSessionRepository
+	scope string
+}
+
+func NewDBStore(repo repository.FSMSessionRepository, scope string) *DBStore {
+	return &DBStore{repo: repo, scope: scope}
+}
+
+func (s *DBStore) Load(userID int64, now time.Time) (*State, bool, error) {
+	session, err := s.repo.Find(s.scope, userID, now)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	tempData := make(TempData)
+	if session.TempData != "" {
+		if err := json.Unmarshal([]byte(session.TempData), &tempData); err != nil {
+			return nil, false, fmt.Errorf("повреждены данные состояния: %w", err)
+		}
+	}
+
+	return &State{
+		Action:    session.Action,
+		Step:      session.Step,
+		EntityID:  session.EntityID,
+		TempData:  tempData,
+		ExpiresAt: session.ExpiresAt,
+	}, true, nil
+}
+
+func (s *DBStore) Save(userID int64, state *State) error {
+	tempData := state.TempData
+	if tempData == nil {
+		tempData = make(TempData)
+	}
+	raw, err := json.Marshal(tempData)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Save(&models.FSMSession{
+		Scope:     s.scope,
+		UserID:    userID,
+		Action:    state.Action,
+		Step:      state.Step,
+		EntityID:  state.EntityID,
+		TempData:  string(raw),
+		ExpiresAt: state.ExpiresAt,
+	})
+}
+
+func (s *DBStore) Delete(userID int64) error {
+	return s.repo.Delete(s.scope, userID)
+}
+
+func (s *DBStore) DeleteExpired(now time.Time) (int64, error) {
+	return s.repo.DeleteExpired(now)
+}
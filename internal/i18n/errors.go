@@ -0,0 +1,82 @@
+package i18n
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"sync"
+)
+
+// Error - ошибка, текст которой берется из каталога. Localizer.Error и Localizer.T
+// выводят ее на языке пользователя, а Error() - на основном языке для логов и API
+type Error struct {
+	Key  string
+	Args []any
+	Err  error // Причина; ее текст добавляется через двоеточие
+}
+
+// NewError - ошибка с сообщением key
+func NewError(key string, args ...any) error {
+	return &Error{Key: key, Args: args}
+}
+
+// WrapError - ошибка с сообщением key и причиной err
+func WrapError(err error, key string, args ...any) error {
+	return &Error{Key: key, Args: args, Err: err}
+}
+
+func (e *Error) Error() string {
+	return defaultLocalizer().Error(e)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Text - сообщение каталога как аргумент другого сообщения: переводится на тот же язык
+type Text struct {
+	Key  string
+	Args []any
+}
+
+// Message - сообщение key с аргументами args для подстановки в другое сообщение
+func Message(key string, args ...any) Text {
+	return Text{Key: key, Args: args}
+}
+
+// Error - текст ошибки на языке переводчика. Ошибки каталога переводятся,
+// несколько ошибок (errors.Join) перечисляются через «; », остальные выводятся как есть
+func (l *Localizer) Error(err error) string {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		parts := make([]string, 0, len(joined.Unwrap()))
+		for _, e := range joined.Unwrap() {
+			parts = append(parts, l.Error(e))
+		}
+		return strings.Join(parts, "; ")
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		return err.Error()
+	}
+	text := l.T(e.Key, e.Args...)
+	if e.Err != nil {
+		text += ": " + l.Error(e.Err)
+	}
+	return text
+}
+
+// Default - переводчик на основной язык встроенного каталога: для текстов,
+// у которых нет читателя с известным языком
+func Default() *Localizer {
+	return defaultLocalizer()
+}
+
+// defaultLocalizer - основной язык встроенного каталога, загружается при первой ошибке
+var defaultLocalizer = sync.OnceValue(func() *Localizer {
+	catalog, err := New()
+	if err != nil {
+		log.Printf("[i18n] failed to load catalog: %v", err)
+		catalog = &Catalog{messages: map[string]map[string]string{DefaultLocale: {}}, locales: []string{DefaultLocale}}
+	}
+	return catalog.Localizer(DefaultLocale)
+})
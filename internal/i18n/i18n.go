@@ -0,0 +1,255 @@
+// Package i18n - каталог сообщений бота на разных языках.
+//
+// Сообщения лежат в locales/<язык>/*.json: ключ - текст сообщения, аргументы
+// подставляются по правилам fmt. Основной язык - DefaultLocale: в нем есть все
+// ключи, и из него берется сообщение, которого нет в другом языке.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+//go:embed locales
+var locales embed.FS
+
+// DefaultLocale - основной язык каталога
+const DefaultLocale = "ru"
+
+// Catalog - сообщения всех языков
+type Catalog struct {
+	messages map[string]map[string]string // язык -> ключ -> текст
+	locales  []string                     // Основной язык первым
+}
+
+// New загружает встроенный каталог
+func New() (*Catalog, error) {
+	return Parse(locales, "locales")
+}
+
+// Parse загружает каталог из каталогов dir/<язык> в fsys. Ключ, которого нет в основном
+// языке, или сообщение с другими аргументами, чем в основном языке, - ошибка
+func Parse(fsys fs.FS, dir string) (*Catalog, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать каталог сообщений: %w", err)
+	}
+
+	c := &Catalog{messages: make(map[string]map[string]string)}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		locale := entry.Name()
+		files, err := fs.Glob(fsys, path.Join(dir, locale, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		messages := make(map[string]string)
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, err
+			}
+			var part map[string]string
+			if err := json.Unmarshal(data, &part); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			for key, text := range part {
+				if _, dup := messages[key]; dup {
+					return nil, fmt.Errorf("%s: ключ %q уже задан", file, key)
+				}
+				messages[key] = text
+			}
+		}
+		c.messages[locale] = messages
+		c.locales = append(c.locales, locale)
+	}
+
+	base, ok := c.messages[DefaultLocale]
+	if !ok {
+		return nil, fmt.Errorf("в каталоге нет основного языка %q", DefaultLocale)
+	}
+	slices.SortFunc(c.locales, func(a, b string) int {
+		switch {
+		case a == DefaultLocale:
+			return -1
+		case b == DefaultLocale:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	for _, locale := range c.locales[1:] {
+		missing := 0
+		for key, text := range c.messages[locale] {
+			want, ok := base[key]
+			if !ok {
+				return nil, fmt.Errorf("%s: ключа %q нет в языке %q", locale, key, DefaultLocale)
+			}
+			if !slices.Equal(verbs(text), verbs(want)) {
+				return nil, fmt.Errorf("%s: аргументы %q не совпадают с языком %q", locale, key, DefaultLocale)
+			}
+		}
+		for key := range base {
+			if _, ok := c.messages[locale][key]; !ok {
+				missing++
+			}
+		}
+		if missing > 0 {
+			log.Printf("[i18n] %s: %d messages are not translated, %q is used instead", locale, missing, DefaultLocale)
+		}
+	}
+	return c, nil
+}
+
+// Глаголы fmt в сообщении: у переводов они должны совпадать по порядку
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+func verbs(text string) []string {
+	var result []string
+	for _, verb := range verbPattern.FindAllString(text, -1) {
+		if verb != "%%" {
+			result = append(result, verb)
+		}
+	}
+	return result
+}
+
+// Locales - языки каталога, основной первым
+func (c *Catalog) Locales() []string {
+	return c.locales
+}
+
+// Match - язык каталога для кода языка Telegram ("en", "en-US", "pt-br").
+// Неизвестный или пустой код - основной язык
+func (c *Catalog) Match(languageCode string) string {
+	code, _, _ := strings.Cut(strings.ToLower(languageCode), "-")
+	if _, ok := c.messages[code]; ok {
+		return code
+	}
+	return DefaultLocale
+}
+
+// Supports - есть ли язык в каталоге
+func (c *Catalog) Supports(locale string) bool {
+	_, ok := c.messages[locale]
+	return ok
+}
+
+// Localizer - сообщения на языке locale; неизвестный язык заменяется основным
+func (c *Catalog) Localizer(locale string) *Localizer {
+	if !c.Supports(locale) {
+		locale = DefaultLocale
+	}
+	return &Localizer{locale: locale, catalog: c}
+}
+
+// Find ищет среди ключей с префиксом prefix тот, чей текст на каком-либо языке равен text.
+// Так кнопки клавиатуры узнаются на любом языке, даже если пользователь сменил язык,
+// а клавиатура в чате осталась прежней
+func (c *Catalog) Find(prefix, text string) (string, bool) {
+	for _, locale := range c.locales {
+		for key, message := range c.messages[locale] {
+			if message == text && strings.HasPrefix(key, prefix) {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Localizer переводит сообщения на один язык
+type Localizer struct {
+	locale  string
+	catalog *Catalog
+}
+
+// Locale - язык переводчика
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// T - сообщение key с подставленными args. Если сообщения нет и в основном языке,
+// возвращается сам ключ, чтобы ошибку было видно
+func (l *Localizer) T(key string, args ...any) string {
+	text, ok := l.catalog.messages[l.locale][key]
+	if !ok {
+		if text, ok = l.catalog.messages[DefaultLocale][key]; !ok {
+			log.Printf("[i18n] unknown message %q", key)
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, l.localizeArgs(args)...)
+}
+
+// localizeArgs переводит аргументы-сообщения (Text) и ошибки каталога (Error)
+// на язык переводчика; остальные аргументы не меняются
+func (l *Localizer) localizeArgs(args []any) []any {
+	var localized []any
+	for i, arg := range args {
+		var text string
+		switch v := arg.(type) {
+		case Text:
+			text = l.T(v.Key, v.Args...)
+		case error:
+			var e *Error
+			if !errors.As(v, &e) {
+				continue
+			}
+			text = l.Error(v)
+		default:
+			continue
+		}
+		if localized == nil {
+			localized = append([]any(nil), args...)
+		}
+		localized[i] = text
+	}
+	if localized == nil {
+		return args
+	}
+	return localized
+}
+
+// Plural - форма слова для числа n. Формы перечисляются через «|» в сообщении key:
+// для русского - одна, две, пять («тренировка|тренировки|тренировок»), для английского - одна и много
+func (l *Localizer) Plural(n int, key string) string {
+	return l.Choose(n, strings.Split(l.T(key), "|")...)
+}
+
+// Choose выбирает из forms форму для числа n по правилам языка
+func (l *Localizer) Choose(n int, forms ...string) string {
+	if len(forms) == 0 {
+		return ""
+	}
+	if n < 0 {
+		n = -n
+	}
+	form := len(forms) - 1
+	switch l.locale {
+	case "ru":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			form = 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 10 || n%100 >= 20):
+			form = 1
+		}
+	default:
+		if n == 1 {
+			form = 0
+		}
+	}
+	return forms[min(form, len(forms)-1)]
+}
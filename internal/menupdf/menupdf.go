@@ -0,0 +1,483 @@
+// Package menupdf печатает недельное меню в PDF: сетку «прием пищи × день»
+// с итогами дня и приложение со списком блюд недели.
+// Шрифты встроены в бинарник, результат зависит только от входных данных.
+package menupdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Macros - калории и БЖУ
+type Macros struct {
+	Calories int
+	Protein  float64
+	Carbs    float64
+	Fats     float64
+}
+
+func (m *Macros) add(other Macros) {
+	m.Calories += other.Calories
+	m.Protein += other.Protein
+	m.Carbs += other.Carbs
+	m.Fats += other.Fats
+}
+
+// Meal - блюдо в приеме пищи; Macros - на одну порцию
+type Meal struct {
+	Type   string // Название приема пищи из меню
+	Time   string // 09:00, может быть пустым
+	DishID uint   // По нему блюда сводятся в приложении
+	Dish   string
+	Macros
+}
+
+// Day - колонка сетки
+type Day struct {
+	Name  string
+	Meals []Meal
+}
+
+// Menu - все, что нужно для печати
+type Menu struct {
+	Title         string
+	Description   string
+	MealTypes     []string // Порядок строк сетки; типы не из списка добавляются в конец
+	Days          []Day
+	CalorieTarget int // Дневная норма пользователя; 0 - не показывать
+	GeneratedAt   time.Time
+}
+
+// DishUsage - строка приложения: блюдо и сколько раз оно встречается за неделю
+type DishUsage struct {
+	Title string
+	Count int
+	Macros
+}
+
+// Dishes сводит блюда недели: чаще встречающиеся первыми, затем по алфавиту
+func (m Menu) Dishes() []DishUsage {
+	index := make(map[uint]int)
+	var dishes []DishUsage
+	for _, day := range m.Days {
+		for _, meal := range day.Meals {
+			i, ok := index[meal.DishID]
+			if !ok {
+				i = len(dishes)
+				index[meal.DishID] = i
+				dishes = append(dishes, DishUsage{Title: meal.Dish, Macros: meal.Macros})
+			}
+			dishes[i].Count++
+		}
+	}
+	sort.SliceStable(dishes, func(i, j int) bool {
+		if dishes[i].Count != dishes[j].Count {
+			return dishes[i].Count > dishes[j].Count
+		}
+		return dishes[i].Title < dishes[j].Title
+	})
+	return dishes
+}
+
+// Отступы и размеры сетки в мм; страница - A4 альбомной ориентации
+const (
+	margin     = 10.0
+	labelWidth = 24.0
+	lineHeight = 3.8
+	cellPad    = 1.2
+	fontFamily = "go"
+)
+
+var (
+	black      = [3]int{0x20, 0x20, 0x20}
+	gridBorder = [3]int{0xb0, 0xb0, 0xb0}
+	headerFill = [3]int{0xe8, 0xf0, 0xe8}
+	totalFill  = [3]int{0xf4, 0xf4, 0xf4}
+	mutedText  = [3]int{0x70, 0x70, 0x70}
+)
+
+// Render печатает меню в PDF; подписи берутся из каталога на языке tr
+func Render(menu Menu, tr *i18n.Localizer) ([]byte, error) {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", gobold.TTF)
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, margin)
+	pdf.SetTitle(menu.Title, true)
+	pdf.SetCreator("FitLife", true)
+	pdf.SetCreationDate(menu.GeneratedAt)
+	pdf.SetModificationDate(menu.GeneratedAt)
+	pdf.SetCatalogSort(true)
+	pdf.AliasNbPages("")
+	pdf.SetDrawColor(gridBorder[0], gridBorder[1], gridBorder[2])
+	pdf.SetLineWidth(0.2)
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-margin + 2)
+		setFont(pdf, "", 7, mutedText)
+		pdf.CellFormat(0, 4, tr.T("pdf.footer", menu.Title, menu.GeneratedAt.Format("02.01.2006")),
+			"", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 4, tr.T("pdf.page", pdf.PageNo(), "{nb}"), "", 0, "R", false, 0, "")
+	})
+
+	r := renderer{pdf: pdf, menu: menu, tr: tr}
+	pdf.AddPage()
+	r.writeHeader()
+	r.writeGrid()
+	r.writeAppendix()
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("не удалось сформировать PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+type renderer struct {
+	pdf  *gofpdf.Fpdf
+	menu Menu
+	tr   *i18n.Localizer
+}
+
+func setFont(pdf *gofpdf.Fpdf, style string, size float64, color [3]int) {
+	pdf.SetFont(fontFamily, style, size)
+	pdf.SetTextColor(color[0], color[1], color[2])
+}
+
+func (r *renderer) contentWidth() float64 {
+	width, _ := r.pdf.GetPageSize()
+	return width - 2*margin
+}
+
+// ensureSpace начинает новую страницу, если блок высотой h не помещается
+func (r *renderer) ensureSpace(h float64) bool {
+	_, height := r.pdf.GetPageSize()
+	if r.pdf.GetY()+h <= height-margin-4 {
+		return false
+	}
+	r.pdf.AddPage()
+	return true
+}
+
+func (r *renderer) writeHeader() {
+	pdf := r.pdf
+	setFont(pdf, "B", 16, black)
+	pdf.MultiCell(0, 7, r.menu.Title, "", "L", false)
+	if r.menu.Description != "" {
+		setFont(pdf, "", 9, mutedText)
+		pdf.MultiCell(0, 4.5, r.menu.Description, "", "L", false)
+	}
+
+	var week Macros
+	days := 0
+	for _, day := range r.menu.Days {
+		if len(day.Meals) > 0 {
+			days++
+		}
+		for _, meal := range day.Meals {
+			week.add(meal.Macros)
+		}
+	}
+	summary := r.tr.T("pdf.week_total", week.Calories, grams(week.Protein), grams(week.Fats), grams(week.Carbs))
+	if days > 0 {
+		summary += r.tr.T("pdf.week_average", week.Calories/days)
+	}
+	if r.menu.CalorieTarget > 0 {
+		summary += r.tr.T("pdf.week_target", r.menu.CalorieTarget)
+	}
+	setFont(pdf, "", 9, black)
+	pdf.MultiCell(0, 5, summary, "", "L", false)
+	pdf.Ln(2)
+}
+
+// line - строка текста в ячейке
+type line struct {
+	text  string
+	style string
+	color [3]int
+}
+
+// rowTypes - строки сетки: стандартные приемы пищи и все прочие в порядке появления
+func (r *renderer) rowTypes() []string {
+	types := append([]string(nil), r.menu.MealTypes...)
+	seen := make(map[string]bool, len(types))
+	for _, t := range types {
+		seen[t] = true
+	}
+	for _, day := range r.menu.Days {
+		for _, meal := range day.Meals {
+			if !seen[meal.Type] {
+				seen[meal.Type] = true
+				types = append(types, meal.Type)
+			}
+		}
+	}
+	return types
+}
+
+func (r *renderer) dayWidth() float64 {
+	if len(r.menu.Days) == 0 {
+		return r.contentWidth() - labelWidth
+	}
+	return (r.contentWidth() - labelWidth) / float64(len(r.menu.Days))
+}
+
+func (r *renderer) writeGrid() {
+	if len(r.menu.Days) == 0 {
+		setFont(r.pdf, "", 10, mutedText)
+		r.pdf.MultiCell(0, 6, r.tr.T("pdf.no_days"), "", "L", false)
+		return
+	}
+
+	r.writeGridHeader()
+	for _, mealType := range r.rowTypes() {
+		cells := make([][]line, len(r.menu.Days))
+		for i, day := range r.menu.Days {
+			for _, meal := range day.Meals {
+				if meal.Type != mealType {
+					continue
+				}
+				title := meal.Dish
+				if meal.Time != "" {
+					title = meal.Time + " " + title
+				}
+				cells[i] = append(cells[i],
+					line{text: title, color: black},
+					line{text: r.tr.T("pdf.meal_macros", meal.Calories,
+						grams(meal.Protein), grams(meal.Fats), grams(meal.Carbs)), color: mutedText})
+			}
+		}
+		r.writeGridRow(mealType, cells, nil)
+	}
+
+	calories := make([][]line, len(r.menu.Days))
+	macros := make([][]line, len(r.menu.Days))
+	for i, day := range r.menu.Days {
+		var total Macros
+		for _, meal := range day.Meals {
+			total.add(meal.Macros)
+		}
+		text := r.tr.T("pdf.kcal", total.Calories)
+		if r.menu.CalorieTarget > 0 {
+			text += fmt.Sprintf(" (%d%%)", total.Calories*100/r.menu.CalorieTarget)
+		}
+		calories[i] = []line{{text: text, style: "B", color: black}}
+		macros[i] = []line{
+			{text: r.tr.T("pdf.protein", grams(total.Protein)), color: black},
+			{text: r.tr.T("pdf.fats", grams(total.Fats)), color: black},
+			{text: r.tr.T("pdf.carbs", grams(total.Carbs)), color: black},
+		}
+	}
+	caloriesLabel := r.tr.T("pdf.total")
+	if r.menu.CalorieTarget > 0 {
+		caloriesLabel = r.tr.T("pdf.total_target")
+	}
+	r.writeGridRow(caloriesLabel, calories, &totalFill)
+	r.writeGridRow(r.tr.T("pdf.macros"), macros, &totalFill)
+}
+
+func (r *renderer) writeGridHeader() {
+	pdf := r.pdf
+	setFont(pdf, "B", 9, black)
+	pdf.SetFillColor(headerFill[0], headerFill[1], headerFill[2])
+	pdf.CellFormat(labelWidth, 7, "", "1", 0, "C", true, 0, "")
+	for i, day := range r.menu.Days {
+		ln := 0
+		if i == len(r.menu.Days)-1 {
+			ln = 1
+		}
+		pdf.CellFormat(r.dayWidth(), 7, day.Name, "1", ln, "C", true, 0, "")
+	}
+}
+
+// writeGridRow печатает строку сетки; высота - по самой длинной ячейке
+func (r *renderer) writeGridRow(label string, cells [][]line, fill *[3]int) {
+	pdf := r.pdf
+	width := r.dayWidth()
+
+	wrapped := make([][]line, len(cells))
+	maxLines := 1
+	for i, cell := range cells {
+		for _, l := range cell {
+			setFont(pdf, l.style, 7.5, l.color)
+			for _, part := range pdf.SplitText(l.text, width-2*cellPad) {
+				wrapped[i] = append(wrapped[i], line{text: part, style: l.style, color: l.color})
+			}
+		}
+		if len(wrapped[i]) > maxLines {
+			maxLines = len(wrapped[i])
+		}
+	}
+	setFont(pdf, "B", 7.5, black)
+	labelLines := pdf.SplitText(label, labelWidth-2*cellPad)
+	if len(labelLines) > maxLines {
+		maxLines = len(labelLines)
+	}
+	height := float64(maxLines)*lineHeight + 2*cellPad
+
+	if r.ensureSpace(height) {
+		r.writeGridHeader()
+	}
+	x, y := pdf.GetXY()
+
+	pdf.SetFillColor(headerFill[0], headerFill[1], headerFill[2])
+	pdf.Rect(x, y, labelWidth, height, "FD")
+	r.writeLines(x, y, labelWidth, labelLinesOf(labelLines))
+
+	style := "D"
+	if fill != nil {
+		pdf.SetFillColor(fill[0], fill[1], fill[2])
+		style = "FD"
+	}
+	for i := range cells {
+		cx := x + labelWidth + float64(i)*width
+		pdf.Rect(cx, y, width, height, style)
+		if len(wrapped[i]) == 0 {
+			wrapped[i] = []line{{text: "—", color: mutedText}}
+		}
+		r.writeLines(cx, y, width, wrapped[i])
+	}
+	pdf.SetXY(x, y+height)
+}
+
+func labelLinesOf(parts []string) []line {
+	lines := make([]line, len(parts))
+	for i, part := range parts {
+		lines[i] = line{text: part, style: "B", color: black}
+	}
+	return lines
+}
+
+func (r *renderer) writeLines(x, y, width float64, lines []line) {
+	for i, l := range lines {
+		setFont(r.pdf, l.style, 7.5, l.color)
+		r.pdf.SetXY(x+cellPad, y+cellPad+float64(i)*lineHeight)
+		r.pdf.CellFormat(width-2*cellPad, lineHeight, l.text, "", 0, "L", false, 0, "")
+	}
+}
+
+// Колонки приложения: ключ заголовка в каталоге; ширина 0 - растянуть на оставшееся место
+var appendixColumns = []struct {
+	title string
+	width float64
+	align string
+}{
+	{"pdf.col.number", 10, "C"},
+	{"pdf.col.dish", 0, "L"},
+	{"pdf.col.count", 26, "C"},
+	{"pdf.col.kcal", 22, "R"},
+	{"pdf.col.protein", 22, "R"},
+	{"pdf.col.fats", 22, "R"},
+	{"pdf.col.carbs", 24, "R"},
+}
+
+func (r *renderer) appendixWidths() []float64 {
+	widths := make([]float64, len(appendixColumns))
+	fixed := 0.0
+	for i, column := range appendixColumns {
+		widths[i] = column.width
+		fixed += column.width
+	}
+	for i := range widths {
+		if widths[i] == 0 {
+			widths[i] = r.contentWidth() - fixed
+		}
+	}
+	return widths
+}
+
+// writeAppendix печатает на новой странице все блюда недели с числом повторов
+// и БЖУ на порцию
+func (r *renderer) writeAppendix() {
+	dishes := r.menu.Dishes()
+	if len(dishes) == 0 {
+		return
+	}
+	pdf := r.pdf
+	pdf.AddPage()
+	setFont(pdf, "B", 14, black)
+	pdf.MultiCell(0, 7, r.tr.T("pdf.appendix"), "", "L", false)
+	setFont(pdf, "", 9, mutedText)
+	pdf.MultiCell(0, 5, r.tr.T("pdf.appendix_note"), "", "L", false)
+	pdf.Ln(2)
+
+	widths := r.appendixWidths()
+	r.writeAppendixHeader(widths)
+	portions := 0
+	for i, dish := range dishes {
+		portions += dish.Count
+		values := []string{
+			fmt.Sprint(i + 1),
+			dish.Title,
+			fmt.Sprint(dish.Count),
+			fmt.Sprint(dish.Calories),
+			grams(dish.Protein),
+			grams(dish.Fats),
+			grams(dish.Carbs),
+		}
+		r.writeAppendixRow(widths, values, "", nil)
+	}
+	r.writeAppendixRow(widths, []string{"", r.tr.T("pdf.dishes_total", len(dishes)), fmt.Sprint(portions), "", "", "", ""}, "B", &totalFill)
+}
+
+func (r *renderer) writeAppendixHeader(widths []float64) {
+	pdf := r.pdf
+	setFont(pdf, "B", 8.5, black)
+	pdf.SetFillColor(headerFill[0], headerFill[1], headerFill[2])
+	for i, column := range appendixColumns {
+		ln := 0
+		if i == len(appendixColumns)-1 {
+			ln = 1
+		}
+		pdf.CellFormat(widths[i], 7, r.tr.T(column.title), "1", ln, "C", true, 0, "")
+	}
+}
+
+func (r *renderer) writeAppendixRow(widths []float64, values []string, style string, fill *[3]int) {
+	pdf := r.pdf
+	setFont(pdf, style, 8.5, black)
+	titleLines := pdf.SplitText(values[1], widths[1]-2*cellPad)
+	if len(titleLines) == 0 {
+		titleLines = []string{""}
+	}
+	height := float64(len(titleLines))*4.2 + 2*cellPad
+	if r.ensureSpace(height) {
+		r.writeAppendixHeader(widths)
+		setFont(pdf, style, 8.5, black)
+	}
+
+	x, y := pdf.GetXY()
+	rectStyle := "D"
+	if fill != nil {
+		pdf.SetFillColor(fill[0], fill[1], fill[2])
+		rectStyle = "FD"
+	}
+	cx := x
+	for i, value := range values {
+		pdf.Rect(cx, y, widths[i], height, rectStyle)
+		lines := []string{value}
+		if i == 1 {
+			lines = titleLines
+		}
+		for j, text := range lines {
+			pdf.SetXY(cx+cellPad, y+cellPad+float64(j)*4.2)
+			pdf.CellFormat(widths[i]-2*cellPad, 4.2, text, "", 0, appendixColumns[i].align, false, 0, "")
+		}
+		cx += widths[i]
+	}
+	pdf.SetXY(x, y+height)
+}
+
+// grams - граммы без лишних нулей: 12, 12.5
+func grams(value float64) string {
+	text := fmt.Sprintf("%.1f", value)
+	return strings.TrimSuffix(text, ".0")
+}
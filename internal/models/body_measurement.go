@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BodyMeasurement - замеры тела пользователя.
+// Незаполненный замер хранится как 0
+type BodyMeasurement struct {
+	gorm.Model
+	UserID     uint      `gorm:"not null;index"`
+	MeasuredAt time.Time `gorm:"not null;index"` // Когда сделан замер
+	WeightKg   float64   // Вес, кг
+	WaistCm    float64   // Талия, см
+	HipsCm     float64   // Бедра, см
+	ChestCm    float64   // Грудь, см
+}
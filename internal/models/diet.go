@@ -0,0 +1,40 @@
+package models
+
+// Диеты, которым соответствует блюдо (биты NutritionPlan.Diets и User.Diets)
+const (
+	DietVegetarian = 1 << iota
+	DietVegan
+	DietGlutenFree
+	DietLactoseFree
+	DietHalal
+)
+
+// Аллергены в составе блюда (биты NutritionPlan.Allergens и User.ExcludedAllergens)
+const (
+	AllergenGluten = 1 << iota
+	AllergenMilk
+	AllergenEggs
+	AllergenNuts
+	AllergenPeanuts
+	AllergenFish
+	AllergenShellfish
+	AllergenSoy
+	AllergenSesame
+)
+
+// DishFilter - ограничения питания: блюдо должно соответствовать всем
+// диетам Diets и не содержать ни одного аллергена из Allergens
+type DishFilter struct {
+	Diets     int
+	Allergens int
+}
+
+// Empty - ограничений нет
+func (f DishFilter) Empty() bool {
+	return f.Diets == 0 && f.Allergens == 0
+}
+
+// Matches - подходит ли блюдо под ограничения
+func (f DishFilter) Matches(plan *NutritionPlan) bool {
+	return plan.Diets&f.Diets == f.Diets && plan.Allergens&f.Allergens == 0
+}
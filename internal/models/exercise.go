@@ -0,0 +1,37 @@
+package models
+
+import "gorm.io/gorm"
+
+// Exercise - упражнение из справочника
+type Exercise struct {
+	gorm.Model
+	Name        string `gorm:"size:255;not null;index"`
+	MuscleGroup string `gorm:"size:100"`  // Основные мышцы, например «ноги, ягодицы»
+	Equipment   string `gorm:"size:100"`  // Инвентарь, пусто - без инвентаря
+	Description string `gorm:"type:text"` // Техника выполнения
+	YouTubeLink string `gorm:"type:text"`
+}
+
+// ProgramDay - тренировочный день программы
+type ProgramDay struct {
+	gorm.Model
+	ProgramID uint              `gorm:"not null;index"`
+	Week      int               `gorm:"not null;default:1"` // Неделя программы, с 1
+	DayNumber int               `gorm:"not null"`           // 1-7 (понедельник-воскресенье)
+	Title     string            `gorm:"size:100"`           // Например, «Ноги и ягодицы»
+	Exercises []ProgramExercise `gorm:"foreignKey:ProgramDayID"`
+}
+
+// ProgramExercise - упражнение в дне программы: подходы, повторы, отдых, темп и вес
+type ProgramExercise struct {
+	gorm.Model
+	ProgramDayID uint     `gorm:"not null;index"`
+	ExerciseID   uint     `gorm:"not null;index"`
+	Exercise     Exercise `gorm:"foreignKey:ExerciseID"`
+	Position     int      // Порядок выполнения в дне
+	Sets         int      `gorm:"not null"`
+	Reps         string   `gorm:"size:20;not null"` // «10», «8-12» или «30 сек»
+	RestSeconds  int      // Отдых между подходами
+	Tempo        string   `gorm:"size:20"`  // Эксцентрика-пауза-концентрика-пауза, например 3-1-1-0
+	Weight       string   `gorm:"size:100"` // Рекомендация по весу: «60% от 1ПМ», «собственный вес»
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FoodDiaryEntry - запись в дневнике питания пользователя.
+// КБЖУ копируются из блюда на момент записи (с учетом порции),
+// чтобы правка каталога не меняла уже записанную историю
+type FoodDiaryEntry struct {
+	gorm.Model
+	UserID      uint          `gorm:"not null;index:idx_diary_user_date"`
+	Date        time.Time     `gorm:"type:date;not null;index:idx_diary_user_date"` // День, к которому относится запись
+	NutritionID uint          `gorm:"not null"`
+	Nutrition   NutritionPlan `gorm:"foreignKey:NutritionID"`
+	Title       string        `gorm:"size:255;not null"`
+	Portion     float64       `gorm:"not null;default:1"` // Множитель порции (0.5, 1, 1.5...)
+	Calories    int
+	Protein     float64
+	Carbs       float64
+	Fats        float64
+}
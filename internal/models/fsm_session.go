@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// FSMSession - сохраненное состояние многошагового диалога (мастера)
+type FSMSession struct {
+	Scope     string    `gorm:"primaryKey;size:20"` // Пространство состояний ("admin", ...)
+	UserID    int64     `gorm:"primaryKey;autoIncrement:false"`
+	Action    string    `gorm:"size:50;not null"`
+	Step      int       `gorm:"not null"`
+	EntityID  uint      // ID редактируемой сущности
+	TempData  string    `gorm:"type:jsonb;not null;default:'{}'"` // Промежуточные данные мастера
+	ExpiresAt time.Time `gorm:"index;not null"`                   // После этого момента состояние считается брошенным
+	UpdatedAt time.Time
+}
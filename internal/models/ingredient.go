@@ -0,0 +1,24 @@
+package models
+
+import "gorm.io/gorm"
+
+// Ingredient - продукт с пищевой ценностью на 100 г
+type Ingredient struct {
+	gorm.Model
+	Name     string  `gorm:"size:255;not null;index"`
+	Section  string  `gorm:"size:64"` // Отдел магазина для списка покупок
+	Calories float64 // ккал на 100 г
+	Protein  float64 // г на 100 г
+	Carbs    float64 // г на 100 г
+	Fats     float64 // г на 100 г
+}
+
+// RecipeItem - ингредиент в рецепте блюда, граммы на одну порцию
+type RecipeItem struct {
+	gorm.Model
+	NutritionID  uint       `gorm:"not null;index"`
+	IngredientID uint       `gorm:"not null;index"`
+	Ingredient   Ingredient `gorm:"foreignKey:IngredientID"`
+	Grams        float64    `gorm:"not null"`
+	Position     int        // Порядок в списке ингредиентов
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// MenuAssignment - назначение недельного меню пользователю. Действует последнее
+// назначение, предыдущие остаются историей
+type MenuAssignment struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"not null;index:idx_menu_assignment_user"`
+	MenuID     uint      // 0 - общее активное меню
+	Source     string    `gorm:"size:10;not null"` // user, admin, auto
+	AssignedBy int64     // Telegram ID администратора, назначившего меню
+	CreatedAt  time.Time `gorm:"index:idx_menu_assignment_user"`
+}
@@ -11,7 +11,11 @@ type NutritionPlan struct {
 	Carbs       float64
 	Fats        float64
 	CategoryID  uint
-	Category    Category `gorm:"foreignKey:CategoryID"`
+	Category    Category     `gorm:"foreignKey:CategoryID"`
+	Diets       int          `gorm:"not null;default:0"` // Маска диет, которым соответствует блюдо (Diet*)
+	Allergens   int          `gorm:"not null;default:0"` // Маска аллергенов в составе (Allergen*)
+	Steps       string       `gorm:"type:text"`          // Шаги приготовления, по одному на строку
+	RecipeItems []RecipeItem `gorm:"foreignKey:NutritionID"`
 }
 
 // ==================== НОВЫЕ МОДЕЛИ ДЛЯ НЕДЕЛЬНОГО МЕНЮ ====================
@@ -22,7 +26,9 @@ type WeeklyMenu struct {
 	Name          string    `gorm:"size:255;not null"` // Название меню
 	Description   string    `gorm:"type:text"`         // Описание
 	TotalCalories int       // Общее количество калорий за неделю
-	Active        bool      `gorm:"default:false"` // Активно ли меню (только одно может быть активным)
+	Active        bool      `gorm:"default:false"` // Общее меню для пользователей без своего (только одно может быть активным)
+	Published     bool      `gorm:"default:false"` // Доступно пользователям для выбора
+	GeneratorSeed int64     // Seed генератора, которым собрано меню; 0 - меню составлено вручную
 	Days          []MenuDay `gorm:"foreignKey:MenuID"`
 }
 
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ReminderSettings - настройки напоминаний пользователя.
+// Если записи нет, действуют настройки по умолчанию (см. service.DefaultReminderSettings)
+type ReminderSettings struct {
+	UserID          uint   `gorm:"primaryKey;autoIncrement:false"`
+	Timezone        string `gorm:"size:64;not null"`           // IANA, например "Europe/Moscow"
+	MealsEnabled    bool   `gorm:"not null"`                   // Напоминать о приемах пищи из недельного меню
+	WorkoutsEnabled bool   `gorm:"not null"`                   // Напоминать о запланированных тренировках
+	WorkoutDays     int    `gorm:"not null"`                   // Дни тренировок: бит 0 - понедельник ... бит 6 - воскресенье
+	WorkoutTime     string `gorm:"size:5;not null"`            // Время тренировки "18:00"
+	DiaryTime       string `gorm:"size:5;not null;default:''"` // Время итога дня по дневнику питания "21:00", пусто - не присылать
+	QuietFrom       string `gorm:"size:5"`                     // Начало тихих часов "23:00", пусто - без тихих часов
+	QuietTo         string `gorm:"size:5"`                     // Конец тихих часов "08:00"
+	UpdatedAt       time.Time
+}
+
+// SentReminder - отметка об отправленном напоминании, чтобы не слать его повторно
+type SentReminder struct {
+	UserID uint      `gorm:"primaryKey;autoIncrement:false"`
+	Key    string    `gorm:"primaryKey;size:100"` // Например "meal:2024-05-20:42"
+	SentAt time.Time `gorm:"index;not null"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// Виды результатов поиска
+const (
+	SearchTraining  = "training"
+	SearchNutrition = "nutrition"
+)
+
+// SearchHit - тренировка или блюдо, найденные поиском, с полями для карточки. Не хранится в БД
+type SearchHit struct {
+	Kind        string // SearchTraining или SearchNutrition
+	ID          uint
+	Title       string
+	Description string
+	Category    string
+	Rank        float64 // Чем больше, тем выше в выдаче
+
+	// Только у тренировок
+	Duration    int // Минуты
+	Difficulty  string
+	YouTubeLink string `gorm:"column:youtube_link"`
+	Weeks       int
+
+	// Только у блюд
+	Calories  int
+	Protein   float64
+	Carbs     float64
+	Fats      float64
+	Diets     int
+	Allergens int
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ShoppingList - список покупок пользователя по недельному меню.
+// У пользователя один список: при новой сборке он заменяется целиком
+type ShoppingList struct {
+	UserID    uint           `gorm:"primaryKey;autoIncrement:false"`
+	MenuID    uint           `gorm:"not null"`
+	Days      int            `gorm:"not null"` // Дни меню: бит 0 - понедельник ... бит 6 - воскресенье
+	Servings  int            `gorm:"not null;default:1"`
+	Items     []ShoppingItem `gorm:"foreignKey:UserID;references:UserID"`
+	UpdatedAt time.Time
+}
+
+// ShoppingItem - позиция списка покупок. Количество уже просуммировано
+// по выбранным дням и умножено на число порций
+type ShoppingItem struct {
+	ID           uint    `gorm:"primaryKey"`
+	UserID       uint    `gorm:"not null;index"`
+	IngredientID uint    // 0 - блюдо без состава, покупается целиком
+	NutritionID  uint    // Блюдо без состава
+	Name         string  `gorm:"size:255;not null"`
+	Section      string  `gorm:"size:64;not null"`
+	Amount       float64 `gorm:"not null"`
+	Unit         string  `gorm:"size:16;not null"` // "г" или "порц."
+	Checked      bool    `gorm:"not null"`
+	Position     int     // Порядок в списке: по отделам, внутри - по названию
+}
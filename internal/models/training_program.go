@@ -3,12 +3,14 @@ package models
 import "gorm.io/gorm"
 
 type TrainingProgram struct {
-	gorm.Model           // добавляет ID, CreatedAt, UpdatedAt, DeletedAt
-	Title       string   `gorm:"type:varchar(100);not null"`
-	Description string   `gorm:"type:text"`
-	Difficulty  string   `gorm:"type:varchar(50)"`
-	Duration    int      `gorm:"not null"` // длительность в минутах
-	CategoryID  *uint    // связь с Category
-	Category    Category `gorm:"foreignKey:CategoryID"`
-	YouTubeLink string   `gorm:"type:text"`
+	gorm.Model               // добавляет ID, CreatedAt, UpdatedAt, DeletedAt
+	Title       string       `gorm:"type:varchar(100);not null"`
+	Description string       `gorm:"type:text"`
+	Difficulty  string       `gorm:"type:varchar(50)"`
+	Duration    int          `gorm:"not null"` // длительность в минутах
+	CategoryID  *uint        // связь с Category
+	Category    Category     `gorm:"foreignKey:CategoryID"`
+	YouTubeLink string       `gorm:"type:text"`
+	Weeks       int          `gorm:"not null;default:1"` // Сколько недель длится программа
+	Days        []ProgramDay `gorm:"foreignKey:ProgramID"`
 }
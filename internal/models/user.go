@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
 	gorm.Model
@@ -11,4 +15,41 @@ type User struct {
 	Name       string
 	Role       string `gorm:"default:'user'"`
 	IsAdmin    bool
+
+	// Профиль для расчета дневной нормы (заполняется при онбординге)
+	Sex           string  `gorm:"size:10"` // "male", "female"
+	Age           int     // лет
+	HeightCm      float64 // см
+	WeightKg      float64 // кг
+	ActivityLevel string  `gorm:"size:20"` // sedentary, light, moderate, active, very_active
+	Goal          string  `gorm:"size:20"` // lose, maintain, gain
+
+	// Рассчитанные нормы
+	BMR                int     // Базовый обмен (Миффлин-Сан Жеор), ккал
+	TDEE               int     // Расход с учетом активности, ккал
+	CalorieTarget      int     // Дневная норма с учетом цели, ккал
+	ProteinTarget      float64 // г
+	CarbsTarget        float64 // г
+	FatsTarget         float64 // г
+	ProfileCompletedAt *time.Time
+
+	// Ограничения питания: диеты, которых придерживается пользователь,
+	// и аллергены, которые нужно исключить (маски Diet* и Allergen*)
+	Diets             int `gorm:"not null;default:0"`
+	ExcludedAllergens int `gorm:"not null;default:0"`
+
+	// Язык интерфейса, выбранный пользователем (пусто - язык Telegram),
+	// и последний известный язык Telegram - по нему пишутся напоминания
+	Locale       string `gorm:"size:8"`
+	LanguageCode string `gorm:"size:16"`
+}
+
+// Restrictions - ограничения питания пользователя как фильтр блюд
+func (u *User) Restrictions() DishFilter {
+	return DishFilter{Diets: u.Diets, Allergens: u.ExcludedAllergens}
+}
+
+// HasTargets - заполнен ли профиль и рассчитана ли норма
+func (u *User) HasTargets() bool {
+	return u.CalorieTarget > 0
 }
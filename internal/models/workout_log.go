@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WorkoutLog - выполненная пользователем тренировка
+type WorkoutLog struct {
+	gorm.Model
+	UserID      uint            `gorm:"not null;index"`
+	TrainingID  uint            `gorm:"not null;index"`
+	Training    TrainingProgram `gorm:"foreignKey:TrainingID"`
+	PerformedAt time.Time       `gorm:"not null;index"` // Когда выполнена
+	Duration    int             `gorm:"not null"`       // Фактическая длительность в минутах
+	Effort      int             `gorm:"not null"`       // Ощущаемая нагрузка (RPE) от 1 до 10
+}
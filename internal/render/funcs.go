@@ -0,0 +1,78 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+)
+
+// funcs - функции, доступные в шаблонах языка переводчика tr
+func funcs(tr *i18n.Localizer) template.FuncMap {
+	return template.FuncMap{
+		"add": func(a, b int) int { return a + b },
+		"sub": func(a, b int) int { return a - b },
+		// КБЖУ и замеры с одним знаком после запятой
+		"macro": func(v float64) string { return strconv.FormatFloat(v, 'f', 1, 64) },
+		"round": func(v float64) string { return strconv.FormatFloat(v, 'f', 0, 64) },
+		// Число без лишних нулей: 1, 1.5, 0.75
+		"number": func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) },
+		"date":   func(t time.Time) string { return t.Format("02.01.2006") },
+		"day":    func(t time.Time) string { return t.Format("02.01") },
+		"join":   strings.Join,
+		"lower":  strings.ToLower,
+		// {{.Description | truncate 600}}
+		"truncate": func(limit int, s string) string {
+			runes := []rune(s)
+			if len(runes) <= limit {
+				return s
+			}
+			return string(runes[:limit-1]) + "…"
+		},
+		// {{t "common.on"}} - сообщение из каталога
+		"t": tr.T,
+		// {{plural .Count "тренировка" "тренировки" "тренировок"}}
+		"plural": tr.Choose,
+		"onOff": func(enabled bool) string {
+			if enabled {
+				return tr.T("common.on")
+			}
+			return tr.T("common.off")
+		},
+		"diets":        func(mask int) []string { return TagLabels(tr, service.DietTags, mask) },
+		"allergens":    func(mask int) []string { return TagLabels(tr, service.AllergenTags, mask) },
+		"rest":         func(seconds int) string { return Rest(tr, seconds) },
+		"itemCalories": service.ItemCalories,
+
+		escapeTextFunc: escapeText,
+		escapeAttrFunc: escapeAttr,
+	}
+}
+
+// TagLabels - подписи диет или аллергенов маски на языке tr (сообщения tag.<ключ>)
+func TagLabels(tr *i18n.Localizer, tags []service.DietTag, mask int) []string {
+	var labels []string
+	for _, tag := range tags {
+		if mask&tag.Bit != 0 {
+			labels = append(labels, tr.T("tag."+tag.Key))
+		}
+	}
+	return labels
+}
+
+// Rest - отдых между подходами: «45 сек», «2 мин», «1 мин 30 сек»
+func Rest(tr *i18n.Localizer, seconds int) string {
+	switch {
+	case seconds <= 0:
+		return tr.T("rest.none")
+	case seconds < 60:
+		return tr.T("rest.seconds", seconds)
+	case seconds%60 == 0:
+		return tr.T("rest.minutes", seconds/60)
+	default:
+		return tr.T("rest.minutes_seconds", seconds/60, seconds%60)
+	}
+}
@@ -0,0 +1,219 @@
+// Package render - тексты сообщений бота: шаблоны text/template, размеченные
+// для HTML parse mode Telegram. Каждое подставляемое значение экранируется
+// по месту вставки - в тексте и в значении атрибута тега; вставка внутрь
+// самого тега считается ошибкой шаблона. Шаблоны встроены в бинарник
+// и лежат в templates/<язык> для каждого языка каталога i18n.
+package render
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// ParseMode - режим разметки, в котором отправляется результат Render
+const ParseMode = "HTML"
+
+// HTML - уже размеченный текст. В тексте сообщения вставляется как есть,
+// поэтому может содержать только разметку, собранную из других шаблонов
+type HTML string
+
+// Renderer собирает сообщения по именованным шаблонам на языке пользователя
+type Renderer struct {
+	sets map[string]*template.Template // Шаблоны по языкам каталога
+}
+
+// New разбирает встроенные шаблоны бота на всех языках каталога
+func New(catalog *i18n.Catalog) (*Renderer, error) {
+	return Parse(templates, "templates", catalog)
+}
+
+// Parse разбирает шаблоны dir/<язык>/*.tmpl из fsys и расставляет в них экранирование.
+// Основа каждого языка - шаблоны основного языка каталога: шаблон другого языка
+// заменяет их по имени, а непереведенные остаются как есть
+func Parse(fsys fs.FS, dir string, catalog *i18n.Catalog) (*Renderer, error) {
+	r := &Renderer{sets: make(map[string]*template.Template)}
+	for _, locale := range catalog.Locales() {
+		patterns := []string{path.Join(dir, i18n.DefaultLocale, "*.tmpl")}
+		if locale != i18n.DefaultLocale {
+			pattern := path.Join(dir, locale, "*.tmpl")
+			if files, _ := fs.Glob(fsys, pattern); len(files) > 0 {
+				patterns = append(patterns, pattern)
+			}
+		}
+
+		tmpl, err := parseSet(fsys, funcs(catalog.Localizer(locale)), patterns...)
+		if err != nil {
+			return nil, fmt.Errorf("шаблоны языка %q: %w", locale, err)
+		}
+		r.sets[locale] = tmpl
+	}
+	return r, nil
+}
+
+// parseSet разбирает один набор шаблонов; шаблон из более позднего файла заменяет одноименный
+func parseSet(fsys fs.FS, fm template.FuncMap, patterns ...string) (*template.Template, error) {
+	tmpl, err := template.New("").Funcs(fm).Option("missingkey=error").ParseFS(fsys, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось разобрать шаблоны: %w", err)
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		if _, err := escapeList(t.Tree, t.Tree.Root, stateText); err != nil {
+			return nil, fmt.Errorf("шаблон %q: %w", t.Name(), err)
+		}
+	}
+	return tmpl, nil
+}
+
+// Render выполняет шаблон name на языке locale с данными data.
+// Неизвестный язык заменяется основным
+func (r *Renderer) Render(locale, name string, data any) (string, error) {
+	tmpl, ok := r.sets[locale]
+	if !ok {
+		tmpl = r.sets[i18n.DefaultLocale]
+	}
+	var sb strings.Builder
+	if err := tmpl.ExecuteTemplate(&sb, name, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// Escape экранирует текст для HTML parse mode
+func Escape(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+// Telegram понимает только эти именованные сущности: &lt; &gt; &amp; &quot;
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// Экранирующие функции, которые Parse дописывает в конец каждого действия
+const (
+	escapeTextFunc = "_escapeText"
+	escapeAttrFunc = "_escapeAttr"
+)
+
+func escapeText(v any) string {
+	if h, ok := v.(HTML); ok {
+		return string(h)
+	}
+	return Escape(fmt.Sprint(v))
+}
+
+func escapeAttr(v any) string {
+	return Escape(fmt.Sprint(v))
+}
+
+// state - где в разметке находится вставка
+type state int
+
+const (
+	stateText state = iota // Текст сообщения
+	stateTag               // Внутри тега, вне значения атрибута
+	stateAttr              // Внутри значения атрибута в кавычках
+)
+
+func (s state) String() string {
+	return [...]string{"текст", "тег", "атрибут"}[s]
+}
+
+// next - состояние после фрагмента разметки text
+func (s state) next(text []byte) state {
+	for _, c := range text {
+		switch {
+		case s == stateText && c == '<':
+			s = stateTag
+		case s == stateTag && c == '>':
+			s = stateText
+		case s == stateTag && c == '"':
+			s = stateAttr
+		case s == stateAttr && c == '"':
+			s = stateTag
+		}
+	}
+	return s
+}
+
+// escapeList дописывает экранирование в действия списка и возвращает состояние после него
+func escapeList(tree *parse.Tree, list *parse.ListNode, s state) (state, error) {
+	if list == nil {
+		return s, nil
+	}
+	for _, node := range list.Nodes {
+		var err error
+		if s, err = escapeNode(tree, node, s); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+func escapeNode(tree *parse.Tree, node parse.Node, s state) (state, error) {
+	switch n := node.(type) {
+	case *parse.TextNode:
+		return s.next(n.Text), nil
+	case *parse.ActionNode:
+		// {{$x := ...}} ничего не выводит
+		if len(n.Pipe.Decl) > 0 {
+			return s, nil
+		}
+		name := escapeTextFunc
+		switch s {
+		case stateTag:
+			return s, fmt.Errorf("строка %d: вставка внутри тега", n.Line)
+		case stateAttr:
+			name = escapeAttrFunc
+		}
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(name).SetTree(tree).SetPos(n.Pos)},
+		})
+		return s, nil
+	case *parse.TemplateNode:
+		// Вложенный шаблон экранирован сам и начинается в тексте
+		if s != stateText {
+			return s, fmt.Errorf("строка %d: вызов шаблона %q внутри тега", n.Line, n.Name)
+		}
+		return s, nil
+	case *parse.IfNode:
+		return escapeBranch(tree, &n.BranchNode, s, false)
+	case *parse.RangeNode:
+		return escapeBranch(tree, &n.BranchNode, s, true)
+	case *parse.WithNode:
+		return escapeBranch(tree, &n.BranchNode, s, false)
+	}
+	return s, nil
+}
+
+// escapeBranch требует, чтобы все ветви (и повторы range) заканчивались в том же
+// состоянии, иначе экранирование после блока зависело бы от данных
+func escapeBranch(tree *parse.Tree, n *parse.BranchNode, s state, loop bool) (state, error) {
+	after, err := escapeList(tree, n.List, s)
+	if err != nil {
+		return s, err
+	}
+	if loop && after != s {
+		return s, fmt.Errorf("строка %d: тело range начинается в состоянии %q, а заканчивается в %q", n.Line, s, after)
+	}
+	afterElse, err := escapeList(tree, n.ElseList, s)
+	if err != nil {
+		return s, err
+	}
+	if after != afterElse {
+		return s, fmt.Errorf("строка %d: ветви заканчиваются в разных состояниях: %q и %q", n.Line, after, afterElse)
+	}
+	return after, nil
+}
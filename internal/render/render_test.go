@@ -0,0 +1,293 @@
+package render
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+)
+
+// go test ./internal/render -update перезаписывает testdata/*.golden
+var update = flag.Bool("update", false, "перезаписать golden-файлы")
+
+var testDate = time.Date(2024, 5, 20, 9, 30, 0, 0, time.UTC)
+
+func testUser() *models.User {
+	return &models.User{
+		Sex: "female", Age: 30, HeightCm: 168, WeightKg: 61.5,
+		ActivityLevel: "moderate", Goal: "lose",
+		BMR: 1370, TDEE: 2124, CalorieTarget: 1805,
+		ProteinTarget: 98, FatsTarget: 60, CarbsTarget: 216,
+		Diets:             models.DietVegetarian,
+		ExcludedAllergens: models.AllergenNuts | models.AllergenMilk,
+	}
+}
+
+func testTraining() *models.TrainingProgram {
+	return &models.TrainingProgram{
+		Title:       "Ноги & ягодицы <база>",
+		Description: "Присед, выпады и мостик",
+		Difficulty:  "Средняя",
+		Duration:    45,
+		Category:    models.Category{Name: "Силовые"},
+		YouTubeLink: `https://youtu.be/x?a=1&b="2"`,
+		Weeks:       4,
+	}
+}
+
+func testDish() *models.NutritionPlan {
+	dish := &models.NutritionPlan{
+		Title:       "Овсянка с ягодами",
+		Description: "Быстрый завтрак <5 минут>",
+		Calories:    320,
+		Protein:     12.5,
+		Carbs:       48,
+		Fats:        7.25,
+		Category:    models.Category{Name: "Завтраки"},
+		Diets:       models.DietVegetarian,
+		Allergens:   models.AllergenGluten | models.AllergenMilk,
+	}
+	dish.ID = 7
+	return dish
+}
+
+func testDiaryDay() *service.DiaryDay {
+	entries := []*models.FoodDiaryEntry{
+		{Title: "Овсянка с ягодами", Portion: 1.5, Calories: 480, Protein: 18.75, Carbs: 72, Fats: 10.9},
+		{Title: "Суп *дня*", Portion: 1, Calories: 250, Protein: 9, Carbs: 30, Fats: 8},
+	}
+	return &service.DiaryDay{
+		Date:    testDate,
+		Entries: entries,
+		Totals:  service.Macros{Calories: 730, Protein: 27.75, Carbs: 102, Fats: 18.9},
+	}
+}
+
+func testMenu() *models.WeeklyMenu {
+	return &models.WeeklyMenu{
+		Name:          "Баланс 1800",
+		Description:   "Меню на неделю",
+		TotalCalories: 12600,
+		Days: []models.MenuDay{{
+			DayNumber: 1, DayName: "Понедельник", TotalCalories: 1800,
+			Meals: []models.DayMeal{{
+				MealType: "Завтрак", MealTime: "08:00", Notes: "Запить водой",
+				Nutrition: *testDish(),
+			}},
+		}},
+	}
+}
+
+// testAdminMenu - меню с разметкой Markdown в названии, днем без приемов пищи
+// и приемом, у которого не загружено блюдо
+func testAdminMenu() AdminMenu {
+	menu := testMenu()
+	menu.Name = "Баланс *1800* <new>"
+	menu.Active = true
+	menu.Days = append(menu.Days,
+		models.MenuDay{DayNumber: 2, DayName: "Вторник"},
+		models.MenuDay{DayNumber: 3, DayName: "Среда", Meals: []models.DayMeal{{MealType: "Обед", MealTime: "13:00", NutritionID: 9}}},
+	)
+	return AdminMenu{Menu: menu, Days: menu.Days}
+}
+
+func testWorkout() *models.WorkoutLog {
+	return &models.WorkoutLog{Training: *testTraining(), PerformedAt: testDate, Duration: 50, Effort: 7}
+}
+
+// Каждый экран сверяется с testdata/<name>.golden
+var screens = []struct {
+	name     string
+	template string
+	data     any
+}{
+	{"welcome", "welcome", nil},
+	{"help", "help", nil},
+	{"failure", "failure", Failure{Message: "Не удалось записать блюдо", Reason: "порция <= 0", Hint: "Попробуйте еще раз"}},
+	{"trainings_item", "trainings.item", TrainingItem{Number: 3, Training: testTraining()}},
+	{"training_card", "training.card", TrainingCard{Training: testTraining(), HasProgram: true}},
+	{"nutrition_header", "nutrition.header", NutritionHeader{CalorieTarget: 1805, Filtered: true}},
+	{"nutrition_item", "nutrition.item", DishItem{Number: 1, Dish: testDish(), Share: "18%"}},
+	{"dish_card", "dish.card", DishCard{
+		Dish: testDish(), Share: "18%", Conflicts: []string{"молоко"},
+		Recipe: []*models.RecipeItem{{Ingredient: models.Ingredient{Name: "Овсяные хлопья", Calories: 350}, Grams: 60}},
+		Steps:  []string{"Залить хлопья кипятком", "Добавить ягоды"},
+	}},
+	{"category_nutrition", "category.nutrition", CategoryPage{Category: &models.Category{Name: "Завтраки"}, Total: 0, Filtered: true}},
+	{"menu_header", "menu.header", MenuHeader{Menu: testMenu(), Source: "📌 Меню подобрано под вашу норму", CalorieTarget: 1805}},
+	{"menu_day", "menu.day", MenuDay{Day: testMenu().Days[0], Share: "100%"}},
+	{"menu_conflicts", "menu.conflicts", []ConflictLine{{Day: "Пн", Reasons: []string{"молоко"}, MenuConflict: service.MenuConflict{
+		DayNumber: 1, MealType: "Завтрак", Dish: "Овсянка с ягодами",
+	}}}},
+	{"menu_picker", "menu.picker", MenuPicker{CalorieTarget: 1805, Hidden: 2}},
+	{"admin_menu", "admin.menu", testAdminMenu()},
+	{"admin_menu_empty", "admin.menu", AdminMenu{Menu: &models.WeeklyMenu{Name: "Новое_меню", Published: true}}},
+	{"admin_foodlist", "admin.foodlist", []*models.NutritionPlan{testDish(), {Title: "Салат *фирменный* <new>", Calories: 150}}},
+	{"reminder_meal", "reminder.meal", service.Reminder{Kind: service.ReminderMeal, Meal: &testMenu().Days[0].Meals[0]}},
+	{"reminder_meal_no_dish", "reminder.meal", service.Reminder{Kind: service.ReminderMeal, Meal: &models.DayMeal{
+		MealType: "Перекус", MealTime: "16:00",
+	}}},
+	{"reminder_workout", "reminder.workout", service.Reminder{Kind: service.ReminderWorkout, Time: "18:00"}},
+	{"shopping", "shopping", ShoppingList{
+		Menu: testMenu(), List: &models.ShoppingList{Servings: 2, Items: make([]models.ShoppingItem, 2)},
+		Days: "Пн-Вс", Checked: 1,
+		Sections: []ShoppingSection{{Name: "Бакалея", Items: []ShoppingLine{
+			{Name: "Овсяные хлопья", Amount: "840 г", Checked: true},
+			{Name: "Мед & орехи", Amount: "1 порц."},
+		}}},
+	}},
+	{"diary", "diary", Diary{Day: testDiaryDay(), User: testUser()}},
+	{"diary_empty", "diary", Diary{Day: &service.DiaryDay{Date: testDate}}},
+	{"diary_dish", "diary.dish", DiaryDish{Dish: testDish(), Share: "18%"}},
+	{"diary_added", "diary.added", DiaryAdded{Entry: testDiaryDay().Entries[1], Day: testDiaryDay(), User: &models.User{CalorieTarget: 700}}},
+	{"diary_summary", "diary.summary", DiarySummary{
+		Diary: Diary{Day: testDiaryDay(), User: testUser()}, Share: "40%",
+		ProteinShare: 15, FatsShare: 23, CarbsShare: 62, Top: testDiaryDay().Entries[0],
+	}},
+	{"diet_settings", "diet.settings", DietSettings{User: testUser(), Conflicts: 3}},
+	{"measurements", "measurements", Measurements{
+		Latest:  &models.BodyMeasurement{MeasuredAt: testDate, WeightKg: 61.5, HipsCm: 96},
+		Changes: []string{"Вес: -1.5 кг", "Бедра: -2.0 см"},
+	}},
+	{"measure_saved", "measure.saved", MeasureSaved{Measurement: &models.BodyMeasurement{WeightKg: 61.5}, CalorieTarget: 1805}},
+	{"profile", "profile", Profile{User: testUser(), Sex: "женский", Activity: "🏃 3-5 тренировок в неделю", Goal: "📉 Похудеть"}},
+	{"onboarding_done", "onboarding.done", OnboardingDone{User: testUser(), MenuName: "Баланс 1800"}},
+	{"reminders", "reminders", Reminders{
+		Settings: &models.ReminderSettings{Timezone: "Europe/Moscow", MealsEnabled: true},
+		Workouts: "Пн, Ср в 18:00", Quiet: "23:00-08:00", Diary: "в 21:00", MealLead: 15, WorkoutLead: 30,
+	}},
+	{"program", "program", Program{Program: testTraining(), Week: 2}},
+	{"program_exercise", "program.exercise", ProgramExercise{
+		Item: &models.ProgramExercise{
+			Exercise: models.Exercise{Name: "Присед", Equipment: "Штанга", Description: "Спина прямая"},
+			Sets:     4, Reps: "8-12", RestSeconds: 90, Weight: "60% от 1ПМ",
+		},
+		Number: 1, Total: 5,
+	}},
+	{"search_results", "search.results", SearchResults{Query: "овс<", Hits: []models.SearchHit{
+		{Kind: models.SearchTraining, Title: "Утренняя зарядка", Duration: 15},
+		{Kind: models.SearchNutrition, Title: "Овсянка с ягодами", Calories: 320, Category: "Завтраки"},
+	}}},
+	{"search_empty", "search.empty", SearchResults{Query: "пицца", Filtered: true}},
+	{"share_training", "share.card", models.SearchHit{
+		Kind: models.SearchTraining, Title: "Ноги & ягодицы", Duration: 45, Difficulty: "Средняя",
+		Weeks: 4, Category: "Силовые", YouTubeLink: "https://youtu.be/x?a=1&b=2",
+	}},
+	{"share_dish", "share.card", models.SearchHit{
+		Kind: models.SearchNutrition, Title: "Овсянка", Calories: 320, Protein: 12.5, Carbs: 48, Fats: 7.25,
+		Diets: models.DietVegetarian, Allergens: models.AllergenGluten, Description: strings.Repeat("а", 700),
+	}},
+	{"workout_start", "workout.start", WorkoutStart{Training: testTraining()}},
+	{"workout_logged", "workout.logged", WorkoutLogged{Workout: testWorkout(), Week: &service.WorkoutTotals{Count: 3, Minutes: 140}}},
+	{"workouts", "workouts", WorkoutHistory{
+		Week:   service.WorkoutTotals{Count: 1, Minutes: 50, AvgEffort: 7},
+		Recent: []*models.WorkoutLog{testWorkout()},
+	}},
+}
+
+func testCatalog(t *testing.T) *i18n.Catalog {
+	t.Helper()
+	catalog, err := i18n.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return catalog
+}
+
+// Экраны основного языка сверяются с testdata/<name>.golden, других - с <name>.<язык>.golden
+func TestScreens(t *testing.T) {
+	catalog := testCatalog(t)
+	r, err := New(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, locale := range catalog.Locales() {
+		for _, tc := range screens {
+			name := tc.name
+			if locale != i18n.DefaultLocale {
+				name += "." + locale
+			}
+			t.Run(name, func(t *testing.T) {
+				got, err := r.Render(locale, tc.template, tc.data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				golden := filepath.Join("testdata", name+".golden")
+				if *update {
+					if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != string(want) {
+					t.Errorf("%s не совпадает с %s:\n%s", tc.template, golden, got)
+				}
+			})
+		}
+	}
+}
+
+func TestEscaping(t *testing.T) {
+	fsys := fstest.MapFS{"ru/t.tmpl": {Data: []byte(
+		`{{define "text"}}<b>{{.}}</b>{{end}}` +
+			`{{define "attr"}}<a href="{{.}}">ссылка</a>{{end}}` +
+			`{{define "nested"}}<i>{{template "text" .}}</i>{{end}}`,
+	)}}
+	r, err := Parse(fsys, ".", testCatalog(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		template string
+		data     any
+		want     string
+	}{
+		{"text", `<script> & "x"`, `<b>&lt;script&gt; &amp; &quot;x&quot;</b>`},
+		{"text", "*жирный* _курсив_ [ссылка](url)", "<b>*жирный* _курсив_ [ссылка](url)</b>"},
+		{"text", 42, "<b>42</b>"},
+		{"text", HTML("<u>готово</u>"), "<b><u>готово</u></b>"},
+		{"attr", `https://x.ru/?a=1&b="2"`, `<a href="https://x.ru/?a=1&amp;b=&quot;2&quot;">ссылка</a>`},
+		{"attr", HTML(`"><b>`), `<a href="&quot;&gt;&lt;b&gt;">ссылка</a>`},
+		{"nested", "a<b", "<i><b>a&lt;b</b></i>"},
+	}
+	for _, tc := range tests {
+		got, err := r.Render(i18n.DefaultLocale, tc.template, tc.data)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.template, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s(%v) = %q, want %q", tc.template, tc.data, got, tc.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"вставка в тег":         `<a {{.}}>x</a>`,
+		"имя тега":              `<{{.}}>x`,
+		"шаблон в атрибуте":     `{{define "x"}}y{{end}}<a href="{{template "x"}}">z</a>`,
+		"ветви if":              `{{if .}}<b{{end}}x`,
+		"тело range":            `{{range .}}<a href="{{end}}`,
+		"ветви with":            `{{with .}}<a href="{{.}}{{else}}x{{end}}`,
+		"незакрытый атрибут if": `<a href="{{if .}}">{{end}}`,
+	}
+	catalog := testCatalog(t)
+	for name, src := range tests {
+		_, err := Parse(fstest.MapFS{"ru/t.tmpl": {Data: []byte(src)}}, ".", catalog)
+		if err == nil {
+			t.Errorf("%s: ожидалась ошибка для %q", name, src)
+		}
+	}
+}
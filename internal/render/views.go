@@ -0,0 +1,220 @@
+package render
+
+import (
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+)
+
+// Данные экранов. Подписи, которые зависят от состояния пользователя
+// (доля нормы, источник меню и т.п.), бот считает сам и передает строками
+
+// Failure - сообщение об ошибке; Reason - текст ошибки сервиса
+type Failure struct {
+	Message string
+	Reason  string
+	Hint    string
+}
+
+// TrainingItem - тренировка в общем списке
+type TrainingItem struct {
+	Number   int
+	Training *models.TrainingProgram
+}
+
+// TrainingCard - карточка тренировки
+type TrainingCard struct {
+	Training   *models.TrainingProgram
+	HasProgram bool // Расписаны дни программы
+}
+
+// NutritionHeader - шапка списка блюд
+type NutritionHeader struct {
+	CalorieTarget int  // 0 - норма не рассчитана
+	Filtered      bool // Учтены ограничения питания
+}
+
+// DishItem - блюдо в общем списке
+type DishItem struct {
+	Number int
+	Dish   *models.NutritionPlan
+	Share  string // Доля дневной нормы, пусто - норма не рассчитана
+}
+
+// DishCard - карточка блюда с рецептом
+type DishCard struct {
+	Dish      *models.NutritionPlan
+	Share     string
+	Conflicts []string // Чем блюдо не подходит пользователю
+	Recipe    []*models.RecipeItem
+	Steps     []string
+}
+
+// CategoryPage - страница тренировок или блюд категории
+type CategoryPage struct {
+	Category *models.Category
+	Total    int64
+	Filtered bool // Учтены ограничения питания (только для блюд)
+}
+
+// MenuHeader - шапка недельного меню
+type MenuHeader struct {
+	Menu          *models.WeeklyMenu
+	Source        string // Откуда у пользователя это меню
+	CalorieTarget int
+}
+
+// MenuDay - день недельного меню
+type MenuDay struct {
+	Day   models.MenuDay
+	Share string
+}
+
+// ConflictLine - прием пищи меню, который пользователь исключил
+type ConflictLine struct {
+	Day     string   // Короткое название дня недели
+	Reasons []string // Чем блюдо не подходит
+	service.MenuConflict
+}
+
+// MenuPicker - выбор недельного меню
+type MenuPicker struct {
+	CalorieTarget int
+	Empty         bool // Выбирать не из чего
+	Hidden        int  // Скрыто меню из-за ограничений питания
+}
+
+// Diary - дневник питания за день
+type Diary struct {
+	Day  *service.DiaryDay
+	User *models.User
+}
+
+// DiaryDish - выбранное для дневника блюдо
+type DiaryDish struct {
+	Dish  *models.NutritionPlan
+	Share string
+}
+
+// DiaryAdded - блюдо записано в дневник; Day - nil, если итог дня не загрузился
+type DiaryAdded struct {
+	Entry *models.FoodDiaryEntry
+	Day   *service.DiaryDay
+	User  *models.User
+}
+
+// DiarySummary - итог дня: доли БЖУ в калориях и самое калорийное блюдо
+type DiarySummary struct {
+	Diary
+	Share                               string  // Доля дневной нормы
+	ProteinShare, FatsShare, CarbsShare float64 // 0, если записей нет
+	Top                                 *models.FoodDiaryEntry
+}
+
+// DietSettings - ограничения питания пользователя
+type DietSettings struct {
+	User      *models.User
+	Conflicts int // Приемов пищи текущего меню, которые не подходят
+}
+
+// Measurements - последний замер и изменения за месяц
+type Measurements struct {
+	Latest  *models.BodyMeasurement
+	Changes []string
+}
+
+// MeasureSaved - новый замер; CalorieTarget - пересчитанная норма или 0
+type MeasureSaved struct {
+	Measurement   *models.BodyMeasurement
+	CalorieTarget int
+}
+
+// Profile - профиль и нормы пользователя
+type Profile struct {
+	User     *models.User
+	Sex      string
+	Activity string
+	Goal     string
+}
+
+// OnboardingDone - итог анкеты; MenuName - подобранное меню, если подобрано
+type OnboardingDone struct {
+	User     *models.User
+	MenuName string
+}
+
+// Reminders - настройки напоминаний
+type Reminders struct {
+	Settings    *models.ReminderSettings
+	Workouts    string // Дни и время тренировок
+	Quiet       string // Тихие часы
+	Diary       string // Когда приходит итог дня по дневнику питания
+	MealLead    int    // За сколько минут напоминать о еде
+	WorkoutLead int
+}
+
+// ShoppingList - список покупок, позиции сгруппированы по отделам
+type ShoppingList struct {
+	Menu     *models.WeeklyMenu
+	List     *models.ShoppingList
+	Days     string
+	Checked  int
+	Sections []ShoppingSection
+}
+
+// ShoppingSection - отдел магазина
+type ShoppingSection struct {
+	Name  string
+	Items []ShoppingLine
+}
+
+// ShoppingLine - позиция списка покупок
+type ShoppingLine struct {
+	Name    string
+	Amount  string
+	Checked bool
+}
+
+// Program - неделя программы тренировок
+type Program struct {
+	Program *models.TrainingProgram
+	Week    int
+	Empty   bool // На этой неделе нет тренировочных дней
+}
+
+// ProgramExercise - упражнение тренировочного дня
+type ProgramExercise struct {
+	Item   *models.ProgramExercise
+	Number int
+	Total  int
+}
+
+// SearchResults - результаты поиска в чате
+type SearchResults struct {
+	Query    string
+	Hits     []models.SearchHit
+	Filtered bool // Учтены ограничения питания
+}
+
+// WorkoutStart - вопрос о длительности выполненной тренировки
+type WorkoutStart struct {
+	Training *models.TrainingProgram
+}
+
+// WorkoutLogged - тренировка записана; Week - nil, если итог недели не загрузился
+type WorkoutLogged struct {
+	Workout *models.WorkoutLog
+	Week    *service.WorkoutTotals
+}
+
+// WorkoutHistory - итоги недели и месяца и последние тренировки
+type WorkoutHistory struct {
+	Week   service.WorkoutTotals
+	Month  service.WorkoutTotals
+	Recent []*models.WorkoutLog
+}
+
+// AdminMenu - недельное меню в админ-панели со статусом и всеми приемами пищи
+type AdminMenu struct {
+	Menu *models.WeeklyMenu
+	Days []models.MenuDay // Дни по номеру
+}
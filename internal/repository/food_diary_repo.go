@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"gorm.io/gorm"
+)
+
+// FoodDiaryRepository - интерфейс для дневника питания
+type FoodDiaryRepository interface {
+	Create(entry *models.FoodDiaryEntry) (*models.FoodDiaryEntry, error)
+	FindByUserAndDate(userID uint, date time.Time) ([]*models.FoodDiaryEntry, error)
+	Delete(userID uint, entryID uint) error
+}
+
+type foodDiaryRepo struct {
+	db *gorm.DB
+}
+
+func NewFoodDiaryRepo(db *gorm.DB) FoodDiaryRepository {
+	return &foodDiaryRepo{db: db}
+}
+
+func (r *foodDiaryRepo) Create(entry *models.FoodDiaryEntry) (*models.FoodDiaryEntry, error) {
+	err := r.db.Create(entry).Error
+	return entry, err
+}
+
+func (r *foodDiaryRepo) FindByUserAndDate(userID uint, date time.Time) ([]*models.FoodDiaryEntry, error) {
+	var entries []*models.FoodDiaryEntry
+	err := r.db.
+		Where("user_id = ? AND date = ?", userID, date.Format("2006-01-02")).
+		Order("created_at").
+		Find(&entries).Error
+	return entries, err
+}
+
+// Delete удаляет запись, только если она принадлежит пользователю
+func (r *foodDiaryRepo) Delete(userID uint, entryID uint) error {
+	result := r.db.Where("user_id = ?", userID).Delete(&models.FoodDiaryEntry{}, entryID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
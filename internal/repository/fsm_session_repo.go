@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FSMSessionRepository - интерфейс для сохраненных состояний мастеров
+type FSMSessionRepository interface {
+	Find(scope string, userID int64, now time.Time) (*models.FSMSession, error)
+	Save(session *models.FSMSession) error
+	Delete(scope string, userID int64) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type fsmSessionRepo struct {
+	db *gorm.DB
+}
+
+func NewFSMSessionRepo(db *gorm.DB) FSMSessionRepository {
+	return &fsmSessionRepo{db: db}
+}
+
+// Find возвращает только не истекшее состояние
+func (r *fsmSessionRepo) Find(scope string, userID int64, now time.Time) (*models.FSMSession, error) {
+	var session models.FSMSession
+	err := r.db.
+		Where("scope = ? AND user_id = ? AND expires_at > ?", scope, userID, now).
+		First(&session).Error
+	return &session, err
+}
+
+// Save создает или перезаписывает состояние (upsert по scope + user_id)
+func (r *fsmSessionRepo) Save(session *models.FSMSession) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(session).Error
+}
+
+func (r *fsmSessionRepo) Delete(scope string, userID int64) error {
+	return r.db.Where("scope = ? AND user_id = ?", scope, userID).Delete(&models.FSMSession{}).Error
+}
+
+func (r *fsmSessionRepo) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&models.FSMSession{})
+	return result.RowsAffected, result.Error
+}
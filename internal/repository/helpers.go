@@ -0,0 +1,8 @@
+package repository
+
+import "strings"
+
+// escapeLike экранирует спецсимволы шаблона LIKE, чтобы ввод пользователя искался буквально
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
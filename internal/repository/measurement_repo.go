@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"gorm.io/gorm"
+)
+
+// MeasurementRepository - интерфейс для замеров тела
+type MeasurementRepository interface {
+	Create(m *models.BodyMeasurement) (*models.BodyMeasurement, error)
+	FindLatestByUser(userID uint) (*models.BodyMeasurement, error)
+	FindByUserSince(userID uint, since time.Time) ([]*models.BodyMeasurement, error)
+}
+
+type measurementRepo struct {
+	db *gorm.DB
+}
+
+func NewMeasurementRepo(db *gorm.DB) MeasurementRepository {
+	return &measurementRepo{db: db}
+}
+
+func (r *measurementRepo) Create(m *models.BodyMeasurement) (*models.BodyMeasurement, error) {
+	err := r.db.Create(m).Error
+	return m, err
+}
+
+func (r *measurementRepo) FindLatestByUser(userID uint) (*models.BodyMeasurement, error) {
+	var m models.BodyMeasurement
+	err := r.db.Where("user_id = ?", userID).
+		Order("measured_at DESC").
+		First(&m).Error
+	return &m, err
+}
+
+// FindByUserSince возвращает замеры начиная с since по возрастанию времени
+func (r *measurementRepo) FindByUserSince(userID uint, since time.Time) ([]*models.BodyMeasurement, error) {
+	var measurements []*models.BodyMeasurement
+	err := r.db.Where("user_id = ? AND measured_at >= ?", userID, since).
+		Order("measured_at").
+		Find(&measurements).Error
+	return measurements, err
+}
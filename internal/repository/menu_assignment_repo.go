@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"gorm.io/gorm"
+)
+
+// MenuAssignmentRepository - история назначений недельных меню пользователям
+type MenuAssignmentRepository interface {
+	Create(assignment *models.MenuAssignment) error
+	// FindCurrent - последнее назначение пользователя
+	FindCurrent(userID uint) (*models.MenuAssignment, error)
+	// FindAllCurrent - последние назначения всех пользователей, у которых они были
+	FindAllCurrent() ([]*models.MenuAssignment, error)
+	// FindHistory - назначения пользователя от новых к старым, limit <= 0 - все
+	FindHistory(userID uint, limit int) ([]*models.MenuAssignment, error)
+}
+
+type menuAssignmentRepo struct {
+	db *gorm.DB
+}
+
+func NewMenuAssignmentRepo(db *gorm.DB) MenuAssignmentRepository {
+	return &menuAssignmentRepo{db: db}
+}
+
+func (r *menuAssignmentRepo) Create(assignment *models.MenuAssignment) error {
+	return r.db.Create(assignment).Error
+}
+
+func (r *menuAssignmentRepo) FindCurrent(userID uint) (*models.MenuAssignment, error) {
+	var assignment models.MenuAssignment
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").First(&assignment).Error
+	return &assignment, err
+}
+
+func (r *menuAssignmentRepo) FindAllCurrent() ([]*models.MenuAssignment, error) {
+	var assignments []*models.MenuAssignment
+	err := r.db.Select("DISTINCT ON (user_id) *").Order("user_id, created_at DESC, id DESC").Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *menuAssignmentRepo) FindHistory(userID uint, limit int) ([]*models.MenuAssignment, error) {
+	var assignments []*models.MenuAssignment
+	query := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&assignments).Error
+	return assignments, err
+}
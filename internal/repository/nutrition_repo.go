@@ -10,7 +10,12 @@ type NutritionRepository interface {
 	Create(plan *models.NutritionPlan) (*models.NutritionPlan, error)
 	FindAll() ([]*models.NutritionPlan, error)
 	FindByID(id uint) (*models.NutritionPlan, error)
+	SearchByTitle(query string, limit int) ([]*models.NutritionPlan, error)
+	FindByCategoryID(categoryID uint, filter models.DishFilter, offset, limit int) ([]*models.NutritionPlan, error)
+	CountByCategoryID(categoryID uint, filter models.DishFilter) (int64, error)
 	Update(plan *models.NutritionPlan) error
+	// UpdateColumns записывает только перечисленные колонки блюда
+	UpdateColumns(plan *models.NutritionPlan, columns ...string) error
 	Delete(id uint) error
 }
 
@@ -21,10 +26,12 @@ type WeeklyMenuRepository interface {
 	FindAll() ([]*models.WeeklyMenu, error)
 	FindByID(id uint) (*models.WeeklyMenu, error)
 	FindActive() (*models.WeeklyMenu, error)
+	FindPublished() ([]*models.WeeklyMenu, error)
 	Update(menu *models.WeeklyMenu) error
 	Delete(id uint) error
 	Activate(id uint) error
 	DeactivateAll() error
+	SetPublished(id uint, published bool) error
 	UpdateMenuCalories(menuID uint, calories int) error
 
 	// Дни
@@ -41,6 +48,7 @@ type WeeklyMenuRepository interface {
 	FindMealByID(mealID uint) (*models.DayMeal, error)
 	UpdateMeal(meal *models.DayMeal) error
 	DeleteMeal(mealID uint) error
+	FindDayIDsByNutritionID(nutritionID uint) ([]uint, error)
 }
 
 type nutritionRepo struct {
@@ -69,11 +77,56 @@ func (r *nutritionRepo) FindByID(id uint) (*models.NutritionPlan, error) {
 	return &plan, result.Error
 }
 
+// SearchByTitle - поиск блюд по части названия без учета регистра
+func (r *nutritionRepo) SearchByTitle(query string, limit int) ([]*models.NutritionPlan, error) {
+	var plans []*models.NutritionPlan
+	result := r.db.
+		Where("title ILIKE ?", "%"+escapeLike(query)+"%").
+		Order("title").
+		Limit(limit).
+		Find(&plans)
+	return plans, result.Error
+}
+
+// FindByCategoryID - страница блюд категории, подходящих под filter, упорядоченных по названию
+func (r *nutritionRepo) FindByCategoryID(categoryID uint, filter models.DishFilter, offset, limit int) ([]*models.NutritionPlan, error) {
+	var plans []*models.NutritionPlan
+	result := withDishFilter(r.db, filter).
+		Where("category_id = ?", categoryID).
+		Order("title, id").
+		Offset(offset).
+		Limit(limit).
+		Find(&plans)
+	return plans, result.Error
+}
+
+func (r *nutritionRepo) CountByCategoryID(categoryID uint, filter models.DishFilter) (int64, error) {
+	var count int64
+	result := withDishFilter(r.db.Model(&models.NutritionPlan{}), filter).Where("category_id = ?", categoryID).Count(&count)
+	return count, result.Error
+}
+
+// withDishFilter оставляет блюда со всеми диетами filter.Diets и без аллергенов filter.Allergens
+func withDishFilter(db *gorm.DB, filter models.DishFilter) *gorm.DB {
+	if filter.Diets != 0 {
+		db = db.Where("diets & ? = ?", filter.Diets, filter.Diets)
+	}
+	if filter.Allergens != 0 {
+		db = db.Where("allergens & ? = 0", filter.Allergens)
+	}
+	return db
+}
+
 func (r *nutritionRepo) Update(plan *models.NutritionPlan) error {
 	result := r.db.Save(plan)
 	return result.Error
 }
 
+func (r *nutritionRepo) UpdateColumns(plan *models.NutritionPlan, columns ...string) error {
+	result := r.db.Model(plan).Select(columns).Updates(plan)
+	return result.Error
+}
+
 func (r *nutritionRepo) Delete(id uint) error {
 	result := r.db.Delete(&models.NutritionPlan{}, id)
 	return result.Error
@@ -114,6 +167,12 @@ func (r *weeklyMenuRepo) FindActive() (*models.WeeklyMenu, error) {
 	return &menu, result.Error
 }
 
+func (r *weeklyMenuRepo) FindPublished() ([]*models.WeeklyMenu, error) {
+	var menus []*models.WeeklyMenu
+	result := r.db.Where("published = ?", true).Order("name, id").Find(&menus)
+	return menus, result.Error
+}
+
 func (r *weeklyMenuRepo) Update(menu *models.WeeklyMenu) error {
 	result := r.db.Save(menu)
 	return result.Error
@@ -135,6 +194,11 @@ func (r *weeklyMenuRepo) DeactivateAll() error {
 	return result.Error
 }
 
+func (r *weeklyMenuRepo) SetPublished(id uint, published bool) error {
+	result := r.db.Model(&models.WeeklyMenu{}).Where("id = ?", id).Update("published", published)
+	return result.Error
+}
+
 func (r *weeklyMenuRepo) UpdateMenuCalories(menuID uint, calories int) error {
 	result := r.db.Model(&models.WeeklyMenu{}).Where("id = ?", menuID).Update("total_calories", calories)
 	return result.Error
@@ -202,3 +266,13 @@ func (r *weeklyMenuRepo) DeleteMeal(mealID uint) error {
 	result := r.db.Delete(&models.DayMeal{}, mealID)
 	return result.Error
 }
+
+// FindDayIDsByNutritionID - дни меню, в которых встречается блюдо
+func (r *weeklyMenuRepo) FindDayIDsByNutritionID(nutritionID uint) ([]uint, error) {
+	var ids []uint
+	result := r.db.Model(&models.DayMeal{}).
+		Where("nutrition_id = ?", nutritionID).
+		Distinct().
+		Pluck("day_id", &ids)
+	return ids, result.Error
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"gorm.io/gorm"
+)
+
+// ExerciseRepository - справочник упражнений
+type ExerciseRepository interface {
+	Create(exercise *models.Exercise) (*models.Exercise, error)
+	FindAll() ([]*models.Exercise, error)
+	FindByID(id uint) (*models.Exercise, error)
+	FindByName(name string) (*models.Exercise, error)
+	Update(exercise *models.Exercise) error
+	Delete(id uint) error
+	// CountUsage - в скольких днях программ стоит упражнение
+	CountUsage(exerciseID uint) (int64, error)
+}
+
+// ProgramRepository - дни тренировочных программ и упражнения в них
+type ProgramRepository interface {
+	CreateDay(day *models.ProgramDay) (*models.ProgramDay, error)
+	// FindDays - дни программы по неделям и дням недели, с упражнениями по порядку
+	FindDays(programID uint) ([]*models.ProgramDay, error)
+	FindDayByID(dayID uint) (*models.ProgramDay, error)
+	CountDays(programID uint) (int64, error)
+	// DeleteDay удаляет день вместе с его упражнениями
+	DeleteDay(dayID uint) error
+
+	CreateExercise(item *models.ProgramExercise) (*models.ProgramExercise, error)
+	FindExerciseByID(id uint) (*models.ProgramExercise, error)
+	DeleteExercise(id uint) error
+}
+
+type exerciseRepo struct {
+	db *gorm.DB
+}
+
+func NewExerciseRepo(db *gorm.DB) ExerciseRepository {
+	return &exerciseRepo{db: db}
+}
+
+func (r *exerciseRepo) Create(exercise *models.Exercise) (*models.Exercise, error) {
+	err := r.db.Create(exercise).Error
+	return exercise, err
+}
+
+func (r *exerciseRepo) FindAll() ([]*models.Exercise, error) {
+	var exercises []*models.Exercise
+	err := r.db.Order("name").Find(&exercises).Error
+	return exercises, err
+}
+
+func (r *exerciseRepo) FindByID(id uint) (*models.Exercise, error) {
+	var exercise models.Exercise
+	err := r.db.First(&exercise, id).Error
+	return &exercise, err
+}
+
+// FindByName - упражнение с таким названием без учета регистра
+func (r *exerciseRepo) FindByName(name string) (*models.Exercise, error) {
+	var exercise models.Exercise
+	err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&exercise).Error
+	return &exercise, err
+}
+
+func (r *exerciseRepo) Update(exercise *models.Exercise) error {
+	return r.db.Save(exercise).Error
+}
+
+func (r *exerciseRepo) Delete(id uint) error {
+	return r.db.Delete(&models.Exercise{}, id).Error
+}
+
+func (r *exerciseRepo) CountUsage(exerciseID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ProgramExercise{}).Where("exercise_id = ?", exerciseID).Count(&count).Error
+	return count, err
+}
+
+type programRepo struct {
+	db *gorm.DB
+}
+
+func NewProgramRepo(db *gorm.DB) ProgramRepository {
+	return &programRepo{db: db}
+}
+
+// withExercises подгружает упражнения дня в порядке выполнения
+func withExercises(db *gorm.DB) *gorm.DB {
+	return db.Preload("Exercises", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, id")
+	}).Preload("Exercises.Exercise")
+}
+
+func (r *programRepo) CreateDay(day *models.ProgramDay) (*models.ProgramDay, error) {
+	err := r.db.Omit("Exercises").Create(day).Error
+	return day, err
+}
+
+func (r *programRepo) FindDays(programID uint) ([]*models.ProgramDay, error) {
+	var days []*models.ProgramDay
+	err := withExercises(r.db).
+		Where("program_id = ?", programID).
+		Order("week, day_number, id").
+		Find(&days).Error
+	return days, err
+}
+
+func (r *programRepo) FindDayByID(dayID uint) (*models.ProgramDay, error) {
+	var day models.ProgramDay
+	err := withExercises(r.db).First(&day, dayID).Error
+	return &day, err
+}
+
+func (r *programRepo) CountDays(programID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ProgramDay{}).Where("program_id = ?", programID).Count(&count).Error
+	return count, err
+}
+
+func (r *programRepo) DeleteDay(dayID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("program_day_id = ?", dayID).Delete(&models.ProgramExercise{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ProgramDay{}, dayID).Error
+	})
+}
+
+func (r *programRepo) CreateExercise(item *models.ProgramExercise) (*models.ProgramExercise, error) {
+	err := r.db.Omit("Exercise").Create(item).Error
+	return item, err
+}
+
+func (r *programRepo) FindExerciseByID(id uint) (*models.ProgramExercise, error) {
+	var item models.ProgramExercise
+	err := r.db.Preload("Exercise").First(&item, id).Error
+	return &item, err
+}
+
+func (r *programRepo) DeleteExercise(id uint) error {
+	return r.db.Delete(&models.ProgramExercise{}, id).Error
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"gorm.io/gorm"
+)
+
+// IngredientRepository - справочник ингредиентов
+type IngredientRepository interface {
+	Create(ingredient *models.Ingredient) (*models.Ingredient, error)
+	FindAll() ([]*models.Ingredient, error)
+	FindByID(id uint) (*models.Ingredient, error)
+	FindByName(name string) (*models.Ingredient, error)
+	Update(ingredient *models.Ingredient) error
+	Delete(id uint) error
+}
+
+// RecipeRepository - составы блюд
+type RecipeRepository interface {
+	FindByNutritionID(nutritionID uint) ([]*models.RecipeItem, error)
+	FindByNutritionIDs(nutritionIDs []uint) ([]*models.RecipeItem, error)
+	FindNutritionIDsByIngredient(ingredientID uint) ([]uint, error)
+	// Replace заменяет весь состав блюда одной транзакцией
+	Replace(nutritionID uint, items []*models.RecipeItem) error
+}
+
+type ingredientRepo struct {
+	db *gorm.DB
+}
+
+func NewIngredientRepo(db *gorm.DB) IngredientRepository {
+	return &ingredientRepo{db: db}
+}
+
+func (r *ingredientRepo) Create(ingredient *models.Ingredient) (*models.Ingredient, error) {
+	err := r.db.Create(ingredient).Error
+	return ingredient, err
+}
+
+func (r *ingredientRepo) FindAll() ([]*models.Ingredient, error) {
+	var ingredients []*models.Ingredient
+	err := r.db.Order("name").Find(&ingredients).Error
+	return ingredients, err
+}
+
+func (r *ingredientRepo) FindByID(id uint) (*models.Ingredient, error) {
+	var ingredient models.Ingredient
+	err := r.db.First(&ingredient, id).Error
+	return &ingredient, err
+}
+
+// FindByName - ингредиент с таким названием без учета регистра
+func (r *ingredientRepo) FindByName(name string) (*models.Ingredient, error) {
+	var ingredient models.Ingredient
+	err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&ingredient).Error
+	return &ingredient, err
+}
+
+func (r *ingredientRepo) Update(ingredient *models.Ingredient) error {
+	return r.db.Save(ingredient).Error
+}
+
+func (r *ingredientRepo) Delete(id uint) error {
+	return r.db.Delete(&models.Ingredient{}, id).Error
+}
+
+type recipeRepo struct {
+	db *gorm.DB
+}
+
+func NewRecipeRepo(db *gorm.DB) RecipeRepository {
+	return &recipeRepo{db: db}
+}
+
+func (r *recipeRepo) FindByNutritionID(nutritionID uint) ([]*models.RecipeItem, error) {
+	var items []*models.RecipeItem
+	err := r.db.Preload("Ingredient").
+		Where("nutrition_id = ?", nutritionID).
+		Order("position, id").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *recipeRepo) FindByNutritionIDs(nutritionIDs []uint) ([]*models.RecipeItem, error) {
+	var items []*models.RecipeItem
+	if len(nutritionIDs) == 0 {
+		return items, nil
+	}
+	err := r.db.Preload("Ingredient").
+		Where("nutrition_id IN ?", nutritionIDs).
+		Order("nutrition_id, position, id").
+		Find(&items).Error
+	return items, err
+}
+
+// FindNutritionIDsByIngredient - неудаленные блюда, в состав которых входит ингредиент
+func (r *recipeRepo) FindNutritionIDsByIngredient(ingredientID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.RecipeItem{}).
+		Joins("JOIN nutrition_plans ON nutrition_plans.id = recipe_items.nutrition_id AND nutrition_plans.deleted_at IS NULL").
+		Where("recipe_items.ingredient_id = ?", ingredientID).
+		Distinct().
+		Pluck("recipe_items.nutrition_id", &ids).Error
+	return ids, err
+}
+
+func (r *recipeRepo) Replace(nutritionID uint, items []*models.RecipeItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("nutrition_id = ?", nutritionID).Delete(&models.RecipeItem{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Omit("Ingredient").Create(&items).Error
+	})
+}
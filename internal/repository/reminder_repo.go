@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReminderRepository - интерфейс для настроек и журнала напоминаний
+type ReminderRepository interface {
+	FindSettings(userID uint) (*models.ReminderSettings, error)
+	FindAllSettings() ([]*models.ReminderSettings, error)
+	SaveSettings(settings *models.ReminderSettings) error
+	// MarkSent отмечает напоминание отправленным. Возвращает false, если оно уже было отмечено
+	MarkSent(userID uint, key string, sentAt time.Time) (bool, error)
+	// UnmarkSent снимает отметку, чтобы напоминание можно было отправить снова
+	UnmarkSent(userID uint, key string) error
+	DeleteSentBefore(t time.Time) (int64, error)
+}
+
+type reminderRepo struct {
+	db *gorm.DB
+}
+
+func NewReminderRepo(db *gorm.DB) ReminderRepository {
+	return &reminderRepo{db: db}
+}
+
+func (r *reminderRepo) FindSettings(userID uint) (*models.ReminderSettings, error) {
+	var settings models.ReminderSettings
+	err := r.db.Where("user_id = ?", userID).First(&settings).Error
+	return &settings, err
+}
+
+func (r *reminderRepo) FindAllSettings() ([]*models.ReminderSettings, error) {
+	var settings []*models.ReminderSettings
+	err := r.db.Find(&settings).Error
+	return settings, err
+}
+
+func (r *reminderRepo) SaveSettings(settings *models.ReminderSettings) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(settings).Error
+}
+
+func (r *reminderRepo) MarkSent(userID uint, key string, sentAt time.Time) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.SentReminder{
+		UserID: userID,
+		Key:    key,
+		SentAt: sentAt,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *reminderRepo) UnmarkSent(userID uint, key string) error {
+	return r.db.Where("user_id = ? AND key = ?", userID, key).Delete(&models.SentReminder{}).Error
+}
+
+func (r *reminderRepo) DeleteSentBefore(t time.Time) (int64, error) {
+	result := r.db.Where("sent_at < ?", t).Delete(&models.SentReminder{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"gorm.io/gorm"
+)
+
+// SearchRepository - полнотекстовый и нечеткий поиск по тренировкам и блюдам
+type SearchRepository interface {
+	SearchTrainings(query string, limit int) ([]models.SearchHit, error)
+	// SearchNutrition ищет только среди блюд, подходящих под filter
+	SearchNutrition(query string, filter models.DishFilter, limit int) ([]models.SearchHit, error)
+}
+
+// Порог похожести слов запроса и названия для нечеткого совпадения (0..1).
+// По умолчанию в pg_trgm он 0.6 - слишком строго для опечаток
+const fuzzyThreshold = 0.4
+
+// searchSQL - запрос для таблицы тренировок или блюд. Находит записи, где запрос
+// совпал по словам с учетом русской морфологии в названии, описании или категории,
+// либо название похоже на запрос по триграммам. Ранг - вес совпавших слов
+// (название важнее описания, описание - категории) плюс похожесть названия
+const searchSQL = `
+SELECT '%[1]s' AS kind, t.id, COALESCE(t.title, '') AS title, COALESCE(t.description, '') AS description,
+	COALESCE(c.name, '') AS category, %[3]s,
+	ts_rank(
+		setweight(to_tsvector('russian', COALESCE(t.title, '')), 'A') ||
+		setweight(to_tsvector('russian', COALESCE(t.description, '')), 'B') ||
+		setweight(to_tsvector('russian', COALESCE(c.name, '')), 'C'),
+		websearch_to_tsquery('russian', @query)
+	) + word_similarity(@query, COALESCE(t.title, '')) AS rank
+FROM %[2]s t
+LEFT JOIN categories c ON c.id = t.category_id AND c.deleted_at IS NULL
+WHERE t.deleted_at IS NULL %[4]s AND (
+	to_tsvector('russian', COALESCE(t.title, '') || ' ' || COALESCE(t.description, '')) @@ websearch_to_tsquery('russian', @query)
+	OR to_tsvector('russian', COALESCE(c.name, '')) @@ websearch_to_tsquery('russian', @query)
+	OR @query <%% t.title
+)
+ORDER BY rank DESC, t.id
+LIMIT @limit`
+
+type searchRepo struct {
+	db *gorm.DB
+}
+
+func NewSearchRepo(db *gorm.DB) SearchRepository {
+	return &searchRepo{db: db}
+}
+
+func (r *searchRepo) SearchTrainings(query string, limit int) ([]models.SearchHit, error) {
+	stmt := fmt.Sprintf(searchSQL, models.SearchTraining, "training_programs",
+		"t.duration, COALESCE(t.difficulty, '') AS difficulty, COALESCE(t.youtube_link, '') AS youtube_link, t.weeks", "")
+	return r.search(stmt, sql.Named("query", query), sql.Named("limit", limit))
+}
+
+func (r *searchRepo) SearchNutrition(query string, filter models.DishFilter, limit int) ([]models.SearchHit, error) {
+	stmt := fmt.Sprintf(searchSQL, models.SearchNutrition, "nutrition_plans",
+		"COALESCE(t.calories, 0) AS calories, COALESCE(t.protein, 0) AS protein, "+
+			"COALESCE(t.carbs, 0) AS carbs, COALESCE(t.fats, 0) AS fats, t.diets, t.allergens",
+		"AND t.diets & @diets = @diets AND t.allergens & @allergens = 0")
+	return r.search(stmt, sql.Named("query", query), sql.Named("limit", limit),
+		sql.Named("diets", filter.Diets), sql.Named("allergens", filter.Allergens))
+}
+
+// search выполняет запрос в транзакции, чтобы порог нечеткого совпадения
+// действовал только на него
+func (r *searchRepo) search(stmt string, args ...any) ([]models.SearchHit, error) {
+	var hits []models.SearchHit
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf("SET LOCAL pg_trgm.word_similarity_threshold = %v", fuzzyThreshold)).Error; err != nil {
+			return err
+		}
+		return tx.Raw(stmt, args...).Scan(&hits).Error
+	})
+	return hits, err
+}
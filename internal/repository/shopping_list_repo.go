@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ShoppingListRepository - списки покупок пользователей
+type ShoppingListRepository interface {
+	// FindByUserID - список с позициями в порядке Position
+	FindByUserID(userID uint) (*models.ShoppingList, error)
+	// Save заменяет список пользователя вместе с позициями одной транзакцией
+	Save(list *models.ShoppingList) error
+	SetChecked(userID, itemID uint, checked bool) error
+	UncheckAll(userID uint) error
+}
+
+type shoppingListRepo struct {
+	db *gorm.DB
+}
+
+func NewShoppingListRepo(db *gorm.DB) ShoppingListRepository {
+	return &shoppingListRepo{db: db}
+}
+
+func (r *shoppingListRepo) FindByUserID(userID uint) (*models.ShoppingList, error) {
+	var list models.ShoppingList
+	err := r.db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, id")
+	}).Where("user_id = ?", userID).First(&list).Error
+	return &list, err
+}
+
+func (r *shoppingListRepo) Save(list *models.ShoppingList) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Items").Clauses(clause.OnConflict{UpdateAll: true}).Create(list).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", list.UserID).Delete(&models.ShoppingItem{}).Error; err != nil {
+			return err
+		}
+		if len(list.Items) == 0 {
+			return nil
+		}
+		for i := range list.Items {
+			list.Items[i].ID = 0
+			list.Items[i].UserID = list.UserID
+		}
+		return tx.Create(&list.Items).Error
+	})
+}
+
+func (r *shoppingListRepo) SetChecked(userID, itemID uint, checked bool) error {
+	return r.db.Model(&models.ShoppingItem{}).
+		Where("id = ? AND user_id = ?", itemID, userID).
+		Update("checked", checked).Error
+}
+
+func (r *shoppingListRepo) UncheckAll(userID uint) error {
+	return r.db.Model(&models.ShoppingItem{}).
+		Where("user_id = ?", userID).
+		Update("checked", false).Error
+}
@@ -11,6 +11,8 @@ type TrainingRepository interface {
 	Create(training *models.TrainingProgram) (*models.TrainingProgram, error)
 	FindAll() ([]*models.TrainingProgram, error)
 	FindByID(id uint) (*models.TrainingProgram, error)
+	FindByCategoryID(categoryID uint, offset, limit int) ([]*models.TrainingProgram, error)
+	CountByCategoryID(categoryID uint) (int64, error)
 	Update(training *models.TrainingProgram) error
 	Delete(id uint) error
 }
@@ -31,10 +33,11 @@ func (r *trainingRepo) Create(training *models.TrainingProgram) (*models.Trainin
 func (r *trainingRepo) FindAll() ([]*models.TrainingProgram, error) {
 	var trainings []*models.TrainingProgram
 
-	// Добавляем SQL-логирование
-	r.db = r.db.Debug() // Это покажет SQL-запросы в логах
+	// Добавляем SQL-логирование. Сессию берем локально: метод вызывается
+	// из нескольких воркеров одновременно, и r.db менять нельзя
+	db := r.db.Debug() // Это покажет SQL-запросы в логах
 
-	err := r.db.Preload("Category").Find(&trainings).Error
+	err := db.Preload("Category").Find(&trainings).Error
 
 	// Логируем результат
 	if err != nil {
@@ -56,6 +59,23 @@ func (r *trainingRepo) FindByID(id uint) (*models.TrainingProgram, error) {
 	return &training, err
 }
 
+// FindByCategoryID - страница тренировок категории, упорядоченных по названию
+func (r *trainingRepo) FindByCategoryID(categoryID uint, offset, limit int) ([]*models.TrainingProgram, error) {
+	var trainings []*models.TrainingProgram
+	err := r.db.Where("category_id = ?", categoryID).
+		Order("title, id").
+		Offset(offset).
+		Limit(limit).
+		Find(&trainings).Error
+	return trainings, err
+}
+
+func (r *trainingRepo) CountByCategoryID(categoryID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.TrainingProgram{}).Where("category_id = ?", categoryID).Count(&count).Error
+	return count, err
+}
+
 func (r *trainingRepo) Update(training *models.TrainingProgram) error {
 	return r.db.Save(training).Error
 }
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"gorm.io/gorm"
+)
+
+// WorkoutLogRepository - интерфейс для журнала тренировок
+type WorkoutLogRepository interface {
+	Create(log *models.WorkoutLog) (*models.WorkoutLog, error)
+	FindRecentByUser(userID uint, limit int) ([]*models.WorkoutLog, error)
+	FindByUserBetween(userID uint, from, to time.Time) ([]*models.WorkoutLog, error)
+}
+
+type workoutLogRepo struct {
+	db *gorm.DB
+}
+
+func NewWorkoutLogRepo(db *gorm.DB) WorkoutLogRepository {
+	return &workoutLogRepo{db: db}
+}
+
+func (r *workoutLogRepo) Create(log *models.WorkoutLog) (*models.WorkoutLog, error) {
+	err := r.db.Create(log).Error
+	return log, err
+}
+
+func (r *workoutLogRepo) FindRecentByUser(userID uint, limit int) ([]*models.WorkoutLog, error) {
+	var logs []*models.WorkoutLog
+	err := r.db.Preload("Training").
+		Where("user_id = ?", userID).
+		Order("performed_at DESC").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+// FindByUserBetween возвращает записи в полуинтервале [from, to)
+func (r *workoutLogRepo) FindByUserBetween(userID uint, from, to time.Time) ([]*models.WorkoutLog, error) {
+	var logs []*models.WorkoutLog
+	err := r.db.Preload("Training").
+		Where("user_id = ? AND performed_at >= ? AND performed_at < ?", userID, from, to).
+		Order("performed_at").
+		Find(&logs).Error
+	return logs, err
+}
@@ -0,0 +1,110 @@
+// Package scheduler периодически рассылает напоминания пользователям
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+)
+
+// Сколько хранить отметки об отправленных напоминаниях
+const sentRetention = 7 * 24 * time.Hour
+
+// Clock - источник текущего времени. Подменяется, чтобы проверять планировщик без ожидания
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock - настоящие часы
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// Source - откуда берутся напоминания (service.ReminderService)
+type Source interface {
+	DueReminders(now time.Time) ([]service.Reminder, error)
+	ReleaseReminder(reminder service.Reminder) error
+	PurgeSent(before time.Time) (int64, error)
+}
+
+// SendFunc доставляет напоминание пользователю
+type SendFunc func(reminder service.Reminder) error
+
+// Scheduler раз в interval спрашивает у Source напоминания на текущий момент и отправляет их
+type Scheduler struct {
+	source   Source
+	send     SendFunc
+	clock    Clock
+	interval time.Duration
+
+	lastPurge time.Time
+}
+
+// NewScheduler создает планировщик. Если clock не задан, используются системные часы
+func NewScheduler(source Source, send SendFunc, clock Clock, interval time.Duration) *Scheduler {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Scheduler{
+		source:   source,
+		send:     send,
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+// Run выполняет Tick сразу и затем раз в interval, пока не отменен ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	log.Printf("⏰ Reminder scheduler started, interval %s", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.Tick()
+		select {
+		case <-ctx.Done():
+			log.Println("⏰ Reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick отправляет все напоминания, срок которых наступил по часам планировщика.
+// Возвращает количество успешно отправленных
+func (s *Scheduler) Tick() int {
+	now := s.clock.Now()
+
+	reminders, err := s.source.DueReminders(now)
+	if err != nil {
+		log.Printf("[Scheduler] failed to collect reminders: %v", err)
+		return 0
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		if err := s.send(reminder); err != nil {
+			log.Printf("[Scheduler] failed to send %s to user %d: %v", reminder.Key, reminder.UserID, err)
+			// Без отметки напоминание уйдет на следующем тике, пока не истекло окно отправки
+			if err := s.source.ReleaseReminder(reminder); err != nil {
+				log.Printf("[Scheduler] failed to release %s for user %d: %v", reminder.Key, reminder.UserID, err)
+			}
+			continue
+		}
+		sent++
+	}
+
+	if now.Sub(s.lastPurge) >= 24*time.Hour {
+		if _, err := s.source.PurgeSent(now.Add(-sentRetention)); err != nil {
+			log.Printf("[Scheduler] failed to purge sent reminders: %v", err)
+		} else {
+			s.lastPurge = now
+		}
+	}
+	return sent
+}
@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+)
+
+// fakeClock - часы, которые двигает тест
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// fakeSource планирует напоминания одного пользователя так же, как ReminderService,
+// и хранит отметки об отправке в памяти вместо БД
+type fakeSource struct {
+	settings *models.ReminderSettings
+	menu     *models.WeeklyMenu
+	sent     map[string]time.Time
+	purged   int
+}
+
+func newFakeSource(settings *models.ReminderSettings, menu *models.WeeklyMenu) *fakeSource {
+	return &fakeSource{settings: settings, menu: menu, sent: make(map[string]time.Time)}
+}
+
+func (s *fakeSource) DueReminders(now time.Time) ([]service.Reminder, error) {
+	var due []service.Reminder
+	for _, reminder := range service.PlanReminders(s.settings, s.menu, now.In(time.UTC)) {
+		if _, ok := s.sent[reminder.Key]; ok {
+			continue
+		}
+		s.sent[reminder.Key] = now
+		reminder.UserID = s.settings.UserID
+		due = append(due, reminder)
+	}
+	return due, nil
+}
+
+func (s *fakeSource) ReleaseReminder(reminder service.Reminder) error {
+	delete(s.sent, reminder.Key)
+	return nil
+}
+
+func (s *fakeSource) PurgeSent(before time.Time) (int64, error) {
+	s.purged++
+	var n int64
+	for key, sentAt := range s.sent {
+		if sentAt.Before(before) {
+			delete(s.sent, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// testSetup - тренировка по понедельникам в 18:00 (напоминание в 17:30) и обед в 13:00
+// (напоминание в 12:45), тихие часы 23:00-08:00
+func testSetup() (*models.ReminderSettings, *models.WeeklyMenu) {
+	settings := service.DefaultReminderSettings(1, "UTC")
+	settings.WorkoutDays = 1
+	lunch := models.DayMeal{MealType: "Обед", MealTime: "13:00"}
+	lunch.ID = 5
+	menu := &models.WeeklyMenu{Days: []models.MenuDay{{DayNumber: 1, Meals: []models.DayMeal{lunch}}}}
+	return settings, menu
+}
+
+// monday - 20.05.2024, понедельник, UTC
+func monday(hour, minute int) time.Time {
+	return time.Date(2024, 5, 20, hour, minute, 0, 0, time.UTC)
+}
+
+// recorder запоминает отправленные ключи; fail - сколько первых отправок завершить ошибкой
+type recorder struct {
+	keys []string
+	fail int
+}
+
+func (r *recorder) send(reminder service.Reminder) error {
+	if r.fail > 0 {
+		r.fail--
+		return errors.New("telegram недоступен")
+	}
+	r.keys = append(r.keys, reminder.Key)
+	return nil
+}
+
+func TestSchedulerTicks(t *testing.T) {
+	type tick struct {
+		at   time.Time
+		want int // Сколько напоминаний отправлено на этом тике
+	}
+	tests := []struct {
+		name   string
+		change func(s *models.ReminderSettings)
+		fail   int
+		ticks  []tick
+	}{
+		{
+			name:  "до срока ничего",
+			ticks: []tick{{monday(12, 44), 0}, {monday(17, 29), 0}},
+		},
+		{
+			name:  "повторный тик не дублирует",
+			ticks: []tick{{monday(12, 45), 1}, {monday(12, 46), 0}, {monday(12, 54), 0}},
+		},
+		{
+			name:  "перезапуск внутри окна",
+			ticks: []tick{{monday(12, 54), 1}},
+		},
+		{
+			name:  "перезапуск после окна",
+			ticks: []tick{{monday(12, 55), 0}},
+		},
+		{
+			name:  "ошибка отправки - повтор на следующем тике",
+			fail:  1,
+			ticks: []tick{{monday(17, 30), 0}, {monday(17, 31), 1}, {monday(17, 32), 0}},
+		},
+		{
+			name:  "ошибки до конца окна",
+			fail:  100,
+			ticks: []tick{{monday(17, 30), 0}, {monday(17, 39), 0}, {monday(17, 40), 0}},
+		},
+		{
+			name: "тихие часы через полночь",
+			change: func(s *models.ReminderSettings) {
+				s.WorkoutTime = "23:30"
+			},
+			ticks: []tick{{monday(23, 0), 0}, {monday(23, 5), 0}},
+		},
+		{
+			name: "конец тихих часов внутри окна",
+			change: func(s *models.ReminderSettings) {
+				s.QuietFrom, s.QuietTo = "22:00", "12:50"
+			},
+			ticks: []tick{{monday(12, 45), 0}, {monday(12, 50), 1}, {monday(12, 51), 0}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			settings, menu := testSetup()
+			if tc.change != nil {
+				tc.change(settings)
+			}
+			source := newFakeSource(settings, menu)
+			rec := &recorder{fail: tc.fail}
+			clock := &fakeClock{}
+			s := NewScheduler(source, rec.send, clock, time.Minute)
+			for _, step := range tc.ticks {
+				clock.now = step.at
+				if got := s.Tick(); got != step.want {
+					t.Errorf("Tick в %s отправил %d, want %d", step.at.Format("15:04"), got, step.want)
+				}
+			}
+		})
+	}
+}
+
+// После перезапуска новый планировщик не повторяет уже отмеченные напоминания
+func TestSchedulerRestartKeepsMarks(t *testing.T) {
+	settings, menu := testSetup()
+	source := newFakeSource(settings, menu)
+	rec := &recorder{}
+	clock := &fakeClock{now: monday(12, 45)}
+
+	if got := NewScheduler(source, rec.send, clock, time.Minute).Tick(); got != 1 {
+		t.Fatalf("первый запуск отправил %d, want 1", got)
+	}
+	clock.Advance(3 * time.Minute)
+	if got := NewScheduler(source, rec.send, clock, time.Minute).Tick(); got != 0 {
+		t.Errorf("после перезапуска отправлено %d, want 0", got)
+	}
+	if len(rec.keys) != 1 || rec.keys[0] != "meal:2024-05-20:5" {
+		t.Errorf("отправлены %v", rec.keys)
+	}
+}
+
+// Старые отметки удаляются не чаще раза в сутки
+func TestSchedulerPurge(t *testing.T) {
+	settings, menu := testSetup()
+	source := newFakeSource(settings, menu)
+	clock := &fakeClock{now: monday(10, 0)}
+	s := NewScheduler(source, (&recorder{}).send, clock, time.Minute)
+
+	s.Tick()
+	clock.Advance(time.Hour)
+	s.Tick()
+	if source.purged != 1 {
+		t.Fatalf("за час очистка вызвана %d раз, want 1", source.purged)
+	}
+	clock.Advance(24 * time.Hour)
+	s.Tick()
+	if source.purged != 2 {
+		t.Errorf("через сутки очистка вызвана %d раз, want 2", source.purged)
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// examplePassword - пароль из .env.example; с ним админка не запускается
+const examplePassword = "admin123"
+
+// BasicAuth - basic auth для API и веб-админки. Пустые логин или пароль
+// и пароль из примера конфигурации - ошибка
+func BasicAuth(username, password string) (gin.HandlerFunc, error) {
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("не заданы ADMIN_USERNAME и ADMIN_PASSWORD")
+	}
+	if password == examplePassword {
+		return nil, fmt.Errorf("ADMIN_PASSWORD совпадает с примером из .env.example, задайте свой пароль")
+	}
+	return gin.BasicAuth(gin.Accounts{username: password}), nil
+}
+
+// SameOrigin отклоняет изменяющие запросы с чужих сайтов: браузер сам
+// подставляет basic auth, поэтому без проверки возможна CSRF.
+// Запрос без Origin и Referer пропускается, только если в нем есть basic auth
+// и нет cookie: так выглядят клиенты API вне браузера
+func SameOrigin(c *gin.Context) {
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		c.Next()
+		return
+	}
+	source := c.GetHeader("Origin")
+	if source == "" {
+		source = c.GetHeader("Referer")
+	}
+	if source == "" {
+		_, _, basic := c.Request.BasicAuth()
+		if !basic || c.GetHeader("Cookie") != "" {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+		return
+	}
+	u, err := url.Parse(source)
+	if err != nil || u.Host != c.Request.Host {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+	c.Next()
+}
+
+// RequireJSON отклоняет изменяющие запросы с телом не в JSON: такой запрос
+// браузер не может отправить с чужого сайта без preflight
+func RequireJSON(c *gin.Context) {
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		c.Next()
+		return
+	}
+	if c.Request.ContentLength != 0 {
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
+				"error": i18n.Default().T("error.api.content_type"),
+				"code":  "error.api.content_type",
+			})
+			return
+		}
+	}
+	c.Next()
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter(middleware gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware)
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/", ok)
+	router.POST("/", ok)
+	router.DELETE("/", ok)
+	return router
+}
+
+func TestSameOrigin(t *testing.T) {
+	router := newTestRouter(SameOrigin)
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		basic   bool
+		want    int
+	}{
+		{name: "GET с чужого сайта", method: http.MethodGet, headers: map[string]string{"Origin": "https://evil.example"}, want: http.StatusOK},
+		{name: "свой Origin", method: http.MethodPost, headers: map[string]string{"Origin": "http://example.com"}, want: http.StatusOK},
+		{name: "чужой Origin", method: http.MethodPost, headers: map[string]string{"Origin": "https://evil.example"}, want: http.StatusForbidden},
+		{name: "свой Referer", method: http.MethodDelete, headers: map[string]string{"Referer": "http://example.com/admin/trainings"}, want: http.StatusOK},
+		{name: "чужой Referer", method: http.MethodPost, headers: map[string]string{"Referer": "https://evil.example/form"}, want: http.StatusForbidden},
+		{name: "Origin важнее Referer", method: http.MethodPost, headers: map[string]string{"Origin": "https://evil.example", "Referer": "http://example.com/"}, want: http.StatusForbidden},
+		{name: "непарсящийся Origin", method: http.MethodPost, headers: map[string]string{"Origin": "://"}, want: http.StatusForbidden},
+		{name: "без Origin, клиент API с basic auth", method: http.MethodPost, basic: true, want: http.StatusOK},
+		{name: "без Origin и без basic auth", method: http.MethodPost, want: http.StatusForbidden},
+		{name: "без Origin, basic auth и cookie", method: http.MethodPost, basic: true, headers: map[string]string{"Cookie": "session=1"}, want: http.StatusForbidden},
+		{name: "без Origin, Bearer вместо basic auth", method: http.MethodPost, headers: map[string]string{"Authorization": "Bearer token"}, want: http.StatusForbidden},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://example.com/", nil)
+			for name, value := range tc.headers {
+				req.Header.Set(name, value)
+			}
+			if tc.basic {
+				req.SetBasicAuth("admin", "secret")
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tc.want {
+				t.Errorf("%s с %v: статус %d, want %d", tc.method, req.Header, rec.Code, tc.want)
+			}
+		})
+	}
+}
+
+func TestRequireJSON(t *testing.T) {
+	router := newTestRouter(RequireJSON)
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		contentType string
+		want        int
+	}{
+		{name: "GET без JSON", method: http.MethodGet, body: "x", contentType: "text/plain", want: http.StatusOK},
+		{name: "JSON", method: http.MethodPost, body: "{}", contentType: "application/json", want: http.StatusOK},
+		{name: "JSON с кодировкой", method: http.MethodPost, body: "{}", contentType: "application/json; charset=utf-8", want: http.StatusOK},
+		{name: "без тела", method: http.MethodDelete, want: http.StatusOK},
+		{name: "форма", method: http.MethodPost, body: "a=1", contentType: "application/x-www-form-urlencoded", want: http.StatusUnsupportedMediaType},
+		{name: "text/plain", method: http.MethodPost, body: "{}", contentType: "text/plain", want: http.StatusUnsupportedMediaType},
+		{name: "без Content-Type", method: http.MethodPost, body: "{}", want: http.StatusUnsupportedMediaType},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/", strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tc.want {
+				t.Errorf("статус %d, want %d", rec.Code, tc.want)
+			}
+			if tc.want == http.StatusUnsupportedMediaType && !strings.Contains(rec.Body.String(), `"code":"error.api.content_type"`) {
+				t.Errorf("тело %s без ключа ошибки", rec.Body)
+			}
+		})
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	tests := []struct {
+		name               string
+		username, password string
+		wantErr            bool
+	}{
+		{"логин и пароль", "admin", "s3cret", false},
+		{"без логина", "", "s3cret", true},
+		{"без пароля", "admin", "", true},
+		{"пароль из примера", "admin", examplePassword, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := BasicAuth(tc.username, tc.password); (err != nil) != tc.wantErr {
+				t.Errorf("BasicAuth() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+)
+
+// Форматы файлов каталога
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Колонки файлов каталога в порядке экспорта
+var (
+	nutritionColumns = []string{"title", "description", "calories", "protein", "carbs", "fats", "category", "diets", "allergens"}
+	trainingColumns  = []string{"title", "description", "difficulty", "duration", "category", "youtube_link"}
+)
+
+// MaxImportRows - сколько строк принимается из одного файла
+const MaxImportRows = 5000
+
+// catalogRow - строка файла: номер (строка CSV или элемент JSON с единицы) и значения колонок
+type catalogRow struct {
+	Num    int
+	Fields map[string]string
+}
+
+// FormatFromFilename определяет формат по расширению файла
+func FormatFromFilename(name string) (string, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".csv"):
+		return FormatCSV, nil
+	case strings.HasSuffix(lower, ".json"):
+		return FormatJSON, nil
+	default:
+		return "", i18n.NewError("error.import.file_type")
+	}
+}
+
+// readCatalogRows разбирает файл в строки с именованными колонками
+func readCatalogRows(format string, r io.Reader) ([]catalogRow, error) {
+	switch format {
+	case FormatCSV:
+		return readCSVRows(r)
+	case FormatJSON:
+		return readJSONRows(r)
+	default:
+		return nil, i18n.NewError("error.import.format", format)
+	}
+}
+
+// readCSVRows читает CSV с заголовком. Разделитель - запятая или точка с запятой
+// (так сохраняет русский Excel), определяется по заголовку
+func readCSVRows(r io.Reader) ([]catalogRow, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(br.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, i18n.WrapError(err, "error.import.read")
+	}
+	firstLine, _, _ := bytes.Cut(header, []byte("\n"))
+
+	reader := csv.NewReader(br)
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns, err := reader.Read()
+	if err == io.EOF {
+		return nil, i18n.NewError("error.import.empty")
+	}
+	if err != nil {
+		return nil, i18n.WrapError(err, "error.import.csv_header")
+	}
+	for i, column := range columns {
+		column = strings.TrimPrefix(column, "\ufeff") // BOM из Excel
+		columns[i] = strings.ToLower(strings.TrimSpace(column))
+	}
+	if !containsString(columns, "title") {
+		return nil, i18n.NewError("error.import.no_title")
+	}
+
+	var rows []catalogRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, i18n.WrapError(err, "error.import.csv")
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(rows) == MaxImportRows {
+			return nil, i18n.NewError("error.import.too_many_rows", MaxImportRows)
+		}
+
+		fields := make(map[string]string, len(columns))
+		for i, column := range columns {
+			if i < len(record) {
+				fields[column] = strings.TrimSpace(record[i])
+			}
+		}
+		rows = append(rows, catalogRow{Num: line, Fields: fields})
+	}
+	return rows, nil
+}
+
+// readJSONRows читает массив объектов. Числа и строки приводятся к строкам,
+// чтобы проверять их так же, как значения из CSV
+func readJSONRows(r io.Reader) ([]catalogRow, error) {
+	var items []map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, i18n.WrapError(err, "error.import.json")
+	}
+	if len(items) > MaxImportRows {
+		return nil, i18n.NewError("error.import.too_many_items", MaxImportRows)
+	}
+
+	rows := make([]catalogRow, 0, len(items))
+	for i, item := range items {
+		fields := make(map[string]string, len(item))
+		for key, raw := range item {
+			fields[strings.ToLower(key)] = jsonScalar(raw)
+		}
+		rows = append(rows, catalogRow{Num: i + 1, Fields: fields})
+	}
+	return rows, nil
+}
+
+// jsonScalar - строка как есть, число - его запись, null - пусто.
+// Объекты и массивы остаются JSON-текстом и не пройдут проверку поля
+func jsonScalar(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+	text := strings.TrimSpace(string(raw))
+	if text == "null" {
+		return ""
+	}
+	return text
+}
+
+// writeCatalog пишет строки records (значения в порядке columns) в CSV или JSON
+func writeCatalog(format string, w io.Writer, columns []string, records [][]any) error {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		for _, record := range records {
+			values := make([]string, len(record))
+			for i, v := range record {
+				values[i] = formatCatalogValue(v)
+			}
+			if err := cw.Write(values); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+
+	case FormatJSON:
+		items := make([]orderedObject, 0, len(records))
+		for _, record := range records {
+			items = append(items, orderedObject{keys: columns, values: record})
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(items)
+
+	default:
+		return i18n.NewError("error.import.format", format)
+	}
+}
+
+// orderedObject - JSON-объект с ключами в заданном порядке, чтобы экспорт
+// читался так же, как CSV, и не менялся от запуска к запуску
+type orderedObject struct {
+	keys   []string
+	values []any
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		v, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func formatCatalogValue(v any) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case int:
+		return strconv.Itoa(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// rowParser собирает ошибки полей одной строки
+type rowParser struct {
+	row    catalogRow
+	errors []error
+}
+
+// fail добавляет ошибку каталога key; первый аргумент сообщений об одном поле - колонка
+func (p *rowParser) fail(key string, args ...any) {
+	p.errors = append(p.errors, i18n.NewError(key, args...))
+}
+
+func (p *rowParser) text(column string, maxLen int, required bool) string {
+	value := p.row.Fields[column]
+	if required && value == "" {
+		p.fail("error.import.required", column)
+	}
+	if maxLen > 0 && len([]rune(value)) > maxLen {
+		p.fail("error.import.too_long", column, maxLen)
+	}
+	return value
+}
+
+func (p *rowParser) int(column string, min, max int) int {
+	value := p.row.Fields[column]
+	if value == "" {
+		value = "0"
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		p.fail("error.import.not_int", column, value)
+		return 0
+	}
+	if n < min || n > max {
+		p.fail("error.import.int_range", column, min, max)
+	}
+	return n
+}
+
+func (p *rowParser) float(column string, max float64) float64 {
+	value := strings.Replace(p.row.Fields[column], ",", ".", 1)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.fail("error.import.not_number", column, p.row.Fields[column])
+		return 0
+	}
+	if n < 0 || n > max {
+		p.fail("error.import.number_range", column, max)
+	}
+	return n
+}
+
+// tags - маска тегов из ключей через запятую или пробел. false - колонки в строке нет
+func (p *rowParser) tags(column string, tags []DietTag) (int, bool) {
+	value, ok := p.row.Fields[column]
+	if !ok {
+		return 0, false
+	}
+	keys := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' || r == ';' })
+	mask, err := ParseTagKeys(tags, keys)
+	if err != nil {
+		p.fail("error.import.field", column, err)
+	}
+	return mask, true
+}
+
+func (p *rowParser) err() error {
+	return errors.Join(p.errors...)
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"io"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+)
+
+// Разделы каталога для импорта и экспорта
+const (
+	CatalogNutrition = "nutrition"
+	CatalogTrainings = "trainings"
+)
+
+// Результат обработки строки импорта
+const (
+	ImportCreated = "create"
+	ImportUpdated = "update"
+	ImportFailed  = "error"
+)
+
+// CatalogService - массовый импорт и экспорт блюд и тренировок в CSV и JSON
+type CatalogService struct {
+	trainingRepo     repository.TrainingRepository
+	nutritionRepo    repository.NutritionRepository
+	categoryRepo     repository.CategoryRepository
+	nutritionService *NutritionService
+}
+
+func NewCatalogService(
+	trainingRepo repository.TrainingRepository,
+	nutritionRepo repository.NutritionRepository,
+	categoryRepo repository.CategoryRepository,
+	nutritionService *NutritionService,
+) *CatalogService {
+	return &CatalogService{
+		trainingRepo:     trainingRepo,
+		nutritionRepo:    nutritionRepo,
+		categoryRepo:     categoryRepo,
+		nutritionService: nutritionService,
+	}
+}
+
+// Колонки блюда, которые описываются в файле импорта. Рецепт и шаги
+// приготовления при импорте не меняются
+var importedNutritionColumns = []string{
+	"title", "description", "calories", "protein", "carbs", "fats", "category_id", "diets", "allergens",
+}
+
+// ImportRow - итог по одной строке файла
+type ImportRow struct {
+	Num    int    // Строка CSV или номер элемента JSON
+	Title  string // Название из строки, если есть
+	Action string // ImportCreated, ImportUpdated или ImportFailed
+	Error  error
+}
+
+// ImportReport - отчет об импорте. При DryRun ничего не записано,
+// но Created и Updated показывают, что произошло бы
+type ImportReport struct {
+	Kind    string
+	DryRun  bool
+	Created int
+	Updated int
+	Failed  int
+	Rows    []ImportRow
+}
+
+func (r *ImportReport) add(row ImportRow) {
+	switch row.Action {
+	case ImportCreated:
+		r.Created++
+	case ImportUpdated:
+		r.Updated++
+	case ImportFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, row)
+}
+
+// Errors - строки с ошибками
+func (r *ImportReport) Errors() []ImportRow {
+	var rows []ImportRow
+	for _, row := range r.Rows {
+		if row.Action == ImportFailed {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Summary - отчет обычным текстом на языке tr; показывает не больше maxErrors ошибок
+func (r *ImportReport) Summary(tr *i18n.Localizer, maxErrors int) string {
+	var sb strings.Builder
+	if r.DryRun {
+		sb.WriteString(tr.T("import.dry_run"))
+	}
+	sb.WriteString(tr.T("import.counts", len(r.Rows), r.Created, r.Updated, r.Failed))
+
+	errs := r.Errors()
+	for i, row := range errs {
+		if i == maxErrors {
+			sb.WriteString(tr.T("import.more", len(errs)-maxErrors))
+			break
+		}
+		title := ""
+		if row.Title != "" {
+			title = " (" + row.Title + ")"
+		}
+		sb.WriteString(tr.T("import.row", row.Num, title, tr.Error(row.Error)))
+	}
+	return sb.String()
+}
+
+// Import читает файл kind в формате format и создает или обновляет записи
+// по названию (без учета регистра). Строки с ошибками пропускаются и попадают
+// в отчет. Ошибка возвращается, только если файл нельзя разобрать целиком
+func (s *CatalogService) Import(kind, format string, r io.Reader, dryRun bool) (*ImportReport, error) {
+	rows, err := readCatalogRows(format, r)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{Kind: kind, DryRun: dryRun}
+	switch kind {
+	case CatalogNutrition:
+		err = s.importNutrition(rows, categories, report)
+	case CatalogTrainings:
+		err = s.importTrainings(rows, categories, report)
+	default:
+		return nil, i18n.NewError("error.import.kind", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *CatalogService) importNutrition(rows []catalogRow, categories map[string]uint, report *ImportReport) error {
+	plans, err := s.nutritionRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*models.NutritionPlan, len(plans))
+	for _, plan := range plans {
+		existing[titleKey(plan.Title)] = plan
+	}
+
+	for _, row := range rows {
+		p := rowParser{row: row}
+		plan := models.NutritionPlan{
+			Title:       p.text("title", 255, true),
+			Description: p.text("description", 0, false),
+			Calories:    p.int("calories", 0, 10000),
+			Protein:     p.float("protein", 1000),
+			Carbs:       p.float("carbs", 1000),
+			Fats:        p.float("fats", 1000),
+		}
+		plan.CategoryID = resolveCategory(&p, categories, true)
+		diets, hasDiets := p.tags("diets", DietTags)
+		allergens, hasAllergens := p.tags("allergens", AllergenTags)
+
+		result := ImportRow{Num: row.Num, Title: plan.Title}
+		key := titleKey(plan.Title)
+		current, found := existing[key]
+		// Без колонки теги существующего блюда не меняются
+		if found {
+			plan.Diets, plan.Allergens = current.Diets, current.Allergens
+		}
+		if hasDiets {
+			plan.Diets = NormalizeDiets(diets)
+		}
+		if hasAllergens {
+			plan.Allergens = allergens
+		}
+		if err := ValidateDishTags(plan.Diets, plan.Allergens); err != nil {
+			p.errors = append(p.errors, err)
+		}
+		if err := p.err(); err != nil {
+			result.Action, result.Error = ImportFailed, err
+			report.add(result)
+			continue
+		}
+
+		switch {
+		case found && report.DryRun:
+			result.Action = ImportUpdated
+		case found:
+			plan.Model = current.Model
+			if err := s.updateImportedDish(&plan, current.Calories); err != nil {
+				result.Action, result.Error = ImportFailed, err
+				break
+			}
+			result.Action = ImportUpdated
+		case report.DryRun:
+			result.Action = ImportCreated
+		default:
+			if _, err := s.nutritionRepo.Create(&plan); err != nil {
+				result.Action, result.Error = ImportFailed, err
+				break
+			}
+			result.Action = ImportCreated
+		}
+		// Повтор названия ниже в файле обновит только что созданную запись
+		if result.Action != ImportFailed {
+			saved := plan
+			existing[key] = &saved
+		}
+		report.add(result)
+	}
+	return nil
+}
+
+// updateImportedDish записывает колонки блюда из файла. У блюда с рецептом КБЖУ
+// остаются посчитанными по составу; при смене калорийности пересчитываются меню
+func (s *CatalogService) updateImportedDish(plan *models.NutritionPlan, oldCalories int) error {
+	if _, err := s.nutritionService.applyRecipe(plan); err != nil {
+		return err
+	}
+	if err := s.nutritionRepo.UpdateColumns(plan, importedNutritionColumns...); err != nil {
+		return err
+	}
+	if plan.Calories != oldCalories {
+		s.nutritionService.RefreshMenusWithDish(plan.ID)
+	}
+	return nil
+}
+
+func (s *CatalogService) importTrainings(rows []catalogRow, categories map[string]uint, report *ImportReport) error {
+	trainings, err := s.trainingRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*models.TrainingProgram, len(trainings))
+	for _, training := range trainings {
+		existing[titleKey(training.Title)] = training
+	}
+
+	for _, row := range rows {
+		p := rowParser{row: row}
+		training := models.TrainingProgram{
+			Title:       p.text("title", 100, true),
+			Description: p.text("description", 0, false),
+			Difficulty:  p.text("difficulty", 50, false),
+			Duration:    p.int("duration", 1, 600),
+			YouTubeLink: p.text("youtube_link", 0, false),
+			Weeks:       1,
+		}
+		if link := training.YouTubeLink; link != "" && !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+			p.fail("error.import.youtube_link", "youtube_link")
+		}
+		if categoryID := resolveCategory(&p, categories, false); categoryID != 0 {
+			training.CategoryID = &categoryID
+		}
+
+		result := ImportRow{Num: row.Num, Title: training.Title}
+		if err := p.err(); err != nil {
+			result.Action, result.Error = ImportFailed, err
+			report.add(result)
+			continue
+		}
+
+		key := titleKey(training.Title)
+		current, found := existing[key]
+		switch {
+		case found && report.DryRun:
+			result.Action = ImportUpdated
+		case found:
+			training.Model = current.Model
+			// Недели и дни программы в файле не описываются и не меняются
+			training.Weeks = current.Weeks
+			if err := s.trainingRepo.Update(&training); err != nil {
+				result.Action, result.Error = ImportFailed, err
+				break
+			}
+			result.Action = ImportUpdated
+		case report.DryRun:
+			result.Action = ImportCreated
+		default:
+			if _, err := s.trainingRepo.Create(&training); err != nil {
+				result.Action, result.Error = ImportFailed, err
+				break
+			}
+			result.Action = ImportCreated
+		}
+		if result.Action != ImportFailed {
+			saved := training
+			existing[key] = &saved
+		}
+		report.add(result)
+	}
+	return nil
+}
+
+// Export пишет все записи раздела kind в формате format
+func (s *CatalogService) Export(kind, format string, w io.Writer) error {
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	names := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+
+	switch kind {
+	case CatalogNutrition:
+		plans, err := s.nutritionRepo.FindAll()
+		if err != nil {
+			return err
+		}
+		records := make([][]any, 0, len(plans))
+		for _, p := range plans {
+			records = append(records, []any{p.Title, p.Description, p.Calories, p.Protein, p.Carbs, p.Fats, names[p.CategoryID],
+				strings.Join(TagKeys(DietTags, p.Diets), ", "), strings.Join(TagKeys(AllergenTags, p.Allergens), ", ")})
+		}
+		return writeCatalog(format, w, nutritionColumns, records)
+
+	case CatalogTrainings:
+		trainings, err := s.trainingRepo.FindAll()
+		if err != nil {
+			return err
+		}
+		records := make([][]any, 0, len(trainings))
+		for _, t := range trainings {
+			category := ""
+			if t.CategoryID != nil {
+				category = names[*t.CategoryID]
+			}
+			records = append(records, []any{t.Title, t.Description, t.Difficulty, t.Duration, category, t.YouTubeLink})
+		}
+		return writeCatalog(format, w, trainingColumns, records)
+
+	default:
+		return i18n.NewError("error.import.kind", kind)
+	}
+}
+
+// categoryIDs - ID категорий по названию без учета регистра
+func (s *CatalogService) categoryIDs() (map[string]uint, error) {
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]uint, len(categories))
+	for _, category := range categories {
+		ids[titleKey(category.Name)] = category.ID
+	}
+	return ids, nil
+}
+
+// resolveCategory находит категорию строки по названию; 0 - не указана
+func resolveCategory(p *rowParser, categories map[string]uint, required bool) uint {
+	name := p.row.Fields["category"]
+	if name == "" {
+		if required {
+			p.fail("error.import.required", "category")
+		}
+		return 0
+	}
+	id, ok := categories[titleKey(name)]
+	if !ok {
+		p.fail("error.import.category", "category", name)
+	}
+	return id
+}
+
+// titleKey - ключ сравнения названий: без учета регистра и крайних пробелов
+func titleKey(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
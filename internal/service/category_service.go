@@ -1,6 +1,7 @@
 package service
 
 import (
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
 	"github.com/alenapavlenkko/telegramfitnes/internal/models"
 	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
 )
@@ -13,9 +14,26 @@ func NewCategoryService(repo repository.CategoryRepository) *CategoryService {
 	return &CategoryService{repo: repo}
 }
 
+// Допустимые типы категорий
+var categoryTypes = map[string]bool{"training": true, "nutrition": true, "general": true}
+
 // CreateCategory - создать категорию
 func (s *CategoryService) CreateCategory(dto CreateCategoryDTO) (*models.Category, error) {
-	category := &models.Category{Name: dto.Name}
+	if dto.Name == "" {
+		return nil, i18n.NewError("error.category.name_empty")
+	}
+	if dto.Type == "" {
+		dto.Type = "general"
+	}
+	if !categoryTypes[dto.Type] {
+		return nil, i18n.NewError("error.category.type")
+	}
+
+	category := &models.Category{
+		Name:        dto.Name,
+		Description: dto.Description,
+		Type:        dto.Type,
+	}
 	return s.repo.Create(category) // repo.Create уже возвращает (*Category, error)
 }
 
@@ -48,8 +66,19 @@ func (s *CategoryService) UpdateCategory(id uint, dto UpdateCategoryDTO) error {
 		category.Description = dto.Description
 	}
 	if dto.Type != "" {
+		if !categoryTypes[dto.Type] {
+			return i18n.NewError("error.category.type")
+		}
 		category.Type = dto.Type
 	}
 
 	return s.repo.Update(category)
 }
+
+// pageOffset - смещение страницы page (с нуля) размером pageSize
+func pageOffset(page, pageSize int) int {
+	if page < 0 {
+		page = 0
+	}
+	return page * pageSize
+}
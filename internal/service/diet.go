@@ -0,0 +1,170 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+)
+
+// DietTag - диета или аллерген: бит маски, ключ для API и файлов каталога, подпись
+type DietTag struct {
+	Bit   int
+	Key   string
+	Label string
+}
+
+// DietTags - диеты, которым может соответствовать блюдо
+var DietTags = []DietTag{
+	{models.DietVegetarian, "vegetarian", "Вегетарианское"},
+	{models.DietVegan, "vegan", "Веганское"},
+	{models.DietGlutenFree, "gluten_free", "Без глютена"},
+	{models.DietLactoseFree, "lactose_free", "Без лактозы"},
+	{models.DietHalal, "halal", "Халяль"},
+}
+
+// AllergenTags - аллергены, которые отмечаются в составе блюда
+var AllergenTags = []DietTag{
+	{models.AllergenGluten, "gluten", "Глютен"},
+	{models.AllergenMilk, "milk", "Молоко"},
+	{models.AllergenEggs, "eggs", "Яйца"},
+	{models.AllergenNuts, "nuts", "Орехи"},
+	{models.AllergenPeanuts, "peanuts", "Арахис"},
+	{models.AllergenFish, "fish", "Рыба"},
+	{models.AllergenShellfish, "shellfish", "Морепродукты"},
+	{models.AllergenSoy, "soy", "Соя"},
+	{models.AllergenSesame, "sesame", "Кунжут"},
+}
+
+// allTags - маска всех известных тегов
+func allTags(tags []DietTag) int {
+	mask := 0
+	for _, tag := range tags {
+		mask |= tag.Bit
+	}
+	return mask
+}
+
+// NormalizeDiets убирает неизвестные биты и добавляет следствия:
+// веганское блюдо - также вегетарианское и без лактозы
+func NormalizeDiets(mask int) int {
+	mask &= allTags(DietTags)
+	if mask&models.DietVegan != 0 {
+		mask |= models.DietVegetarian | models.DietLactoseFree
+	}
+	return mask
+}
+
+// ValidateDishTags проверяет, что отметки блюда не противоречат друг другу
+func ValidateDishTags(diets, allergens int) error {
+	if diets&models.DietGlutenFree != 0 && allergens&models.AllergenGluten != 0 {
+		return i18n.NewError("error.diet.gluten_free")
+	}
+	if diets&models.DietVegan != 0 && allergens&(models.AllergenMilk|models.AllergenEggs|models.AllergenFish|models.AllergenShellfish) != 0 {
+		return i18n.NewError("error.diet.vegan")
+	}
+	if diets&models.DietVegetarian != 0 && allergens&(models.AllergenFish|models.AllergenShellfish) != 0 {
+		return i18n.NewError("error.diet.vegetarian")
+	}
+	return nil
+}
+
+// NormalizeAllergens убирает неизвестные биты
+func NormalizeAllergens(mask int) int {
+	return mask & allTags(AllergenTags)
+}
+
+// TagLabels - подписи тегов маски в порядке списка
+func TagLabels(tags []DietTag, mask int) []string {
+	var labels []string
+	for _, tag := range tags {
+		if mask&tag.Bit != 0 {
+			labels = append(labels, tag.Label)
+		}
+	}
+	return labels
+}
+
+// TagKeys - ключи тегов маски в порядке списка
+func TagKeys(tags []DietTag, mask int) []string {
+	keys := []string{}
+	for _, tag := range tags {
+		if mask&tag.Bit != 0 {
+			keys = append(keys, tag.Key)
+		}
+	}
+	return keys
+}
+
+// ParseTagKeys собирает маску из ключей; неизвестный ключ - ошибка со списком допустимых
+func ParseTagKeys(tags []DietTag, keys []string) (int, error) {
+	mask := 0
+	for _, key := range keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		found := false
+		for _, tag := range tags {
+			if tag.Key == key {
+				mask |= tag.Bit
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, i18n.NewError("error.diet.unknown_tag", key, strings.Join(TagKeys(tags, allTags(tags)), ", "))
+		}
+	}
+	return mask, nil
+}
+
+// DishConflict - почему блюдо не подходит под ограничения: аллергены из состава
+// и диеты, которым оно не отмечено. Подписи бот берет из каталога сообщений
+type DishConflict struct {
+	Allergens int
+	Diets     int
+}
+
+// Empty - блюдо подходит
+func (c DishConflict) Empty() bool {
+	return c.Allergens == 0 && c.Diets == 0
+}
+
+// DishConflicts - чем блюдо не подходит под ограничения filter
+func DishConflicts(plan *models.NutritionPlan, filter models.DishFilter) DishConflict {
+	return DishConflict{
+		Allergens: plan.Allergens & filter.Allergens,
+		Diets:     filter.Diets &^ plan.Diets,
+	}
+}
+
+// MenuConflict - прием пищи меню, который не подходит под ограничения
+type MenuConflict struct {
+	DayNumber int
+	MealType  string
+	Dish      string
+	Conflict  DishConflict
+}
+
+// MenuConflicts - приемы пищи полного меню (с блюдами), не подходящие под ограничения
+func MenuConflicts(menu *models.WeeklyMenu, filter models.DishFilter) []MenuConflict {
+	if filter.Empty() {
+		return nil
+	}
+	var conflicts []MenuConflict
+	for _, day := range menu.Days {
+		for _, meal := range day.Meals {
+			if meal.Nutrition.ID == 0 || filter.Matches(&meal.Nutrition) {
+				continue
+			}
+			conflicts = append(conflicts, MenuConflict{
+				DayNumber: day.DayNumber,
+				MealType:  meal.MealType,
+				Dish:      meal.Nutrition.Title,
+				Conflict:  DishConflicts(&meal.Nutrition, filter),
+			})
+		}
+	}
+	return conflicts
+}
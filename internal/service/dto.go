@@ -1,5 +1,12 @@
 package service
 
+import (
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+)
+
 // Training DTOs
 type CreateTrainingDTO struct {
 	Title       string
@@ -21,8 +28,9 @@ type UpdateTrainingDTO struct {
 
 // DTO для недельного меню
 type CreateWeeklyMenuDTO struct {
-	Name        string
-	Description string
+	Name          string
+	Description   string
+	GeneratorSeed int64 // Заполняет генератор меню
 }
 
 type AddDayToMenuDTO struct {
@@ -39,6 +47,22 @@ type AddMealToDayDTO struct {
 	Notes       string
 }
 
+// GenerateMenuDTO - параметры генератора недельного меню
+type GenerateMenuDTO struct {
+	Name         string // Пусто - по калорийности
+	Calories     int    // Дневная норма, ккал
+	ProteinPct   int    // Доли энергии БЖУ в процентах, в сумме 100;
+	FatsPct      int    // все три 0 - DefaultMacroSplit
+	CarbsPct     int
+	MealTypes    []string          // Приемы пищи дня по порядку: ID из MealSlots или свое название; пусто - DefaultMealSlots
+	ExcludeIDs   []uint            // Блюда, которые не должны попасть в меню
+	ExcludeWords []string          // Слова в названии блюда, например «рыба»
+	Restrictions models.DishFilter // Диеты, которым должны соответствовать блюда, и исключенные аллергены
+	MaxRepeats   int               // Сколько раз одно блюдо может встретиться за неделю, 0 - DefaultMaxRepeats
+	Seed         int64             // 0 - случайный; использованный seed возвращается в результате и сохраняется в меню
+	Localizer    *i18n.Localizer   // Язык названий приемов пищи и описания меню; nil - основной
+}
+
 // Остальные существующие DTO...
 type CreateNutritionDTO struct {
 	Title       string
@@ -48,6 +72,8 @@ type CreateNutritionDTO struct {
 	Carbs       float64
 	Fats        float64
 	CategoryID  uint
+	Diets       int // Маска models.Diet*
+	Allergens   int // Маска models.Allergen*
 }
 
 type UpdateNutritionDTO struct {
@@ -58,6 +84,54 @@ type UpdateNutritionDTO struct {
 	Carbs       float64
 	Fats        float64
 	CategoryID  uint
+	Diets       int // Маска models.Diet*
+	Allergens   int // Маска models.Allergen*
+}
+
+// Recipe DTOs
+type IngredientDTO struct {
+	Name     string
+	Section  string  // Ключ отдела магазина из StoreSections, пусто - DefaultSection
+	Calories float64 // На 100 г
+	Protein  float64
+	Carbs    float64
+	Fats     float64
+}
+
+type RecipeItemDTO struct {
+	IngredientID uint
+	Grams        float64 // На одну порцию
+}
+
+type SetRecipeDTO struct {
+	Steps string
+	Items []RecipeItemDTO
+}
+
+// Program DTOs
+type ExerciseDTO struct {
+	Name        string
+	MuscleGroup string
+	Equipment   string
+	Description string
+	YouTubeLink string
+}
+
+type ProgramDayDTO struct {
+	ProgramID uint
+	Week      int // Неделя программы, с 1
+	DayNumber int // 1-7, где 1 - понедельник
+	Title     string
+}
+
+type ProgramExerciseDTO struct {
+	DayID       uint
+	ExerciseID  uint
+	Sets        int
+	Reps        string // «10», «8-12», «30 сек»
+	RestSeconds int
+	Tempo       string // Пусто или четыре части через дефис, например 3-1-1-0
+	Weight      string
 }
 
 // Category DTOs
@@ -72,9 +146,46 @@ type UpdateCategoryDTO struct {
 	Type        string
 }
 
+// Workout DTOs
+type LogWorkoutDTO struct {
+	UserID      uint
+	TrainingID  uint
+	PerformedAt time.Time // Нулевое значение - текущий момент
+	Duration    int
+	Effort      int
+}
+
+// Food diary DTOs
+type AddDiaryEntryDTO struct {
+	UserID      uint
+	NutritionID uint
+	Portion     float64
+	Date        time.Time // Нулевое значение - сегодня
+}
+
+// Measurement DTOs
+type AddMeasurementDTO struct {
+	UserID     uint
+	MeasuredAt time.Time // Нулевое значение - текущий момент
+	WeightKg   float64   // 0 - не измерялся
+	WaistCm    float64
+	HipsCm     float64
+	ChestCm    float64
+}
+
 // User DTOs
 type CreateUserDTO struct {
 	TelegramID int64
 	Name       string
 	Role       string
 }
+
+// ProfileDTO - данные онбординга для расчета нормы
+type ProfileDTO struct {
+	Sex           string // "male", "female"
+	Age           int
+	HeightCm      float64
+	WeightKg      float64
+	ActivityLevel string // ключ ActivityFactors
+	Goal          string // "lose", "maintain", "gain"
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+)
+
+type FoodDiaryService struct {
+	repo          repository.FoodDiaryRepository
+	nutritionRepo repository.NutritionRepository
+}
+
+func NewFoodDiaryService(repo repository.FoodDiaryRepository, nutritionRepo repository.NutritionRepository) *FoodDiaryService {
+	return &FoodDiaryService{
+		repo:          repo,
+		nutritionRepo: nutritionRepo,
+	}
+}
+
+// Macros - сумма КБЖУ
+type Macros struct {
+	Calories int
+	Protein  float64
+	Carbs    float64
+	Fats     float64
+}
+
+// DiaryDay - записи дневника за день и их сумма
+type DiaryDay struct {
+	Date    time.Time
+	Entries []*models.FoodDiaryEntry
+	Totals  Macros
+}
+
+// AddEntry - записать блюдо из каталога в дневник с множителем порции
+func (s *FoodDiaryService) AddEntry(dto AddDiaryEntryDTO) (*models.FoodDiaryEntry, error) {
+	if dto.UserID == 0 {
+		return nil, i18n.NewError("error.user.invalid")
+	}
+	if dto.Portion <= 0 || dto.Portion > 10 {
+		return nil, i18n.NewError("error.diary.portion")
+	}
+
+	plan, err := s.nutritionRepo.FindByID(dto.NutritionID)
+	if err != nil {
+		return nil, i18n.WrapError(err, "error.dish.not_found")
+	}
+
+	date := dto.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	entry := &models.FoodDiaryEntry{
+		UserID:      dto.UserID,
+		Date:        truncateToDay(date),
+		NutritionID: plan.ID,
+		Title:       plan.Title,
+		Portion:     dto.Portion,
+		Calories:    int(math.Round(float64(plan.Calories) * dto.Portion)),
+		Protein:     roundTenth(plan.Protein * dto.Portion),
+		Carbs:       roundTenth(plan.Carbs * dto.Portion),
+		Fats:        roundTenth(plan.Fats * dto.Portion),
+	}
+	return s.repo.Create(entry)
+}
+
+// GetDay - дневник пользователя за день с итогами
+func (s *FoodDiaryService) GetDay(userID uint, date time.Time) (*DiaryDay, error) {
+	entries, err := s.repo.FindByUserAndDate(userID, truncateToDay(date))
+	if err != nil {
+		return nil, err
+	}
+
+	day := &DiaryDay{Date: truncateToDay(date), Entries: entries}
+	for _, e := range entries {
+		day.Totals.Calories += e.Calories
+		day.Totals.Protein += e.Protein
+		day.Totals.Carbs += e.Carbs
+		day.Totals.Fats += e.Fats
+	}
+	return day, nil
+}
+
+// DeleteEntry - удалить запись из своего дневника
+func (s *FoodDiaryService) DeleteEntry(userID, entryID uint) error {
+	return s.repo.Delete(userID, entryID)
+}
+
+// FindDishes - поиск блюда для дневника: по ID, если введено число, иначе по названию
+func (s *FoodDiaryService) FindDishes(query string, limit int) ([]*models.NutritionPlan, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, i18n.NewError("error.search.empty")
+	}
+
+	if id, err := strconv.ParseUint(query, 10, 64); err == nil {
+		plan, err := s.nutritionRepo.FindByID(uint(id))
+		if err != nil {
+			return nil, nil
+		}
+		return []*models.NutritionPlan{plan}, nil
+	}
+
+	return s.nutritionRepo.SearchByTitle(query, limit)
+}
+
+// truncateToDay отбрасывает время, оставляя календарный день
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func roundTenth(v float64) float64 {
+	return math.Round(v*10) / 10
+}
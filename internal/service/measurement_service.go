@@ -0,0 +1,144 @@
+package service
+
+import (
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/chart"
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+)
+
+type MeasurementService struct {
+	repo repository.MeasurementRepository
+}
+
+func NewMeasurementService(repo repository.MeasurementRepository) *MeasurementService {
+	return &MeasurementService{repo: repo}
+}
+
+// ProgressChart - готовый график прогресса в PNG
+type ProgressChart struct {
+	Title string
+	PNG   []byte
+}
+
+// ErrNoProgressData - за период нет ни одного замера для графика
+var ErrNoProgressData = i18n.NewError("error.measure.no_data")
+
+// ChartLabels - подписи графиков прогресса на языке пользователя
+type ChartLabels struct {
+	Weight  string // Заголовок и линия графика веса
+	Girths  string // Заголовок графика обхватов
+	Waist   string
+	Hips    string
+	Chest   string
+	Kg      string
+	Cm      string
+	Decimal string // Десятичный разделитель чисел на оси
+}
+
+// Допустимые диапазоны замеров
+const (
+	minWeightKg = 30
+	maxWeightKg = 300
+	minGirthCm  = 30
+	maxGirthCm  = 250
+)
+
+// AddMeasurement - записать замеры. Нужен хотя бы один замер, незаполненные равны 0
+func (s *MeasurementService) AddMeasurement(dto AddMeasurementDTO) (*models.BodyMeasurement, error) {
+	if dto.UserID == 0 {
+		return nil, i18n.NewError("error.user.invalid")
+	}
+	if dto.WeightKg == 0 && dto.WaistCm == 0 && dto.HipsCm == 0 && dto.ChestCm == 0 {
+		return nil, i18n.NewError("error.measure.empty")
+	}
+	if dto.WeightKg != 0 && (dto.WeightKg < minWeightKg || dto.WeightKg > maxWeightKg) {
+		return nil, i18n.NewError("error.measure.weight", minWeightKg, maxWeightKg)
+	}
+	for _, girth := range []float64{dto.WaistCm, dto.HipsCm, dto.ChestCm} {
+		if girth != 0 && (girth < minGirthCm || girth > maxGirthCm) {
+			return nil, i18n.NewError("error.measure.girth", minGirthCm, maxGirthCm)
+		}
+	}
+
+	measuredAt := dto.MeasuredAt
+	if measuredAt.IsZero() {
+		measuredAt = time.Now()
+	}
+
+	return s.repo.Create(&models.BodyMeasurement{
+		UserID:     dto.UserID,
+		MeasuredAt: measuredAt,
+		WeightKg:   roundTenth(dto.WeightKg),
+		WaistCm:    roundTenth(dto.WaistCm),
+		HipsCm:     roundTenth(dto.HipsCm),
+		ChestCm:    roundTenth(dto.ChestCm),
+	})
+}
+
+// GetLatest - последний замер пользователя
+func (s *MeasurementService) GetLatest(userID uint) (*models.BodyMeasurement, error) {
+	return s.repo.FindLatestByUser(userID)
+}
+
+// ListSince - замеры пользователя начиная с since
+func (s *MeasurementService) ListSince(userID uint, since time.Time) ([]*models.BodyMeasurement, error) {
+	return s.repo.FindByUserSince(userID, since)
+}
+
+// RenderProgress - графики веса и обхватов за период начиная с since
+func (s *MeasurementService) RenderProgress(userID uint, since time.Time, labels ChartLabels) ([]ProgressChart, error) {
+	measurements, err := s.repo.FindByUserSince(userID, since)
+	if err != nil {
+		return nil, err
+	}
+	return BuildProgressCharts(measurements, labels)
+}
+
+// BuildProgressCharts рисует графики по готовому списку замеров.
+// Вес и обхваты рисуются отдельно, так как у них разные единицы.
+// Функция не обращается к БД и часам, поэтому результат детерминирован
+func BuildProgressCharts(measurements []*models.BodyMeasurement, labels ChartLabels) ([]ProgressChart, error) {
+	var weight, waist, hips, chest []chart.Point
+	for _, m := range measurements {
+		add := func(points *[]chart.Point, value float64) {
+			if value > 0 {
+				*points = append(*points, chart.Point{Time: m.MeasuredAt, Value: value})
+			}
+		}
+		add(&weight, m.WeightKg)
+		add(&waist, m.WaistCm)
+		add(&hips, m.HipsCm)
+		add(&chest, m.ChestCm)
+	}
+
+	var charts []ProgressChart
+	if len(weight) > 0 {
+		png, err := chart.RenderPNG(
+			chart.Options{Title: labels.Weight, Unit: labels.Kg, Decimal: labels.Decimal},
+			chart.Series{Name: labels.Weight, Color: chart.Blue, Points: weight},
+		)
+		if err != nil {
+			return nil, err
+		}
+		charts = append(charts, ProgressChart{Title: labels.Weight, PNG: png})
+	}
+	if len(waist)+len(hips)+len(chest) > 0 {
+		png, err := chart.RenderPNG(
+			chart.Options{Title: labels.Girths, Unit: labels.Cm, Decimal: labels.Decimal},
+			chart.Series{Name: labels.Waist, Color: chart.Orange, Points: waist},
+			chart.Series{Name: labels.Hips, Color: chart.Green, Points: hips},
+			chart.Series{Name: labels.Chest, Color: chart.Red, Points: chest},
+		)
+		if err != nil {
+			return nil, err
+		}
+		charts = append(charts, ProgressChart{Title: labels.Girths, PNG: png})
+	}
+	if len(charts) == 0 {
+		return nil, ErrNoProgressData
+	}
+	return charts, nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+)
+
+func TestBuildProgressCharts(t *testing.T) {
+	day := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	labels := ChartLabels{Weight: "Вес", Girths: "Обхваты", Decimal: ","}
+	tests := []struct {
+		name         string
+		measurements []*models.BodyMeasurement
+		want         []string // Заголовки графиков
+		wantErr      error
+	}{
+		{name: "нет замеров", wantErr: ErrNoProgressData},
+		{name: "замеры без значений", measurements: []*models.BodyMeasurement{{MeasuredAt: day}}, wantErr: ErrNoProgressData},
+		{name: "только вес", measurements: []*models.BodyMeasurement{{MeasuredAt: day, WeightKg: 61.5}}, want: []string{"Вес"}},
+		{
+			name: "вес и обхваты",
+			measurements: []*models.BodyMeasurement{
+				{MeasuredAt: day, WeightKg: 61.5, WaistCm: 70},
+				{MeasuredAt: day.AddDate(0, 0, 7), HipsCm: 96},
+			},
+			want: []string{"Вес", "Обхваты"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			charts, err := BuildProgressCharts(tc.measurements, labels)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("BuildProgressCharts() error = %v, want %v", err, tc.wantErr)
+			}
+			if len(charts) != len(tc.want) {
+				t.Fatalf("графиков %d, want %d", len(charts), len(tc.want))
+			}
+			for i, c := range charts {
+				if c.Title != tc.want[i] || len(c.PNG) == 0 {
+					t.Errorf("график %d: %q, %d байт PNG", i, c.Title, len(c.PNG))
+				}
+			}
+		})
+	}
+}
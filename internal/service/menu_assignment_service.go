@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Кто назначил меню пользователю
+const (
+	AssignedByUser  = "user"
+	AssignedByAdmin = "admin"
+	AssignedAuto    = "auto" // Подобрано по дневной норме калорий
+)
+
+// MenuAssignmentService - личные недельные меню пользователей.
+// Пользователь видит назначенное ему меню, а без назначения - общее активное
+type MenuAssignmentService struct {
+	repo             repository.MenuAssignmentRepository
+	nutritionService *NutritionService
+}
+
+func NewMenuAssignmentService(repo repository.MenuAssignmentRepository, nutritionService *NutritionService) *MenuAssignmentService {
+	return &MenuAssignmentService{
+		repo:             repo,
+		nutritionService: nutritionService,
+	}
+}
+
+// MenuForUser - недельное меню пользователя. Пользователю, которому меню еще
+// ни разу не назначали, при рассчитанной норме подбирается опубликованное меню
+// по калорийности. Если назначенного меню нет или оно удалено, возвращается
+// общее активное. nil без ошибки - меню нет совсем
+func (s *MenuAssignmentService) MenuForUser(user *models.User) (*models.WeeklyMenu, error) {
+	current, err := s.CurrentAssignment(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil && user.HasTargets() {
+		menu, err := s.AssignByTarget(user)
+		if err != nil {
+			log.Printf("[MenuAssignmentService] auto assign for user %d: %v", user.ID, err)
+		} else if menu != nil {
+			return menu, nil
+		}
+	}
+	return s.assignedMenu(current)
+}
+
+// CurrentMenu - меню, которое пользователь видит сейчас, без автоматического подбора
+func (s *MenuAssignmentService) CurrentMenu(userID uint) (*models.WeeklyMenu, error) {
+	current, err := s.CurrentAssignment(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.assignedMenu(current)
+}
+
+// CurrentMenuIDs - меню по последнему назначению каждого пользователя, у которого
+// были назначения (0 - общее активное). Меню не подбираются и не назначаются
+func (s *MenuAssignmentService) CurrentMenuIDs() (map[uint]uint, error) {
+	assignments, err := s.repo.FindAllCurrent()
+	if err != nil {
+		return nil, err
+	}
+	menuIDs := make(map[uint]uint, len(assignments))
+	for _, assignment := range assignments {
+		menuIDs[assignment.UserID] = assignment.MenuID
+	}
+	return menuIDs, nil
+}
+
+// CurrentAssignment - действующее назначение пользователя, nil - назначений не было
+func (s *MenuAssignmentService) CurrentAssignment(userID uint) (*models.MenuAssignment, error) {
+	current, err := s.repo.FindCurrent(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return current, err
+}
+
+// History - назначения пользователя от новых к старым
+func (s *MenuAssignmentService) History(userID uint, limit int) ([]*models.MenuAssignment, error) {
+	return s.repo.FindHistory(userID, limit)
+}
+
+// ChooseMenu - пользователь сам выбирает опубликованное меню без блюд,
+// которые он исключил. menuID 0 - вернуться к общему
+func (s *MenuAssignmentService) ChooseMenu(user *models.User, menuID uint) error {
+	if menuID != 0 {
+		menu, err := s.nutritionService.GetWeeklyMenuByID(menuID)
+		if err != nil {
+			return i18n.NewError("error.menu.not_found")
+		}
+		if !menu.Published {
+			return i18n.NewError("error.menu.unavailable", menu.Name)
+		}
+		conflicts, err := s.Conflicts(menuID, user.Restrictions())
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return i18n.NewError("error.menu.conflicts", menu.Name)
+		}
+	}
+	return s.repo.Create(&models.MenuAssignment{UserID: user.ID, MenuID: menuID, Source: AssignedByUser})
+}
+
+// AssignMenu - администратор назначает пользователю любое меню, в том числе
+// неопубликованное. menuID 0 - вернуть пользователя к общему меню
+func (s *MenuAssignmentService) AssignMenu(userID, menuID uint, adminTelegramID int64) error {
+	if menuID != 0 {
+		if _, err := s.nutritionService.GetWeeklyMenuByID(menuID); err != nil {
+			return i18n.NewError("error.menu.not_found")
+		}
+	}
+	return s.repo.Create(&models.MenuAssignment{
+		UserID:     userID,
+		MenuID:     menuID,
+		Source:     AssignedByAdmin,
+		AssignedBy: adminTelegramID,
+	})
+}
+
+// AssignByTarget назначает пользователю опубликованное меню, средняя калорийность
+// дня которого ближе всего к его норме, без исключенных им блюд.
+// nil без ошибки - подходящих меню нет
+func (s *MenuAssignmentService) AssignByTarget(user *models.User) (*models.WeeklyMenu, error) {
+	if !user.HasTargets() {
+		return nil, i18n.NewError("error.menu.no_targets")
+	}
+	menu, err := s.MatchMenu(user.CalorieTarget, user.Restrictions())
+	if err != nil || menu == nil {
+		return nil, err
+	}
+	err = s.repo.Create(&models.MenuAssignment{UserID: user.ID, MenuID: menu.ID, Source: AssignedAuto})
+	if err != nil {
+		return nil, err
+	}
+	return menu, nil
+}
+
+// MatchMenu - опубликованное меню с дневной калорийностью, ближайшей к calorieTarget.
+// Меню без заполненных дней и с блюдами, не подходящими под filter, не предлагаются
+func (s *MenuAssignmentService) MatchMenu(calorieTarget int, filter models.DishFilter) (*models.WeeklyMenu, error) {
+	menus, err := s.nutritionService.ListPublishedWeeklyMenus()
+	if err != nil {
+		return nil, err
+	}
+
+	var best *models.WeeklyMenu
+	bestDiff := 0
+	for _, menu := range menus {
+		daily, err := s.nutritionService.AverageDailyCalories(menu.ID)
+		if err != nil {
+			return nil, err
+		}
+		if daily == 0 {
+			continue
+		}
+		conflicts, err := s.Conflicts(menu.ID, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicts) > 0 {
+			continue
+		}
+		diff := daily - calorieTarget
+		if diff < 0 {
+			diff = -diff
+		}
+		if best == nil || diff < bestDiff {
+			best, bestDiff = menu, diff
+		}
+	}
+	return best, nil
+}
+
+// Conflicts - приемы пищи меню, не подходящие под ограничения питания
+func (s *MenuAssignmentService) Conflicts(menuID uint, filter models.DishFilter) ([]MenuConflict, error) {
+	if filter.Empty() {
+		return nil, nil
+	}
+	menu, err := s.nutritionService.GetFullWeeklyMenu(menuID)
+	if err != nil {
+		return nil, err
+	}
+	return MenuConflicts(menu, filter), nil
+}
+
+// assignedMenu - меню по назначению current; без назначения или если меню удалено - общее
+func (s *MenuAssignmentService) assignedMenu(current *models.MenuAssignment) (*models.WeeklyMenu, error) {
+	if current != nil && current.MenuID != 0 {
+		if menu, err := s.nutritionService.GetWeeklyMenuByID(current.MenuID); err == nil {
+			return menu, nil
+		}
+	}
+	return s.activeMenu()
+}
+
+// activeMenu - общее активное меню, nil - его нет
+func (s *MenuAssignmentService) activeMenu() (*models.WeeklyMenu, error) {
+	menu, err := s.nutritionService.GetActiveWeeklyMenu()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return menu, nil
+}
@@ -0,0 +1,522 @@
+package service
+
+import (
+	"log"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+)
+
+// Параметры генератора по умолчанию
+const (
+	DefaultMaxRepeats = 2
+
+	minGeneratedCalories = 800
+	maxGeneratedCalories = 6000
+	maxMealSlots         = 6
+
+	// Шагов локального поиска после жадной расстановки
+	generatorIterations = 4000
+	// Штраф за каждый повтор блюда за неделю, примерно как промах дня
+	// по калориям на 3%: при близкой точности выбирается более разнообразное меню
+	repeatPenalty = 0.003
+)
+
+// DefaultMacroSplit - доли энергии белков, жиров и углеводов, %
+var DefaultMacroSplit = [3]int{30, 25, 45}
+
+// MealSlot - стандартный прием пищи. ID не зависит от языка, название -
+// сообщение meal.<ID> каталога
+type MealSlot struct {
+	ID    string
+	Share float64 // Доля дневной калорийности в генераторе
+	Time  string  // Время в сгенерированном меню, по нему приходят напоминания
+}
+
+// MealSlots - стандартные приемы пищи генератора
+var MealSlots = []MealSlot{
+	{ID: "breakfast", Share: 25, Time: "08:00"},
+	{ID: "second_breakfast", Share: otherMealShare},
+	{ID: "lunch", Share: 35, Time: "13:00"},
+	{ID: "afternoon_snack", Share: otherMealShare},
+	{ID: "snack", Share: 10, Time: "16:00"},
+	{ID: "dinner", Share: 30, Time: "19:00"},
+}
+
+// DefaultMealSlots - ID приемов пищи дня, если они не заданы
+var DefaultMealSlots = []string{"breakfast", "lunch", "dinner"}
+
+// Доля дневной калорийности для своих приемов пищи, которых нет в MealSlots
+const otherMealShare = 20
+
+// MealSlotByID - стандартный прием пищи по ID
+func MealSlotByID(id string) (MealSlot, bool) {
+	for _, slot := range MealSlots {
+		if slot.ID == id {
+			return slot, true
+		}
+	}
+	return MealSlot{}, false
+}
+
+// MealName - название приема пищи: стандартный ID переводится, свое название остается как есть
+func MealName(tr *i18n.Localizer, mealType string) string {
+	if _, ok := MealSlotByID(mealType); ok {
+		return tr.T("meal." + mealType)
+	}
+	return mealType
+}
+
+// GeneratedMenu - сохраненное меню и то, насколько оно попало в норму
+type GeneratedMenu struct {
+	Menu   *models.WeeklyMenu // С днями и блюдами
+	Seed   int64              // Повторная генерация с тем же seed и каталогом дает то же меню
+	Target Macros             // Дневная норма КБЖУ
+	Days   []Macros           // КБЖУ каждого дня по порядку
+}
+
+// MenuGeneratorService собирает недельное меню из каталога блюд под дневную
+// норму калорий и БЖУ. Подбор детерминирован: жадная расстановка и локальный
+// поиск используют генератор случайных чисел с заданным seed
+type MenuGeneratorService struct {
+	nutritionService *NutritionService
+}
+
+func NewMenuGeneratorService(nutritionService *NutritionService) *MenuGeneratorService {
+	return &MenuGeneratorService{nutritionService: nutritionService}
+}
+
+// Generate подбирает блюда на 7 дней и сохраняет меню через NutritionService.
+// Меню создается неактивным и неопубликованным
+func (s *MenuGeneratorService) Generate(dto GenerateMenuDTO) (*GeneratedMenu, error) {
+	if err := normalizeGenerateDTO(&dto); err != nil {
+		return nil, err
+	}
+
+	plans, err := s.nutritionService.ListNutrition()
+	if err != nil {
+		return nil, err
+	}
+	g, err := planMenu(plans, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	menu, err := s.save(dto, g)
+	if err != nil {
+		return nil, err
+	}
+	result := &GeneratedMenu{Menu: menu, Seed: dto.Seed, Target: g.target}
+	for day := range g.week {
+		result.Days = append(result.Days, g.dayTotals(day))
+	}
+	return result, nil
+}
+
+// normalizeGenerateDTO проверяет параметры и подставляет значения по умолчанию
+func normalizeGenerateDTO(dto *GenerateMenuDTO) error {
+	if dto.Calories < minGeneratedCalories || dto.Calories > maxGeneratedCalories {
+		return i18n.NewError("error.generate.calories", minGeneratedCalories, maxGeneratedCalories)
+	}
+
+	if dto.ProteinPct == 0 && dto.FatsPct == 0 && dto.CarbsPct == 0 {
+		dto.ProteinPct, dto.FatsPct, dto.CarbsPct = DefaultMacroSplit[0], DefaultMacroSplit[1], DefaultMacroSplit[2]
+	}
+	if dto.ProteinPct < 0 || dto.FatsPct < 0 || dto.CarbsPct < 0 || dto.ProteinPct+dto.FatsPct+dto.CarbsPct != 100 {
+		return i18n.NewError("error.generate.shares")
+	}
+
+	if dto.Localizer == nil {
+		dto.Localizer = i18n.Default()
+	}
+	if len(dto.MealTypes) == 0 {
+		dto.MealTypes = DefaultMealSlots
+	}
+	if len(dto.MealTypes) > maxMealSlots {
+		return i18n.NewError("error.generate.too_many_meals", maxMealSlots)
+	}
+	seen := make(map[string]bool)
+	mealTypes := make([]string, 0, len(dto.MealTypes))
+	for _, mealType := range dto.MealTypes {
+		mealType = strings.TrimSpace(mealType)
+		if mealType == "" || len([]rune(mealType)) > 50 {
+			return i18n.NewError("error.generate.meal_name")
+		}
+		mealType = mealSlotID(dto.Localizer, mealType)
+		if seen[strings.ToLower(mealType)] {
+			return i18n.NewError("error.generate.meal_twice", MealName(dto.Localizer, mealType))
+		}
+		seen[strings.ToLower(mealType)] = true
+		mealTypes = append(mealTypes, mealType)
+	}
+	dto.MealTypes = mealTypes
+
+	if dto.MaxRepeats == 0 {
+		dto.MaxRepeats = DefaultMaxRepeats
+	}
+	if dto.MaxRepeats < 1 || dto.MaxRepeats > 7 {
+		return i18n.NewError("error.generate.repeats")
+	}
+
+	// Случайный seed не теряется: он возвращается в GeneratedMenu и сохраняется
+	// в меню (GeneratorSeed), по нему меню можно собрать повторно
+	if dto.Seed == 0 {
+		dto.Seed = time.Now().UnixNano()
+	}
+	dto.Name = strings.TrimSpace(dto.Name)
+	if dto.Name == "" {
+		dto.Name = dto.Localizer.T("generate.menu_name", dto.Calories)
+	}
+	return nil
+}
+
+// planMenu расставляет блюда каталога по неделе без записи в БД. dto уже
+// нормализован. Если подходящих блюд не хватает, возвращается ошибка, а не неполное меню
+func planMenu(plans []*models.NutritionPlan, dto GenerateMenuDTO) (*menuGenerator, error) {
+	dishes := filterDishes(plans, dto)
+	slots := len(dto.MealTypes)
+	if len(dishes) < slots {
+		return nil, i18n.NewError("error.generate.few_dishes", len(dishes), slots)
+	}
+	if len(dishes)*dto.MaxRepeats < 7*slots {
+		return nil, i18n.NewError("error.generate.few_repeats", len(dishes), dto.MaxRepeats, 7*slots)
+	}
+
+	g := newMenuGenerator(dishes, dto)
+	if err := g.greedy(); err != nil {
+		return nil, err
+	}
+	g.improve(generatorIterations)
+	return g, nil
+}
+
+// mealSlotID - ID стандартного приема пищи, если он указан ID или названием
+// на языке tr либо основном языке; свое название возвращается как есть
+func mealSlotID(tr *i18n.Localizer, mealType string) string {
+	for _, slot := range MealSlots {
+		key := "meal." + slot.ID
+		if mealType == slot.ID || strings.EqualFold(mealType, tr.T(key)) || strings.EqualFold(mealType, i18n.Default().T(key)) {
+			return slot.ID
+		}
+	}
+	return mealType
+}
+
+// filterDishes - блюда каталога с калорийностью, без исключенных, по возрастанию ID
+func filterDishes(plans []*models.NutritionPlan, dto GenerateMenuDTO) []*models.NutritionPlan {
+	excluded := make(map[uint]bool, len(dto.ExcludeIDs))
+	for _, id := range dto.ExcludeIDs {
+		excluded[id] = true
+	}
+	var words []string
+	for _, word := range dto.ExcludeWords {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			words = append(words, word)
+		}
+	}
+
+	var dishes []*models.NutritionPlan
+	for _, plan := range plans {
+		if plan.Calories <= 0 || excluded[plan.ID] || !dto.Restrictions.Matches(plan) {
+			continue
+		}
+		title := strings.ToLower(plan.Title)
+		skip := false
+		for _, word := range words {
+			if strings.Contains(title, word) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			dishes = append(dishes, plan)
+		}
+	}
+	sort.Slice(dishes, func(i, j int) bool { return dishes[i].ID < dishes[j].ID })
+	return dishes
+}
+
+// save записывает меню; если запись прервалась, недособранное меню удаляется
+func (s *MenuGeneratorService) save(dto GenerateMenuDTO, g *menuGenerator) (*models.WeeklyMenu, error) {
+	tr := dto.Localizer
+	names := make([]string, len(dto.MealTypes))
+	for i, mealType := range dto.MealTypes {
+		names[i] = MealName(tr, mealType)
+	}
+	description := tr.T("generate.description", dto.Calories, dto.ProteinPct, dto.FatsPct, dto.CarbsPct,
+		strings.ToLower(strings.Join(names, ", ")), dto.MaxRepeats, dto.Seed)
+	if len(dto.ExcludeWords) > 0 {
+		description += tr.T("generate.description_without", strings.Join(dto.ExcludeWords, ", "))
+	}
+	if labels := tagNames(tr, DietTags, dto.Restrictions.Diets); labels != "" {
+		description += tr.T("generate.description_diets", labels)
+	}
+	if labels := tagNames(tr, AllergenTags, dto.Restrictions.Allergens); labels != "" {
+		description += tr.T("generate.description_allergens", labels)
+	}
+
+	menu, err := s.nutritionService.CreateWeeklyMenu(CreateWeeklyMenuDTO{
+		Name:          dto.Name,
+		Description:   description,
+		GeneratorSeed: dto.Seed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fill(menu.ID, dto.MealTypes, names, g); err != nil {
+		if delErr := s.nutritionService.DeleteWeeklyMenu(menu.ID); delErr != nil {
+			log.Printf("[MenuGenerator] failed to delete incomplete menu %d: %v", menu.ID, delErr)
+		}
+		return nil, err
+	}
+	return s.nutritionService.GetFullWeeklyMenu(menu.ID)
+}
+
+// fill записывает блюда; names - названия приемов пищи mealTypes для меню
+func (s *MenuGeneratorService) fill(menuID uint, mealTypes, names []string, g *menuGenerator) error {
+	for day, dishes := range g.week {
+		menuDay, err := s.nutritionService.AddDayToWeeklyMenu(AddDayToMenuDTO{MenuID: menuID, DayNumber: day + 1})
+		if err != nil {
+			return err
+		}
+		for slot, dish := range dishes {
+			_, err := s.nutritionService.AddMealToDay(AddMealToDayDTO{
+				DayID:       menuDay.ID,
+				MealType:    names[slot],
+				MealTime:    mealSlotTime(mealTypes[slot]),
+				NutritionID: g.dishes[dish].ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mealSlotTime - время стандартного приема пищи; у своих приемов времени нет
+func mealSlotTime(mealType string) string {
+	slot, _ := MealSlotByID(mealType)
+	return slot.Time
+}
+
+// tagNames - названия тегов маски на языке tr через запятую, строчными
+func tagNames(tr *i18n.Localizer, tags []DietTag, mask int) string {
+	keys := TagKeys(tags, mask)
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = strings.ToLower(tr.T("tag." + key))
+	}
+	return strings.Join(names, ", ")
+}
+
+// ==================== ПОДБОР ====================
+
+// menuGenerator - расстановка блюд по неделе. week[день][прием] - индекс в dishes
+type menuGenerator struct {
+	dishes     []*models.NutritionPlan
+	target     Macros
+	slotShares []float64 // Доля дневной калорийности каждого приема, в сумме 1
+	maxRepeats int
+	rng        *rand.Rand
+
+	week   [7][]int
+	counts []int // Сколько раз блюдо стоит в меню
+}
+
+func newMenuGenerator(dishes []*models.NutritionPlan, dto GenerateMenuDTO) *menuGenerator {
+	calories := float64(dto.Calories)
+	g := &menuGenerator{
+		dishes: dishes,
+		target: Macros{
+			Calories: dto.Calories,
+			Protein:  roundTenth(calories * float64(dto.ProteinPct) / 100 / 4),
+			Fats:     roundTenth(calories * float64(dto.FatsPct) / 100 / 9),
+			Carbs:    roundTenth(calories * float64(dto.CarbsPct) / 100 / 4),
+		},
+		maxRepeats: dto.MaxRepeats,
+		rng:        rand.New(rand.NewPCG(uint64(dto.Seed), uint64(dto.Seed)>>32)),
+		counts:     make([]int, len(dishes)),
+	}
+
+	total := 0.0
+	for _, mealType := range dto.MealTypes {
+		share := float64(otherMealShare)
+		if slot, ok := MealSlotByID(mealType); ok {
+			share = slot.Share
+		}
+		g.slotShares = append(g.slotShares, share)
+		total += share
+	}
+	for i := range g.slotShares {
+		g.slotShares[i] /= total
+	}
+	for day := range g.week {
+		g.week[day] = make([]int, len(dto.MealTypes))
+		for slot := range g.week[day] {
+			g.week[day][slot] = -1
+		}
+	}
+	return g
+}
+
+// greedy расставляет блюда по порядку: в каждый прием ставится блюдо, с которым
+// день ближе всего к норме, если остальные приемы попадут точно в свою долю.
+// Кандидаты перебираются в перемешанном seed порядке, он же решает равенства
+func (g *menuGenerator) greedy() error {
+	order := make([]int, len(g.dishes))
+	for i := range order {
+		order[i] = i
+	}
+	for day := range g.week {
+		g.rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		for slot := range g.week[day] {
+			best, bestCost := -1, math.Inf(1)
+			for _, dish := range order {
+				if !g.allowed(day, dish) {
+					continue
+				}
+				g.week[day][slot] = dish
+				cost := g.dayCost(day) + repeatPenalty*float64(g.counts[dish])
+				g.week[day][slot] = -1
+				if cost < bestCost {
+					best, bestCost = dish, cost
+				}
+			}
+			if best < 0 && !g.borrow(day, slot) {
+				return i18n.NewError("error.generate.day_repeats")
+			}
+			if best >= 0 {
+				g.week[day][slot] = best
+				g.counts[best]++
+			}
+		}
+	}
+	return nil
+}
+
+// borrow заполняет прием, когда все блюда с запасом повторов уже стоят в этом дне:
+// такое блюдо ставится в тот же прием другого дня, а стоявшее там переезжает сюда
+func (g *menuGenerator) borrow(day, slot int) bool {
+	for spare := range g.dishes {
+		if g.counts[spare] >= g.maxRepeats {
+			continue
+		}
+		for other := range g.week {
+			moved := g.week[other][slot]
+			if other == day || moved < 0 || moved == spare || g.inDay(other, spare) || g.inDay(day, moved) {
+				continue
+			}
+			g.week[other][slot] = spare
+			g.week[day][slot] = moved
+			g.counts[spare]++
+			return true
+		}
+	}
+	return false
+}
+
+// improve - локальный поиск: случайная замена блюда в приеме или обмен блюд
+// одного приема между двумя днями. Изменение остается, только если меню стало точнее
+func (g *menuGenerator) improve(iterations int) {
+	slots := len(g.slotShares)
+	for range iterations {
+		day, slot := g.rng.IntN(7), g.rng.IntN(slots)
+		current := g.week[day][slot]
+
+		if g.rng.IntN(2) == 0 {
+			dish := g.rng.IntN(len(g.dishes))
+			if dish == current || !g.allowed(day, dish) {
+				continue
+			}
+			before := g.dayCost(day)
+			g.week[day][slot] = dish
+			delta := g.dayCost(day) - before +
+				repeatPenalty*float64(g.counts[dish]-(g.counts[current]-1))
+			if delta < -1e-9 {
+				g.counts[current]--
+				g.counts[dish]++
+			} else {
+				g.week[day][slot] = current
+			}
+			continue
+		}
+
+		other := g.rng.IntN(7)
+		swap := g.week[other][slot]
+		if other == day || swap == current || g.inDay(day, swap) || g.inDay(other, current) {
+			continue
+		}
+		before := g.dayCost(day) + g.dayCost(other)
+		g.week[day][slot], g.week[other][slot] = swap, current
+		if g.dayCost(day)+g.dayCost(other)-before >= -1e-9 {
+			g.week[day][slot], g.week[other][slot] = current, swap
+		}
+	}
+}
+
+// allowed - можно ли поставить блюдо в день: в одном дне блюда не повторяются,
+// за неделю - не больше maxRepeats раз
+func (g *menuGenerator) allowed(day, dish int) bool {
+	return g.counts[dish] < g.maxRepeats && !g.inDay(day, dish)
+}
+
+// inDay - стоит ли блюдо уже в каком-то приеме дня
+func (g *menuGenerator) inDay(day, dish int) bool {
+	for _, placed := range g.week[day] {
+		if placed == dish {
+			return true
+		}
+	}
+	return false
+}
+
+// dayCost - отклонение дня от нормы: сумма квадратов отклонений калорий
+// (с тройным весом), энергии каждого из БЖУ и калорий каждого приема от его доли,
+// все в долях дневной нормы. Из-за квадратов недобор размазывается по неделе,
+// а не копится в последних днях. Пустые приемы считаются попавшими в долю точно
+func (g *menuGenerator) dayCost(day int) float64 {
+	target := float64(g.target.Calories)
+	sq := func(diff float64) float64 { return diff * diff / (target * target) }
+	var calories, protein, fats, carbs, slotErr float64
+	for slot, dish := range g.week[day] {
+		share := g.slotShares[slot]
+		if dish < 0 {
+			calories += target * share
+			protein += g.target.Protein * share
+			fats += g.target.Fats * share
+			carbs += g.target.Carbs * share
+			continue
+		}
+		plan := g.dishes[dish]
+		calories += float64(plan.Calories)
+		protein += plan.Protein
+		fats += plan.Fats
+		carbs += plan.Carbs
+		slotErr += sq(float64(plan.Calories) - target*share)
+	}
+
+	macroErr := sq((protein-g.target.Protein)*4) + sq((fats-g.target.Fats)*9) + sq((carbs-g.target.Carbs)*4)
+	return 3*sq(calories-target) + macroErr + 0.5*slotErr
+}
+
+// dayTotals - КБЖУ дня
+func (g *menuGenerator) dayTotals(day int) Macros {
+	var totals Macros
+	for _, dish := range g.week[day] {
+		plan := g.dishes[dish]
+		totals.Calories += plan.Calories
+		totals.Protein += plan.Protein
+		totals.Fats += plan.Fats
+		totals.Carbs += plan.Carbs
+	}
+	totals.Protein = roundTenth(totals.Protein)
+	totals.Fats = roundTenth(totals.Fats)
+	totals.Carbs = roundTenth(totals.Carbs)
+	return totals
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+)
+
+// testCatalog - каталог блюд в памяти: калорийность от 250 до 950 ккал, БЖУ
+// около доли по умолчанию с перекосом то в белки, то в углеводы. Каждое третье
+// блюдо веганское, каждое четвертое с орехами, в названии каждого пятого есть «рыба»
+func testCatalog(n int) []*models.NutritionPlan {
+	plans := make([]*models.NutritionPlan, n)
+	for i := range plans {
+		calories := 250 + (i*137)%700
+		skew := float64(i%5-2) * 0.04 // Перекос энергии между белками и углеводами
+		plan := &models.NutritionPlan{
+			Title:    fmt.Sprintf("Блюдо %d", i+1),
+			Calories: calories,
+			Protein:  roundTenth(float64(calories) * (0.30 + skew) / 4),
+			Fats:     roundTenth(float64(calories) * 0.25 / 9),
+			Carbs:    roundTenth(float64(calories) * (0.45 - skew) / 4),
+		}
+		plan.ID = uint(i + 1)
+		if i%3 == 0 {
+			plan.Diets = models.DietVegan | models.DietVegetarian | models.DietLactoseFree
+		}
+		if i%4 == 0 {
+			plan.Allergens = models.AllergenNuts
+		}
+		if i%5 == 0 {
+			plan.Title += " рыба"
+		}
+		plans[i] = plan
+	}
+	return plans
+}
+
+// plan нормализует dto и расставляет блюда каталога
+func plan(t *testing.T, plans []*models.NutritionPlan, dto GenerateMenuDTO) (*menuGenerator, error) {
+	t.Helper()
+	if err := normalizeGenerateDTO(&dto); err != nil {
+		t.Fatalf("normalizeGenerateDTO: %v", err)
+	}
+	return planMenu(plans, dto)
+}
+
+// weekDishes - блюда меню по дням
+func weekDishes(g *menuGenerator) [7][]*models.NutritionPlan {
+	var week [7][]*models.NutritionPlan
+	for day, dishes := range g.week {
+		for _, dish := range dishes {
+			week[day] = append(week[day], g.dishes[dish])
+		}
+	}
+	return week
+}
+
+func TestPlanMenuSameSeed(t *testing.T) {
+	catalog := testCatalog(40)
+	dto := GenerateMenuDTO{Calories: 2000, Seed: 42}
+	first, err := plan(t, catalog, dto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := plan(t, catalog, dto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first.week, again.week) {
+		t.Errorf("один seed дал разные меню:\n%v\n%v", first.week, again.week)
+	}
+
+	dto.Seed = 43
+	other, err := plan(t, catalog, dto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(other.week, first.week) {
+		t.Error("разные seed дали одинаковое меню")
+	}
+}
+
+func TestPlanMenuTargets(t *testing.T) {
+	tests := []struct {
+		name string
+		dto  GenerateMenuDTO
+	}{
+		{"по умолчанию", GenerateMenuDTO{Calories: 2000}},
+		{"мало калорий", GenerateMenuDTO{Calories: 1500}},
+		{"много калорий, четыре приема", GenerateMenuDTO{Calories: 3000, MealTypes: []string{"breakfast", "lunch", "snack", "dinner"}}},
+		{"белковое меню", GenerateMenuDTO{Calories: 2200, ProteinPct: 35, FatsPct: 25, CarbsPct: 40}},
+	}
+	// Допуск дня: калории - 5% нормы, энергия каждого из БЖУ - 5% калорий нормы
+	const tolerance = 0.05
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.dto.Seed = 7
+			g, err := plan(t, testCatalog(60), tc.dto)
+			if err != nil {
+				t.Fatal(err)
+			}
+			target := float64(g.target.Calories)
+			for day := range g.week {
+				got := g.dayTotals(day)
+				if diff := math.Abs(float64(got.Calories) - target); diff > tolerance*target {
+					t.Errorf("день %d: %d ккал, норма %d", day+1, got.Calories, g.target.Calories)
+				}
+				macros := []struct {
+					name      string
+					got, want float64
+					kcal      float64
+				}{
+					{"белки", got.Protein, g.target.Protein, 4},
+					{"жиры", got.Fats, g.target.Fats, 9},
+					{"углеводы", got.Carbs, g.target.Carbs, 4},
+				}
+				for _, m := range macros {
+					if diff := math.Abs(m.got-m.want) * m.kcal; diff > tolerance*target {
+						t.Errorf("день %d: %s %.1f г, норма %.1f г", day+1, m.name, m.got, m.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPlanMenuExclusions(t *testing.T) {
+	catalog := testCatalog(60)
+	dto := GenerateMenuDTO{
+		Calories:     2000,
+		Seed:         3,
+		ExcludeIDs:   []uint{3, 6},
+		ExcludeWords: []string{" РЫБА "},
+		Restrictions: models.DishFilter{Diets: models.DietVegetarian, Allergens: models.AllergenNuts},
+	}
+	g, err := plan(t, catalog, dto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for day, dishes := range weekDishes(g) {
+		for _, dish := range dishes {
+			switch {
+			case dish.ID == 3 || dish.ID == 6:
+				t.Errorf("день %d: исключенное блюдо %d", day+1, dish.ID)
+			case strings.Contains(dish.Title, "рыба"):
+				t.Errorf("день %d: блюдо с исключенным словом %q", day+1, dish.Title)
+			case dish.Diets&models.DietVegetarian == 0:
+				t.Errorf("день %d: невегетарианское блюдо %q", day+1, dish.Title)
+			case dish.Allergens&models.AllergenNuts != 0:
+				t.Errorf("день %d: блюдо с орехами %q", day+1, dish.Title)
+			}
+		}
+	}
+}
+
+func TestPlanMenuRepeats(t *testing.T) {
+	tests := []struct {
+		name       string
+		dishes     int
+		maxRepeats int
+	}{
+		{"без повторов", 21, 1},
+		{"по умолчанию", 12, 0},
+		{"три раза за неделю", 7, 3},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := plan(t, testCatalog(tc.dishes), GenerateMenuDTO{Calories: 2000, Seed: 11, MaxRepeats: tc.maxRepeats})
+			if err != nil {
+				t.Fatal(err)
+			}
+			limit := tc.maxRepeats
+			if limit == 0 {
+				limit = DefaultMaxRepeats
+			}
+			counts := make(map[uint]int)
+			for day, dishes := range weekDishes(g) {
+				seen := make(map[uint]bool)
+				for _, dish := range dishes {
+					if seen[dish.ID] {
+						t.Errorf("день %d: блюдо %d дважды", day+1, dish.ID)
+					}
+					seen[dish.ID] = true
+					counts[dish.ID]++
+				}
+			}
+			for id, count := range counts {
+				if count > limit {
+					t.Errorf("блюдо %d стоит %d раз, можно %d", id, count, limit)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanMenuInfeasible(t *testing.T) {
+	tests := []struct {
+		name    string
+		catalog []*models.NutritionPlan
+		dto     GenerateMenuDTO
+		want    string // Ключ ошибки
+	}{
+		{"блюд меньше приемов", testCatalog(2), GenerateMenuDTO{Calories: 2000}, "error.generate.few_dishes"},
+		{"не хватает повторов", testCatalog(10), GenerateMenuDTO{Calories: 2000, MaxRepeats: 1}, "error.generate.few_repeats"},
+		{
+			"ограничения убрали все блюда", testCatalog(40),
+			GenerateMenuDTO{Calories: 2000, Restrictions: models.DishFilter{Diets: models.DietHalal}},
+			"error.generate.few_dishes",
+		},
+		{
+			"блюда без калорийности не считаются", []*models.NutritionPlan{{Title: "вода"}, {Title: "чай"}, {Title: "кофе"}},
+			GenerateMenuDTO{Calories: 2000}, "error.generate.few_dishes",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.dto.Seed = 1
+			g, err := plan(t, tc.catalog, tc.dto)
+			if err == nil {
+				t.Fatalf("нет ошибки, меню %v", g.week)
+			}
+			if g != nil {
+				t.Error("вместе с ошибкой возвращено меню")
+			}
+			var e *i18n.Error
+			if !errors.As(err, &e) || e.Key != tc.want {
+				t.Errorf("ошибка %v, want %s", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeGenerateDTO(t *testing.T) {
+	tests := []struct {
+		name    string
+		dto     GenerateMenuDTO
+		wantErr bool
+	}{
+		{"по умолчанию", GenerateMenuDTO{Calories: 2000}, false},
+		{"мало калорий", GenerateMenuDTO{Calories: minGeneratedCalories - 1}, true},
+		{"много калорий", GenerateMenuDTO{Calories: maxGeneratedCalories + 1}, true},
+		{"доли не в сумме 100", GenerateMenuDTO{Calories: 2000, ProteinPct: 30, FatsPct: 30, CarbsPct: 30}, true},
+		{"отрицательная доля", GenerateMenuDTO{Calories: 2000, ProteinPct: -10, FatsPct: 60, CarbsPct: 50}, true},
+		{"прием дважды", GenerateMenuDTO{Calories: 2000, MealTypes: []string{"lunch", "Обед"}}, true},
+		{"пустой прием", GenerateMenuDTO{Calories: 2000, MealTypes: []string{"lunch", " "}}, true},
+		{"слишком много приемов", GenerateMenuDTO{Calories: 2000, MealTypes: []string{"1", "2", "3", "4", "5", "6", "7"}}, true},
+		{"повторов больше 7", GenerateMenuDTO{Calories: 2000, MaxRepeats: 8}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := normalizeGenerateDTO(&tc.dto)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("normalizeGenerateDTO() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && tc.dto.Seed == 0 {
+				t.Error("seed не выбран")
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/menupdf"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+)
+
+// WeeklyMenuPDF - недельное меню menuID в PDF на языке tr. calorieTarget - дневная норма
+// пользователя для процентов в итогах дня, 0 - без нормы
+func (s *NutritionService) WeeklyMenuPDF(menuID uint, calorieTarget int, tr *i18n.Localizer) ([]byte, error) {
+	menu, err := s.GetFullWeeklyMenu(menuID)
+	if err != nil {
+		return nil, err
+	}
+	return BuildWeeklyMenuPDF(menu, calorieTarget, time.Now(), tr)
+}
+
+// BuildWeeklyMenuPDF печатает загруженное меню. Сетка всегда на 7 дней:
+// дни, которых нет в меню, остаются пустыми. Приемы пищи без блюда пропускаются
+func BuildWeeklyMenuPDF(menu *models.WeeklyMenu, calorieTarget int, generatedAt time.Time, tr *i18n.Localizer) ([]byte, error) {
+	doc := menupdf.Menu{
+		Title:         menu.Name,
+		Description:   menu.Description,
+		MealTypes:     MealTypeNames(tr),
+		Days:          make([]menupdf.Day, 7),
+		CalorieTarget: calorieTarget,
+		GeneratedAt:   generatedAt,
+	}
+	for i := range doc.Days {
+		doc.Days[i].Name = tr.T("weekday." + strconv.Itoa(i+1))
+	}
+
+	for _, day := range menu.Days {
+		if day.DayNumber < 1 || day.DayNumber > len(doc.Days) {
+			continue
+		}
+		column := &doc.Days[day.DayNumber-1]
+		for _, meal := range day.Meals {
+			if meal.Nutrition.ID == 0 {
+				continue
+			}
+			column.Meals = append(column.Meals, menupdf.Meal{
+				Type:   meal.MealType,
+				Time:   meal.MealTime,
+				DishID: meal.Nutrition.ID,
+				Dish:   meal.Nutrition.Title,
+				Macros: menupdf.Macros{
+					Calories: meal.Nutrition.Calories,
+					Protein:  meal.Nutrition.Protein,
+					Carbs:    meal.Nutrition.Carbs,
+					Fats:     meal.Nutrition.Fats,
+				},
+			})
+		}
+	}
+	return menupdf.Render(doc, tr)
+}
@@ -1,9 +1,10 @@
 package service
 
 import (
-	"fmt"
 	"log"
+	"strconv"
 
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
 	"github.com/alenapavlenkko/telegramfitnes/internal/models"
 	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
 )
@@ -11,17 +12,34 @@ import (
 type NutritionService struct {
 	repo           repository.NutritionRepository
 	weeklyMenuRepo repository.WeeklyMenuRepository
+	recipeRepo     repository.RecipeRepository
 }
 
-func NewNutritionService(repo repository.NutritionRepository, weeklyMenuRepo repository.WeeklyMenuRepository) *NutritionService {
+func NewNutritionService(
+	repo repository.NutritionRepository,
+	weeklyMenuRepo repository.WeeklyMenuRepository,
+	recipeRepo repository.RecipeRepository,
+) *NutritionService {
 	return &NutritionService{
 		repo:           repo,
 		weeklyMenuRepo: weeklyMenuRepo,
+		recipeRepo:     recipeRepo,
 	}
 }
 
 // CreateNutrition - создать план питания
 func (s *NutritionService) CreateNutrition(dto CreateNutritionDTO) (*models.NutritionPlan, error) {
+	if dto.Title == "" {
+		return nil, i18n.NewError("error.dish.title_empty")
+	}
+	if dto.Calories < 0 || dto.Protein < 0 || dto.Carbs < 0 || dto.Fats < 0 {
+		return nil, i18n.NewError("error.macros.negative")
+	}
+	diets, allergens := NormalizeDiets(dto.Diets), NormalizeAllergens(dto.Allergens)
+	if err := ValidateDishTags(diets, allergens); err != nil {
+		return nil, err
+	}
+
 	plan := &models.NutritionPlan{
 		Title:       dto.Title,
 		Description: dto.Description,
@@ -30,6 +48,8 @@ func (s *NutritionService) CreateNutrition(dto CreateNutritionDTO) (*models.Nutr
 		Carbs:       dto.Carbs,
 		Fats:        dto.Fats,
 		CategoryID:  dto.CategoryID,
+		Diets:       diets,
+		Allergens:   allergens,
 	}
 	return s.repo.Create(plan)
 }
@@ -39,6 +59,35 @@ func (s *NutritionService) ListNutrition() ([]*models.NutritionPlan, error) {
 	return s.repo.FindAll()
 }
 
+// ListNutritionFor - блюда, подходящие под ограничения питания filter
+func (s *NutritionService) ListNutritionFor(filter models.DishFilter) ([]*models.NutritionPlan, error) {
+	plans, err := s.repo.FindAll()
+	if err != nil || filter.Empty() {
+		return plans, err
+	}
+	matched := make([]*models.NutritionPlan, 0, len(plans))
+	for _, plan := range plans {
+		if filter.Matches(plan) {
+			matched = append(matched, plan)
+		}
+	}
+	return matched, nil
+}
+
+// ListNutritionByCategory - страница блюд категории (page с нуля), подходящих
+// под ограничения filter, и их общее количество
+func (s *NutritionService) ListNutritionByCategory(categoryID uint, filter models.DishFilter, page, pageSize int) ([]*models.NutritionPlan, int64, error) {
+	if categoryID == 0 {
+		return nil, 0, i18n.NewError("error.category.invalid_id")
+	}
+	total, err := s.repo.CountByCategoryID(categoryID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	plans, err := s.repo.FindByCategoryID(categoryID, filter, pageOffset(page, pageSize), pageSize)
+	return plans, total, err
+}
+
 // GetNutritionByID - получить план питания по ID
 func (s *NutritionService) GetNutritionByID(id uint) (*models.NutritionPlan, error) {
 	return s.repo.FindByID(id)
@@ -55,6 +104,7 @@ func (s *NutritionService) UpdateNutrition(id uint, dto UpdateNutritionDTO) erro
 	if err != nil {
 		return err
 	}
+	oldCalories := plan.Calories
 
 	if dto.Title != "" {
 		plan.Title = dto.Title
@@ -77,8 +127,36 @@ func (s *NutritionService) UpdateNutrition(id uint, dto UpdateNutritionDTO) erro
 	if dto.CategoryID > 0 {
 		plan.CategoryID = dto.CategoryID
 	}
+	// Отметки диет и аллергенов заменяются всегда: пустая маска - отметок нет
+	plan.Diets, plan.Allergens = NormalizeDiets(dto.Diets), NormalizeAllergens(dto.Allergens)
+	if err := ValidateDishTags(plan.Diets, plan.Allergens); err != nil {
+		return err
+	}
+	// У блюда с рецептом КБЖУ считаются по составу, а не берутся из запроса
+	if _, err := s.applyRecipe(plan); err != nil {
+		return err
+	}
+
+	caloriesChanged := plan.Calories != oldCalories
+	if err := s.repo.Update(plan); err != nil {
+		return err
+	}
+	if caloriesChanged {
+		s.RefreshMenusWithDish(id)
+	}
+	return nil
+}
 
-	return s.repo.Update(plan)
+// applyRecipe заменяет КБЖУ блюда посчитанными по составу и сообщает, есть ли состав.
+// КБЖУ блюда без состава не меняются
+func (s *NutritionService) applyRecipe(plan *models.NutritionPlan) (bool, error) {
+	items, err := s.recipeRepo.FindByNutritionID(plan.ID)
+	if err != nil || len(items) == 0 {
+		return false, err
+	}
+	totals := RecipeTotals(items)
+	plan.Calories, plan.Protein, plan.Carbs, plan.Fats = totals.Calories, totals.Protein, totals.Carbs, totals.Fats
+	return true, nil
 }
 
 // ==================== МЕТОДЫ ДЛЯ НЕДЕЛЬНОГО МЕНЮ ====================
@@ -86,12 +164,13 @@ func (s *NutritionService) UpdateNutrition(id uint, dto UpdateNutritionDTO) erro
 // CreateWeeklyMenu - создать недельное меню
 func (s *NutritionService) CreateWeeklyMenu(dto CreateWeeklyMenuDTO) (*models.WeeklyMenu, error) {
 	if dto.Name == "" {
-		return nil, fmt.Errorf("название меню не может быть пустым")
+		return nil, i18n.NewError("error.menu.name_empty")
 	}
 
 	menu := &models.WeeklyMenu{
 		Name:          dto.Name,
 		Description:   dto.Description,
+		GeneratorSeed: dto.GeneratorSeed,
 		TotalCalories: 0,
 		Active:        false,
 	}
@@ -104,6 +183,21 @@ func (s *NutritionService) ListWeeklyMenus() ([]*models.WeeklyMenu, error) {
 	return s.weeklyMenuRepo.FindAll()
 }
 
+// GetWeeklyMenuByID - меню без дней и приемов пищи
+func (s *NutritionService) GetWeeklyMenuByID(id uint) (*models.WeeklyMenu, error) {
+	return s.weeklyMenuRepo.FindByID(id)
+}
+
+// GetMenuDayByID - день меню без приемов пищи
+func (s *NutritionService) GetMenuDayByID(dayID uint) (*models.MenuDay, error) {
+	return s.weeklyMenuRepo.FindDayByID(dayID)
+}
+
+// GetDayMealByID - прием пищи дня меню
+func (s *NutritionService) GetDayMealByID(mealID uint) (*models.DayMeal, error) {
+	return s.weeklyMenuRepo.FindMealByID(mealID)
+}
+
 // GetActiveWeeklyMenu - получить активное недельное меню
 func (s *NutritionService) GetActiveWeeklyMenu() (*models.WeeklyMenu, error) {
 	return s.weeklyMenuRepo.FindActive()
@@ -120,10 +214,56 @@ func (s *NutritionService) ActivateWeeklyMenu(menuID uint) error {
 	return s.weeklyMenuRepo.Activate(menuID)
 }
 
-// AddDayToWeeklyMenu - добавить день в недельное меню
+// ListPublishedWeeklyMenus - меню, которые пользователи могут выбрать себе
+func (s *NutritionService) ListPublishedWeeklyMenus() ([]*models.WeeklyMenu, error) {
+	return s.weeklyMenuRepo.FindPublished()
+}
+
+// SetWeeklyMenuPublished - открыть меню для выбора пользователями или скрыть его.
+// Уже назначенное пользователям меню остается у них и после снятия с публикации
+func (s *NutritionService) SetWeeklyMenuPublished(menuID uint, published bool) error {
+	return s.weeklyMenuRepo.SetPublished(menuID, published)
+}
+
+// AverageDailyCalories - средняя калорийность дня меню по заполненным дням
+func (s *NutritionService) AverageDailyCalories(menuID uint) (int, error) {
+	days, err := s.weeklyMenuRepo.FindDaysByMenuID(menuID)
+	if err != nil {
+		return 0, err
+	}
+	total, filled := 0, 0
+	for _, day := range days {
+		if day.TotalCalories > 0 {
+			total += day.TotalCalories
+			filled++
+		}
+	}
+	if filled == 0 {
+		return 0, nil
+	}
+	return total / filled, nil
+}
+
+// MealTypes - ID стандартных приемов пищи в порядке строк сетки меню
+var MealTypes = []string{"breakfast", "lunch", "dinner", "snack"}
+
+// MealTypeNames - названия стандартных приемов пищи на языке tr в порядке MealTypes
+func MealTypeNames(tr *i18n.Localizer) []string {
+	names := make([]string, len(MealTypes))
+	for i, mealType := range MealTypes {
+		names[i] = MealName(tr, mealType)
+	}
+	return names
+}
+
+// AddDayToWeeklyMenu - добавить день в недельное меню.
+// Если название дня не задано, берется по номеру
 func (s *NutritionService) AddDayToWeeklyMenu(dto AddDayToMenuDTO) (*models.MenuDay, error) {
 	if dto.DayNumber < 1 || dto.DayNumber > 7 {
-		return nil, fmt.Errorf("номер дня должен быть от 1 до 7")
+		return nil, i18n.NewError("error.day.number")
+	}
+	if dto.DayName == "" {
+		dto.DayName = i18n.Default().T("weekday." + strconv.Itoa(dto.DayNumber))
 	}
 
 	day := &models.MenuDay{
@@ -140,7 +280,7 @@ func (s *NutritionService) AddMealToDay(dto AddMealToDayDTO) (*models.DayMeal, e
 	// Проверяем существование питания
 	_, err := s.repo.FindByID(dto.NutritionID)
 	if err != nil {
-		return nil, fmt.Errorf("питание не найдено: %w", err)
+		return nil, i18n.WrapError(err, "error.dish.not_found")
 	}
 
 	meal := &models.DayMeal{
@@ -173,6 +313,59 @@ func (s *NutritionService) AddMealToDay(dto AddMealToDayDTO) (*models.DayMeal, e
 	return createdMeal, nil
 }
 
+// EnsureMenuDay возвращает день меню с номером dayNumber, создавая его при отсутствии
+func (s *NutritionService) EnsureMenuDay(menuID uint, dayNumber int) (*models.MenuDay, error) {
+	days, err := s.weeklyMenuRepo.FindDaysByMenuID(menuID)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range days {
+		if day.DayNumber == dayNumber {
+			return day, nil
+		}
+	}
+	return s.AddDayToWeeklyMenu(AddDayToMenuDTO{MenuID: menuID, DayNumber: dayNumber})
+}
+
+// MoveMealToDay переносит прием пищи в другой день того же меню.
+// Пустой mealType оставляет тип приема пищи прежним
+func (s *NutritionService) MoveMealToDay(mealID, dayID uint, mealType string) error {
+	meal, err := s.weeklyMenuRepo.FindMealByID(mealID)
+	if err != nil {
+		return i18n.WrapError(err, "error.meal.not_found")
+	}
+	from, err := s.weeklyMenuRepo.FindDayByID(meal.DayID)
+	if err != nil {
+		return err
+	}
+	to, err := s.weeklyMenuRepo.FindDayByID(dayID)
+	if err != nil {
+		return i18n.WrapError(err, "error.menu_day.not_found")
+	}
+	if from.MenuID != to.MenuID {
+		return i18n.NewError("error.meal.move")
+	}
+
+	meal.DayID = dayID
+	if mealType != "" {
+		meal.MealType = mealType
+	}
+	if err := s.weeklyMenuRepo.UpdateMeal(meal); err != nil {
+		return err
+	}
+
+	// Пересчитываем калории обоих дней и недели
+	for _, id := range []uint{from.ID, to.ID} {
+		if err := s.updateDayCalories(id); err != nil {
+			log.Printf("Warning: failed to update day calories: %v", err)
+		}
+	}
+	if err := s.updateMenuCalories(to.MenuID); err != nil {
+		log.Printf("Warning: failed to update menu calories: %v", err)
+	}
+	return nil
+}
+
 // GetFullWeeklyMenu - получить полное меню с днями и приемами пищи
 func (s *NutritionService) GetFullWeeklyMenu(menuID uint) (*models.WeeklyMenu, error) {
 	menu, err := s.weeklyMenuRepo.FindByID(menuID)
@@ -237,6 +430,32 @@ func (s *NutritionService) updateDayCalories(dayID uint) error {
 	return s.weeklyMenuRepo.UpdateDayCalories(dayID, totalCalories)
 }
 
+// RefreshMenusWithDish пересчитывает калории дней и меню, в которых есть блюдо.
+// Вызывается после изменения калорийности блюда; ошибки только логируются
+func (s *NutritionService) RefreshMenusWithDish(nutritionID uint) {
+	dayIDs, err := s.weeklyMenuRepo.FindDayIDsByNutritionID(nutritionID)
+	if err != nil {
+		log.Printf("Warning: failed to find menu days with dish %d: %v", nutritionID, err)
+		return
+	}
+
+	menuIDs := make(map[uint]bool)
+	for _, dayID := range dayIDs {
+		if err := s.updateDayCalories(dayID); err != nil {
+			log.Printf("Warning: failed to update day calories: %v", err)
+			continue
+		}
+		if day, err := s.weeklyMenuRepo.FindDayByID(dayID); err == nil {
+			menuIDs[day.MenuID] = true
+		}
+	}
+	for menuID := range menuIDs {
+		if err := s.updateMenuCalories(menuID); err != nil {
+			log.Printf("Warning: failed to update menu calories: %v", err)
+		}
+	}
+}
+
 // updateMenuCalories - обновить калории меню
 func (s *NutritionService) updateMenuCalories(menuID uint) error {
 	days, err := s.weeklyMenuRepo.FindDaysByMenuID(menuID)
@@ -259,7 +478,19 @@ func (s *NutritionService) DeleteWeeklyMenu(id uint) error {
 
 // DeleteDayFromMenu - удалить день из меню
 func (s *NutritionService) DeleteDayFromMenu(dayID uint) error {
-	return s.weeklyMenuRepo.DeleteDay(dayID)
+	day, err := s.weeklyMenuRepo.FindDayByID(dayID)
+	if err != nil {
+		return err
+	}
+	if err := s.weeklyMenuRepo.DeleteDay(dayID); err != nil {
+		return err
+	}
+
+	// Обновляем калории недели
+	if err := s.updateMenuCalories(day.MenuID); err != nil {
+		log.Printf("Warning: failed to update menu calories: %v", err)
+	}
+	return nil
 }
 
 // DeleteMealFromDay - удалить прием пищи из дня
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Пределы тренировочной программы
+const (
+	MaxProgramWeeks    = 12
+	maxDayExercises    = 20
+	maxExerciseSets    = 20
+	maxRestSeconds     = 600
+	maxPrescriptionLen = 20
+)
+
+// Темп: четыре фазы через дефис, цифры или X (взрывная фаза)
+var tempoPattern = regexp.MustCompile(`^[0-9xX]-[0-9xX]-[0-9xX]-[0-9xX]$`)
+
+// ProgramService - справочник упражнений и структура тренировочных программ:
+// недели, тренировочные дни и упражнения с подходами и отдыхом
+type ProgramService struct {
+	programRepo  repository.ProgramRepository
+	exerciseRepo repository.ExerciseRepository
+	trainingRepo repository.TrainingRepository
+}
+
+func NewProgramService(
+	programRepo repository.ProgramRepository,
+	exerciseRepo repository.ExerciseRepository,
+	trainingRepo repository.TrainingRepository,
+) *ProgramService {
+	return &ProgramService{
+		programRepo:  programRepo,
+		exerciseRepo: exerciseRepo,
+		trainingRepo: trainingRepo,
+	}
+}
+
+// ==================== УПРАЖНЕНИЯ ====================
+
+// ListExercises - все упражнения по алфавиту
+func (s *ProgramService) ListExercises() ([]*models.Exercise, error) {
+	return s.exerciseRepo.FindAll()
+}
+
+// GetExerciseByID - получить упражнение по ID
+func (s *ProgramService) GetExerciseByID(id uint) (*models.Exercise, error) {
+	return s.exerciseRepo.FindByID(id)
+}
+
+// FindExercise - упражнение по ID или точному названию без учета регистра
+func (s *ProgramService) FindExercise(ref string) (*models.Exercise, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.ParseUint(ref, 10, 64); err == nil {
+		return s.exerciseRepo.FindByID(uint(id))
+	}
+	return s.exerciseRepo.FindByName(ref)
+}
+
+// CreateExercise - добавить упражнение в справочник
+func (s *ProgramService) CreateExercise(dto ExerciseDTO) (*models.Exercise, error) {
+	dto = trimExercise(dto)
+	if err := validateExercise(dto); err != nil {
+		return nil, err
+	}
+	if err := s.checkExerciseName(dto.Name, 0); err != nil {
+		return nil, err
+	}
+
+	exercise := &models.Exercise{
+		Name:        dto.Name,
+		MuscleGroup: dto.MuscleGroup,
+		Equipment:   dto.Equipment,
+		Description: dto.Description,
+		YouTubeLink: dto.YouTubeLink,
+	}
+	return s.exerciseRepo.Create(exercise)
+}
+
+// UpdateExercise заменяет все поля упражнения
+func (s *ProgramService) UpdateExercise(id uint, dto ExerciseDTO) error {
+	exercise, err := s.exerciseRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	dto = trimExercise(dto)
+	if err := validateExercise(dto); err != nil {
+		return err
+	}
+	if err := s.checkExerciseName(dto.Name, id); err != nil {
+		return err
+	}
+
+	exercise.Name = dto.Name
+	exercise.MuscleGroup = dto.MuscleGroup
+	exercise.Equipment = dto.Equipment
+	exercise.Description = dto.Description
+	exercise.YouTubeLink = dto.YouTubeLink
+	return s.exerciseRepo.Update(exercise)
+}
+
+// DeleteExercise удаляет упражнение, если оно не стоит ни в одной программе
+func (s *ProgramService) DeleteExercise(id uint) error {
+	used, err := s.exerciseRepo.CountUsage(id)
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return i18n.NewError("error.exercise.in_use", used)
+	}
+	return s.exerciseRepo.Delete(id)
+}
+
+func trimExercise(dto ExerciseDTO) ExerciseDTO {
+	dto.Name = strings.TrimSpace(dto.Name)
+	dto.MuscleGroup = strings.TrimSpace(dto.MuscleGroup)
+	dto.Equipment = strings.TrimSpace(dto.Equipment)
+	dto.Description = strings.TrimSpace(dto.Description)
+	dto.YouTubeLink = strings.TrimSpace(dto.YouTubeLink)
+	return dto
+}
+
+func validateExercise(dto ExerciseDTO) error {
+	if dto.Name == "" {
+		return i18n.NewError("error.exercise.name_empty")
+	}
+	if len([]rune(dto.Name)) > 255 {
+		return i18n.NewError("error.exercise.name_long")
+	}
+	if len([]rune(dto.MuscleGroup)) > 100 || len([]rune(dto.Equipment)) > 100 {
+		return i18n.NewError("error.exercise.details_long")
+	}
+	if link := dto.YouTubeLink; link != "" && !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+		return i18n.NewError("error.exercise.video_link")
+	}
+	return nil
+}
+
+// checkExerciseName проверяет, что название не занято другим упражнением
+func (s *ProgramService) checkExerciseName(name string, selfID uint) error {
+	existing, err := s.exerciseRepo.FindByName(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != selfID {
+		return i18n.NewError("error.exercise.exists", existing.Name)
+	}
+	return nil
+}
+
+// ==================== ПРОГРАММЫ ====================
+
+// GetProgram - тренировка со всеми днями и упражнениями
+func (s *ProgramService) GetProgram(programID uint) (*models.TrainingProgram, error) {
+	program, err := s.trainingRepo.FindByID(programID)
+	if err != nil {
+		return nil, err
+	}
+	days, err := s.programRepo.FindDays(programID)
+	if err != nil {
+		return nil, err
+	}
+	program.Days = make([]models.ProgramDay, 0, len(days))
+	for _, day := range days {
+		program.Days = append(program.Days, *day)
+	}
+	return program, nil
+}
+
+// HasDays - расписаны ли в программе тренировочные дни
+func (s *ProgramService) HasDays(programID uint) (bool, error) {
+	count, err := s.programRepo.CountDays(programID)
+	return count > 0, err
+}
+
+// SetWeeks меняет длительность программы. Сократить ее можно,
+// только если в отрезаемых неделях нет дней
+func (s *ProgramService) SetWeeks(programID uint, weeks int) error {
+	if weeks < 1 || weeks > MaxProgramWeeks {
+		return i18n.NewError("error.program.weeks", MaxProgramWeeks)
+	}
+	program, err := s.GetProgram(programID)
+	if err != nil {
+		return err
+	}
+	for _, day := range program.Days {
+		if day.Week > weeks {
+			return i18n.NewError("error.program.week_has_days", day.Week)
+		}
+	}
+	program.Days = nil
+	program.Weeks = weeks
+	return s.trainingRepo.Update(program)
+}
+
+// GetDay - тренировочный день с упражнениями по порядку
+func (s *ProgramService) GetDay(dayID uint) (*models.ProgramDay, error) {
+	return s.programRepo.FindDayByID(dayID)
+}
+
+// AddDay добавляет тренировочный день; в одной неделе день недели не повторяется
+func (s *ProgramService) AddDay(dto ProgramDayDTO) (*models.ProgramDay, error) {
+	program, err := s.GetProgram(dto.ProgramID)
+	if err != nil {
+		return nil, i18n.NewError("error.training.not_found_plain")
+	}
+	if dto.Week < 1 || dto.Week > program.Weeks {
+		return nil, i18n.NewError("error.program.week_number", program.Weeks)
+	}
+	if dto.DayNumber < 1 || dto.DayNumber > 7 {
+		return nil, i18n.NewError("error.day.number")
+	}
+	dto.Title = strings.TrimSpace(dto.Title)
+	if len([]rune(dto.Title)) > 100 {
+		return nil, i18n.NewError("error.program.day_title")
+	}
+	for _, day := range program.Days {
+		if day.Week == dto.Week && day.DayNumber == dto.DayNumber {
+			return nil, i18n.NewError("error.program.day_exists", i18n.Message("weekday."+strconv.Itoa(dto.DayNumber)), dto.Week)
+		}
+	}
+
+	return s.programRepo.CreateDay(&models.ProgramDay{
+		ProgramID: dto.ProgramID,
+		Week:      dto.Week,
+		DayNumber: dto.DayNumber,
+		Title:     dto.Title,
+	})
+}
+
+// DeleteDay удаляет тренировочный день с его упражнениями
+func (s *ProgramService) DeleteDay(dayID uint) error {
+	return s.programRepo.DeleteDay(dayID)
+}
+
+// AddExercise добавляет упражнение в конец дня
+func (s *ProgramService) AddExercise(dto ProgramExerciseDTO) (*models.ProgramExercise, error) {
+	day, err := s.programRepo.FindDayByID(dto.DayID)
+	if err != nil {
+		return nil, i18n.NewError("error.program.day_not_found")
+	}
+	if len(day.Exercises) >= maxDayExercises {
+		return nil, i18n.NewError("error.program.too_many_exercises", maxDayExercises)
+	}
+	if _, err := s.exerciseRepo.FindByID(dto.ExerciseID); err != nil {
+		return nil, i18n.NewError("error.exercise.not_found")
+	}
+	item, err := newProgramExercise(dto)
+	if err != nil {
+		return nil, err
+	}
+	item.Position = len(day.Exercises) + 1
+	return s.programRepo.CreateExercise(item)
+}
+
+// GetProgramExercise - упражнение дня с предписанием
+func (s *ProgramService) GetProgramExercise(id uint) (*models.ProgramExercise, error) {
+	return s.programRepo.FindExerciseByID(id)
+}
+
+// RemoveExercise убирает упражнение из дня
+func (s *ProgramService) RemoveExercise(id uint) error {
+	return s.programRepo.DeleteExercise(id)
+}
+
+// RepeatWeek копирует дни недели week во все недели программы, где дней еще нет.
+// Возвращает, сколько недель заполнено
+func (s *ProgramService) RepeatWeek(programID uint, week int) (int, error) {
+	program, err := s.GetProgram(programID)
+	if err != nil {
+		return 0, err
+	}
+	var source []models.ProgramDay
+	filled := map[int]bool{}
+	for _, day := range program.Days {
+		filled[day.Week] = true
+		if day.Week == week {
+			source = append(source, day)
+		}
+	}
+	if len(source) == 0 {
+		return 0, i18n.NewError("error.program.week_empty", week)
+	}
+
+	copied := 0
+	for target := 1; target <= program.Weeks; target++ {
+		if filled[target] {
+			continue
+		}
+		for _, day := range source {
+			created, err := s.programRepo.CreateDay(&models.ProgramDay{
+				ProgramID: programID,
+				Week:      target,
+				DayNumber: day.DayNumber,
+				Title:     day.Title,
+			})
+			if err != nil {
+				return copied, err
+			}
+			for _, item := range day.Exercises {
+				clone := item
+				clone.Model = gorm.Model{}
+				clone.ProgramDayID = created.ID
+				if _, err := s.programRepo.CreateExercise(&clone); err != nil {
+					return copied, err
+				}
+			}
+		}
+		copied++
+	}
+	return copied, nil
+}
+
+// newProgramExercise проверяет предписание упражнения
+func newProgramExercise(dto ProgramExerciseDTO) (*models.ProgramExercise, error) {
+	dto.Reps = strings.TrimSpace(dto.Reps)
+	dto.Tempo = strings.ToUpper(strings.TrimSpace(dto.Tempo))
+	dto.Weight = strings.TrimSpace(dto.Weight)
+
+	if dto.Sets < 1 || dto.Sets > maxExerciseSets {
+		return nil, i18n.NewError("error.program.sets", maxExerciseSets)
+	}
+	if dto.Reps == "" || len([]rune(dto.Reps)) > maxPrescriptionLen {
+		return nil, i18n.NewError("error.program.reps")
+	}
+	if dto.RestSeconds < 0 || dto.RestSeconds > maxRestSeconds {
+		return nil, i18n.NewError("error.program.rest", maxRestSeconds)
+	}
+	if dto.Tempo != "" && !tempoPattern.MatchString(dto.Tempo) {
+		return nil, i18n.NewError("error.program.tempo")
+	}
+	if len([]rune(dto.Weight)) > 100 {
+		return nil, i18n.NewError("error.program.weight")
+	}
+
+	return &models.ProgramExercise{
+		ProgramDayID: dto.DayID,
+		ExerciseID:   dto.ExerciseID,
+		Sets:         dto.Sets,
+		Reps:         dto.Reps,
+		RestSeconds:  dto.RestSeconds,
+		Tempo:        dto.Tempo,
+		Weight:       dto.Weight,
+	}, nil
+}
+
+// ParseSetsReps разбирает запись «3x10», «4х8-12» или «3 x 30 сек» на подходы и повторы
+func ParseSetsReps(text string) (int, string, error) {
+	text = strings.TrimSpace(text)
+	for _, sep := range []string{"x", "х", "X", "Х", "*", "×"} {
+		sets, reps, found := strings.Cut(text, sep)
+		if !found {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(sets))
+		if err != nil {
+			break
+		}
+		return n, strings.TrimSpace(reps), nil
+	}
+	return 0, "", i18n.NewError("error.program.sets_reps")
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Пределы рецепта
+const (
+	maxRecipeItems = 50
+	maxItemGrams   = 5000
+)
+
+// RecipeService - ингредиенты и составы блюд. Если у блюда есть состав,
+// его КБЖУ считаются по ингредиентам и пересчитываются при каждом изменении
+type RecipeService struct {
+	ingredientRepo   repository.IngredientRepository
+	recipeRepo       repository.RecipeRepository
+	nutritionRepo    repository.NutritionRepository
+	nutritionService *NutritionService
+}
+
+func NewRecipeService(
+	ingredientRepo repository.IngredientRepository,
+	recipeRepo repository.RecipeRepository,
+	nutritionRepo repository.NutritionRepository,
+	nutritionService *NutritionService,
+) *RecipeService {
+	return &RecipeService{
+		ingredientRepo:   ingredientRepo,
+		recipeRepo:       recipeRepo,
+		nutritionRepo:    nutritionRepo,
+		nutritionService: nutritionService,
+	}
+}
+
+// ==================== ИНГРЕДИЕНТЫ ====================
+
+// ListIngredients - все ингредиенты по алфавиту
+func (s *RecipeService) ListIngredients() ([]*models.Ingredient, error) {
+	return s.ingredientRepo.FindAll()
+}
+
+// GetIngredientByID - получить ингредиент по ID
+func (s *RecipeService) GetIngredientByID(id uint) (*models.Ingredient, error) {
+	return s.ingredientRepo.FindByID(id)
+}
+
+// CreateIngredient - добавить ингредиент в справочник
+func (s *RecipeService) CreateIngredient(dto IngredientDTO) (*models.Ingredient, error) {
+	dto.Name = strings.TrimSpace(dto.Name)
+	if err := validateIngredient(dto); err != nil {
+		return nil, err
+	}
+	section, err := ValidateSection(dto.Section)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkIngredientName(dto.Name, 0); err != nil {
+		return nil, err
+	}
+
+	ingredient := &models.Ingredient{
+		Name:     dto.Name,
+		Section:  section,
+		Calories: dto.Calories,
+		Protein:  dto.Protein,
+		Carbs:    dto.Carbs,
+		Fats:     dto.Fats,
+	}
+	return s.ingredientRepo.Create(ingredient)
+}
+
+// UpdateIngredient заменяет название и пищевую ценность ингредиента
+// и пересчитывает все блюда, в которых он есть
+func (s *RecipeService) UpdateIngredient(id uint, dto IngredientDTO) error {
+	ingredient, err := s.ingredientRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	dto.Name = strings.TrimSpace(dto.Name)
+	if err := validateIngredient(dto); err != nil {
+		return err
+	}
+	section, err := ValidateSection(dto.Section)
+	if err != nil {
+		return err
+	}
+	if err := s.checkIngredientName(dto.Name, id); err != nil {
+		return err
+	}
+
+	ingredient.Name = dto.Name
+	ingredient.Section = section
+	ingredient.Calories = dto.Calories
+	ingredient.Protein = dto.Protein
+	ingredient.Carbs = dto.Carbs
+	ingredient.Fats = dto.Fats
+	if err := s.ingredientRepo.Update(ingredient); err != nil {
+		return err
+	}
+
+	nutritionIDs, err := s.recipeRepo.FindNutritionIDsByIngredient(id)
+	if err != nil {
+		return err
+	}
+	for _, nutritionID := range nutritionIDs {
+		if err := s.RecalculateDish(nutritionID); err != nil {
+			return i18n.WrapError(err, "error.recipe.recalculate", nutritionID)
+		}
+	}
+	return nil
+}
+
+// DeleteIngredient удаляет ингредиент, если он не входит ни в один рецепт
+func (s *RecipeService) DeleteIngredient(id uint) error {
+	nutritionIDs, err := s.recipeRepo.FindNutritionIDsByIngredient(id)
+	if err != nil {
+		return err
+	}
+	if len(nutritionIDs) > 0 {
+		return i18n.NewError("error.ingredient.in_use", len(nutritionIDs))
+	}
+	return s.ingredientRepo.Delete(id)
+}
+
+func validateIngredient(dto IngredientDTO) error {
+	if dto.Name == "" {
+		return i18n.NewError("error.ingredient.name_empty")
+	}
+	if len([]rune(dto.Name)) > 255 {
+		return i18n.NewError("error.ingredient.name_long")
+	}
+	if dto.Calories < 0 || dto.Protein < 0 || dto.Carbs < 0 || dto.Fats < 0 {
+		return i18n.NewError("error.macros.negative")
+	}
+	if dto.Calories > 900 {
+		return i18n.NewError("error.ingredient.calories")
+	}
+	if dto.Protein+dto.Carbs+dto.Fats > 100 {
+		return i18n.NewError("error.ingredient.macros")
+	}
+	return nil
+}
+
+// checkIngredientName проверяет, что название не занято другим ингредиентом
+func (s *RecipeService) checkIngredientName(name string, selfID uint) error {
+	existing, err := s.ingredientRepo.FindByName(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != selfID {
+		return i18n.NewError("error.ingredient.exists", existing.Name)
+	}
+	return nil
+}
+
+// ==================== РЕЦЕПТЫ ====================
+
+// GetRecipe - состав блюда с ингредиентами в порядке рецепта
+func (s *RecipeService) GetRecipe(nutritionID uint) ([]*models.RecipeItem, error) {
+	return s.recipeRepo.FindByNutritionID(nutritionID)
+}
+
+// SetRecipe заменяет состав и шаги приготовления блюда и пересчитывает его КБЖУ.
+// Если состав пуст, КБЖУ блюда остаются введенными вручную
+func (s *RecipeService) SetRecipe(nutritionID uint, dto SetRecipeDTO) (*models.NutritionPlan, error) {
+	plan, err := s.nutritionRepo.FindByID(nutritionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(dto.Items) > maxRecipeItems {
+		return nil, i18n.NewError("error.recipe.too_many", maxRecipeItems)
+	}
+
+	items := make([]*models.RecipeItem, 0, len(dto.Items))
+	seen := make(map[uint]bool, len(dto.Items))
+	for i, item := range dto.Items {
+		if item.Grams <= 0 || item.Grams > maxItemGrams {
+			return nil, i18n.NewError("error.recipe.grams", i+1, maxItemGrams)
+		}
+		ingredient, err := s.ingredientRepo.FindByID(item.IngredientID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, i18n.NewError("error.ingredient.not_found", item.IngredientID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if seen[ingredient.ID] {
+			return nil, i18n.NewError("error.recipe.twice", ingredient.Name)
+		}
+		seen[ingredient.ID] = true
+		items = append(items, &models.RecipeItem{
+			NutritionID:  nutritionID,
+			IngredientID: ingredient.ID,
+			Ingredient:   *ingredient,
+			Grams:        item.Grams,
+			Position:     i,
+		})
+	}
+
+	if err := s.recipeRepo.Replace(nutritionID, items); err != nil {
+		return nil, err
+	}
+
+	plan.Steps = normalizeSteps(dto.Steps)
+	if err := s.saveDish(plan, items); err != nil {
+		return nil, err
+	}
+	plan.RecipeItems = nil
+	for _, item := range items {
+		plan.RecipeItems = append(plan.RecipeItems, *item)
+	}
+	return plan, nil
+}
+
+// RecalculateDish пересчитывает КБЖУ блюда по его составу.
+// Блюда без состава не меняются
+func (s *RecipeService) RecalculateDish(nutritionID uint) error {
+	items, err := s.recipeRepo.FindByNutritionID(nutritionID)
+	if err != nil || len(items) == 0 {
+		return err
+	}
+	plan, err := s.nutritionRepo.FindByID(nutritionID)
+	if err != nil {
+		return err
+	}
+	return s.saveDish(plan, items)
+}
+
+// saveDish записывает блюдо с КБЖУ по составу items и обновляет
+// калории меню, если калорийность изменилась
+func (s *RecipeService) saveDish(plan *models.NutritionPlan, items []*models.RecipeItem) error {
+	oldCalories := plan.Calories
+	if len(items) > 0 {
+		totals := RecipeTotals(items)
+		plan.Calories = totals.Calories
+		plan.Protein = totals.Protein
+		plan.Carbs = totals.Carbs
+		plan.Fats = totals.Fats
+	}
+	if err := s.nutritionRepo.Update(plan); err != nil {
+		return err
+	}
+	if plan.Calories != oldCalories {
+		s.nutritionService.RefreshMenusWithDish(plan.ID)
+	}
+	return nil
+}
+
+// RecipeTotals - КБЖУ порции по составу: пищевая ценность на 100 г × граммы / 100
+func RecipeTotals(items []*models.RecipeItem) Macros {
+	var calories, protein, carbs, fats float64
+	for _, item := range items {
+		factor := item.Grams / 100
+		calories += item.Ingredient.Calories * factor
+		protein += item.Ingredient.Protein * factor
+		carbs += item.Ingredient.Carbs * factor
+		fats += item.Ingredient.Fats * factor
+	}
+	return Macros{
+		Calories: int(math.Round(calories)),
+		Protein:  roundTenth(protein),
+		Carbs:    roundTenth(carbs),
+		Fats:     roundTenth(fats),
+	}
+}
+
+// ItemCalories - калории одного ингредиента в рецепте
+func ItemCalories(item *models.RecipeItem) int {
+	return int(math.Round(item.Ingredient.Calories * item.Grams / 100))
+}
+
+// RecipeSteps - шаги приготовления по строкам без пустых
+func RecipeSteps(steps string) []string {
+	var lines []string
+	for _, line := range strings.Split(steps, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// stepNumber - номер шага, набранный вручную: «1.», «2)»
+var stepNumber = regexp.MustCompile(`^\d+[.)]\s*`)
+
+// normalizeSteps убирает пустые строки, лишние пробелы и ручную нумерацию:
+// шаги нумеруются при показе
+func normalizeSteps(steps string) string {
+	lines := RecipeSteps(steps)
+	for i, line := range lines {
+		lines[i] = stepNumber.ReplaceAllString(line, "")
+	}
+	return strings.Join(lines, "\n")
+}
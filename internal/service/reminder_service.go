@@ -0,0 +1,346 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Виды напоминаний
+const (
+	ReminderMeal    = "meal"
+	ReminderWorkout = "workout"
+	ReminderDiary   = "diary" // Итог дня по дневнику питания
+)
+
+// DefaultDiaryTime - время итога дня, когда он включен
+const DefaultDiaryTime = "21:00"
+
+// За сколько до события присылать напоминание
+const (
+	MealReminderLead    = 15 * time.Minute
+	WorkoutReminderLead = 30 * time.Minute
+)
+
+// ReminderWindow - сколько после положенного момента напоминание еще можно отправить.
+// Покрывает пропущенные тики и перезапуск бота
+const ReminderWindow = 10 * time.Minute
+
+// Reminder - напоминание, готовое к отправке
+type Reminder struct {
+	UserID     uint
+	TelegramID int64
+	Kind       string
+	Key        string          // Уникален для пользователя, защищает от повторной отправки
+	Meal       *models.DayMeal // Прием пищи для ReminderMeal
+	Time       string          // Время тренировки для ReminderWorkout и итога дня для ReminderDiary
+}
+
+type ReminderService struct {
+	repo             repository.ReminderRepository
+	userRepo         repository.UserRepository
+	nutritionService *NutritionService
+	menuService      *MenuAssignmentService
+	defaultTimezone  string
+}
+
+func NewReminderService(
+	repo repository.ReminderRepository,
+	userRepo repository.UserRepository,
+	nutritionService *NutritionService,
+	menuService *MenuAssignmentService,
+	defaultTimezone string,
+) *ReminderService {
+	if _, err := time.LoadLocation(defaultTimezone); err != nil || defaultTimezone == "" {
+		log.Printf("[ReminderService] unknown timezone %q, falling back to UTC", defaultTimezone)
+		defaultTimezone = "UTC"
+	}
+	return &ReminderService{
+		repo:             repo,
+		userRepo:         userRepo,
+		nutritionService: nutritionService,
+		menuService:      menuService,
+		defaultTimezone:  defaultTimezone,
+	}
+}
+
+// DefaultReminderSettings - настройки пользователя, который их еще не менял:
+// напоминания о еде и итог дня включены, тренировки не запланированы, тихие часы 23:00-08:00
+func DefaultReminderSettings(userID uint, timezone string) *models.ReminderSettings {
+	return &models.ReminderSettings{
+		UserID:          userID,
+		Timezone:        timezone,
+		MealsEnabled:    true,
+		WorkoutsEnabled: true,
+		WorkoutTime:     "18:00",
+		DiaryTime:       DefaultDiaryTime,
+		QuietFrom:       "23:00",
+		QuietTo:         "08:00",
+	}
+}
+
+// GetSettings - настройки напоминаний пользователя (или настройки по умолчанию)
+func (s *ReminderService) GetSettings(userID uint) (*models.ReminderSettings, error) {
+	settings, err := s.repo.FindSettings(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return DefaultReminderSettings(userID, s.defaultTimezone), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// Location - часовой пояс пользователя из настроек напоминаний. По нему же считаются
+// границы дня и недели, чтобы они совпадали с напоминаниями
+func (s *ReminderService) Location(userID uint) *time.Location {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		log.Printf("[ReminderService] settings for user %d: %v", userID, err)
+		settings = DefaultReminderSettings(userID, s.defaultTimezone)
+	}
+	return s.location(settings)
+}
+
+// location - часовой пояс настроек, при ошибке - пояс по умолчанию
+func (s *ReminderService) location(settings *models.ReminderSettings) *time.Location {
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		loc, _ = time.LoadLocation(s.defaultTimezone)
+	}
+	return loc
+}
+
+// SaveSettings - проверить и сохранить настройки напоминаний
+func (s *ReminderService) SaveSettings(settings *models.ReminderSettings) error {
+	if settings.UserID == 0 {
+		return i18n.NewError("error.user.invalid")
+	}
+	if _, err := time.LoadLocation(settings.Timezone); err != nil || settings.Timezone == "" {
+		return i18n.NewError("error.reminders.timezone", settings.Timezone)
+	}
+	if _, ok := ParseClock(settings.WorkoutTime); !ok {
+		return i18n.NewError("error.reminders.workout_time")
+	}
+	if _, ok := ParseClock(settings.DiaryTime); settings.DiaryTime != "" && !ok {
+		return i18n.NewError("error.reminders.diary_time")
+	}
+	if (settings.QuietFrom == "") != (settings.QuietTo == "") {
+		return i18n.NewError("error.reminders.quiet_pair")
+	}
+	if settings.QuietFrom != "" {
+		_, okFrom := ParseClock(settings.QuietFrom)
+		_, okTo := ParseClock(settings.QuietTo)
+		if !okFrom || !okTo {
+			return i18n.NewError("error.reminders.quiet_format")
+		}
+	}
+	settings.WorkoutDays &= 0x7f
+	return s.repo.SaveSettings(settings)
+}
+
+// DueReminders - напоминания, которые пора отправить в момент now.
+// Каждое напоминание отмечается отправленным, поэтому повторный вызов его уже не вернет;
+// если доставить его не удалось, отметку снимает ReleaseReminder
+func (s *ReminderService) DueReminders(now time.Time) ([]Reminder, error) {
+	users, err := s.userRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	settingsList, err := s.repo.FindAllSettings()
+	if err != nil {
+		return nil, err
+	}
+	settingsByUser := make(map[uint]*models.ReminderSettings, len(settingsList))
+	for _, settings := range settingsList {
+		settingsByUser[settings.UserID] = settings
+	}
+	assigned, err := s.menuService.CurrentMenuIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	// Меню загружаются один раз за вызов. Меню здесь не подбираются: без назначения
+	// или с удаленным меню действует общее активное, а если меню нет - напоминаем
+	// только о тренировках
+	fullMenus := make(map[uint]*models.WeeklyMenu)
+	loadMenu := func(menuID uint) *models.WeeklyMenu {
+		full, loaded := fullMenus[menuID]
+		if !loaded {
+			if full, err = s.nutritionService.GetFullWeeklyMenu(menuID); err != nil {
+				full = nil
+			}
+			fullMenus[menuID] = full
+		}
+		return full
+	}
+	var activeID uint
+	activeLoaded := false
+	menuFor := func(user *models.User) *models.WeeklyMenu {
+		if menuID := assigned[user.ID]; menuID != 0 {
+			if menu := loadMenu(menuID); menu != nil {
+				return menu
+			}
+		}
+		if !activeLoaded {
+			activeLoaded = true
+			if active, err := s.nutritionService.GetActiveWeeklyMenu(); err == nil {
+				activeID = active.ID
+			}
+		}
+		if activeID == 0 {
+			return nil
+		}
+		return loadMenu(activeID)
+	}
+
+	var due []Reminder
+	for _, user := range users {
+		settings, ok := settingsByUser[user.ID]
+		if !ok {
+			settings = DefaultReminderSettings(user.ID, s.defaultTimezone)
+		}
+		loc := s.location(settings)
+
+		var menu *models.WeeklyMenu
+		if settings.MealsEnabled {
+			menu = menuFor(user)
+		}
+		for _, reminder := range PlanReminders(settings, menu, now.In(loc)) {
+			sent, err := s.repo.MarkSent(user.ID, reminder.Key, now)
+			if err != nil {
+				log.Printf("[ReminderService] mark sent %s for user %d: %v", reminder.Key, user.ID, err)
+				continue
+			}
+			if !sent {
+				continue
+			}
+			reminder.UserID = user.ID
+			reminder.TelegramID = user.TelegramID
+			due = append(due, reminder)
+		}
+	}
+	return due, nil
+}
+
+// ReleaseReminder снимает отметку с напоминания, которое не удалось доставить,
+// чтобы следующий вызов DueReminders вернул его снова, пока не истекло окно отправки
+func (s *ReminderService) ReleaseReminder(reminder Reminder) error {
+	return s.repo.UnmarkSent(reminder.UserID, reminder.Key)
+}
+
+// PurgeSent удаляет отметки об отправке старше before
+func (s *ReminderService) PurgeSent(before time.Time) (int64, error) {
+	return s.repo.DeleteSentBefore(before)
+}
+
+// PlanReminders - напоминания пользователя, срок которых наступил к моменту local
+// (время уже в часовом поясе пользователя). Не обращается к БД и часам
+func PlanReminders(settings *models.ReminderSettings, menu *models.WeeklyMenu, local time.Time) []Reminder {
+	if InQuietHours(settings, local) {
+		return nil
+	}
+
+	date := local.Format("2006-01-02")
+	weekday := Weekday(local)
+	var reminders []Reminder
+
+	if settings.MealsEnabled && menu != nil {
+		for _, day := range menu.Days {
+			if day.DayNumber != weekday {
+				continue
+			}
+			for _, meal := range day.Meals {
+				at, ok := atClock(local, meal.MealTime)
+				if !ok || !isDue(at.Add(-MealReminderLead), local) {
+					continue
+				}
+				reminders = append(reminders, Reminder{
+					Kind: ReminderMeal,
+					Key:  fmt.Sprintf("%s:%s:%d", ReminderMeal, date, meal.ID),
+					Meal: &meal,
+				})
+			}
+		}
+	}
+
+	if settings.WorkoutsEnabled && HasWorkoutDay(settings.WorkoutDays, weekday) {
+		if at, ok := atClock(local, settings.WorkoutTime); ok && isDue(at.Add(-WorkoutReminderLead), local) {
+			reminders = append(reminders, Reminder{
+				Kind: ReminderWorkout,
+				Key:  fmt.Sprintf("%s:%s", ReminderWorkout, date),
+				Time: settings.WorkoutTime,
+			})
+		}
+	}
+
+	if at, ok := atClock(local, settings.DiaryTime); ok && isDue(at, local) {
+		reminders = append(reminders, Reminder{
+			Kind: ReminderDiary,
+			Key:  fmt.Sprintf("%s:%s", ReminderDiary, date),
+			Time: settings.DiaryTime,
+		})
+	}
+	return reminders
+}
+
+// InQuietHours - попадает ли local в тихие часы. Интервал может переходить через полночь
+func InQuietHours(settings *models.ReminderSettings, local time.Time) bool {
+	from, okFrom := ParseClock(settings.QuietFrom)
+	to, okTo := ParseClock(settings.QuietTo)
+	if !okFrom || !okTo || from == to {
+		return false
+	}
+	current := local.Hour()*60 + local.Minute()
+	if from < to {
+		return current >= from && current < to
+	}
+	return current >= from || current < to
+}
+
+// Weekday - номер дня недели от 1 (понедельник) до 7 (воскресенье), как MenuDay.DayNumber
+func Weekday(t time.Time) int {
+	return (int(t.Weekday())+6)%7 + 1
+}
+
+// HasWorkoutDay - отмечен ли день недели (1-7) в маске ReminderSettings.WorkoutDays
+func HasWorkoutDay(mask, weekday int) bool {
+	return weekday >= 1 && weekday <= 7 && mask&(1<<(weekday-1)) != 0
+}
+
+// ParseClock разбирает время "ЧЧ:ММ" в минуты от начала суток
+func ParseClock(value string) (int, bool) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, false
+	}
+	hours, errH := strconv.Atoi(parts[0])
+	minutes, errM := strconv.Atoi(parts[1])
+	if errH != nil || errM != nil || hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+		return 0, false
+	}
+	return hours*60 + minutes, true
+}
+
+// atClock - момент в тот же день, что и local, во время value
+func atClock(local time.Time, value string) (time.Time, bool) {
+	minutes, ok := ParseClock(value)
+	if !ok {
+		return time.Time{}, false
+	}
+	year, month, day := local.Date()
+	return time.Date(year, month, day, minutes/60, minutes%60, 0, 0, local.Location()), true
+}
+
+// isDue - наступил ли момент fireAt и не истекло ли окно отправки
+func isDue(fireAt, now time.Time) bool {
+	return !now.Before(fireAt) && now.Before(fireAt.Add(ReminderWindow))
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+)
+
+// at - момент 20.05.2024 (понедельник) в часовом поясе loc
+func at(loc *time.Location, hour, minute int) time.Time {
+	return time.Date(2024, 5, 20, hour, minute, 0, 0, loc)
+}
+
+func TestInQuietHours(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		hour     int
+		minute   int
+		want     bool
+	}{
+		{"через полночь: начало", "23:00", "08:00", 23, 0, true},
+		{"через полночь: до начала", "23:00", "08:00", 22, 59, false},
+		{"через полночь: после полуночи", "23:00", "08:00", 0, 30, true},
+		{"через полночь: последняя минута", "23:00", "08:00", 7, 59, true},
+		{"через полночь: конец", "23:00", "08:00", 8, 0, false},
+		{"днем: внутри", "13:00", "15:00", 14, 0, true},
+		{"днем: конец", "13:00", "15:00", 15, 0, false},
+		{"днем: до начала", "13:00", "15:00", 12, 59, false},
+		{"без тихих часов", "", "", 3, 0, false},
+		{"начало равно концу", "10:00", "10:00", 10, 0, false},
+		{"неверный формат", "25:00", "08:00", 3, 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := &models.ReminderSettings{QuietFrom: tc.from, QuietTo: tc.to}
+			if got := InQuietHours(settings, at(time.UTC, tc.hour, tc.minute)); got != tc.want {
+				t.Errorf("InQuietHours(%s-%s, %02d:%02d) = %v, want %v", tc.from, tc.to, tc.hour, tc.minute, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsDue(t *testing.T) {
+	fireAt := at(time.UTC, 18, 0)
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"до срока", fireAt.Add(-time.Second), false},
+		{"ровно в срок", fireAt, true},
+		{"внутри окна", fireAt.Add(5 * time.Minute), true},
+		{"последняя секунда окна", fireAt.Add(ReminderWindow - time.Second), true},
+		{"окно истекло", fireAt.Add(ReminderWindow), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isDue(fireAt, tc.now); got != tc.want {
+				t.Errorf("isDue(%s, %s) = %v, want %v", fireAt.Format("15:04:05"), tc.now.Format("15:04:05"), got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPlanReminders(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skipf("нет базы часовых поясов: %v", err)
+	}
+	breakfast := models.DayMeal{MealType: "Завтрак", MealTime: "09:00"}
+	breakfast.ID = 11
+	dinner := models.DayMeal{MealType: "Ужин", MealTime: "19:00"}
+	dinner.ID = 12
+	menu := &models.WeeklyMenu{Days: []models.MenuDay{
+		{DayNumber: 1, Meals: []models.DayMeal{breakfast, dinner}},
+		{DayNumber: 2, Meals: []models.DayMeal{breakfast}},
+	}}
+	settings := func(change func(s *models.ReminderSettings)) *models.ReminderSettings {
+		s := DefaultReminderSettings(1, "Europe/Moscow")
+		s.WorkoutDays = 1 // Понедельник
+		if change != nil {
+			change(s)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		settings *models.ReminderSettings
+		menu     *models.WeeklyMenu
+		local    time.Time
+		want     []string
+	}{
+		{"завтрак за 15 минут", settings(nil), menu, at(moscow, 8, 45), []string{"meal:2024-05-20:11"}},
+		{"завтрак еще рано", settings(nil), menu, at(moscow, 8, 44), nil},
+		{"после перезапуска внутри окна", settings(nil), menu, at(moscow, 8, 54), []string{"meal:2024-05-20:11"}},
+		{"окно истекло", settings(nil), menu, at(moscow, 8, 55), nil},
+		{"тренировка за 30 минут", settings(nil), menu, at(moscow, 17, 30), []string{"workout:2024-05-20"}},
+		{"тренировка не в этот день", settings(func(s *models.ReminderSettings) { s.WorkoutDays = 2 }), menu, at(moscow, 17, 30), nil},
+		{"напоминания о еде выключены", settings(func(s *models.ReminderSettings) { s.MealsEnabled = false }), menu, at(moscow, 8, 45), nil},
+		{"без меню", settings(nil), nil, at(moscow, 8, 45), nil},
+		{"тихие часы через полночь", settings(func(s *models.ReminderSettings) {
+			s.WorkoutTime = "23:40"
+			s.QuietFrom, s.QuietTo = "23:00", "07:00"
+		}), menu, at(moscow, 23, 10), nil},
+		{"тихие часы закончились", settings(func(s *models.ReminderSettings) {
+			s.QuietFrom, s.QuietTo = "23:00", "08:50"
+		}), menu, at(moscow, 8, 50), []string{"meal:2024-05-20:11"}},
+		{"одновременно еда и тренировка", settings(func(s *models.ReminderSettings) { s.WorkoutTime = "19:15" }), menu,
+			at(moscow, 18, 45), []string{"meal:2024-05-20:12", "workout:2024-05-20"}},
+		{"итог дня", settings(nil), menu, at(moscow, 21, 0), []string{"diary:2024-05-20"}},
+		{"итог дня еще рано", settings(nil), menu, at(moscow, 20, 59), nil},
+		{"итог дня выключен", settings(func(s *models.ReminderSettings) { s.DiaryTime = "" }), menu, at(moscow, 21, 0), nil},
+		{"итог дня в тихие часы", settings(func(s *models.ReminderSettings) {
+			s.QuietFrom, s.QuietTo = "20:00", "08:00"
+		}), menu, at(moscow, 21, 5), nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, reminder := range PlanReminders(tc.settings, tc.menu, tc.local) {
+				got = append(got, reminder.Key)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("PlanReminders = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("PlanReminders = %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
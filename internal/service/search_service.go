@@ -0,0 +1,175 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+)
+
+// Пределы поискового запроса
+const (
+	MinSearchQuery  = 2
+	maxSearchQuery  = 100
+	maxSearchLimit  = 50
+	maxSearchOffset = 200 // Дальше листать выдачу бессмысленно
+)
+
+// SearchService - поиск по тренировкам и блюдам с учетом морфологии и опечаток
+type SearchService struct {
+	repo          repository.SearchRepository
+	trainingRepo  repository.TrainingRepository
+	nutritionRepo repository.NutritionRepository
+}
+
+func NewSearchService(
+	repo repository.SearchRepository,
+	trainingRepo repository.TrainingRepository,
+	nutritionRepo repository.NutritionRepository,
+) *SearchService {
+	return &SearchService{
+		repo:          repo,
+		trainingRepo:  trainingRepo,
+		nutritionRepo: nutritionRepo,
+	}
+}
+
+// NormalizeSearchQuery убирает лишние пробелы и проверяет длину запроса
+func NormalizeSearchQuery(query string) (string, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	switch n := len([]rune(query)); {
+	case n < MinSearchQuery:
+		return "", i18n.NewError("error.search.short", MinSearchQuery)
+	case n > maxSearchQuery:
+		return "", i18n.NewError("error.search.long", maxSearchQuery)
+	}
+	return query, nil
+}
+
+// Search ищет тренировки и блюда и возвращает до limit результатов по убыванию ранга.
+// Блюда, не подходящие под filter, в выдачу не попадают
+func (s *SearchService) Search(query string, filter models.DishFilter, limit int) ([]models.SearchHit, error) {
+	hits, _, err := s.SearchPage(query, filter, 0, limit)
+	return hits, err
+}
+
+// SearchPage - страница выдачи, начиная с offset; more - есть ли результаты дальше
+func (s *SearchService) SearchPage(query string, filter models.DishFilter, offset, limit int) ([]models.SearchHit, bool, error) {
+	query, err := NormalizeSearchQuery(query)
+	if err != nil {
+		return nil, false, err
+	}
+	offset, limit = searchWindow(offset, limit)
+
+	// Тренировки и блюда ранжируются вместе, поэтому из каждой таблицы
+	// нужны все результаты до конца страницы и один сверх нее
+	want := offset + limit + 1
+	trainings, err := s.repo.SearchTrainings(query, want)
+	if err != nil {
+		return nil, false, err
+	}
+	dishes, err := s.repo.SearchNutrition(query, filter, want)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hits := append(trainings, dishes...)
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Rank > hits[j].Rank
+	})
+	page, more := pageOf(hits, offset, limit)
+	return page, more, nil
+}
+
+// Browse - весь каталог по алфавиту: тренировки, затем подходящие под filter блюда.
+// Показывается, пока запрос пустой
+func (s *SearchService) Browse(filter models.DishFilter, offset, limit int) ([]models.SearchHit, bool, error) {
+	offset, limit = searchWindow(offset, limit)
+
+	trainings, err := s.trainingRepo.FindAll()
+	if err != nil {
+		return nil, false, err
+	}
+	dishes, err := s.nutritionRepo.FindAll()
+	if err != nil {
+		return nil, false, err
+	}
+
+	hits := make([]models.SearchHit, 0, len(trainings)+len(dishes))
+	for _, t := range trainings {
+		hits = append(hits, TrainingHit(t))
+	}
+	for _, d := range dishes {
+		if filter.Matches(d) {
+			hits = append(hits, DishHit(d))
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Kind != hits[j].Kind {
+			return hits[i].Kind == models.SearchTraining
+		}
+		return strings.ToLower(hits[i].Title) < strings.ToLower(hits[j].Title)
+	})
+	page, more := pageOf(hits, offset, limit)
+	return page, more, nil
+}
+
+// TrainingHit - тренировка в виде результата поиска
+func TrainingHit(t *models.TrainingProgram) models.SearchHit {
+	return models.SearchHit{
+		Kind:        models.SearchTraining,
+		ID:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		Category:    t.Category.Name,
+		Duration:    t.Duration,
+		Difficulty:  t.Difficulty,
+		YouTubeLink: t.YouTubeLink,
+		Weeks:       t.Weeks,
+	}
+}
+
+// DishHit - блюдо в виде результата поиска
+func DishHit(p *models.NutritionPlan) models.SearchHit {
+	return models.SearchHit{
+		Kind:        models.SearchNutrition,
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		Category:    p.Category.Name,
+		Calories:    p.Calories,
+		Protein:     p.Protein,
+		Carbs:       p.Carbs,
+		Fats:        p.Fats,
+		Diets:       p.Diets,
+		Allergens:   p.Allergens,
+	}
+}
+
+// searchWindow приводит offset и limit к допустимым значениям
+func searchWindow(offset, limit int) (int, int) {
+	if limit <= 0 || limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > maxSearchOffset {
+		offset = maxSearchOffset
+	}
+	return offset, limit
+}
+
+// pageOf вырезает страницу из отсортированной выдачи
+func pageOf(hits []models.SearchHit, offset, limit int) ([]models.SearchHit, bool) {
+	if offset >= len(hits) {
+		return nil, false
+	}
+	hits = hits[offset:]
+	if len(hits) > limit {
+		return hits[:limit], offset+limit < maxSearchOffset
+	}
+	return hits, false
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+	"gorm.io/gorm"
+)
+
+// StoreSections - ключи отделов магазина в порядке обхода; название отдела -
+// сообщение section.<ключ> каталога. В этом порядке группируется список покупок;
+// ингредиент без отдела попадает в DefaultSection
+var StoreSections = []string{
+	"produce",
+	"meat_fish",
+	"dairy_eggs",
+	"bakery",
+	"grains",
+	"grocery",
+	"nuts_dried_fruit",
+	"frozen",
+	"drinks",
+	DefaultSection,
+}
+
+const (
+	DefaultSection = "other"
+	// ReadyDishesSection - блюда без состава: их покупают или готовят целиком
+	ReadyDishesSection = "ready_dishes"
+
+	// AllMenuDays - маска всех семи дней недели
+	AllMenuDays = 1<<7 - 1
+	MaxServings = 20
+
+	UnitGrams    = "г"
+	UnitPortions = "порц."
+)
+
+// ShoppingService собирает список покупок по недельному меню
+// и хранит отметки о купленном для каждого пользователя
+type ShoppingService struct {
+	repo             repository.ShoppingListRepository
+	recipeRepo       repository.RecipeRepository
+	nutritionService *NutritionService
+}
+
+func NewShoppingService(
+	repo repository.ShoppingListRepository,
+	recipeRepo repository.RecipeRepository,
+	nutritionService *NutritionService,
+) *ShoppingService {
+	return &ShoppingService{
+		repo:             repo,
+		recipeRepo:       recipeRepo,
+		nutritionService: nutritionService,
+	}
+}
+
+// GetList - сохраненный список пользователя, nil - списка еще нет
+func (s *ShoppingService) GetList(userID uint) (*models.ShoppingList, error) {
+	list, err := s.repo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// BuildList собирает список пользователя заново по меню menuID за дни days
+// (маска, бит 0 - понедельник) на servings порций и сохраняет его.
+// Отметки о купленном переносятся на позиции, которые остались в списке
+func (s *ShoppingService) BuildList(userID, menuID uint, days, servings int) (*models.ShoppingList, error) {
+	days &= AllMenuDays
+	if days == 0 {
+		return nil, i18n.NewError("error.shopping.no_days")
+	}
+	if servings < 1 || servings > MaxServings {
+		return nil, i18n.NewError("error.shopping.servings", MaxServings)
+	}
+
+	menu, err := s.nutritionService.GetFullWeeklyMenu(menuID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.aggregate(menu, days, servings)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.GetList(userID)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		checked := make(map[string]bool)
+		for _, item := range previous.Items {
+			if item.Checked {
+				checked[shoppingKey(item)] = true
+			}
+		}
+		for i := range items {
+			items[i].Checked = checked[shoppingKey(items[i])]
+		}
+	}
+
+	list := &models.ShoppingList{
+		UserID:   userID,
+		MenuID:   menuID,
+		Days:     days,
+		Servings: servings,
+		Items:    items,
+	}
+	if err := s.repo.Save(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ToggleItem отмечает позицию купленной или снимает отметку
+func (s *ShoppingService) ToggleItem(userID, itemID uint) (*models.ShoppingList, error) {
+	list, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		if list.Items[i].ID != itemID {
+			continue
+		}
+		item := &list.Items[i]
+		if err := s.repo.SetChecked(userID, item.ID, !item.Checked); err != nil {
+			return nil, err
+		}
+		item.Checked = !item.Checked
+		return list, nil
+	}
+	return nil, i18n.NewError("error.shopping.item_not_found")
+}
+
+// UncheckAll снимает все отметки о купленном
+func (s *ShoppingService) UncheckAll(userID uint) (*models.ShoppingList, error) {
+	if err := s.repo.UncheckAll(userID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByUserID(userID)
+}
+
+// aggregate суммирует продукты всех приемов пищи выбранных дней.
+// Блюдо с составом раскладывается на ингредиенты, блюдо без состава
+// остается в списке целиком, в порциях
+func (s *ShoppingService) aggregate(menu *models.WeeklyMenu, days, servings int) ([]models.ShoppingItem, error) {
+	// Сколько раз каждое блюдо встречается в выбранных днях
+	counts := make(map[uint]int)
+	titles := make(map[uint]string)
+	var dishIDs []uint
+	for _, day := range menu.Days {
+		if day.DayNumber < 1 || day.DayNumber > 7 || days&(1<<(day.DayNumber-1)) == 0 {
+			continue
+		}
+		for _, meal := range day.Meals {
+			id := meal.Nutrition.ID
+			if id == 0 {
+				continue
+			}
+			if counts[id] == 0 {
+				dishIDs = append(dishIDs, id)
+				titles[id] = meal.Nutrition.Title
+			}
+			counts[id]++
+		}
+	}
+
+	recipeItems, err := s.recipeRepo.FindByNutritionIDs(dishIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*models.ShoppingItem
+	byIngredient := make(map[uint]*models.ShoppingItem)
+	withRecipe := make(map[uint]bool)
+	for _, recipeItem := range recipeItems {
+		withRecipe[recipeItem.NutritionID] = true
+		item := byIngredient[recipeItem.IngredientID]
+		if item == nil {
+			item = &models.ShoppingItem{
+				IngredientID: recipeItem.IngredientID,
+				Name:         recipeItem.Ingredient.Name,
+				Section:      sectionOrDefault(recipeItem.Ingredient.Section),
+				Unit:         UnitGrams,
+			}
+			byIngredient[recipeItem.IngredientID] = item
+			items = append(items, item)
+		}
+		item.Amount += recipeItem.Grams * float64(counts[recipeItem.NutritionID]*servings)
+	}
+	for _, id := range dishIDs {
+		if withRecipe[id] {
+			continue
+		}
+		items = append(items, &models.ShoppingItem{
+			NutritionID: id,
+			Name:        titles[id],
+			Section:     ReadyDishesSection,
+			Amount:      float64(counts[id] * servings),
+			Unit:        UnitPortions,
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		ri, rj := sectionRank(items[i].Section), sectionRank(items[j].Section)
+		if ri != rj {
+			return ri < rj
+		}
+		return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
+	})
+
+	result := make([]models.ShoppingItem, len(items))
+	for i, item := range items {
+		item.Position = i
+		result[i] = *item
+	}
+	return result, nil
+}
+
+// ValidateSection проверяет отдел магазина и возвращает его ключ. Отдел можно указать
+// ключом или названием на основном языке; пустой отдел - DefaultSection
+func ValidateSection(section string) (string, error) {
+	section = sectionOrDefault(section)
+	for _, known := range StoreSections {
+		if section == known {
+			return section, nil
+		}
+	}
+	if key, ok := StoreSectionKeys(i18n.Default())[section]; ok && key != ReadyDishesSection {
+		return key, nil
+	}
+	return "", i18n.NewError("error.shopping.section", strings.Join(StoreSections, ", "))
+}
+
+// SectionName - название отдела на языке tr; неизвестный отдел выводится как есть
+func SectionName(tr *i18n.Localizer, section string) string {
+	if section == ReadyDishesSection || sectionRank(section) < len(StoreSections) {
+		return tr.T("section." + section)
+	}
+	return section
+}
+
+// StoreSectionKeys - ключи отделов по их названиям на языке tr, включая ReadyDishesSection
+func StoreSectionKeys(tr *i18n.Localizer) map[string]string {
+	keys := make(map[string]string, len(StoreSections)+1)
+	for _, key := range append(StoreSections[:len(StoreSections):len(StoreSections)], ReadyDishesSection) {
+		keys[tr.T("section."+key)] = key
+	}
+	return keys
+}
+
+func sectionOrDefault(section string) string {
+	if section = strings.TrimSpace(section); section == "" {
+		return DefaultSection
+	}
+	return section
+}
+
+// sectionRank - место отдела в порядке обхода магазина.
+// Блюда без состава идут в конце списка
+func sectionRank(section string) int {
+	for i, known := range StoreSections {
+		if section == known {
+			return i
+		}
+	}
+	return len(StoreSections)
+}
+
+// shoppingKey - позиция, которая остается той же при пересборке списка
+func shoppingKey(item models.ShoppingItem) string {
+	if item.IngredientID != 0 {
+		return fmt.Sprintf("i%d", item.IngredientID)
+	}
+	return fmt.Sprintf("n%d", item.NutritionID)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"math"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+)
+
+// Уровни активности и их коэффициенты для расчета TDEE
+var ActivityFactors = map[string]float64{
+	"sedentary":   1.2,   // сидячий образ жизни
+	"light":       1.375, // 1-3 тренировки в неделю
+	"moderate":    1.55,  // 3-5 тренировок в неделю
+	"active":      1.725, // 6-7 тренировок в неделю
+	"very_active": 1.9,   // тяжелая физическая работа или 2 тренировки в день
+}
+
+// Поправка калорий и белок (г на кг веса) для каждой цели
+var goalParams = map[string]struct {
+	calorieFactor float64
+	proteinPerKg  float64
+}{
+	"lose":     {calorieFactor: 0.85, proteinPerKg: 2.0},
+	"maintain": {calorieFactor: 1.0, proteinPerKg: 1.6},
+	"gain":     {calorieFactor: 1.1, proteinPerKg: 1.8},
+}
+
+// Доля жиров в калорийности рациона
+const fatsEnergyShare = 0.25
+
+// NutritionTargets - рассчитанные дневные нормы
+type NutritionTargets struct {
+	BMR      int
+	TDEE     int
+	Calories int
+	Protein  float64
+	Carbs    float64
+	Fats     float64
+}
+
+// CalculateTargets считает BMR по формуле Миффлина-Сан Жеора, TDEE с учетом
+// активности и дневную норму КБЖУ с учетом цели
+func CalculateTargets(p ProfileDTO) (NutritionTargets, error) {
+	if err := p.validate(); err != nil {
+		return NutritionTargets{}, err
+	}
+
+	// BMR = 10 × вес + 6.25 × рост − 5 × возраст + 5 (муж.) / − 161 (жен.)
+	bmr := 10*p.WeightKg + 6.25*p.HeightCm - 5*float64(p.Age)
+	if p.Sex == "male" {
+		bmr += 5
+	} else {
+		bmr -= 161
+	}
+
+	tdee := bmr * ActivityFactors[p.ActivityLevel]
+	goal := goalParams[p.Goal]
+	calories := math.Round(tdee * goal.calorieFactor)
+
+	protein := goal.proteinPerKg * p.WeightKg
+	fats := calories * fatsEnergyShare / 9
+	carbs := (calories - protein*4 - fats*9) / 4
+	if carbs < 0 {
+		carbs = 0
+	}
+
+	return NutritionTargets{
+		BMR:      int(math.Round(bmr)),
+		TDEE:     int(math.Round(tdee)),
+		Calories: int(calories),
+		Protein:  math.Round(protein),
+		Carbs:    math.Round(carbs),
+		Fats:     math.Round(fats),
+	}, nil
+}
+
+func (p ProfileDTO) validate() error {
+	if p.Sex != "male" && p.Sex != "female" {
+		return i18n.NewError("error.profile.sex")
+	}
+	if p.Age < 14 || p.Age > 100 {
+		return i18n.NewError("error.profile.age")
+	}
+	if p.HeightCm < 100 || p.HeightCm > 250 {
+		return i18n.NewError("error.profile.height")
+	}
+	if p.WeightKg < 30 || p.WeightKg > 300 {
+		return i18n.NewError("error.profile.weight")
+	}
+	if _, ok := ActivityFactors[p.ActivityLevel]; !ok {
+		return i18n.NewError("error.profile.activity")
+	}
+	if _, ok := goalParams[p.Goal]; !ok {
+		return i18n.NewError("error.profile.goal")
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+)
+
+// Ожидаемые значения посчитаны вручную:
+// BMR = 10 × вес + 6.25 × рост − 5 × возраст + 5 (муж.) / − 161 (жен.),
+// TDEE = BMR × коэффициент активности, калории = TDEE × поправка цели,
+// белки = г/кг × вес, жиры = 25% калорий / 9, углеводы - остаток / 4
+func TestCalculateTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile ProfileDTO
+		want    NutritionTargets
+	}{
+		{
+			// BMR 800 + 1125 − 150 + 5 = 1780; TDEE 1780 × 1.2 = 2136;
+			// жиры 534 ккал = 59.3 г; углеводы (2136 − 512 − 534) / 4 = 272.5
+			"мужчина, сидячий, поддержание",
+			ProfileDTO{Sex: "male", Age: 30, HeightCm: 180, WeightKg: 80, ActivityLevel: "sedentary", Goal: "maintain"},
+			NutritionTargets{BMR: 1780, TDEE: 2136, Calories: 2136, Protein: 128, Fats: 59, Carbs: 273},
+		},
+		{
+			// BMR 600 + 1031.25 − 150 − 161 = 1320.25; TDEE × 1.375 = 1815.3; × 0.85 = 1543.04
+			"женщина, легкая активность, похудение",
+			ProfileDTO{Sex: "female", Age: 30, HeightCm: 165, WeightKg: 60, ActivityLevel: "light", Goal: "lose"},
+			NutritionTargets{BMR: 1320, TDEE: 1815, Calories: 1543, Protein: 120, Fats: 43, Carbs: 169},
+		},
+		{
+			// BMR 700 + 1093.75 − 125 + 5 = 1673.75; TDEE × 1.55 = 2594.3; × 1.1 = 2853.7
+			"мужчина, умеренная активность, набор",
+			ProfileDTO{Sex: "male", Age: 25, HeightCm: 175, WeightKg: 70, ActivityLevel: "moderate", Goal: "gain"},
+			NutritionTargets{BMR: 1674, TDEE: 2594, Calories: 2854, Protein: 126, Fats: 79, Carbs: 409},
+		},
+		{
+			// BMR 700 + 1062.5 − 200 − 161 = 1401.5; TDEE × 1.725 = 2417.6
+			"женщина, высокая активность, поддержание",
+			ProfileDTO{Sex: "female", Age: 40, HeightCm: 170, WeightKg: 70, ActivityLevel: "active", Goal: "maintain"},
+			NutritionTargets{BMR: 1402, TDEE: 2418, Calories: 2418, Protein: 112, Fats: 67, Carbs: 341},
+		},
+		{
+			// BMR 950 + 1156.25 − 250 + 5 = 1861.25; TDEE × 1.9 = 3536.4; × 0.85 = 3005.9;
+			// жиры 751.5 ккал = 83.5 г
+			"мужчина, очень высокая активность, похудение",
+			ProfileDTO{Sex: "male", Age: 50, HeightCm: 185, WeightKg: 95, ActivityLevel: "very_active", Goal: "lose"},
+			NutritionTargets{BMR: 1861, TDEE: 3536, Calories: 3006, Protein: 190, Fats: 84, Carbs: 374},
+		},
+		{
+			// BMR 550 + 1000 − 100 − 161 = 1289; TDEE × 1.9 = 2449.1; × 1.1 = 2694.01
+			"женщина, очень высокая активность, набор",
+			ProfileDTO{Sex: "female", Age: 20, HeightCm: 160, WeightKg: 55, ActivityLevel: "very_active", Goal: "gain"},
+			NutritionTargets{BMR: 1289, TDEE: 2449, Calories: 2694, Protein: 99, Fats: 75, Carbs: 406},
+		},
+		{
+			// BMR 300 + 625 − 70 − 161 = 694; TDEE × 1.2 = 832.8; × 0.85 = 707.9
+			"нижние границы",
+			ProfileDTO{Sex: "female", Age: 14, HeightCm: 100, WeightKg: 30, ActivityLevel: "sedentary", Goal: "lose"},
+			NutritionTargets{BMR: 694, TDEE: 833, Calories: 708, Protein: 60, Fats: 20, Carbs: 73},
+		},
+		{
+			// BMR 3000 + 1562.5 − 500 + 5 = 4067.5; TDEE × 1.9 = 7728.25; × 1.1 = 8501.1
+			"верхние границы",
+			ProfileDTO{Sex: "male", Age: 100, HeightCm: 250, WeightKg: 300, ActivityLevel: "very_active", Goal: "gain"},
+			NutritionTargets{BMR: 4068, TDEE: 7728, Calories: 8501, Protein: 540, Fats: 236, Carbs: 1054},
+		},
+		{
+			// BMR 3000 + 625 − 500 − 161 = 2964; калории 3023, из них белки 2400 и жиры 756:
+			// на углеводы не остается, они не уходят в минус
+			"белки и жиры больше нормы калорий",
+			ProfileDTO{Sex: "female", Age: 100, HeightCm: 100, WeightKg: 300, ActivityLevel: "sedentary", Goal: "lose"},
+			NutritionTargets{BMR: 2964, TDEE: 3557, Calories: 3023, Protein: 600, Fats: 84, Carbs: 0},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := CalculateTargets(tc.profile)
+			if err != nil {
+				t.Fatalf("CalculateTargets: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("CalculateTargets(%+v)\n got %+v\nwant %+v", tc.profile, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCalculateTargetsInvalid(t *testing.T) {
+	valid := ProfileDTO{Sex: "female", Age: 30, HeightCm: 165, WeightKg: 60, ActivityLevel: "light", Goal: "lose"}
+	tests := []struct {
+		name   string
+		change func(p *ProfileDTO)
+		want   string // Ключ ошибки
+	}{
+		{"пол не указан", func(p *ProfileDTO) { p.Sex = "" }, "error.profile.sex"},
+		{"неизвестный пол", func(p *ProfileDTO) { p.Sex = "Female" }, "error.profile.sex"},
+		{"младше 14", func(p *ProfileDTO) { p.Age = 13 }, "error.profile.age"},
+		{"старше 100", func(p *ProfileDTO) { p.Age = 101 }, "error.profile.age"},
+		{"рост ниже 100 см", func(p *ProfileDTO) { p.HeightCm = 99.9 }, "error.profile.height"},
+		{"рост выше 250 см", func(p *ProfileDTO) { p.HeightCm = 250.1 }, "error.profile.height"},
+		{"вес меньше 30 кг", func(p *ProfileDTO) { p.WeightKg = 29.9 }, "error.profile.weight"},
+		{"вес больше 300 кг", func(p *ProfileDTO) { p.WeightKg = 300.1 }, "error.profile.weight"},
+		{"отрицательный вес", func(p *ProfileDTO) { p.WeightKg = -60 }, "error.profile.weight"},
+		{"неизвестная активность", func(p *ProfileDTO) { p.ActivityLevel = "extreme" }, "error.profile.activity"},
+		{"цель не указана", func(p *ProfileDTO) { p.Goal = "" }, "error.profile.goal"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			profile := valid
+			tc.change(&profile)
+			got, err := CalculateTargets(profile)
+			var e *i18n.Error
+			if !errors.As(err, &e) || e.Key != tc.want {
+				t.Fatalf("CalculateTargets(%+v) error = %v, want %s", profile, err, tc.want)
+			}
+			if got != (NutritionTargets{}) {
+				t.Errorf("вместе с ошибкой возвращены нормы %+v", got)
+			}
+		})
+	}
+}
@@ -1,9 +1,9 @@
 package service
 
 import (
-	"fmt"
 	"log"
 
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
 	"github.com/alenapavlenkko/telegramfitnes/internal/models"
 	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
 )
@@ -19,10 +19,10 @@ func NewTrainingService(repo repository.TrainingRepository) *TrainingService {
 func (s *TrainingService) CreateTraining(dto CreateTrainingDTO) (*models.TrainingProgram, error) {
 	// Валидация
 	if dto.Title == "" {
-		return nil, fmt.Errorf("название тренировки не может быть пустым")
+		return nil, i18n.NewError("error.training.title_empty")
 	}
 	if dto.Duration <= 0 {
-		return nil, fmt.Errorf("длительность должна быть положительным числом")
+		return nil, i18n.NewError("error.training.duration")
 	}
 
 	training := &models.TrainingProgram{
@@ -32,6 +32,7 @@ func (s *TrainingService) CreateTraining(dto CreateTrainingDTO) (*models.Trainin
 		Difficulty:  dto.Difficulty,
 		CategoryID:  dto.CategoryID,
 		YouTubeLink: dto.YouTubeLink,
+		Weeks:       1,
 	}
 
 	return s.repo.Create(training)
@@ -47,29 +48,42 @@ func (s *TrainingService) ListTrainings() ([]*models.TrainingProgram, error) {
 	return trainings, nil
 }
 
+// ListTrainingsByCategory - страница тренировок категории (page с нуля) и их общее количество
+func (s *TrainingService) ListTrainingsByCategory(categoryID uint, page, pageSize int) ([]*models.TrainingProgram, int64, error) {
+	if categoryID == 0 {
+		return nil, 0, i18n.NewError("error.category.invalid_id")
+	}
+	total, err := s.repo.CountByCategoryID(categoryID)
+	if err != nil {
+		return nil, 0, err
+	}
+	trainings, err := s.repo.FindByCategoryID(categoryID, pageOffset(page, pageSize), pageSize)
+	return trainings, total, err
+}
+
 func (s *TrainingService) GetTrainingByID(id uint) (*models.TrainingProgram, error) {
 	if id == 0 {
-		return nil, fmt.Errorf("неверный ID")
+		return nil, i18n.NewError("error.invalid_id")
 	}
 	return s.repo.FindByID(id)
 }
 
 func (s *TrainingService) DeleteTraining(id uint) error {
 	if id == 0 {
-		return fmt.Errorf("неверный ID")
+		return i18n.NewError("error.invalid_id")
 	}
 	return s.repo.Delete(id)
 }
 
 func (s *TrainingService) UpdateTraining(id uint, dto UpdateTrainingDTO) error {
 	if id == 0 {
-		return fmt.Errorf("неверный ID")
+		return i18n.NewError("error.invalid_id")
 	}
 
 	// Получаем существующую тренировку
 	training, err := s.repo.FindByID(id)
 	if err != nil {
-		return fmt.Errorf("тренировка не найдена: %w", err)
+		return i18n.WrapError(err, "error.training.not_found")
 	}
 
 	// Валидация
@@ -88,6 +102,9 @@ func (s *TrainingService) UpdateTraining(id uint, dto UpdateTrainingDTO) error {
 	if dto.CategoryID != nil {
 		training.CategoryID = dto.CategoryID
 	}
+	if dto.YouTubeLink != "" {
+		training.YouTubeLink = dto.YouTubeLink
+	}
 
 	return s.repo.Update(training)
 }
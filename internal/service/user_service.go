@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/alenapavlenkko/telegramfitnes/internal/models"
 	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
 )
@@ -37,3 +39,97 @@ func (s *UserService) GetUsersCount() (int64, error) {
 func (s *UserService) GetAllUsers() ([]*models.User, error) {
 	return s.repo.FindAll()
 }
+
+// UpdateProfile - сохранить профиль пользователя и пересчитать дневные нормы
+func (s *UserService) UpdateProfile(telegramID int64, dto ProfileDTO) (*models.User, error) {
+	targets, err := CalculateTargets(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.FindByTelegramID(telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.Sex = dto.Sex
+	user.Age = dto.Age
+	user.HeightCm = dto.HeightCm
+	user.WeightKg = dto.WeightKg
+	user.ActivityLevel = dto.ActivityLevel
+	user.Goal = dto.Goal
+	user.BMR = targets.BMR
+	user.TDEE = targets.TDEE
+	user.CalorieTarget = targets.Calories
+	user.ProteinTarget = targets.Protein
+	user.CarbsTarget = targets.Carbs
+	user.FatsTarget = targets.Fats
+	user.ProfileCompletedAt = &now
+
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetDietRestrictions - диеты, которых придерживается пользователь, и аллергены,
+// которые он исключает. Неизвестные биты отбрасываются
+func (s *UserService) SetDietRestrictions(telegramID int64, diets, allergens int) (*models.User, error) {
+	user, err := s.repo.FindByTelegramID(telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Diets = diets & allTags(DietTags)
+	user.ExcludedAllergens = NormalizeAllergens(allergens)
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetLocale - язык интерфейса, выбранный пользователем. Пустая строка - язык Telegram
+func (s *UserService) SetLocale(telegramID int64, locale string) (*models.User, error) {
+	user, err := s.repo.FindByTelegramID(telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Locale = locale
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetLanguageCode запоминает язык Telegram пользователя
+func (s *UserService) SetLanguageCode(user *models.User, code string) error {
+	user.LanguageCode = code
+	return s.repo.Update(user)
+}
+
+// UpdateWeight - обновить вес. Если профиль заполнен, нормы пересчитываются под новый вес
+func (s *UserService) UpdateWeight(telegramID int64, weightKg float64) (*models.User, error) {
+	user, err := s.repo.FindByTelegramID(telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.HasTargets() {
+		return s.UpdateProfile(telegramID, ProfileDTO{
+			Sex:           user.Sex,
+			Age:           user.Age,
+			HeightCm:      user.HeightCm,
+			WeightKg:      weightKg,
+			ActivityLevel: user.ActivityLevel,
+			Goal:          user.Goal,
+		})
+	}
+
+	user.WeightKg = weightKg
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"time"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/repository"
+)
+
+type WorkoutService struct {
+	repo         repository.WorkoutLogRepository
+	trainingRepo repository.TrainingRepository
+}
+
+func NewWorkoutService(repo repository.WorkoutLogRepository, trainingRepo repository.TrainingRepository) *WorkoutService {
+	return &WorkoutService{
+		repo:         repo,
+		trainingRepo: trainingRepo,
+	}
+}
+
+// WorkoutTotals - сводка по тренировкам за период
+type WorkoutTotals struct {
+	Count     int
+	Minutes   int
+	AvgEffort float64
+}
+
+// LogWorkout - записать выполненную тренировку
+func (s *WorkoutService) LogWorkout(dto LogWorkoutDTO) (*models.WorkoutLog, error) {
+	if dto.UserID == 0 {
+		return nil, i18n.NewError("error.user.invalid")
+	}
+	if dto.Duration <= 0 || dto.Duration > 600 {
+		return nil, i18n.NewError("error.workout.duration")
+	}
+	if dto.Effort < 1 || dto.Effort > 10 {
+		return nil, i18n.NewError("error.workout.effort")
+	}
+
+	training, err := s.trainingRepo.FindByID(dto.TrainingID)
+	if err != nil {
+		return nil, i18n.WrapError(err, "error.training.not_found")
+	}
+
+	performedAt := dto.PerformedAt
+	if performedAt.IsZero() {
+		performedAt = time.Now()
+	}
+
+	workout := &models.WorkoutLog{
+		UserID:      dto.UserID,
+		TrainingID:  training.ID,
+		PerformedAt: performedAt,
+		Duration:    dto.Duration,
+		Effort:      dto.Effort,
+	}
+	created, err := s.repo.Create(workout)
+	if err != nil {
+		return nil, err
+	}
+	created.Training = *training
+	return created, nil
+}
+
+// ListRecentWorkouts - последние выполненные тренировки пользователя
+func (s *WorkoutService) ListRecentWorkouts(userID uint, limit int) ([]*models.WorkoutLog, error) {
+	return s.repo.FindRecentByUser(userID, limit)
+}
+
+// GetTotals - сводка за период [from, to)
+func (s *WorkoutService) GetTotals(userID uint, from, to time.Time) (WorkoutTotals, error) {
+	logs, err := s.repo.FindByUserBetween(userID, from, to)
+	if err != nil {
+		return WorkoutTotals{}, err
+	}
+
+	var totals WorkoutTotals
+	effortSum := 0
+	for _, l := range logs {
+		totals.Count++
+		totals.Minutes += l.Duration
+		effortSum += l.Effort
+	}
+	if totals.Count > 0 {
+		totals.AvgEffort = float64(effortSum) / float64(totals.Count)
+	}
+	return totals, nil
+}
+
+// GetWeeklyTotals - сводка за текущую неделю (с понедельника)
+func (s *WorkoutService) GetWeeklyTotals(userID uint, now time.Time) (WorkoutTotals, error) {
+	from := StartOfWeek(now)
+	return s.GetTotals(userID, from, from.AddDate(0, 0, 7))
+}
+
+// GetMonthlyTotals - сводка за текущий календарный месяц
+func (s *WorkoutService) GetMonthlyTotals(userID uint, now time.Time) (WorkoutTotals, error) {
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return s.GetTotals(userID, from, from.AddDate(0, 1, 0))
+}
+
+// StartOfWeek возвращает полночь понедельника недели, в которую попадает t
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // понедельник = 0
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,417 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// editForm - данные страницы создания или редактирования
+type editForm struct {
+	ID         uint // 0 - новая запись
+	Form       *form
+	Categories []*models.Category
+}
+
+// categoryNames - названия категорий по ID для списков
+func (d *Dashboard) categoryNames() (map[uint]string, []*models.Category, error) {
+	categories, err := d.categoryService.ListCategories()
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make(map[uint]string, len(categories))
+	for _, cat := range categories {
+		names[cat.ID] = cat.Name
+	}
+	return names, categories, nil
+}
+
+// ==================== ТРЕНИРОВКИ ====================
+
+func (d *Dashboard) listTrainings(c *gin.Context) {
+	trainings, err := d.trainingService.ListTrainings()
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	names, _, err := d.categoryNames()
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	d.render(c, http.StatusOK, "trainings.html", page{
+		Title:  "Тренировки",
+		Nav:    "trainings",
+		Notice: c.Query("notice"),
+		Data: struct {
+			Trainings  []*models.TrainingProgram
+			Categories map[uint]string
+		}{trainings, names},
+	})
+}
+
+func (d *Dashboard) newTraining(c *gin.Context) {
+	d.trainingForm(c, http.StatusOK, 0, &form{Values: map[string]string{}})
+}
+
+func (d *Dashboard) editTraining(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	training, err := d.trainingService.GetTrainingByID(id)
+	if err != nil {
+		c.String(http.StatusNotFound, "Тренировка не найдена")
+		return
+	}
+	d.trainingForm(c, http.StatusOK, id, &form{Values: map[string]string{
+		"title":        training.Title,
+		"description":  training.Description,
+		"difficulty":   training.Difficulty,
+		"duration":     strconv.Itoa(training.Duration),
+		"category_id":  formatID(derefUint(training.CategoryID)),
+		"youtube_link": training.YouTubeLink,
+	}})
+}
+
+func (d *Dashboard) trainingForm(c *gin.Context, status int, id uint, f *form) {
+	_, categories, err := d.categoryNames()
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	title := "Новая тренировка"
+	if id != 0 {
+		title = fmt.Sprintf("Тренировка #%d", id)
+	}
+	d.render(c, status, "training_form.html", page{
+		Title: title,
+		Nav:   "trainings",
+		Error: f.Error(),
+		Data:  editForm{ID: id, Form: f, Categories: categories},
+	})
+}
+
+// parseTraining читает форму тренировки; ошибки копятся в f
+func parseTraining(f *form) service.CreateTrainingDTO {
+	dto := service.CreateTrainingDTO{
+		Title:       f.required("title", "Название"),
+		Description: f.Get("description"),
+		Difficulty:  f.Get("difficulty"),
+		Duration:    f.int("duration", "Длительность"),
+		YouTubeLink: f.Get("youtube_link"),
+	}
+	if categoryID := f.uint("category_id", "Категория"); categoryID != 0 {
+		dto.CategoryID = &categoryID
+	}
+	return dto
+}
+
+func (d *Dashboard) createTraining(c *gin.Context) {
+	f := newForm(c)
+	dto := parseTraining(f)
+	if f.ok() {
+		if _, err := d.trainingService.CreateTraining(dto); err != nil {
+			f.Errors = append(f.Errors, err.Error())
+		}
+	}
+	if !f.ok() {
+		d.trainingForm(c, http.StatusBadRequest, 0, f)
+		return
+	}
+	redirect(c, "/admin/trainings?notice=Тренировка+создана")
+}
+
+func (d *Dashboard) updateTraining(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	f := newForm(c)
+	dto := parseTraining(f)
+	if f.ok() {
+		err := d.trainingService.UpdateTraining(id, service.UpdateTrainingDTO(dto))
+		if err != nil {
+			f.Errors = append(f.Errors, err.Error())
+		}
+	}
+	if !f.ok() {
+		d.trainingForm(c, http.StatusBadRequest, id, f)
+		return
+	}
+	redirect(c, "/admin/trainings?notice=Тренировка+сохранена")
+}
+
+func (d *Dashboard) deleteTraining(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	if err := d.trainingService.DeleteTraining(id); err != nil {
+		d.fail(c, err)
+		return
+	}
+	redirect(c, "/admin/trainings?notice=Тренировка+удалена")
+}
+
+// ==================== ПИТАНИЕ ====================
+
+func (d *Dashboard) listNutrition(c *gin.Context) {
+	plans, err := d.nutritionService.ListNutrition()
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	names, _, err := d.categoryNames()
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	d.render(c, http.StatusOK, "nutrition.html", page{
+		Title:  "Блюда",
+		Nav:    "nutrition",
+		Notice: c.Query("notice"),
+		Data: struct {
+			Plans      []*models.NutritionPlan
+			Categories map[uint]string
+		}{plans, names},
+	})
+}
+
+func (d *Dashboard) newNutrition(c *gin.Context) {
+	d.nutritionForm(c, http.StatusOK, 0, &form{Values: map[string]string{}})
+}
+
+func (d *Dashboard) editNutrition(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	plan, err := d.nutritionService.GetNutritionByID(id)
+	if err != nil {
+		c.String(http.StatusNotFound, "Блюдо не найдено")
+		return
+	}
+	f := &form{Values: map[string]string{
+		"title":       plan.Title,
+		"description": plan.Description,
+		"calories":    strconv.Itoa(plan.Calories),
+		"protein":     strconv.FormatFloat(plan.Protein, 'f', -1, 64),
+		"carbs":       strconv.FormatFloat(plan.Carbs, 'f', -1, 64),
+		"fats":        strconv.FormatFloat(plan.Fats, 'f', -1, 64),
+		"category_id": formatID(plan.CategoryID),
+	}}
+	for _, key := range service.TagKeys(service.DietTags, plan.Diets) {
+		f.Values["diet_"+key] = "on"
+	}
+	for _, key := range service.TagKeys(service.AllergenTags, plan.Allergens) {
+		f.Values["allergen_"+key] = "on"
+	}
+	if message := c.Query("error"); message != "" {
+		f.Errors = append(f.Errors, message)
+	}
+	d.nutritionForm(c, http.StatusOK, id, f)
+}
+
+func (d *Dashboard) nutritionForm(c *gin.Context, status int, id uint, f *form) {
+	_, categories, err := d.categoryNames()
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	data := nutritionEditForm{editForm: editForm{ID: id, Form: f, Categories: categories}}
+	title := "Новое блюдо"
+	if id != 0 {
+		title = fmt.Sprintf("Блюдо #%d", id)
+		if err := d.loadRecipe(id, &data); err != nil {
+			d.fail(c, err)
+			return
+		}
+	}
+	d.render(c, status, "nutrition_form.html", page{
+		Title:  title,
+		Nav:    "nutrition",
+		Error:  f.Error(),
+		Notice: c.Query("notice"),
+		Data:   data,
+	})
+}
+
+// parseNutrition читает форму блюда; ошибки копятся в f
+func parseNutrition(f *form) service.CreateNutritionDTO {
+	dto := service.CreateNutritionDTO{
+		Title:       f.required("title", "Название"),
+		Description: f.Get("description"),
+		Calories:    f.int("calories", "Калории"),
+		Protein:     f.float("protein", "Белки"),
+		Carbs:       f.float("carbs", "Углеводы"),
+		Fats:        f.float("fats", "Жиры"),
+	}
+	f.required("category_id", "Категория")
+	dto.CategoryID = f.uint("category_id", "Категория")
+	dto.Diets = f.tags("diet_", service.DietTags)
+	dto.Allergens = f.tags("allergen_", service.AllergenTags)
+	return dto
+}
+
+func (d *Dashboard) createNutrition(c *gin.Context) {
+	f := newForm(c)
+	dto := parseNutrition(f)
+	if f.ok() {
+		if _, err := d.nutritionService.CreateNutrition(dto); err != nil {
+			f.Errors = append(f.Errors, err.Error())
+		}
+	}
+	if !f.ok() {
+		d.nutritionForm(c, http.StatusBadRequest, 0, f)
+		return
+	}
+	redirect(c, "/admin/nutrition?notice=Блюдо+создано")
+}
+
+func (d *Dashboard) updateNutrition(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	f := newForm(c)
+	dto := parseNutrition(f)
+	if f.ok() {
+		if err := d.nutritionService.UpdateNutrition(id, service.UpdateNutritionDTO(dto)); err != nil {
+			f.Errors = append(f.Errors, err.Error())
+		}
+	}
+	if !f.ok() {
+		d.nutritionForm(c, http.StatusBadRequest, id, f)
+		return
+	}
+	redirect(c, "/admin/nutrition?notice=Блюдо+сохранено")
+}
+
+func (d *Dashboard) deleteNutrition(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	if err := d.nutritionService.DeleteNutrition(id); err != nil {
+		d.fail(c, err)
+		return
+	}
+	redirect(c, "/admin/nutrition?notice=Блюдо+удалено")
+}
+
+// ==================== КАТЕГОРИИ ====================
+
+func (d *Dashboard) listCategories(c *gin.Context) {
+	categories, err := d.categoryService.ListCategories()
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	d.render(c, http.StatusOK, "categories.html", page{
+		Title:  "Категории",
+		Nav:    "categories",
+		Notice: c.Query("notice"),
+		Data:   categories,
+	})
+}
+
+func (d *Dashboard) newCategory(c *gin.Context) {
+	d.categoryForm(c, http.StatusOK, 0, &form{Values: map[string]string{"type": "general"}})
+}
+
+func (d *Dashboard) editCategory(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	category, err := d.categoryService.GetCategoryByID(id)
+	if err != nil {
+		c.String(http.StatusNotFound, "Категория не найдена")
+		return
+	}
+	d.categoryForm(c, http.StatusOK, id, &form{Values: map[string]string{
+		"name":        category.Name,
+		"description": category.Description,
+		"type":        category.Type,
+	}})
+}
+
+func (d *Dashboard) categoryForm(c *gin.Context, status int, id uint, f *form) {
+	title := "Новая категория"
+	if id != 0 {
+		title = fmt.Sprintf("Категория #%d", id)
+	}
+	d.render(c, status, "category_form.html", page{
+		Title: title,
+		Nav:   "categories",
+		Error: f.Error(),
+		Data:  editForm{ID: id, Form: f},
+	})
+}
+
+func (d *Dashboard) createCategory(c *gin.Context) {
+	f := newForm(c)
+	dto := service.CreateCategoryDTO{
+		Name:        f.required("name", "Название"),
+		Description: f.Get("description"),
+		Type:        f.Get("type"),
+	}
+	if f.ok() {
+		if _, err := d.categoryService.CreateCategory(dto); err != nil {
+			f.Errors = append(f.Errors, err.Error())
+		}
+	}
+	if !f.ok() {
+		d.categoryForm(c, http.StatusBadRequest, 0, f)
+		return
+	}
+	redirect(c, "/admin/categories?notice=Категория+создана")
+}
+
+func (d *Dashboard) updateCategory(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	f := newForm(c)
+	dto := service.UpdateCategoryDTO{
+		Name:        f.required("name", "Название"),
+		Description: f.Get("description"),
+		Type:        f.Get("type"),
+	}
+	if f.ok() {
+		if err := d.categoryService.UpdateCategory(id, dto); err != nil {
+			f.Errors = append(f.Errors, err.Error())
+		}
+	}
+	if !f.ok() {
+		d.categoryForm(c, http.StatusBadRequest, id, f)
+		return
+	}
+	redirect(c, "/admin/categories?notice=Категория+сохранена")
+}
+
+func (d *Dashboard) deleteCategory(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	if err := d.categoryService.DeleteCategory(id); err != nil {
+		d.fail(c, err)
+		return
+	}
+	redirect(c, "/admin/categories?notice=Категория+удалена")
+}
+
+// formatID - ID для поля формы, пусто для нуля
+func formatID(id uint) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
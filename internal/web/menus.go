@@ -0,0 +1,296 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// menuGrid - недельное меню в виде сетки "прием пищи × 7 дней"
+type menuGrid struct {
+	Menu      *models.WeeklyMenu
+	Days      [7]gridDay
+	Rows      []gridRow
+	Dishes    []*models.NutritionPlan
+	MealTypes []string
+}
+
+// gridDay - заголовок колонки с итогами дня
+type gridDay struct {
+	Number   int
+	Name     string
+	Calories int
+	Protein  float64
+	Carbs    float64
+	Fats     float64
+}
+
+// gridRow - строка сетки: приемы пищи одного типа по дням
+type gridRow struct {
+	MealType string
+	Cells    [7][]models.DayMeal
+}
+
+// buildMenuGrid раскладывает приемы пищи по типам и дням. Стандартные типы
+// идут первыми, нестандартные - следом в порядке появления
+func buildMenuGrid(menu *models.WeeklyMenu) menuGrid {
+	tr := i18n.Default()
+	grid := menuGrid{Menu: menu, MealTypes: service.MealTypeNames(tr)}
+	for i := range grid.Days {
+		grid.Days[i] = gridDay{Number: i + 1, Name: tr.T("weekday." + strconv.Itoa(i+1))}
+	}
+
+	rowIndex := map[string]int{}
+	for _, mealType := range grid.MealTypes {
+		rowIndex[mealType] = len(grid.Rows)
+		grid.Rows = append(grid.Rows, gridRow{MealType: mealType})
+	}
+
+	for _, day := range menu.Days {
+		if day.DayNumber < 1 || day.DayNumber > 7 {
+			continue
+		}
+		col := day.DayNumber - 1
+		header := &grid.Days[col]
+		if day.DayName != "" {
+			header.Name = day.DayName
+		}
+		for _, meal := range day.Meals {
+			header.Calories += meal.Nutrition.Calories
+			header.Protein += meal.Nutrition.Protein
+			header.Carbs += meal.Nutrition.Carbs
+			header.Fats += meal.Nutrition.Fats
+
+			idx, ok := rowIndex[meal.MealType]
+			if !ok {
+				idx = len(grid.Rows)
+				rowIndex[meal.MealType] = idx
+				grid.Rows = append(grid.Rows, gridRow{MealType: meal.MealType})
+			}
+			grid.Rows[idx].Cells[col] = append(grid.Rows[idx].Cells[col], meal)
+		}
+	}
+
+	for r := range grid.Rows {
+		for col := range grid.Rows[r].Cells {
+			cell := grid.Rows[r].Cells[col]
+			sort.SliceStable(cell, func(i, j int) bool { return cell[i].MealTime < cell[j].MealTime })
+		}
+	}
+	return grid
+}
+
+func (d *Dashboard) listMenus(c *gin.Context) {
+	menus, err := d.nutritionService.ListWeeklyMenus()
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	d.render(c, http.StatusOK, "menus.html", page{
+		Title:  "Недельные меню",
+		Nav:    "menus",
+		Error:  c.Query("error"),
+		Notice: c.Query("notice"),
+		Data:   menus,
+	})
+}
+
+func (d *Dashboard) createMenu(c *gin.Context) {
+	f := newForm(c)
+	menu, err := d.nutritionService.CreateWeeklyMenu(service.CreateWeeklyMenuDTO{
+		Name:        f.Get("name"),
+		Description: f.Get("description"),
+	})
+	if err != nil {
+		redirect(c, "/admin/menus?error="+url.QueryEscape(err.Error()))
+		return
+	}
+	redirect(c, fmt.Sprintf("/admin/menus/%d", menu.ID))
+}
+
+// showMenu - редактор меню: сетка 7 дней и список блюд для перетаскивания
+func (d *Dashboard) showMenu(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	menu, err := d.nutritionService.GetFullWeeklyMenu(id)
+	if err != nil {
+		c.String(http.StatusNotFound, "Меню не найдено")
+		return
+	}
+	dishes, err := d.nutritionService.ListNutrition()
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	sort.SliceStable(dishes, func(i, j int) bool { return dishes[i].Title < dishes[j].Title })
+
+	grid := buildMenuGrid(menu)
+	grid.Dishes = dishes
+	d.render(c, http.StatusOK, "menu.html", page{
+		Title:  menu.Name,
+		Nav:    "menus",
+		Error:  c.Query("error"),
+		Notice: c.Query("notice"),
+		Data:   grid,
+	})
+}
+
+func (d *Dashboard) activateMenu(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	if _, err := d.nutritionService.GetWeeklyMenuByID(id); err != nil {
+		c.String(http.StatusNotFound, "Меню не найдено")
+		return
+	}
+	if err := d.nutritionService.ActivateWeeklyMenu(id); err != nil {
+		d.fail(c, err)
+		return
+	}
+	redirect(c, "/admin/menus?notice=Меню+активировано")
+}
+
+func (d *Dashboard) publishMenu(c *gin.Context) {
+	d.setMenuPublished(c, true, "/admin/menus?notice=Меню+опубликовано")
+}
+
+func (d *Dashboard) unpublishMenu(c *gin.Context) {
+	d.setMenuPublished(c, false, "/admin/menus?notice=Меню+снято+с+публикации")
+}
+
+// setMenuPublished открывает меню для выбора пользователями или скрывает его
+func (d *Dashboard) setMenuPublished(c *gin.Context, published bool, location string) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	if _, err := d.nutritionService.GetWeeklyMenuByID(id); err != nil {
+		c.String(http.StatusNotFound, "Меню не найдено")
+		return
+	}
+	if err := d.nutritionService.SetWeeklyMenuPublished(id, published); err != nil {
+		d.fail(c, err)
+		return
+	}
+	redirect(c, location)
+}
+
+func (d *Dashboard) deleteMenu(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	if err := d.nutritionService.DeleteWeeklyMenu(id); err != nil {
+		d.fail(c, err)
+		return
+	}
+	redirect(c, "/admin/menus?notice=Меню+удалено")
+}
+
+// addMeal кладет блюдо в клетку сетки, создавая день меню при необходимости
+func (d *Dashboard) addMeal(c *gin.Context) {
+	menuID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	f := newForm(c)
+	dayNumber := f.int("day_number", "День")
+	mealType := f.required("meal_type", "Прием пищи")
+	f.required("nutrition_id", "Блюдо")
+	nutritionID := f.uint("nutrition_id", "Блюдо")
+	if !f.ok() {
+		d.menuDone(c, menuID, errors.New(f.Error()))
+		return
+	}
+
+	day, err := d.nutritionService.EnsureMenuDay(menuID, dayNumber)
+	if err == nil {
+		_, err = d.nutritionService.AddMealToDay(service.AddMealToDayDTO{
+			DayID:       day.ID,
+			MealType:    mealType,
+			MealTime:    f.Get("meal_time"),
+			NutritionID: nutritionID,
+		})
+	}
+	d.menuDone(c, menuID, err)
+}
+
+// moveMeal переносит прием пищи в другую клетку сетки
+func (d *Dashboard) moveMeal(c *gin.Context) {
+	menuID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	mealID, ok := pathID(c, "mealID")
+	if !ok {
+		return
+	}
+	f := newForm(c)
+	dayNumber := f.int("day_number", "День")
+	mealType := f.required("meal_type", "Прием пищи")
+	if !f.ok() {
+		d.menuDone(c, menuID, errors.New(f.Error()))
+		return
+	}
+
+	day, err := d.nutritionService.EnsureMenuDay(menuID, dayNumber)
+	if err == nil {
+		err = d.nutritionService.MoveMealToDay(mealID, day.ID, mealType)
+	}
+	d.menuDone(c, menuID, err)
+}
+
+func (d *Dashboard) deleteMeal(c *gin.Context) {
+	menuID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	mealID, ok := pathID(c, "mealID")
+	if !ok {
+		return
+	}
+
+	// Прием пищи должен принадлежать этому меню
+	meal, err := d.nutritionService.GetDayMealByID(mealID)
+	if err != nil {
+		d.menuDone(c, menuID, fmt.Errorf("прием пищи не найден"))
+		return
+	}
+	day, err := d.nutritionService.GetMenuDayByID(meal.DayID)
+	if err != nil || day.MenuID != menuID {
+		d.menuDone(c, menuID, fmt.Errorf("прием пищи не найден"))
+		return
+	}
+	d.menuDone(c, menuID, d.nutritionService.DeleteMealFromDay(mealID))
+}
+
+// menuDone завершает изменение сетки: скрипт редактора получает 204 или
+// JSON с ошибкой, обычная форма - редирект обратно на меню
+func (d *Dashboard) menuDone(c *gin.Context, menuID uint, err error) {
+	if strings.Contains(c.GetHeader("Accept"), "application/json") {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	location := fmt.Sprintf("/admin/menus/%d", menuID)
+	if err != nil {
+		location += "?error=" + url.QueryEscape(err.Error())
+	}
+	redirect(c, location)
+}
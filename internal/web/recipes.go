@@ -0,0 +1,237 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/models"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// nutritionEditForm - форма блюда с его рецептом
+type nutritionEditForm struct {
+	editForm
+	Recipe      []*models.RecipeItem
+	Ingredients []*models.Ingredient // Для выбора нового ингредиента
+	Steps       string
+}
+
+// loadRecipe заполняет рецепт блюда id для формы
+func (d *Dashboard) loadRecipe(id uint, data *nutritionEditForm) error {
+	plan, err := d.nutritionService.GetNutritionByID(id)
+	if err != nil {
+		return err
+	}
+	data.Steps = plan.Steps
+	if data.Recipe, err = d.recipeService.GetRecipe(id); err != nil {
+		return err
+	}
+	data.Ingredients, err = d.recipeService.ListIngredients()
+	return err
+}
+
+// ==================== РЕЦЕПТ БЛЮДА ====================
+
+// updateRecipe меняет текущий рецепт блюда через change и сохраняет его целиком
+func (d *Dashboard) updateRecipe(c *gin.Context, change func(recipe *service.SetRecipeDTO) error) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	plan, err := d.nutritionService.GetNutritionByID(id)
+	if err != nil {
+		c.String(http.StatusNotFound, "Блюдо не найдено")
+		return
+	}
+	items, err := d.recipeService.GetRecipe(id)
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+
+	recipe := service.SetRecipeDTO{Steps: plan.Steps}
+	for _, item := range items {
+		recipe.Items = append(recipe.Items, service.RecipeItemDTO{IngredientID: item.IngredientID, Grams: item.Grams})
+	}
+	if err = change(&recipe); err == nil {
+		_, err = d.recipeService.SetRecipe(id, recipe)
+	}
+
+	location := fmt.Sprintf("/admin/nutrition/%d", id)
+	if err != nil {
+		redirect(c, location+"?error="+url.QueryEscape(err.Error()))
+		return
+	}
+	redirect(c, location+"?notice=Рецепт+сохранен")
+}
+
+func (d *Dashboard) addRecipeItem(c *gin.Context) {
+	d.updateRecipe(c, func(recipe *service.SetRecipeDTO) error {
+		f := newForm(c)
+		f.required("ingredient_id", "Ингредиент")
+		f.required("grams", "Граммы")
+		item := service.RecipeItemDTO{
+			IngredientID: f.uint("ingredient_id", "Ингредиент"),
+			Grams:        f.float("grams", "Граммы"),
+		}
+		if !f.ok() {
+			return errors.New(f.Error())
+		}
+		// Повторно выбранный ингредиент меняет вес, а не дублируется
+		for i := range recipe.Items {
+			if recipe.Items[i].IngredientID == item.IngredientID {
+				recipe.Items[i].Grams = item.Grams
+				return nil
+			}
+		}
+		recipe.Items = append(recipe.Items, item)
+		return nil
+	})
+}
+
+func (d *Dashboard) deleteRecipeItem(c *gin.Context) {
+	ingredientID, ok := pathID(c, "ingredientID")
+	if !ok {
+		return
+	}
+	d.updateRecipe(c, func(recipe *service.SetRecipeDTO) error {
+		items := recipe.Items[:0]
+		for _, item := range recipe.Items {
+			if item.IngredientID != ingredientID {
+				items = append(items, item)
+			}
+		}
+		recipe.Items = items
+		return nil
+	})
+}
+
+func (d *Dashboard) saveRecipeSteps(c *gin.Context) {
+	d.updateRecipe(c, func(recipe *service.SetRecipeDTO) error {
+		if err := c.Request.ParseForm(); err != nil {
+			return errors.New("не удалось прочитать форму")
+		}
+		recipe.Steps = c.Request.PostForm.Get("steps")
+		return nil
+	})
+}
+
+// ==================== ИНГРЕДИЕНТЫ ====================
+
+func (d *Dashboard) listIngredients(c *gin.Context) {
+	ingredients, err := d.recipeService.ListIngredients()
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	d.render(c, http.StatusOK, "ingredients.html", page{
+		Title:  "Ингредиенты",
+		Nav:    "ingredients",
+		Error:  c.Query("error"),
+		Notice: c.Query("notice"),
+		Data:   ingredients,
+	})
+}
+
+func (d *Dashboard) newIngredient(c *gin.Context) {
+	d.ingredientForm(c, http.StatusOK, 0, &form{Values: map[string]string{"section": service.DefaultSection}})
+}
+
+func (d *Dashboard) editIngredient(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	ingredient, err := d.recipeService.GetIngredientByID(id)
+	if err != nil {
+		c.String(http.StatusNotFound, "Ингредиент не найден")
+		return
+	}
+	section := ingredient.Section
+	if section == "" {
+		section = service.DefaultSection
+	}
+	d.ingredientForm(c, http.StatusOK, id, &form{Values: map[string]string{
+		"name":     ingredient.Name,
+		"section":  section,
+		"calories": strconv.FormatFloat(ingredient.Calories, 'f', -1, 64),
+		"protein":  strconv.FormatFloat(ingredient.Protein, 'f', -1, 64),
+		"carbs":    strconv.FormatFloat(ingredient.Carbs, 'f', -1, 64),
+		"fats":     strconv.FormatFloat(ingredient.Fats, 'f', -1, 64),
+	}})
+}
+
+func (d *Dashboard) ingredientForm(c *gin.Context, status int, id uint, f *form) {
+	title := "Новый ингредиент"
+	if id != 0 {
+		title = fmt.Sprintf("Ингредиент #%d", id)
+	}
+	d.render(c, status, "ingredient_form.html", page{
+		Title: title,
+		Nav:   "ingredients",
+		Error: f.Error(),
+		Data:  editForm{ID: id, Form: f},
+	})
+}
+
+// parseIngredient читает форму ингредиента; ошибки копятся в f
+func parseIngredient(f *form) service.IngredientDTO {
+	return service.IngredientDTO{
+		Name:     f.required("name", "Название"),
+		Section:  f.Get("section"),
+		Calories: f.float("calories", "Калории"),
+		Protein:  f.float("protein", "Белки"),
+		Carbs:    f.float("carbs", "Углеводы"),
+		Fats:     f.float("fats", "Жиры"),
+	}
+}
+
+func (d *Dashboard) createIngredient(c *gin.Context) {
+	f := newForm(c)
+	dto := parseIngredient(f)
+	if f.ok() {
+		if _, err := d.recipeService.CreateIngredient(dto); err != nil {
+			f.Errors = append(f.Errors, err.Error())
+		}
+	}
+	if !f.ok() {
+		d.ingredientForm(c, http.StatusBadRequest, 0, f)
+		return
+	}
+	redirect(c, "/admin/ingredients?notice=Ингредиент+создан")
+}
+
+func (d *Dashboard) updateIngredient(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	f := newForm(c)
+	dto := parseIngredient(f)
+	if f.ok() {
+		if err := d.recipeService.UpdateIngredient(id, dto); err != nil {
+			f.Errors = append(f.Errors, err.Error())
+		}
+	}
+	if !f.ok() {
+		d.ingredientForm(c, http.StatusBadRequest, id, f)
+		return
+	}
+	redirect(c, "/admin/ingredients?notice=Ингредиент+сохранен")
+}
+
+func (d *Dashboard) deleteIngredient(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	if err := d.recipeService.DeleteIngredient(id); err != nil {
+		redirect(c, "/admin/ingredients?error="+url.QueryEscape(err.Error()))
+		return
+	}
+	redirect(c, "/admin/ingredients?notice=Ингредиент+удален")
+}
@@ -0,0 +1,86 @@
+// Редактор недельного меню: перетаскивание блюд в сетку, между клетками и в корзину.
+// Каждое действие - POST на сервер, после успеха страница перерисовывается.
+(function () {
+  const editor = document.querySelector('.editor');
+  if (!editor) return;
+  const base = '/admin/menus/' + editor.dataset.menu + '/meals';
+
+  function showError(message) {
+    const box = document.getElementById('error');
+    box.textContent = message;
+    box.hidden = false;
+    window.scrollTo(0, 0);
+  }
+
+  async function send(url, fields) {
+    const response = await fetch(url, {
+      method: 'POST',
+      headers: { 'Accept': 'application/json' },
+      body: new URLSearchParams(fields),
+    });
+    if (response.ok) {
+      window.location.reload();
+      return;
+    }
+    const body = await response.json().catch(() => ({}));
+    showError(body.error || 'Не удалось сохранить изменения');
+  }
+
+  editor.addEventListener('dragstart', (e) => {
+    const item = e.target.closest('[data-nutrition], [data-meal]');
+    if (!item) return;
+    const payload = item.dataset.meal ? { meal: item.dataset.meal } : { nutrition: item.dataset.nutrition };
+    e.dataTransfer.setData('application/json', JSON.stringify(payload));
+    e.dataTransfer.effectAllowed = 'move';
+  });
+
+  function dropTarget(e) {
+    return e.target.closest('.cell, .trash');
+  }
+
+  editor.addEventListener('dragover', (e) => {
+    const target = dropTarget(e);
+    if (!target) return;
+    e.preventDefault();
+    target.classList.add('over');
+  });
+
+  editor.addEventListener('dragleave', (e) => {
+    const target = dropTarget(e);
+    if (target) target.classList.remove('over');
+  });
+
+  editor.addEventListener('drop', (e) => {
+    const target = dropTarget(e);
+    if (!target) return;
+    e.preventDefault();
+    target.classList.remove('over');
+
+    let payload;
+    try {
+      payload = JSON.parse(e.dataTransfer.getData('application/json'));
+    } catch (_) {
+      return;
+    }
+
+    if (target.classList.contains('trash')) {
+      if (payload.meal) send(base + '/' + payload.meal + '/delete', {});
+      return;
+    }
+
+    const cell = { day_number: target.dataset.day, meal_type: target.dataset.type };
+    if (payload.meal) {
+      send(base + '/' + payload.meal + '/move', cell);
+    } else if (payload.nutrition) {
+      send(base, Object.assign(cell, { nutrition_id: payload.nutrition }));
+    }
+  });
+
+  const filter = document.getElementById('dish-filter');
+  filter.addEventListener('input', () => {
+    const query = filter.value.trim().toLowerCase();
+    document.querySelectorAll('.dish').forEach((dish) => {
+      dish.hidden = query !== '' && !dish.dataset.title.toLowerCase().includes(query);
+    });
+  });
+})();
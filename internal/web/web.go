@@ -0,0 +1,285 @@
+// Package web - браузерная админка: серверные страницы на html/template
+// для тренировок, блюд с рецептами, ингредиентов, категорий и недельных меню.
+// Шаблоны и статика встроены в бинарник, данные идут через те же сервисы,
+// что и Telegram-админка.
+package web
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alenapavlenkko/telegramfitnes/internal/i18n"
+	"github.com/alenapavlenkko/telegramfitnes/internal/server"
+	"github.com/alenapavlenkko/telegramfitnes/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed templates static
+var assets embed.FS
+
+// Страницы, у каждой свой набор шаблонов поверх layout.html
+var pageFiles = []string{
+	"trainings.html",
+	"training_form.html",
+	"nutrition.html",
+	"nutrition_form.html",
+	"categories.html",
+	"category_form.html",
+	"ingredients.html",
+	"ingredient_form.html",
+	"menus.html",
+	"menu.html",
+}
+
+// Dashboard отдает страницы админки
+type Dashboard struct {
+	trainingService  *service.TrainingService
+	nutritionService *service.NutritionService
+	categoryService  *service.CategoryService
+	recipeService    *service.RecipeService
+
+	pages map[string]*template.Template
+}
+
+func NewDashboard(
+	trainingService *service.TrainingService,
+	nutritionService *service.NutritionService,
+	categoryService *service.CategoryService,
+	recipeService *service.RecipeService,
+) (*Dashboard, error) {
+	funcs := template.FuncMap{
+		"add":     func(a, b int) int { return a + b },
+		"macro":   func(v float64) string { return strconv.FormatFloat(v, 'f', 1, 64) },
+		"derefID": func(id *uint) uint { return derefUint(id) },
+		// Калории ингредиента в рецепте
+		"itemCalories": service.ItemCalories,
+		// Отделы магазина для формы ингредиента
+		"storeSections": func() []string { return service.StoreSections },
+		"sectionName":   func(section string) string { return service.SectionName(i18n.Default(), section) },
+		// Диеты и аллергены для формы и списка блюд
+		"dietTags":     func() []service.DietTag { return service.DietTags },
+		"allergenTags": func() []service.DietTag { return service.AllergenTags },
+		"dietLabels": func(mask int) string {
+			return strings.Join(service.TagLabels(service.DietTags, mask), ", ")
+		},
+		"allergenLabels": func(mask int) string {
+			return strings.ToLower(strings.Join(service.TagLabels(service.AllergenTags, mask), ", "))
+		},
+	}
+
+	pages := make(map[string]*template.Template, len(pageFiles))
+	for _, page := range pageFiles {
+		tmpl, err := template.New(page).Funcs(funcs).ParseFS(assets, "templates/layout.html", "templates/"+page)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось разобрать шаблон %s: %w", page, err)
+		}
+		pages[page] = tmpl
+	}
+
+	return &Dashboard{
+		trainingService:  trainingService,
+		nutritionService: nutritionService,
+		categoryService:  categoryService,
+		recipeService:    recipeService,
+		pages:            pages,
+	}, nil
+}
+
+// Register подключает страницы /admin к router под basic auth
+func (d *Dashboard) Register(router gin.IRouter, username, password string) error {
+	auth, err := server.BasicAuth(username, password)
+	if err != nil {
+		return err
+	}
+	static, err := fs.Sub(assets, "static")
+	if err != nil {
+		return err
+	}
+
+	group := router.Group("/admin", auth, server.SameOrigin)
+	group.StaticFS("/static", http.FS(static))
+	group.GET("", func(c *gin.Context) { c.Redirect(http.StatusFound, "/admin/menus") })
+
+	group.GET("/trainings", d.listTrainings)
+	group.GET("/trainings/new", d.newTraining)
+	group.POST("/trainings", d.createTraining)
+	group.GET("/trainings/:id", d.editTraining)
+	group.POST("/trainings/:id", d.updateTraining)
+	group.POST("/trainings/:id/delete", d.deleteTraining)
+
+	group.GET("/nutrition", d.listNutrition)
+	group.GET("/nutrition/new", d.newNutrition)
+	group.POST("/nutrition", d.createNutrition)
+	group.GET("/nutrition/:id", d.editNutrition)
+	group.POST("/nutrition/:id", d.updateNutrition)
+	group.POST("/nutrition/:id/delete", d.deleteNutrition)
+	group.POST("/nutrition/:id/recipe/items", d.addRecipeItem)
+	group.POST("/nutrition/:id/recipe/items/:ingredientID/delete", d.deleteRecipeItem)
+	group.POST("/nutrition/:id/recipe/steps", d.saveRecipeSteps)
+
+	group.GET("/ingredients", d.listIngredients)
+	group.GET("/ingredients/new", d.newIngredient)
+	group.POST("/ingredients", d.createIngredient)
+	group.GET("/ingredients/:id", d.editIngredient)
+	group.POST("/ingredients/:id", d.updateIngredient)
+	group.POST("/ingredients/:id/delete", d.deleteIngredient)
+
+	group.GET("/categories", d.listCategories)
+	group.GET("/categories/new", d.newCategory)
+	group.POST("/categories", d.createCategory)
+	group.GET("/categories/:id", d.editCategory)
+	group.POST("/categories/:id", d.updateCategory)
+	group.POST("/categories/:id/delete", d.deleteCategory)
+
+	group.GET("/menus", d.listMenus)
+	group.POST("/menus", d.createMenu)
+	group.GET("/menus/:id", d.showMenu)
+	group.POST("/menus/:id/activate", d.activateMenu)
+	group.POST("/menus/:id/publish", d.publishMenu)
+	group.POST("/menus/:id/unpublish", d.unpublishMenu)
+	group.POST("/menus/:id/delete", d.deleteMenu)
+	group.POST("/menus/:id/meals", d.addMeal)
+	group.POST("/menus/:id/meals/:mealID/move", d.moveMeal)
+	group.POST("/menus/:id/meals/:mealID/delete", d.deleteMeal)
+
+	return nil
+}
+
+// page - общие данные страницы для layout.html
+type page struct {
+	Title  string
+	Nav    string // Активный раздел меню
+	Error  string
+	Notice string
+	Data   any
+}
+
+// render выполняет шаблон страницы. Ошибка шаблона - ошибка программиста, отвечаем 500
+func (d *Dashboard) render(c *gin.Context, status int, name string, p page) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	if err := d.pages[name].ExecuteTemplate(c.Writer, "layout", p); err != nil {
+		log.Printf("[web] render %s: %v", name, err)
+	}
+}
+
+// fail показывает ошибку чтения данных
+func (d *Dashboard) fail(c *gin.Context, err error) {
+	log.Printf("[web] %s %s: %v", c.Request.Method, c.FullPath(), err)
+	c.String(http.StatusInternalServerError, "Внутренняя ошибка")
+}
+
+// pathID читает положительный числовой параметр пути
+func pathID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		c.String(http.StatusNotFound, "Страница не найдена")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// redirect после успешного POST, чтобы обновление страницы не повторяло запрос
+func redirect(c *gin.Context, location string) {
+	c.Redirect(http.StatusSeeOther, location)
+}
+
+// form - значения полей формы и ошибки их разбора
+type form struct {
+	Values map[string]string
+	Errors []string
+}
+
+func newForm(c *gin.Context) *form {
+	f := &form{Values: map[string]string{}}
+	if err := c.Request.ParseForm(); err != nil {
+		f.Errors = append(f.Errors, "не удалось прочитать форму")
+		return f
+	}
+	for key := range c.Request.PostForm {
+		f.Values[key] = strings.TrimSpace(c.Request.PostForm.Get(key))
+	}
+	return f
+}
+
+// Get - значение поля для шаблона
+func (f *form) Get(name string) string {
+	return f.Values[name]
+}
+
+func (f *form) required(name, label string) string {
+	value := f.Values[name]
+	if value == "" {
+		f.Errors = append(f.Errors, label+": обязательное поле")
+	}
+	return value
+}
+
+func (f *form) int(name, label string) int {
+	value := f.Values[name]
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		f.Errors = append(f.Errors, label+": введите целое число")
+	}
+	return n
+}
+
+func (f *form) float(name, label string) float64 {
+	value := strings.Replace(f.Values[name], ",", ".", 1)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		f.Errors = append(f.Errors, label+": введите число")
+	}
+	return n
+}
+
+func (f *form) uint(name, label string) uint {
+	value := f.Values[name]
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		f.Errors = append(f.Errors, label+": неверный ID")
+	}
+	return uint(n)
+}
+
+// tags - маска отмеченных флажков prefix_<ключ тега>
+func (f *form) tags(prefix string, tags []service.DietTag) int {
+	mask := 0
+	for _, tag := range tags {
+		if f.Values[prefix+tag.Key] != "" {
+			mask |= tag.Bit
+		}
+	}
+	return mask
+}
+
+// Error - ошибки формы одной строкой
+func (f *form) Error() string {
+	return strings.Join(f.Errors, "; ")
+}
+
+func (f *form) ok() bool {
+	return len(f.Errors) == 0
+}
+
+func derefUint(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}